@@ -1,23 +1,55 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"tron/internal/app"
+	"tron/internal/git"
+	"tron/internal/keyboard"
 )
 
 func main() {
+	if socket := os.Getenv(git.AskpassEnv); socket != "" {
+		os.Exit(git.AskpassMain(socket, os.Args[1:]))
+	}
+
+	script := flag.String("script", "", "run the commands in `file` without the interface; - reads them from stdin")
+	flag.Parse()
+	if *script != "" {
+		if err := runScript(*script); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	m := app.New()
 	p := tea.NewProgram(
 		m,
 		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
+		tea.WithOutput(keyboard.Output),
+		tea.WithMouseAllMotion(),
 	)
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+func runScript(path string) error {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	return app.RunScript(r, path, os.Stdout)
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tron/internal/i18n"
+	"tron/internal/syntax"
+)
+
+// AccessibleEnv turns on accessibility mode regardless of the saved setting.
+const AccessibleEnv = "TRON_ACCESSIBLE"
+
+type accessibilitySettings struct {
+	Enabled      bool `json:"enabled"`
+	HighContrast bool `json:"highContrast"`
+}
+
+func accessibilityPath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "accessibility.json")
+}
+
+func loadAccessibility(rootPath string) *accessibilitySettings {
+	s := &accessibilitySettings{}
+	if data, err := os.ReadFile(accessibilityPath(rootPath)); err == nil {
+		_ = json.Unmarshal(data, s)
+	}
+	if v := os.Getenv(AccessibleEnv); v != "" && v != "0" {
+		s.Enabled = true
+	}
+	return s
+}
+
+func (s *accessibilitySettings) save(rootPath string) error {
+	path := accessibilityPath(rootPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (m *Model) applyAccessibility() {
+	a := m.accessibility
+	m.Tabs.Accessible = a.Enabled
+	m.FileTree.Accessible = a.Enabled
+	for _, ed := range m.groups.editors() {
+		ed.Accessible = a.Enabled
+		if a.HighContrast {
+			ed.SetTheme(syntax.HighContrastTheme())
+		} else {
+			ed.SetTheme(syntax.GetTheme())
+		}
+	}
+	m.refreshGutter()
+}
+
+func (m *Model) toggleAccessibility() {
+	m.accessibility.Enabled = !m.accessibility.Enabled
+	m.saveAccessibility()
+}
+
+func (m *Model) toggleHighContrast() {
+	m.accessibility.HighContrast = !m.accessibility.HighContrast
+	m.saveAccessibility()
+}
+
+func (m *Model) saveAccessibility() {
+	m.applyAccessibility()
+	if err := m.accessibility.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
+
+// describeContext prints the cursor, file and panel state as plain text so
+// it can be read by a screen reader following the terminal output.
+func (m *Model) describeContext() {
+	for _, line := range m.contextDescription() {
+		m.Terminal.Println(line)
+	}
+}
+
+func (m *Model) contextDescription() []string {
+	var lines []string
+	ed := m.Editor
+	if ed.FilePath == "" {
+		lines = append(lines, i18n.T("describe.noFile"))
+	} else {
+		state := i18n.T("describe.saved")
+		if ed.IsDirty() {
+			state = i18n.T("describe.modified")
+		}
+		if ed.ReadOnly {
+			state += ", " + i18n.T("describe.readOnly")
+		}
+		lines = append(lines, i18n.T("describe.file", ed.FilePath, state))
+		lines = append(lines, i18n.T("describe.cursor", ed.Cursor.Line+1, ed.Buffer.LineCount(), ed.Cursor.Column+1))
+		if sel := ed.Selection.Normalized(); !sel.IsEmpty() {
+			lines = append(lines, i18n.T("describe.selection",
+				sel.Start.Line+1, sel.Start.Column+1, sel.End.Line+1, sel.End.Column+1))
+		}
+	}
+
+	if active := m.Tabs.GetActive(); active != nil {
+		lines = append(lines, i18n.T("describe.tab", active.Index+1, m.Tabs.TabCount()))
+	}
+
+	var focus []string
+	if ed.Focused() {
+		focus = append(focus, i18n.T("describe.editor"))
+	}
+	if m.FileTree.Focused() {
+		tree := i18n.T("describe.fileTree")
+		if path := m.FileTree.SelectedPath(); path != "" && path != "." {
+			tree += ", " + i18n.T("describe.selected", path)
+		}
+		focus = append(focus, tree)
+	}
+	if len(focus) > 0 {
+		lines = append(lines, i18n.T("describe.focus", strings.Join(focus, "; ")))
+	}
+
+	if name := m.visibleOverlayName(); name != "" {
+		lines = append(lines, i18n.T("describe.panel", name))
+	}
+	return lines
+}
+
+func (m *Model) visibleOverlayName() string {
+	panels := []struct {
+		title string
+		panel overlayPanel
+	}{
+		{"breakpoints.title", m.bpPanel},
+		{"problems.title", m.problemsPanel},
+		{"repl.title", m.replPanel},
+		{"http.title", m.httpPanel},
+		{"sql.title", m.dbPanel},
+		{"docker.panel", m.dockerPanel},
+		{"collab.title", m.collab.panel},
+		{"scaffold.title", m.scaffoldPanel},
+		{"python.title", m.python.panel},
+		{"goenv.title", m.goEnvPanel},
+		{"git.title", m.git.panel},
+		{"gitlog.panel", m.git.log},
+		{"branch.title", m.git.branches},
+		{"keymap.title", m.keymapPanel},
+		{"palette.title", m.palette},
+	}
+	for _, p := range panels {
+		if p.panel.Visible() {
+			return i18n.T(p.title)
+		}
+	}
+	return ""
+}
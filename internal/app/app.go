@@ -1,17 +1,38 @@
 package app
 
 import (
-	"strings"
+	"errors"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"tron/internal/breakpoints"
+	"tron/internal/collab"
+	"tron/internal/command"
+	"tron/internal/dbconsole"
+	"tron/internal/docker"
 	"tron/internal/editor"
 	"tron/internal/filetree"
+	"tron/internal/git"
+	"tron/internal/httprunner"
+	"tron/internal/i18n"
+	"tron/internal/jobs"
+	"tron/internal/keyboard"
+	"tron/internal/preview"
+	"tron/internal/problems"
+	"tron/internal/pyenv"
+	"tron/internal/recent"
+	"tron/internal/remote"
+	"tron/internal/repl"
 	"tron/internal/runconfig"
+	"tron/internal/scaffold"
+	"tron/internal/suggest"
 	"tron/internal/tabs"
+	"tron/internal/termcaps"
 	"tron/internal/terminal"
 	"tron/pkg/layout"
+	"tron/pkg/pathutil"
 )
 
 type EditorPanel struct {
@@ -33,17 +54,38 @@ func (tp *TerminalPanel) Update(msg tea.Msg) tea.Cmd {
 }
 
 type headerPanel struct {
-	tabs   *tabs.TabBar
-	runBar *runconfig.RunBar
-	width  int
-	height int
+	tabs     *tabs.TabBar
+	runBar   *runconfig.RunBar
+	coverage *coverageState
+	remote   *remote.Remote
+	python   *pythonState
+	cargo    *cargoState
+	git      *gitState
+	follow   *followState
+	jobs     *jobs.Pool
+	goModule string
+	width    int
+	height   int
+
+	// statusX and branchWidth locate the clickable branch name drawn by
+	// the last View.
+	statusX     int
+	branchWidth int
 }
 
-func newHeaderPanel(rootPath string) *headerPanel {
+func newHeaderPanel(rootPath string, cov *coverageState, rem *remote.Remote, py *pythonState, cs *cargoState, gs *gitState, fs *followState, jp *jobs.Pool) *headerPanel {
 	return &headerPanel{
-		tabs:   tabs.New(),
-		runBar: runconfig.NewRunBar(rootPath),
-		height: 1,
+		tabs:     tabs.New(),
+		runBar:   runconfig.NewRunBar(rootPath),
+		coverage: cov,
+		remote:   rem,
+		python:   py,
+		cargo:    cs,
+		git:      gs,
+		follow:   fs,
+		jobs:     jp,
+		goModule: runconfig.GoModulePath(rootPath),
+		height:   1,
 	}
 }
 
@@ -52,6 +94,10 @@ func (h *headerPanel) Init() tea.Cmd {
 }
 
 func (h *headerPanel) Update(msg tea.Msg) tea.Cmd {
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		return h.handleMouse(mouse)
+	}
+
 	var cmds []tea.Cmd
 
 	if _, cmd := h.tabs.Update(msg); cmd != nil {
@@ -64,6 +110,20 @@ func (h *headerPanel) Update(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+func (h *headerPanel) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft && msg.X >= h.statusX && msg.X < h.statusX+h.branchWidth {
+		return func() tea.Msg { return openBranchPickerMsg{} }
+	}
+	runBarStart := h.width - lipgloss.Width(h.runBar.View())
+	if msg.X >= runBarStart {
+		msg.X -= runBarStart
+		_, cmd := h.runBar.Update(msg)
+		return cmd
+	}
+	_, cmd := h.tabs.Update(msg)
+	return cmd
+}
+
 func (h *headerPanel) View() string {
 	if h.width == 0 {
 		return ""
@@ -86,6 +146,7 @@ func (h *headerPanel) View() string {
 	}
 
 	headerStyle := lipgloss.NewStyle().Background(lipgloss.Color("#1e1e2e"))
+	h.statusX = lipgloss.Width(tabsView) + 1
 	spacer := h.width - lipgloss.Width(tabsView) - lipgloss.Width(runBarView)
 	if spacer < 0 {
 		spacer = 0
@@ -94,11 +155,62 @@ func (h *headerPanel) View() string {
 	return lipgloss.JoinHorizontal(
 		lipgloss.Top,
 		tabsView,
-		headerStyle.Render(makeSpacer(spacer)),
+		h.renderStatus(spacer, headerStyle),
 		runBarView,
 	)
 }
 
+func (h *headerPanel) renderStatus(width int, style lipgloss.Style) string {
+	branch := h.git.branchText()
+	status := branch
+	if text := h.tabs.StatusText(); text != "" {
+		if status != "" {
+			status += "  "
+		}
+		status += text
+	}
+	if active := h.tabs.GetActive(); active != nil {
+		for _, text := range []string{h.goStatusText(active.Path), h.python.statusText(active.Path), h.cargo.statusText(active.Path), h.coverage.statusText(active.Path), h.follow.statusText(active.Path)} {
+			if text == "" {
+				continue
+			}
+			if status != "" {
+				status += "  "
+			}
+			status += text
+		}
+	}
+	for _, text := range []string{h.git.statusText(), jobsStatusText(h.jobs)} {
+		if text == "" {
+			continue
+		}
+		if status != "" {
+			status += "  "
+		}
+		status += text
+	}
+	if h.remote != nil {
+		if status != "" {
+			status += "  "
+		}
+		status += h.remote.StatusText()
+	}
+	h.branchWidth = 0
+	if status == "" || width < 4 {
+		return style.Render(makeSpacer(width))
+	}
+
+	runes := []rune(status)
+	if len(runes) > width-2 {
+		runes = append([]rune("…"), runes[len(runes)-(width-3):]...)
+	} else {
+		h.branchWidth = lipgloss.Width(branch)
+	}
+	text := " " + string(runes) + " "
+	text += makeSpacer(width - lipgloss.Width(text))
+	return style.Foreground(lipgloss.Color("#6c7086")).Render(text)
+}
+
 func (h *headerPanel) SetSize(w, height int) {
 	h.width = w
 	h.height = height
@@ -117,100 +229,488 @@ func makeSpacer(n int) string {
 	return string(result)
 }
 
+const (
+	MinWidth  = 50
+	MinHeight = 12
+)
+
 type Model struct {
-	Width    int
-	Height   int
-	Root     layout.Panel
-	FileTree *filetree.FileTree
-	Tabs     *tabs.TabBar
-	RunBar   *runconfig.RunBar
-	header   *headerPanel
-	Terminal *TerminalPanel
-	Editor   *EditorPanel
+	Width         int
+	Height        int
+	RootPath      string
+	Root          layout.Panel
+	FileTree      *filetree.FileTree
+	Tabs          *tabs.TabBar
+	RunBar        *runconfig.RunBar
+	header        *headerPanel
+	Terminal      *TerminalPanel
+	Editor        *EditorPanel
+	Breakpoints   *breakpoints.Store
+	bpPanel       *breakpoints.Panel
+	lsp           *lspState
+	remote        *remote.Remote
+	coverage      *coverageState
+	replPanel     *repl.Panel
+	httpPanel     *httprunner.Panel
+	dbPanel       *dbconsole.Panel
+	dockerPanel   *docker.Panel
+	collab        *collabState
+	preview       *preview.Server
+	suggestions   suggest.Provider
+	scaffoldPanel *scaffold.Panel
+	python        *pythonState
+	cargo         *cargoState
+	problemsPanel *problems.Panel
+	goEnvPanel    *runconfig.GoEnvPanel
+	palette       *command.Palette
+	keymapPanel   *command.KeymapPanel
+	keymap        map[string]command.Invocation
+	macroDepth    int
+	commandErr    error
+	autosaveSeq   int
+	tabDocs       *tabDocuments
+	accessibility *accessibilitySettings
+	git           *gitState
+	follow        *followState
+	groups        *editorGroups
+	appearance    *appearanceSettings
+	frames        []titledFrame
+	splits        map[string]*layout.Split
+	floats        *floatingPanels
+	usage         *command.Usage
+	keyboard      keyboardState
+	editing       *editorSettings
+	jobs          *jobs.Pool
+	jumps         *jumpList
+	recent        *recent.List
+	recentPanel   *recent.Panel
+	openPrompt    *openPrompt
+	fileOps       *fileOps
+
+	sqlErrorsPath string
 }
 
+// New opens the workspace at the process's working directory.
 func New() Model {
-	ft := filetree.New(".")
+	return newAt(".")
+}
+
+// newAt opens the workspace rooted at rootPath.
+func newAt(rootPath string) Model {
+	i18n.SetLocale(i18n.Detect(rootPath))
+	caps := termcaps.Detect()
+	termcaps.Apply(caps)
+	ft := filetree.New(rootPath)
 	ed := &EditorPanel{editor.New()}
 	term := &TerminalPanel{terminal.New()}
-	header := newHeaderPanel(".")
+	cov := newCoverageState(rootPath)
+	rem := loadRemote(rootPath)
+	py := newPythonState(rootPath)
+	cs := newCargoState(rootPath)
+	gs := &gitState{panel: git.NewPanel(), log: git.NewLogPanel(), branches: git.NewBranchPanel()}
+	fs := &followState{}
+	jp := jobs.NewPool(jobs.DefaultWorkers)
+	header := newHeaderPanel(rootPath, cov, rem, py, cs, gs, fs, jp)
+
+	groups := newEditorGroups(rootPath, ed)
+	focus := &layout.FrameGroup{}
+	frames := []titledFrame{
+		{layout.NewFrame(ft, "", focus), "layout.explorer"},
+		{layout.NewFrame(groups, "", focus), "layout.editor"},
+		{layout.NewFrame(term, "", focus), "layout.terminal"},
+	}
+	focus.Focus(frames[1].Frame)
 
-	editorTerminalSplit := layout.NewVerticalSplit(ed, term, 0.7)
+	editorTerminalSplit := layout.NewVerticalSplit(frames[1], frames[2], 0.7)
 	editorTerminalSplit.SetMinSizes(5, 3)
 
-	mainSplit := layout.NewHorizontalSplit(ft, editorTerminalSplit, 0.2)
+	mainSplit := layout.NewHorizontalSplit(frames[0], editorTerminalSplit, 0.2)
 	mainSplit.SetMinSizes(15, 30)
 
 	rootSplit := layout.NewVerticalSplit(header, mainSplit, 0.05)
 	rootSplit.SetMinSizes(1, 5)
 
-	return Model{
-		Root:     rootSplit,
-		FileTree: ft,
-		Tabs:     header.tabs,
-		RunBar:   header.runBar,
-		header:   header,
-		Terminal: term,
-		Editor:   ed,
+	bpStore := breakpoints.NewStore(rootPath)
+
+	m := Model{
+		RootPath:      rootPath,
+		Root:          rootSplit,
+		FileTree:      ft,
+		Tabs:          header.tabs,
+		RunBar:        header.runBar,
+		header:        header,
+		Terminal:      term,
+		Editor:        ed,
+		Breakpoints:   bpStore,
+		bpPanel:       breakpoints.NewPanel(bpStore),
+		lsp:           newLSPState(rootPath, rem),
+		remote:        rem,
+		coverage:      cov,
+		replPanel:     repl.NewPanel(rootPath),
+		httpPanel:     httprunner.NewPanel(),
+		dbPanel:       dbconsole.NewPanel(rootPath),
+		dockerPanel:   docker.NewPanel(rootPath),
+		collab:        newCollabState(),
+		suggestions:   suggest.Load(),
+		scaffoldPanel: scaffold.NewPanel(),
+		python:        py,
+		cargo:         cs,
+		problemsPanel: problems.NewPanel(rootPath),
+		goEnvPanel:    runconfig.NewGoEnvPanel(),
+		palette:       command.NewPalette(),
+		keymapPanel:   command.NewKeymapPanel(),
+		keymap:        loadKeymap(),
+		tabDocs:       newTabDocuments(rootPath),
+		accessibility: loadAccessibility(rootPath),
+		git:           gs,
+		follow:        fs,
+		groups:        groups,
+		appearance:    loadAppearance(rootPath),
+		frames:        frames,
+		splits: map[string]*layout.Split{
+			"header":   rootSplit,
+			"sidebar":  mainSplit,
+			"terminal": editorTerminalSplit,
+		},
+		floats:      newFloatingPanels(),
+		usage:       command.LoadUsage(filepath.Join(rootPath, ".tron", "usage.json")),
+		editing:     loadEditorSettings(rootPath),
+		jobs:        jp,
+		jumps:       &jumpList{},
+		recent:      recent.NewList(rootPath),
+		recentPanel: recent.NewPanel(),
+		openPrompt:  &openPrompt{},
+		fileOps:     &fileOps{},
+	}
+	ed.Suggestions = m.suggestions != nil
+	m.groups.setKeymap(m.keymap)
+	m.applyAccessibility()
+	m.applyEditorSettings()
+	warnDegraded(term, caps)
+
+	if session, err := LoadSession(rootPath); err == nil {
+		m.restoreSession(session)
 	}
+	m.applyAppearance()
+
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return nil
+	cmds := []tea.Cmd{
+		m.FileTree.Load(),
+		m.RunBar.GetManager().Detect(),
+		m.coverage.load(),
+		m.python.detect(),
+		m.cargo.detect(),
+		m.checkRemote(),
+		openGitRepo(m.RootPath),
+		followTick(),
+		m.jobs.Listen(),
+		keyboard.Enable(),
+	}
+	if m.Editor.FilePath != "" {
+		cmds = append(cmds, m.lsp.openDocument(m.Editor.FilePath, m.Editor.Content()))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if decoded, ok := keyboard.Decode(msg); ok {
+		return m.Update(decoded)
+	}
 	switch msg := msg.(type) {
+	case keyboard.ExtendedKeyMsg:
+		return m.handleExtendedKey(msg)
+	case keyboard.ReportMsg:
+		m.handleKeyboardReport(msg)
+		return m, nil
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
-			return m, tea.Quit
+		if m.Tabs.CapturesInput() {
+			_, cmd := m.Tabs.Update(msg)
+			return m, cmd
+		}
+		if m.FileTree.CapturesInput() {
+			return m, m.FileTree.Update(msg)
+		}
+		if m.floats.stack.Focused() != nil {
+			if msg.String() == "esc" {
+				m.floats.stack.Blur()
+				return m, nil
+			}
+			return m, m.floats.stack.Update(msg)
+		}
+		if m.Editor.CapturesKey(msg) {
+			return m, m.Editor.Update(msg)
+		}
+		for _, panel := range m.overlays() {
+			if panel.Visible() {
+				return m, panel.Update(msg)
+			}
+		}
+		if inv, ok := m.keymap[msg.String()]; ok {
+			return m, m.execute(inv)
 		}
+		if m.frames[0].Focused() {
+			if cmd, ok := m.FileTree.HandleFileKey(msg); ok {
+				return m, cmd
+			}
+		}
+	case tea.MouseMsg:
+		if cmd, ok := m.floats.stack.HandleMouse(msg); ok {
+			return m, cmd
+		}
+	case tea.ResumeMsg:
+		m.Root.SetSize(m.Width, m.Height)
+		return m, tea.Batch(tea.ClearScreen, keyboard.Enable(), m.Root.Update(msg))
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
+		m.Root.SetSize(msg.Width, msg.Height)
+		m.floats.stack.SetSize(msg.Width, msg.Height)
+		m.httpPanel.SetSize(msg.Width*4/5, msg.Height*4/5)
+		m.dbPanel.SetSize(msg.Width*4/5, msg.Height*4/5)
+		m.git.log.SetSize(msg.Width*4/5, msg.Height*4/5)
+		return m, nil
 	case filetree.FileSelectedMsg:
 		if !msg.IsDir {
 			return m, m.openFile(msg.Path)
 		}
 	case tabs.TabSwitchedMsg:
+		m.recordJump()
 		m.Tabs.SetActive(msg.Index)
 		return m, m.switchToTab(msg.Index)
 	case tabs.TabClosedMsg:
 		m.Tabs.CloseTab(msg.Index)
+		m.tabDocs.forget(msg.FilePath)
+		m.jobs.Cancel(msg.FilePath)
+	case filetree.DeleteMsg:
+		return m, m.trashFile(msg.Path)
+	case filetree.RenameMsg:
+		return m, m.renameFile(msg.From, msg.To)
+	case tabs.TabRevealMsg:
+		m.FileTree.Reveal(msg.FilePath)
+		return m, nil
+	case tabs.TabSplitMsg:
+		return m, m.openInSplit(msg.Index)
 	case tabs.NewTabMsg:
+	case layout.RatioChangedMsg:
+		_ = SaveSession(m.RootPath, m.captureSession())
+		return m, nil
 	case runconfig.RunCommandMsg:
 		return m, m.handleRunCommand(msg)
 	case editor.EditorSavedMsg:
 		m.Tabs.MarkDirty(m.Tabs.FindTab(msg.Path), false)
-	}
-
-	var cmd tea.Cmd
-	if cmd = m.Root.Update(msg); cmd != nil {
+		m.reloadPreview()
+		return m, m.loadGitHunks()
+	case gitRepoMsg:
+		m.git.repo = msg.repo
+		return m, tea.Batch(m.loadGitHunks(), m.loadBranchStatus(), gitStatusTick())
+	case gitStatusMsg:
+		m.git.branch, m.git.branchOK = msg.status, msg.ok
+	case gitStatusTickMsg:
+		return m, tea.Batch(m.loadBranchStatus(), m.loadGitHunks(), gitStatusTick())
+	case openBranchPickerMsg:
+		return m, m.openBranchPicker()
+	case gitBranchesMsg:
+		m.git.branches.Open(msg.branches)
+	case git.BranchMsg:
+		return m, m.runBranchAction(msg)
+	case recent.OpenMsg:
+		return m, m.openFile(msg.Path)
+	case openChoiceMsg:
+		return m, m.handleOpenChoice(msg)
+	case gitBranchDoneMsg:
+		return m, m.handleBranchDone(msg)
+	case groupFocusMsg:
+		return m, m.focusGroup(msg.index)
+	case groupSplitMsg:
+		return m, m.splitGroup(msg.index)
+	case groupCloseMsg:
+		return m, m.closeGroup(msg.index)
+	case groupMoveMsg:
+		return m, m.moveToGroup(msg.from, msg.to)
+	case gitHunksMsg:
+		m.handleGitHunks(msg)
+	case gitActionDoneMsg:
+		return m, m.handleGitActionDone(msg)
+	case gitStashesMsg:
+		m.git.panel.SetStashes(msg.stashes)
+	case git.StashMsg:
+		return m, m.runStashAction(msg)
+	case gitStashDoneMsg:
+		return m, m.handleStashDone(msg)
+	case git.RemoteMsg:
+		return m, m.runRemote(msg.Op)
+	case git.OpenRevisionMsg:
+		return m, m.openRevision(msg)
+	case jobs.ProgressMsg:
+		return m, m.jobs.Listen()
+	case compileDBMsg:
+		return m, m.handleCompileDB(msg)
+	case captureDoneMsg:
+		return m, m.handleCaptureDone(msg)
+	case outputDoneMsg:
+		return m, m.openVirtual(outputScheme+msg.command, "", msg.output)
+	case git.LogMsg, git.RevisionMsg:
+		return m, m.git.log.Update(msg)
+	case gitProgressMsg, gitPromptMsg, gitRemoteDoneMsg:
+		return m, m.handleGitRemoteEvent(msg)
+	case editor.EditorSaveFailedMsg:
+		m.reportCommandError(errors.New(i18n.T("editor.saveFailed", msg.Path, msg.Err)))
+	case editor.ConflictMergeMsg:
+		return m, m.openMerge(msg)
+	case editor.EditorChangedMsg:
+		m.syncEditorDirtyState()
+		m.refreshHTTPMarkers()
+		m.refreshMergeMarkers()
+		m.diffBuffer()
+		autosave := m.scheduleAutosave()
+		if msg.Path != "" && msg.Path == m.Editor.FilePath {
+			change := m.lsp.changeDocument(msg.Path, m.Editor.Content(), m.Editor.TakeChanges())
+			if req, ok := m.Editor.TakeCompletionRequest(); ok {
+				change = tea.Sequence(change, m.lsp.complete(req))
+			}
+			return m, tea.Batch(autosave, change)
+		}
+		return m, autosave
+	case autosaveMsg:
+		return m, m.handleAutosave(msg)
+	case keyboard.FocusMsg:
+		if !msg.Focused && m.editing.AutosaveOnFocusLoss {
+			return m, m.autosave()
+		}
+		return m, nil
+	case editor.ToggleBreakpointMsg:
+		m.Breakpoints.Toggle(msg.Path, msg.Line)
+		_ = m.Breakpoints.Save()
+		m.refreshBreakpointMarkers()
+		return m, nil
+	case breakpoints.ChangedMsg:
+		_ = m.Breakpoints.Save()
+		m.refreshBreakpointMarkers()
+		return m, nil
+	case breakpoints.JumpMsg:
+		cmd := m.openFile(msg.Path)
+		m.Editor.GoToLine(msg.Line)
+		return m, cmd
+	case problems.JumpMsg:
+		cmd := m.openFile(msg.Path)
+		m.Editor.GoTo(editor.Position{Line: msg.Line, Column: msg.Column})
 		return m, cmd
+	case editor.HoverRequestMsg:
+		if text, ok := m.problemHover(msg); ok {
+			return m, func() tea.Msg { return editor.HoverResultMsg{Seq: msg.Seq, Text: text} }
+		}
+		return m, m.lsp.hover(msg)
+	case editor.SuggestionRequestMsg:
+		return m, m.requestSuggestion(msg)
+	case lspStartedMsg:
+		m.lsp.handleStarted(msg)
+		return m, nil
+	case docker.ContainersMsg, docker.ActionDoneMsg:
+		return m, m.dockerPanel.Update(msg)
+	case dbconsole.ResultsMsg:
+		cmd := m.dbPanel.Update(msg)
+		m.sqlErrorsPath = msg.Path
+		m.refreshSQLMarkers()
+		return m, cmd
+	case httprunner.ResponseMsg:
+		return m, m.httpPanel.Update(msg)
+	case repl.EvalResultMsg:
+		return m, m.replPanel.Update(msg)
+	case command.RunMsg:
+		return m, m.execute(msg.Invocation)
+	case command.KeymapChangeMsg:
+		if err := m.saveKeybindings(msg.Bindings); err != nil {
+			m.reportCommandError(err)
+		}
+		return m, nil
+	case runconfig.GoEnvMsg:
+		m.setGoEnv(msg.Env)
+		return m, nil
+	case pyenv.SelectedMsg:
+		return m, m.selectPythonEnv(msg.Env)
+	case openWorkspaceMsg:
+		return m.openWorkspace(msg.dir, "")
+	case scaffold.CreateMsg:
+		return m.createProject(msg)
+	case collab.HostMsg:
+		return m, m.hostCollab(msg.Addr)
+	case collab.JoinMsg:
+		return m, m.joinCollab(msg.Addr)
+	case collab.LeaveMsg:
+		m.leaveCollab()
+		return m, nil
+	case collab.EventMsg:
+		return m, m.handleCollabEvent(msg)
+	case collab.DisconnectedMsg:
+		m.handleCollabDisconnected(msg)
+		return m, nil
+	case remoteCheckMsg:
+		return m, m.handleRemoteCheck(msg)
+	case remoteTickMsg:
+		return m, m.checkRemote()
+	case coverageLoadedMsg:
+		m.handleCoverageLoaded(msg)
+		return m, nil
+	case pythonDetectedMsg:
+		m.handlePythonDetected(msg)
+		return m, nil
+	case cargoToolchainMsg:
+		m.cargo.toolchain = msg.toolchain
+		return m, nil
+	case cargoCheckedMsg:
+		m.handleCargoChecked(msg)
+		return m, nil
+	case coveragePollMsg:
+		return m, m.handleCoveragePoll()
+	case followTickMsg:
+		return m, m.handleFollowTick()
 	}
 
-	m.syncEditorDirtyState()
-
-	return m, nil
+	cmd := tea.Batch(m.Root.Update(msg), m.floats.stack.Update(msg))
+	m.syncCollabCursor()
+	m.follow.update(m.Editor)
+	return m, cmd
 }
 
+// openFile shows path, recording a jump from the cursor's location.
 func (m *Model) openFile(path string) tea.Cmd {
+	m.recordJump()
+	return m.showFile(path)
+}
+
+// showFile switches to path's tab, opening one when it has none.
+func (m *Model) showFile(path string) tea.Cmd {
+	m.leaveCollabForPath(pathutil.Canonical(path))
 	idx := m.Tabs.FindTab(path)
 	if idx >= 0 {
 		m.Tabs.SetActive(idx)
 		return m.switchToTab(idx)
 	}
+	if !pathutil.IsVirtual(path) {
+		if kind, size, err := editor.InspectFile(path); err == nil && kind != editor.TextFile {
+			return m.promptOpen(pathutil.Canonical(path), kind, size)
+		}
+	}
+	return m.openTab(path)
+}
 
+// openTab opens path in a new tab without looking at its content first.
+func (m *Model) openTab(path string) tea.Cmd {
+	stash := m.stashActiveDocument()
 	m.Tabs.AddTab(path)
 	m.Tabs.SetActive(m.Tabs.TabCount() - 1)
 
-	if err := m.Editor.LoadFile(path); err != nil {
-		m.Editor.SetContent("")
-		m.Editor.FilePath = path
+	err := m.loadDocument(path)
+	m.refreshGutter()
+	if err != nil {
+		return stash
 	}
+	_ = m.recent.Add(path)
 
-	return nil
+	return tea.Batch(stash, m.lsp.openDocument(m.Editor.FilePath, m.Editor.Content()), m.loadGitHunks())
 }
 
 func (m *Model) switchToTab(index int) tea.Cmd {
@@ -218,12 +718,20 @@ func (m *Model) switchToTab(index int) tea.Cmd {
 	if tab == nil {
 		return nil
 	}
+	_ = m.recent.Add(tab.Path)
 
+	if group := m.groups.showing(tab.Path); group >= 0 {
+		return m.focusGroup(group)
+	}
 	if m.Editor.FilePath != tab.Path {
-		if err := m.Editor.LoadFile(tab.Path); err != nil {
-			m.Editor.SetContent("")
-			m.Editor.FilePath = tab.Path
+		m.leaveCollabForPath(tab.Path)
+		stash := m.stashActiveDocument()
+		err := m.loadDocument(tab.Path)
+		m.refreshGutter()
+		if err != nil {
+			return stash
 		}
+		return tea.Batch(stash, m.lsp.openDocument(m.Editor.FilePath, m.Editor.Content()), m.loadGitHunks())
 	}
 
 	return nil
@@ -239,26 +747,76 @@ func (m *Model) syncEditorDirtyState() {
 	}
 }
 
+func (m *Model) refreshGutter() {
+	m.refreshBreakpointMarkers()
+	m.refreshCoverageMarkers()
+	m.refreshHTTPMarkers()
+	m.refreshSQLMarkers()
+	m.refreshMergeMarkers()
+	m.refreshGitMarkers()
+	m.refreshProblemMarkers()
+}
+
 func (m Model) handleRunCommand(msg runconfig.RunCommandMsg) tea.Cmd {
 	if msg.Config == nil {
 		return nil
 	}
-	cmdParts := []string{msg.Config.Command}
-	cmdParts = append(cmdParts, msg.Config.Args...)
-	cmdStr := strings.Join(cmdParts, " ")
+	cmdStr := msg.Config.CommandLine()
 
 	cwd := msg.Config.WorkingDir
 	if cwd == "" {
 		cwd = "."
 	}
 
-	m.Terminal.RunCommand(cmdStr, cwd)
+	m.runShell(cmdStr, cwd)
 	return nil
 }
 
+func (m Model) runTestUnderCursor(debug bool) tea.Cmd {
+	if m.Editor.Buffer == nil || m.Editor.FilePath == "" {
+		return nil
+	}
+	target, ok := runconfig.TestAtCursor(m.RootPath, m.Editor.FilePath, m.Editor.Buffer.Lines(), m.Editor.Cursor.Line)
+	if !ok {
+		return nil
+	}
+	if m.python.ok {
+		target.Python = m.python.env.Interpreter
+	}
+	return m.handleRunCommand(runconfig.RunCommandMsg{Config: target.RunConfig(m.RootPath, debug)})
+}
+
+type overlayPanel interface {
+	Visible() bool
+	Update(msg tea.Msg) tea.Cmd
+	View() string
+}
+
+func (m Model) overlays() []overlayPanel {
+	return []overlayPanel{m.bpPanel, m.problemsPanel, m.replPanel, m.httpPanel, m.dbPanel, m.dockerPanel, m.collab.panel, m.scaffoldPanel, m.python.panel, m.goEnvPanel, m.git.panel, m.git.log, m.git.branches, m.keymapPanel, m.recentPanel, m.openPrompt, m.palette}
+}
+
 func (m Model) View() string {
 	if m.Width == 0 || m.Height == 0 {
 		return ""
 	}
-	return m.Root.View()
+	if m.Width < MinWidth || m.Height < MinHeight {
+		return m.tooSmallView()
+	}
+	view := m.floats.stack.View(m.Root.View())
+	if switcher := m.Tabs.SwitcherView(); switcher != "" {
+		view = layout.OverlayCenter(view, switcher, m.Width, m.Height)
+	}
+	for _, panel := range m.overlays() {
+		if v := panel.View(); v != "" {
+			view = layout.OverlayCenter(view, v, m.Width, m.Height)
+		}
+	}
+	return view
+}
+
+func (m Model) tooSmallView() string {
+	msg := i18n.T("app.tooSmall", MinWidth, MinHeight, m.Width, m.Height)
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, style.Width(m.Width).Align(lipgloss.Center).Render(msg))
 }
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tron/internal/filetree"
+	"tron/internal/i18n"
+	"tron/pkg/layout"
+)
+
+type appearanceSettings struct {
+	// Borders surrounds the file tree, editor and terminal with titled
+	// borders instead of only the split dividers.
+	Borders bool `json:"borders"`
+	// Splits overrides the minimum sizes of the named splits: "header",
+	// "sidebar" and "terminal".
+	Splits map[string]splitMinimums `json:"splits,omitempty"`
+	// IconTheme is one of filetree.IconThemes; empty picks Nerd Font icons
+	// when a Nerd Font is installed.
+	IconTheme string `json:"iconTheme,omitempty"`
+}
+
+// splitMinimums are in cells; zero keeps the built-in minimum.
+type splitMinimums struct {
+	First  int `json:"first,omitempty"`
+	Second int `json:"second,omitempty"`
+}
+
+func appearancePath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "appearance.json")
+}
+
+func loadAppearance(rootPath string) *appearanceSettings {
+	s := &appearanceSettings{}
+	if data, err := os.ReadFile(appearancePath(rootPath)); err == nil {
+		_ = json.Unmarshal(data, s)
+	}
+	return s
+}
+
+func (s *appearanceSettings) save(rootPath string) error {
+	path := appearancePath(rootPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// titledFrame is a panel frame whose title is looked up again whenever the
+// display language changes.
+type titledFrame struct {
+	*layout.Frame
+	key string
+}
+
+func (m *Model) applyAppearance() {
+	for _, f := range m.frames {
+		f.Title = i18n.T(f.key)
+		f.SetBorder(m.appearance.Borders)
+	}
+	m.floats.applyTitles()
+	m.FileTree.IconTheme, _ = filetree.ParseIconTheme(m.appearance.IconTheme)
+	for name, mins := range m.appearance.Splits {
+		split, ok := m.splits[name]
+		if !ok {
+			continue
+		}
+		state := split.State()
+		if mins.First > 0 {
+			state.MinFirst = mins.First
+		}
+		if mins.Second > 0 {
+			state.MinSecond = mins.Second
+		}
+		split.SetMinSizes(state.MinFirst, state.MinSecond)
+	}
+}
+
+func (m *Model) toggleBorders() {
+	m.appearance.Borders = !m.appearance.Borders
+	m.applyAppearance()
+	if err := m.appearance.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
+
+func (m *Model) setIconTheme(name string) {
+	theme, ok := filetree.ParseIconTheme(name)
+	if !ok {
+		names := make([]string, len(filetree.IconThemes))
+		for i, t := range filetree.IconThemes {
+			names[i] = string(t)
+		}
+		m.reportCommandError(errors.New(i18n.T("command.unknownIconTheme", name, strings.Join(names, ", "))))
+		return
+	}
+	m.appearance.IconTheme = string(theme)
+	m.applyAppearance()
+	if err := m.appearance.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
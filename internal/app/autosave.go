@@ -0,0 +1,49 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// autosaveMsg asks for an autosave unless an edit since has restarted the
+// wait; see scheduleAutosave.
+type autosaveMsg struct {
+	seq int
+}
+
+// scheduleAutosave restarts the wait after which the editors' unsaved
+// changes are written, when autosave is on.
+func (m *Model) scheduleAutosave() tea.Cmd {
+	if m.editing.AutosaveDelay <= 0 {
+		return nil
+	}
+	m.autosaveSeq++
+	seq := m.autosaveSeq
+	return tea.Tick(time.Duration(m.editing.AutosaveDelay)*time.Second, func(time.Time) tea.Msg {
+		return autosaveMsg{seq: seq}
+	})
+}
+
+func (m *Model) handleAutosave(msg autosaveMsg) tea.Cmd {
+	if msg.seq != m.autosaveSeq || m.editing.AutosaveDelay <= 0 {
+		return nil
+	}
+	return m.autosave()
+}
+
+// autosave writes the unsaved changes of every editor group.
+func (m *Model) autosave() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, ed := range m.groups.editors() {
+		cmds = append(cmds, ed.AutoSave())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) setAutosaveDelay(seconds int) {
+	m.editing.AutosaveDelay = max(seconds, 0)
+	if err := m.editing.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
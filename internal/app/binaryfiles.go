@@ -0,0 +1,181 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/editor"
+	"tron/internal/i18n"
+)
+
+// binaryViewLimit is how much of a binary or large file the read-only and
+// hex views show.
+const binaryViewLimit = 1 << 20
+
+type openChoice int
+
+const (
+	openAnyway openChoice = iota
+	openHex
+	openCancel
+)
+
+type openChoiceMsg struct {
+	path   string
+	kind   editor.FileKind
+	choice openChoice
+}
+
+// openPrompt asks what to do with a binary or very large file before it
+// is opened, instead of loading it into the editor as text.
+type openPrompt struct {
+	visible  bool
+	path     string
+	kind     editor.FileKind
+	size     int64
+	selected int
+}
+
+func (p *openPrompt) Visible() bool {
+	return p.visible
+}
+
+func (p *openPrompt) Open(path string, kind editor.FileKind, size int64) {
+	p.visible = true
+	p.path, p.kind, p.size = path, kind, size
+	p.selected = 0
+}
+
+func (p *openPrompt) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		p.visible = false
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < int(openCancel) {
+			p.selected++
+		}
+	case "enter":
+		return p.choose(openChoice(p.selected))
+	case "o":
+		return p.choose(openAnyway)
+	case "h":
+		return p.choose(openHex)
+	}
+	return nil
+}
+
+func (p *openPrompt) choose(choice openChoice) tea.Cmd {
+	p.visible = false
+	if choice == openCancel {
+		return nil
+	}
+	path, kind := p.path, p.kind
+	return func() tea.Msg { return openChoiceMsg{path: path, kind: kind, choice: choice} }
+}
+
+func (p *openPrompt) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+
+	question := "openPrompt.large"
+	if p.kind == editor.BinaryFile {
+		question = "openPrompt.binary"
+	}
+	lines := []string{
+		titleStyle.Render(filepath.Base(p.path)),
+		textStyle.Render(i18n.T(question, formatSize(p.size))),
+		"",
+	}
+	for i, key := range []string{"openPrompt.anyway", "openPrompt.hex", "openPrompt.cancel"} {
+		if i == p.selected {
+			lines = append(lines, selectedStyle.Render(i18n.T(key)))
+		} else {
+			lines = append(lines, textStyle.Render(i18n.T(key)))
+		}
+	}
+	lines = append(lines, "", hintStyle.Render(i18n.T("openPrompt.hint")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#f9e2af")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(60).
+		Render(strings.Join(lines, "\n"))
+}
+
+// formatSize renders a byte count with a binary unit.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// promptOpen asks how to open a binary or large file, unless one of its
+// views is already open in a tab.
+func (m *Model) promptOpen(path string, kind editor.FileKind, size int64) tea.Cmd {
+	for _, scheme := range []string{binaryScheme, hexScheme} {
+		if idx := m.Tabs.FindTab(scheme + path); idx >= 0 {
+			m.Tabs.SetActive(idx)
+			return m.switchToTab(idx)
+		}
+	}
+	m.openPrompt.Open(path, kind, size)
+	return nil
+}
+
+// handleOpenChoice opens a file the open prompt asked about. A large text
+// file is opened as usual but read-only; binary content is only ever shown
+// through a virtual document, so it cannot be saved back mangled.
+func (m *Model) handleOpenChoice(msg openChoiceMsg) tea.Cmd {
+	switch {
+	case msg.choice == openHex:
+		return m.openBinaryView(hexScheme, msg.path, editor.HexDump)
+	case msg.kind == editor.LargeFile:
+		cmd := m.openTab(msg.path)
+		if m.Editor.FilePath == msg.path {
+			m.Editor.ReadOnly = true
+		}
+		return cmd
+	}
+	return m.openBinaryView(binaryScheme, msg.path, editor.Printable)
+}
+
+func (m *Model) openBinaryView(scheme, path string, render func([]byte) string) tea.Cmd {
+	data, more, err := editor.ReadPrefix(path, binaryViewLimit)
+	if err != nil {
+		m.reportCommandError(err)
+		return nil
+	}
+	text := render(data)
+	if more {
+		text = strings.TrimRight(text, "\n") + "\n\n" + i18n.T("openPrompt.truncated", formatSize(binaryViewLimit))
+	}
+	return m.openVirtual(scheme+path, "", text)
+}
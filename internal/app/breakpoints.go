@@ -0,0 +1,18 @@
+package app
+
+import (
+	"tron/internal/editor"
+	"tron/internal/termcaps"
+)
+
+func (m *Model) refreshBreakpointMarkers() {
+	markers := make(map[int]editor.GutterMarker)
+	for _, bp := range m.Breakpoints.ForFile(m.Editor.FilePath) {
+		marker := editor.GutterMarker{Symbol: termcaps.Symbol("●", "*"), Color: "#f38ba8"}
+		if !bp.Enabled {
+			marker = editor.GutterMarker{Symbol: termcaps.Symbol("○", "o"), Color: "#6c7086"}
+		}
+		markers[bp.Line] = marker
+	}
+	m.Editor.SetGutterMarkers("breakpoints", markers)
+}
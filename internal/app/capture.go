@@ -0,0 +1,136 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+	"tron/internal/jobs"
+	"tron/internal/runconfig"
+	"tron/pkg/pathutil"
+)
+
+// captureJob prefixes the owner of a capture run, one per configuration.
+const captureJob = "capture:"
+
+type captureDoneMsg struct {
+	name   string
+	stdout []byte
+	stderr string
+	err    error
+}
+
+func scratchDir(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "scratch")
+}
+
+// runCapture runs config like the run bar does, but puts what it prints on
+// stdout in a new scratch file instead of the terminal, so it can be
+// edited and searched. Its stderr still goes to the terminal.
+func (m *Model) runCapture(config *runconfig.RunConfig) tea.Cmd {
+	if config == nil {
+		return nil
+	}
+	cwd := config.WorkingDir
+	if cwd == "" {
+		cwd = "."
+	}
+	shell, script, cwd := m.shellSession(config.CommandLine(), cwd)
+	name := config.Name
+	owner := captureJob + name
+	m.jobs.Cancel(owner)
+	return m.jobs.Submit(jobs.Job{Owner: owner, Title: name, Priority: jobs.Normal, Run: func(ctx context.Context, _ func(int, int)) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, outputTimeout)
+		defer cancel()
+		c := exec.CommandContext(ctx, shell, "-c", script)
+		c.Dir = cwd
+		// Children of the killed shell may hold its output open.
+		c.WaitDelay = time.Second
+		var stderr bytes.Buffer
+		c.Stderr = &stderr
+		out, err := c.Output()
+		return captureDoneMsg{name: name, stdout: out, stderr: stderr.String(), err: err}
+	}})
+}
+
+func (m *Model) handleCaptureDone(msg captureDoneMsg) tea.Cmd {
+	for _, line := range strings.Split(strings.TrimRight(msg.stderr, "\n"), "\n") {
+		if line != "" {
+			m.Terminal.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render(line))
+		}
+	}
+	if msg.err != nil {
+		m.reportCommandError(fmt.Errorf("%s: %s", msg.name, i18n.T("output.failed", msg.err)))
+	}
+	path, err := writeScratch(m.RootPath, msg.name, msg.stdout)
+	if err != nil {
+		m.reportCommandError(err)
+		return nil
+	}
+	m.Terminal.Println(i18n.T("capture.saved", m.relPath(path)))
+	return m.openFile(path)
+}
+
+// writeScratch saves output in a new file under .tron/scratch named after
+// the configuration, with the extension of the format output is in.
+func writeScratch(rootPath, name string, output []byte) (string, error) {
+	dir := scratchDir(rootPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, scratchName(name)+"-*"+outputExt(output))
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(output); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	path, err := filepath.Abs(f.Name())
+	return pathutil.Canonical(path), err
+}
+
+// scratchName makes name safe to use in a file name.
+func scratchName(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '-'
+	}, name)
+	if slug = strings.Trim(slug, "-"); slug == "" {
+		return "output"
+	}
+	return slug
+}
+
+// outputExt guesses the format of output from its content, so the scratch
+// file gets the matching highlighting.
+func outputExt(output []byte) string {
+	text := bytes.TrimSpace(output)
+	switch {
+	case len(text) > 0 && (text[0] == '{' || text[0] == '[') && json.Valid(text):
+		return ".json"
+	case bytes.HasPrefix(text, []byte("diff ")) || bytes.HasPrefix(text, []byte("--- ")) && bytes.Contains(text, []byte("\n+++ ")):
+		return ".diff"
+	case bytes.HasPrefix(text, []byte("<?xml")):
+		return ".xml"
+	case bytes.HasPrefix(bytes.ToLower(text), []byte("<!doctype html")) || bytes.HasPrefix(bytes.ToLower(text), []byte("<html")):
+		return ".html"
+	}
+	return ".txt"
+}
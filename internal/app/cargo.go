@@ -0,0 +1,141 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/cargo"
+	"tron/internal/editor"
+	"tron/internal/i18n"
+	"tron/internal/jobs"
+	"tron/internal/problems"
+)
+
+// cargoCheckJob owns the running cargo check, so a new one replaces it.
+const cargoCheckJob = "cargo:check"
+
+type cargoState struct {
+	rootPath  string
+	toolchain string
+}
+
+type cargoToolchainMsg struct {
+	toolchain string
+}
+
+type cargoCheckedMsg struct {
+	problems []problems.Problem
+	stderr   string
+	err      error
+}
+
+func newCargoState(rootPath string) *cargoState {
+	return &cargoState{rootPath: rootPath}
+}
+
+// detect looks up the active toolchain in the background, since that
+// shells out to rustup.
+func (c *cargoState) detect() tea.Cmd {
+	if !cargo.HasManifest(c.rootPath) {
+		return nil
+	}
+	rootPath := c.rootPath
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		toolchain, err := cargo.Toolchain(ctx, rootPath)
+		if err != nil {
+			return nil
+		}
+		return cargoToolchainMsg{toolchain: toolchain}
+	}
+}
+
+func (c *cargoState) statusText(path string) string {
+	if c.toolchain == "" || (filepath.Ext(path) != ".rs" && filepath.Base(path) != "Cargo.toml") {
+		return ""
+	}
+	return "rust " + c.toolchain
+}
+
+// runCargoCheck checks the project in the background and lists what the
+// compiler reports in the problems panel.
+func (m *Model) runCargoCheck(clippy bool) tea.Cmd {
+	if !cargo.HasManifest(m.RootPath) {
+		m.reportCommandError(fmt.Errorf("%s", i18n.T("cargo.noManifest")))
+		return nil
+	}
+	shell, script, cwd := m.shellSession(cargo.CheckCommand(clippy), m.RootPath)
+	rootPath := m.RootPath
+	m.jobs.Cancel(cargoCheckJob)
+	return m.jobs.Submit(jobs.Job{Owner: cargoCheckJob, Title: "cargo", Priority: jobs.Normal, Run: func(ctx context.Context, _ func(int, int)) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, outputTimeout)
+		defer cancel()
+		c := exec.CommandContext(ctx, shell, "-c", script)
+		c.Dir = cwd
+		c.WaitDelay = time.Second
+		var stderr bytes.Buffer
+		c.Stderr = &stderr
+		out, err := c.Output()
+		return cargoCheckedMsg{problems: cargo.ParseDiagnostics(out, rootPath), stderr: stderr.String(), err: err}
+	}})
+}
+
+func (m *Model) handleCargoChecked(msg cargoCheckedMsg) {
+	// cargo fails whenever the code has errors; only a failure without
+	// any diagnostics means it could not check the project.
+	if msg.err != nil && len(msg.problems) == 0 {
+		if stderr := strings.TrimSpace(msg.stderr); stderr != "" {
+			m.Terminal.Println(stderr)
+		}
+		m.reportCommandError(fmt.Errorf("cargo: %s", i18n.T("output.failed", msg.err)))
+		return
+	}
+	m.problemsPanel.SetProblems(msg.problems)
+	m.refreshProblemMarkers()
+	errors, warnings := problems.Count(msg.problems)
+	m.Terminal.Println(i18n.T("cargo.checked", errors, warnings))
+	if len(msg.problems) > 0 {
+		m.problemsPanel.Open()
+	}
+}
+
+func (m *Model) refreshProblemMarkers() {
+	markers := make(map[int]editor.GutterMarker)
+	// Problems are sorted by severity, so the worst one on a line wins.
+	for _, p := range problems.ForFile(m.problemsPanel.Problems(), m.Editor.FilePath) {
+		if _, ok := markers[p.Line]; ok {
+			continue
+		}
+		switch p.Severity {
+		case problems.Error:
+			markers[p.Line] = editor.GutterMarker{Symbol: "✖", Color: "#f38ba8"}
+		case problems.Warning:
+			markers[p.Line] = editor.GutterMarker{Symbol: "▲", Color: "#f9e2af"}
+		default:
+			markers[p.Line] = editor.GutterMarker{Symbol: "●", Color: "#89b4fa"}
+		}
+	}
+	m.Editor.SetGutterMarkers("problems", markers)
+}
+
+// problemHover returns the message of a listed problem at the hovered
+// position, if there is one.
+func (m *Model) problemHover(msg editor.HoverRequestMsg) (string, bool) {
+	pos := msg.Position
+	for _, p := range problems.ForFile(m.problemsPanel.Problems(), msg.Path) {
+		after := pos.Line > p.Line || pos.Line == p.Line && pos.Column >= p.Column
+		before := pos.Line < p.EndLine || pos.Line == p.EndLine && pos.Column < p.EndColumn
+		if after && before {
+			return p.Message, true
+		}
+	}
+	return "", false
+}
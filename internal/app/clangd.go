@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/i18n"
+	"tron/internal/runconfig"
+)
+
+const compileDBTimeout = 2 * time.Minute
+
+// compileDBMsg reports that CMake is done writing compile_commands.json,
+// so clangd can start with it.
+type compileDBMsg struct {
+	languageID string
+	err        error
+}
+
+// clangdArgs points clangd at the project's compilation database, which
+// CMake projects keep in their build directory.
+func clangdArgs(args []string, rootPath string) []string {
+	if dir := runconfig.CompileCommandsDir(rootPath); dir != "" {
+		return append(append([]string{}, args...), "--compile-commands-dir="+dir)
+	}
+	return args
+}
+
+// generateCompileDB has CMake write the compilation database before
+// clangd starts, for a CMake project that has none. It is tried once a
+// session, and not at all without CMake or on a remote workspace.
+func (l *lspState) generateCompileDB(languageID string) tea.Cmd {
+	if l.remote != nil || l.compileDBTried || !runconfig.CanGenerateCompileCommands(l.rootPath) {
+		return nil
+	}
+	argv := runconfig.CMakeConfigureArgs()
+	if _, err := exec.LookPath(argv[0]); err != nil {
+		return nil
+	}
+	l.compileDBTried = true
+	l.starting[languageID] = true
+	rootPath := l.rootPath
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), compileDBTimeout)
+		defer cancel()
+		c := exec.CommandContext(ctx, argv[0], argv[1:]...)
+		c.Dir = rootPath
+		if out, err := c.CombinedOutput(); err != nil {
+			return compileDBMsg{languageID: languageID, err: fmt.Errorf("%s: %w\n%s", strings.Join(argv, " "), err, strings.TrimSpace(string(out)))}
+		}
+		return compileDBMsg{languageID: languageID}
+	}
+}
+
+// handleCompileDB starts the language server that waited for the
+// compilation database, with or without one.
+func (m *Model) handleCompileDB(msg compileDBMsg) tea.Cmd {
+	delete(m.lsp.starting, msg.languageID)
+	if msg.err != nil {
+		m.reportCommandError(msg.err)
+	} else {
+		m.Terminal.Println(i18n.T("clangd.compileDB", runconfig.CMakeBuildDir))
+		m.FileTree.Refresh()
+	}
+	return m.lsp.openDocument(m.Editor.FilePath, m.Editor.Content())
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/editor"
+	"tron/internal/i18n"
+	"tron/pkg/pathutil"
+)
+
+// openFromClipboard opens the first file location in the clipboard, or in
+// the primary selection when the clipboard names none, such as a line of
+// a stack trace. Relative paths are taken from the workspace root.
+func (m *Model) openFromClipboard() tea.Cmd {
+	loc, ok := m.clipboardLocation(false)
+	if !ok {
+		loc, ok = m.clipboardLocation(true)
+	}
+	if !ok {
+		m.reportCommandError(errors.New(i18n.T("clipboard.noLocation")))
+		return nil
+	}
+	cmd := m.openFile(loc.Path)
+	if loc.Line > 0 {
+		m.Editor.GoTo(editor.Position{Line: loc.Line - 1, Column: max(loc.Column-1, 0)})
+	}
+	return cmd
+}
+
+// clipboardLocation finds a location naming an existing file in the
+// clipboard or, with primary, the X11 primary selection.
+func (m *Model) clipboardLocation(primary bool) (pathutil.Location, bool) {
+	clipboard.Primary = primary
+	text, err := clipboard.ReadAll()
+	clipboard.Primary = false
+	if err != nil {
+		return pathutil.Location{}, false
+	}
+	for _, loc := range pathutil.FindLocations(text) {
+		if !filepath.IsAbs(loc.Path) {
+			loc.Path = filepath.Join(m.RootPath, loc.Path)
+		}
+		if info, err := os.Stat(loc.Path); err == nil && info.Mode().IsRegular() {
+			return loc, true
+		}
+	}
+	return pathutil.Location{}, false
+}
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/collab"
+	"tron/internal/editor"
+	"tron/internal/i18n"
+)
+
+type collabState struct {
+	panel      *collab.Panel
+	server     *collab.Server
+	client     *collab.Client
+	shared     *collab.SharedBuffer
+	path       string
+	lastCursor int
+	lastAnchor int
+}
+
+func newCollabState() *collabState {
+	return &collabState{panel: collab.NewPanel()}
+}
+
+func (c *collabState) active() bool {
+	return c.client != nil
+}
+
+func participantName() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "guest"
+}
+
+func (m *Model) hostCollab(addr string) tea.Cmd {
+	if m.Editor.FilePath == "" {
+		m.collab.panel.Err = errors.New(i18n.T("collab.noFile"))
+		return nil
+	}
+	server, err := collab.Host(addr, filepath.Base(m.Editor.FilePath), m.Editor.Content())
+	if err != nil {
+		m.collab.panel.Err = err
+		return nil
+	}
+	_, port, _ := net.SplitHostPort(server.Addr())
+	client, _, err := collab.Dial("localhost:"+port, server.Token(), participantName())
+	if err != nil {
+		server.Close()
+		m.collab.panel.Err = err
+		return nil
+	}
+	m.collab.server = server
+	m.collab.panel.Hosting = server.Addr()
+	m.collab.panel.Invite = server.Token() + "@" + server.Addr()
+	return m.attachCollab(client)
+}
+
+func (m *Model) joinCollab(invite string) tea.Cmd {
+	token, addr, err := collab.ParseInvite(invite)
+	if err != nil {
+		m.collab.panel.Err = err
+		return nil
+	}
+	client, welcome, err := collab.Dial(addr, token, participantName())
+	if err != nil {
+		m.collab.panel.Err = err
+		return nil
+	}
+
+	dir := filepath.Join(os.TempDir(), "tron-collab", filepath.Base(addr))
+	path := filepath.Join(dir, filepath.Base(welcome.DocName))
+	if err := os.MkdirAll(dir, 0755); err == nil {
+		err = os.WriteFile(path, []byte(welcome.Doc), 0644)
+	}
+	if err != nil {
+		client.Close()
+		m.collab.panel.Err = err
+		return nil
+	}
+
+	openCmd := m.openFile(path)
+	return tea.Batch(openCmd, m.attachCollab(client))
+}
+
+func (m *Model) attachCollab(client *collab.Client) tea.Cmd {
+	m.collab.client = client
+	m.collab.path = m.Editor.FilePath
+	m.collab.shared = collab.NewSharedBuffer(m.Editor.Buffer, client)
+	m.Editor.SetBuffer(m.collab.shared)
+	m.collab.panel.Client = client
+	m.collab.panel.Err = nil
+	m.collab.panel.Status = ""
+	m.collab.lastCursor, m.collab.lastAnchor = -1, -1
+	m.syncCollabCursor()
+	return client.Listen()
+}
+
+func (m *Model) leaveCollab() {
+	c := m.collab
+	if !c.active() {
+		return
+	}
+	if m.Editor.Buffer == c.shared {
+		m.Editor.SetBuffer(c.shared.Inner())
+	}
+	c.client.Close()
+	if c.server != nil {
+		c.server.Close()
+	}
+	c.server, c.client, c.shared = nil, nil, nil
+	c.panel.Client = nil
+	c.panel.Hosting = ""
+	c.panel.Invite = ""
+	m.Editor.SetRemoteCursors(nil)
+}
+
+// leaveCollabForPath ends the session before the editor switches away from
+// the shared document, since the session only covers one buffer.
+func (m *Model) leaveCollabForPath(path string) {
+	if m.collab.active() && path != m.collab.path {
+		m.leaveCollab()
+		m.collab.panel.Status = i18n.T("collab.endedSwitched")
+	}
+}
+
+func (m *Model) handleCollabEvent(msg collab.EventMsg) tea.Cmd {
+	c := m.collab
+	if !c.active() {
+		return nil
+	}
+	op, err := c.client.Handle(msg.Message)
+	if err != nil {
+		c.panel.Err = err
+		return c.client.Listen()
+	}
+
+	var cmds []tea.Cmd
+	if op != nil && !op.IsNoop() {
+		cursor := collab.Offset(c.shared, m.Editor.Cursor)
+		selStart := collab.Offset(c.shared, m.Editor.Selection.Start)
+		selEnd := collab.Offset(c.shared, m.Editor.Selection.End)
+		if err := c.shared.ApplyRemote(op); err != nil {
+			c.panel.Err = err
+		}
+		m.Editor.Cursor = collab.PositionAt(c.shared, collab.TransformIndex(op, cursor))
+		m.Editor.Selection.Start = collab.PositionAt(c.shared, collab.TransformIndex(op, selStart))
+		m.Editor.Selection.End = collab.PositionAt(c.shared, collab.TransformIndex(op, selEnd))
+		cmds = append(cmds, m.Editor.NotifyExternalChange())
+	}
+	m.refreshRemoteCursors()
+	cmds = append(cmds, c.client.Listen())
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) handleCollabDisconnected(msg collab.DisconnectedMsg) {
+	if !m.collab.active() {
+		return
+	}
+	m.leaveCollab()
+	m.collab.panel.Status = i18n.T("collab.disconnected")
+	m.collab.panel.Err = msg.Err
+}
+
+func (m *Model) refreshRemoteCursors() {
+	c := m.collab
+	var cursors []editor.RemoteCursor
+	for _, p := range c.client.Participants {
+		pos := collab.PositionAt(c.shared, p.Cursor)
+		cursors = append(cursors, editor.RemoteCursor{
+			Name:  p.Name,
+			Color: p.Color,
+			Pos:   pos,
+			Selection: editor.Selection{
+				Start: collab.PositionAt(c.shared, p.Anchor),
+				End:   pos,
+			},
+		})
+	}
+	m.Editor.SetRemoteCursors(cursors)
+}
+
+func (m *Model) syncCollabCursor() {
+	c := m.collab
+	if !c.active() || m.Editor.Buffer != c.shared {
+		return
+	}
+	cursor := collab.Offset(c.shared, m.Editor.Cursor)
+	anchor := cursor
+	if !m.Editor.Selection.IsEmpty() {
+		anchor = collab.Offset(c.shared, m.Editor.Selection.Start)
+		if anchor == cursor {
+			anchor = collab.Offset(c.shared, m.Editor.Selection.End)
+		}
+	}
+	if cursor == c.lastCursor && anchor == c.lastAnchor {
+		return
+	}
+	c.lastCursor, c.lastAnchor = cursor, anchor
+	c.client.SendCursor(cursor, anchor)
+}
@@ -0,0 +1,582 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/command"
+	"tron/internal/dbconsole"
+	"tron/internal/editor"
+	"tron/internal/git"
+	"tron/internal/httprunner"
+	"tron/internal/i18n"
+	"tron/internal/keyboard"
+	"tron/internal/merge"
+	"tron/internal/runconfig"
+	"tron/internal/terminal"
+)
+
+const maxMacroDepth = 8
+
+// quickActionCount is how many of the most used commands the palette
+// offers on the digit keys.
+const quickActionCount = 5
+
+type commandHandler func(m *Model, args command.Args) tea.Cmd
+
+type commandEntry struct {
+	spec command.Spec
+	run  commandHandler
+}
+
+var commandIndex map[string]commandEntry
+
+func init() {
+	commandIndex = make(map[string]commandEntry)
+	for _, entry := range builtinCommands() {
+		commandIndex[entry.spec.Name] = entry
+	}
+}
+
+func cmd(name, title string, run commandHandler, params ...command.Param) commandEntry {
+	return commandEntry{spec: command.Spec{Name: name, Title: title, Params: params}, run: run}
+}
+
+func toggle(fn func(m *Model)) commandHandler {
+	return func(m *Model, _ command.Args) tea.Cmd {
+		fn(m)
+		return nil
+	}
+}
+
+func builtinCommands() []commandEntry {
+	return []commandEntry{
+		cmd("app.quit", "Quit", func(m *Model, _ command.Args) tea.Cmd {
+			m.closeWorkspace()
+			return tea.Sequence(keyboard.Disable(), tea.Quit)
+		}),
+		cmd("app.suspend", "Suspend to shell", func(m *Model, _ command.Args) tea.Cmd {
+			return tea.Sequence(keyboard.Disable(), tea.Suspend)
+		}),
+		cmd("app.diagnostics", "Show diagnostics", toggle((*Model).showDiagnostics)),
+		cmd("palette.open", "Command palette", toggle(func(m *Model) {
+			specs := commandSpecs()
+			m.palette.Open(specs, m.usage.Top(specs, quickActionCount))
+		})),
+		cmd("keymap.edit", "Keyboard shortcuts", toggle(func(m *Model) {
+			m.keymapPanel.Open(commandSpecs(), m.keymap, builtinKeys())
+		})),
+		cmd("macro.run", "Run macro file", func(m *Model, args command.Args) tea.Cmd {
+			return m.runMacro(args.String("path"))
+		}, command.Param{Name: "path", Type: command.String}),
+
+		cmd("file.open", "Open file", func(m *Model, args command.Args) tea.Cmd {
+			path := args.String("path")
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(m.RootPath, path)
+			}
+			openCmd := m.openFile(path)
+			if args.Has("line") {
+				m.Editor.GoToLine(args.Int("line") - 1)
+			}
+			return openCmd
+		}, command.Param{Name: "path", Type: command.String}, command.Param{Name: "line", Type: command.Int, Optional: true}),
+
+		cmd("file.new", "New file", func(m *Model, args command.Args) tea.Cmd {
+			if !args.Has("path") {
+				m.palette.Prompt(commandSpecs(), "file.new", "path")
+				return nil
+			}
+			return m.newFile(args.String("path"))
+		}, command.Param{Name: "path", Type: command.String, Optional: true}),
+		cmd("workspace.open", "Open folder", func(m *Model, args command.Args) tea.Cmd {
+			if !args.Has("path") {
+				m.palette.Prompt(commandSpecs(), "workspace.open", "path")
+				return nil
+			}
+			dir := args.String("path")
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(m.RootPath, dir)
+			}
+			return func() tea.Msg { return openWorkspaceMsg{dir: dir} }
+		}, command.Param{Name: "path", Type: command.String, Optional: true}),
+		cmd("file.delete", "Move file to trash", func(m *Model, args command.Args) tea.Cmd {
+			return m.trashFile(args.String("path"))
+		}, command.Param{Name: "path", Type: command.String}),
+		cmd("file.rename", "Rename or move file", func(m *Model, args command.Args) tea.Cmd {
+			return m.renameFile(args.String("path"), args.String("to"))
+		}, command.Param{Name: "path", Type: command.String}, command.Param{Name: "to", Type: command.String}),
+		cmd("file.undoOperation", "Undo file operation", func(m *Model, _ command.Args) tea.Cmd {
+			return m.undoFileOp()
+		}),
+		cmd("file.recent", "Recent files", func(m *Model, _ command.Args) tea.Cmd {
+			m.recentPanel.Open(m.recent, m.Editor.FilePath)
+			return nil
+		}),
+		cmd("file.openFromClipboard", "Open file location from clipboard", func(m *Model, _ command.Args) tea.Cmd {
+			return m.openFromClipboard()
+		}),
+		cmd("editor.goToLine", "Go to line", func(m *Model, args command.Args) tea.Cmd {
+			m.recordJump()
+			m.Editor.GoToLine(args.Int("line") - 1)
+			return nil
+		}, command.Param{Name: "line", Type: command.Int}),
+		cmd("editor.insertText", "Insert text", func(m *Model, args command.Args) tea.Cmd {
+			return m.Editor.InsertText(args.String("text"))
+		}, command.Param{Name: "text", Type: command.String}),
+		cmd("editor.undo", "Undo", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.Undo() }),
+		cmd("editor.redo", "Redo", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.Redo() }),
+		cmd("editor.selectAll", "Select all", toggle(func(m *Model) { m.Editor.SelectAll() })),
+		cmd("editor.copy", "Copy", toggle(func(m *Model) { m.Editor.Copy() })),
+		cmd("editor.cut", "Cut", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.Cut() }),
+		cmd("editor.paste", "Paste", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.Paste() }),
+		cmd("editor.save", "Save file", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.SaveFile() }),
+		cmd("editor.find", "Find", toggle(func(m *Model) { m.Editor.OpenSearch(false) })),
+		cmd("editor.replace", "Find and replace", toggle(func(m *Model) { m.Editor.OpenSearch(true) })),
+		cmd("editor.toggleReadOnly", "Toggle read-only", toggle(func(m *Model) { m.Editor.ReadOnly = !m.Editor.ReadOnly })),
+		cmd("editor.toggleLineEnding", "Convert line endings (LF/CRLF)", toggle(func(m *Model) {
+			m.Editor.ToggleLineEnding()
+			m.syncEditorDirtyState()
+		})),
+		cmd("editor.linkScroll", "Lock scrolling with the next editor group", toggle((*Model).linkScroll)),
+		cmd("editor.unlinkScroll", "Unlock scrolling between editor groups", toggle(func(m *Model) { m.Editor.UnlinkScroll() })),
+		cmd("editor.toggleScrollLock", "Toggle scroll lock", toggle(func(m *Model) { m.Editor.ToggleScrollLock() })),
+		cmd("editor.toggleWrap", "Toggle line wrapping", toggle(func(m *Model) { m.Editor.ToggleWrap() })),
+		cmd("editor.toggleBlock", "Toggle block selection", toggle(func(m *Model) { m.Editor.ToggleBlockSelection() })),
+		cmd("editor.toggleComment", "Toggle comment", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.ToggleComment() }),
+		cmd("editor.toggleVim", "Toggle vim mode", toggle((*Model).toggleVim)),
+		cmd("editor.toggleMinimap", "Toggle minimap", toggle((*Model).toggleMinimap)),
+		cmd("editor.toggleWhitespace", "Toggle whitespace and indent guides", toggle((*Model).toggleWhitespace)),
+		cmd("editor.setScrollMargin", "Set scroll margin", func(m *Model, args command.Args) tea.Cmd {
+			m.setScrollMargin(args.Int("lines"))
+			return nil
+		}, command.Param{Name: "lines", Type: command.Int}),
+		cmd("editor.toggleScrollPastEnd", "Toggle scrolling past the end", toggle((*Model).toggleScrollPastEnd)),
+		cmd("editor.setTabWidth", "Set tab width", func(m *Model, args command.Args) tea.Cmd {
+			m.setTabWidth(args.Int("width"))
+			return nil
+		}, command.Param{Name: "width", Type: command.Int}),
+		cmd("editor.toggleInsertSpaces", "Indent using spaces or tabs", toggle(func(m *Model) { m.Editor.ToggleInsertSpaces() })),
+		cmd("editor.toggleDeadKeys", "Toggle dead key composition", toggle(func(m *Model) { m.Editor.ToggleDeadKeys() })),
+		cmd("editor.sortLinesAscending", "Sort lines ascending", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.SortLines(false, false) }),
+		cmd("editor.sortLinesDescending", "Sort lines descending", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.SortLines(true, false) }),
+		cmd("editor.sortLinesUnique", "Sort lines, removing duplicates", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.SortLines(false, true) }),
+		cmd("editor.reverseLines", "Reverse lines", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.ReverseLines() }),
+		cmd("editor.upperCase", "Transform to upper case", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.TransformCase(editor.UpperCase) }),
+		cmd("editor.lowerCase", "Transform to lower case", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.TransformCase(editor.LowerCase) }),
+		cmd("editor.titleCase", "Transform to title case", func(m *Model, _ command.Args) tea.Cmd { return m.Editor.TransformCase(editor.TitleCase) }),
+		cmd("editor.fold", "Fold block", toggle(func(m *Model) { m.Editor.Fold() })),
+		cmd("editor.unfold", "Unfold block", toggle(func(m *Model) { m.Editor.Unfold() })),
+		cmd("editor.foldAll", "Fold all", toggle(func(m *Model) { m.Editor.FoldAll() })),
+		cmd("editor.unfoldAll", "Unfold all", toggle(func(m *Model) { m.Editor.UnfoldAll() })),
+		cmd("editor.toggleSuggestions", "Toggle inline suggestions", toggle((*Model).toggleSuggestions)),
+
+		cmd("editor.splitGroup", "Split editor", func(m *Model, _ command.Args) tea.Cmd { return m.splitGroup(m.groups.active) }),
+		cmd("editor.closeGroup", "Close editor group", func(m *Model, _ command.Args) tea.Cmd { return m.closeGroup(m.groups.active) }),
+		cmd("editor.nextGroup", "Focus next editor group", func(m *Model, _ command.Args) tea.Cmd {
+			return m.focusGroup((m.groups.active + 1) % len(m.groups.panes))
+		}),
+
+		cmd("editor.setAutosaveDelay", "Set autosave delay", func(m *Model, args command.Args) tea.Cmd {
+			m.setAutosaveDelay(args.Int("seconds"))
+			return nil
+		}, command.Param{Name: "seconds", Type: command.Int}),
+		cmd("tabs.quickSwitch", "Switch to previous tab", func(m *Model, _ command.Args) tea.Cmd { return m.Tabs.QuickSwitch() }),
+		cmd("tabs.close", "Close tab", func(m *Model, _ command.Args) tea.Cmd { return m.Tabs.CloseActive() }),
+		cmd("tabs.setMemoryLimit", "Set number of tabs kept in memory", func(m *Model, args command.Args) tea.Cmd {
+			if err := m.tabDocs.setLimit(args.Int("limit")); err != nil {
+				m.reportCommandError(err)
+			}
+			m.hibernateTabs()
+			return nil
+		}, command.Param{Name: "limit", Type: command.Int}),
+
+		cmd("bookmarks.toggle", "Toggle bookmark", toggle(func(m *Model) { m.Editor.ToggleBookmark() })),
+		cmd("bookmarks.next", "Next bookmark", toggle(func(m *Model) {
+			if len(m.Editor.Bookmarks()) > 0 {
+				m.recordJump()
+				m.Editor.NextBookmark()
+			}
+		})),
+		cmd("bookmarks.previous", "Previous bookmark", toggle(func(m *Model) {
+			if len(m.Editor.Bookmarks()) > 0 {
+				m.recordJump()
+				m.Editor.PrevBookmark()
+			}
+		})),
+		cmd("nav.back", "Go back", func(m *Model, _ command.Args) tea.Cmd { return m.jumpBack() }),
+		cmd("nav.forward", "Go forward", func(m *Model, _ command.Args) tea.Cmd { return m.jumpForward() }),
+
+		cmd("breakpoints.toggle", "Toggle breakpoint", toggle(func(m *Model) {
+			if m.Editor.FilePath == "" {
+				return
+			}
+			m.Breakpoints.Toggle(m.Editor.FilePath, m.Editor.Cursor.Line)
+			_ = m.Breakpoints.Save()
+			m.refreshBreakpointMarkers()
+		})),
+		cmd("breakpoints.panel", "Breakpoints panel", toggle(func(m *Model) { m.bpPanel.Toggle() })),
+
+		cmd("run.config", "Run configuration", func(m *Model, args command.Args) tea.Cmd {
+			return m.runConfigNamed(args.String("name"))
+		}, command.Param{Name: "name", Type: command.String, Optional: true}),
+		cmd("run.capture", "Run configuration and capture its output", func(m *Model, args command.Args) tea.Cmd {
+			config, ok := m.findRunConfig(args.String("name"))
+			if !ok {
+				return nil
+			}
+			return m.runCapture(config)
+		}, command.Param{Name: "name", Type: command.String, Optional: true}),
+		cmd("cargo.check", "Check Rust project", func(m *Model, _ command.Args) tea.Cmd { return m.runCargoCheck(false) }),
+		cmd("cargo.clippy", "Check Rust project with clippy", func(m *Model, _ command.Args) tea.Cmd { return m.runCargoCheck(true) }),
+		cmd("problems.panel", "Problems panel", toggle(func(m *Model) { m.problemsPanel.Toggle() })),
+		cmd("run.atCursor", "Run request or statement at cursor", func(m *Model, _ command.Args) tea.Cmd {
+			if httprunner.IsHTTPFile(m.Editor.FilePath) {
+				return m.sendHTTPRequest()
+			}
+			if dbconsole.IsSQLFile(m.Editor.FilePath) {
+				return m.executeSQL(false)
+			}
+			return nil
+		}),
+		cmd("terminal.run", "Run shell command", func(m *Model, args command.Args) tea.Cmd {
+			cwd := args.String("cwd")
+			if cwd == "" {
+				cwd = m.RootPath
+			}
+			m.runShell(args.String("command"), cwd)
+			return nil
+		}, command.Param{Name: "command", Type: command.String}, command.Param{Name: "cwd", Type: command.String, Optional: true}),
+		cmd("test.run", "Run test at cursor", func(m *Model, _ command.Args) tea.Cmd { return m.runTestUnderCursor(false) }),
+		cmd("test.debug", "Debug test at cursor", func(m *Model, _ command.Args) tea.Cmd { return m.runTestUnderCursor(true) }),
+		cmd("coverage.toggle", "Toggle coverage", toggle((*Model).toggleCoverage)),
+		cmd("coverage.run", "Run tests with coverage", func(m *Model, _ command.Args) tea.Cmd { return m.rerunCoverage() }),
+
+		cmd("repl.toggle", "REPL", toggle(func(m *Model) { m.replPanel.Toggle() })),
+		cmd("sql.runFile", "Run SQL file", func(m *Model, _ command.Args) tea.Cmd {
+			if !dbconsole.IsSQLFile(m.Editor.FilePath) {
+				return nil
+			}
+			return m.executeSQL(true)
+		}),
+		cmd("sql.toggle", "SQL results", toggle(func(m *Model) { m.dbPanel.Toggle() })),
+		cmd("docker.toggle", "Containers", func(m *Model, _ command.Args) tea.Cmd { return m.dockerPanel.Toggle() }),
+		cmd("collab.toggle", "Shared session", toggle(func(m *Model) { m.collab.panel.Toggle() })),
+		cmd("preview.toggle", "Toggle preview server", toggle((*Model).togglePreview)),
+		cmd("go.environment", "Go build environment", toggle((*Model).openGoEnv)),
+		cmd("python.selectInterpreter", "Select Python interpreter", toggle((*Model).openPythonPicker)),
+		cmd("project.new", "New project", toggle(func(m *Model) { m.scaffoldPanel.Toggle() })),
+
+		cmd("merge.next", "Next conflict", toggle(func(m *Model) { m.jumpToConflict(1) })),
+		cmd("merge.previous", "Previous conflict", toggle(func(m *Model) { m.jumpToConflict(-1) })),
+		cmd("merge.acceptOurs", "Accept ours in conflict", func(m *Model, _ command.Args) tea.Cmd { return m.resolveConflict(merge.AcceptOurs) }),
+		cmd("merge.acceptTheirs", "Accept theirs in conflict", func(m *Model, _ command.Args) tea.Cmd { return m.resolveConflict(merge.AcceptTheirs) }),
+		cmd("merge.acceptBoth", "Accept both in conflict", func(m *Model, _ command.Args) tea.Cmd { return m.resolveConflict(merge.AcceptBoth) }),
+
+		cmd("git.stageHunk", "Stage hunk", func(m *Model, _ command.Args) tea.Cmd { return m.stageHunk() }),
+		cmd("git.unstageHunk", "Unstage hunk", func(m *Model, _ command.Args) tea.Cmd { return m.unstageHunk() }),
+		cmd("git.revertHunk", "Revert hunk", func(m *Model, _ command.Args) tea.Cmd { return m.revertHunk() }),
+		cmd("git.nextHunk", "Next change", toggle(func(m *Model) { m.jumpToHunk(1) })),
+		cmd("git.previousHunk", "Previous change", toggle(func(m *Model) { m.jumpToHunk(-1) })),
+		cmd("git.panel", "Git stashes and remotes", func(m *Model, _ command.Args) tea.Cmd { return m.toggleGitPanel() }),
+		cmd("git.stash", "Stash changes", func(m *Model, args command.Args) tea.Cmd {
+			return m.runStashAction(git.StashMsg{Action: git.SaveStash, Message: args.String("message")})
+		}, command.Param{Name: "message", Type: command.String, Optional: true}),
+		cmd("git.stashPop", "Pop latest stash", func(m *Model, _ command.Args) tea.Cmd {
+			return m.runStashAction(git.StashMsg{Action: git.PopStash, Ref: "stash@{0}"})
+		}),
+		cmd("git.log", "Repository history", func(m *Model, _ command.Args) tea.Cmd { return m.openGitLog(false) }),
+		cmd("git.fileLog", "File history", func(m *Model, _ command.Args) tea.Cmd { return m.openGitLog(true) }),
+		cmd("git.branches", "Switch branch", func(m *Model, _ command.Args) tea.Cmd { return m.openBranchPicker() }),
+		cmd("git.checkout", "Check out branch", func(m *Model, args command.Args) tea.Cmd {
+			return m.runBranchAction(git.BranchMsg{Action: git.CheckoutBranch, Name: args.String("branch")})
+		}, command.Param{Name: "branch", Type: command.String}),
+		cmd("git.fetch", "Fetch", func(m *Model, _ command.Args) tea.Cmd { return m.runRemote(git.Fetch) }),
+		cmd("git.pull", "Pull", func(m *Model, _ command.Args) tea.Cmd { return m.runRemote(git.Pull) }),
+		cmd("git.push", "Push", func(m *Model, _ command.Args) tea.Cmd { return m.runRemote(git.Push) }),
+
+		cmd("output.run", "Run command into a tab", func(m *Model, args command.Args) tea.Cmd {
+			return m.runOutput(args.String("command"))
+		}, command.Param{Name: "command", Type: command.String}),
+		cmd("lsp.showLog", "Language server log", func(m *Model, _ command.Args) tea.Cmd { return m.showLSPLog() }),
+
+		cmd("accessibility.toggle", "Toggle accessibility mode", toggle((*Model).toggleAccessibility)),
+		cmd("accessibility.highContrast", "Toggle high-contrast theme", toggle((*Model).toggleHighContrast)),
+		cmd("accessibility.describe", "Describe current context", toggle((*Model).describeContext)),
+		cmd("layout.toggleBorders", "Toggle panel borders", toggle((*Model).toggleBorders)),
+		cmd("layout.floatTerminal", "Floating terminal", toggle((*Model).toggleFloatingTerminal)),
+		cmd("layout.floatNotes", "Floating notes", toggle((*Model).toggleFloatingNotes)),
+		cmd("layout.setIconTheme", "Set file icon theme", func(m *Model, args command.Args) tea.Cmd {
+			m.setIconTheme(args.String("theme"))
+			return nil
+		}, command.Param{Name: "theme", Type: command.String}),
+		cmd("i18n.setLocale", "Set display language", func(m *Model, args command.Args) tea.Cmd {
+			m.setLocale(args.String("locale"))
+			return nil
+		}, command.Param{Name: "locale", Type: command.String}),
+	}
+}
+
+var defaultKeymap = map[string]string{
+	"ctrl+c":    "app.quit",
+	"esc":       "app.quit",
+	"ctrl+z":    "app.suspend",
+	"ctrl+p":    "palette.open",
+	"ctrl+e":    "file.recent",
+	"ctrl+n":    "file.new",
+	"alt+Z":     "file.undoOperation",
+	"alt+O":     "workspace.open",
+	"alt+q":     "tabs.close",
+	"alt+b":     "breakpoints.panel",
+	"alt+e":     "repl.toggle",
+	"alt+enter": "run.atCursor",
+	"alt+R":     "run.capture",
+	"alt+E":     "sql.runFile",
+	"alt+d":     "sql.toggle",
+	"alt+k":     "docker.toggle",
+	"alt+m":     "collab.toggle",
+	"alt+w":     "preview.toggle",
+	"alt+i":     "editor.toggleSuggestions",
+	"alt+g":     "go.environment",
+	"alt+p":     "python.selectInterpreter",
+	"alt+n":     "project.new",
+	"alt+t":     "test.run",
+	"alt+T":     "test.debug",
+	"alt+c":     "coverage.toggle",
+	"alt+C":     "coverage.run",
+	"alt+a":     "accessibility.describe",
+	"alt+.":     "merge.next",
+	"alt+,":     "merge.previous",
+	"alt+1":     "merge.acceptOurs",
+	"alt+2":     "merge.acceptTheirs",
+	"alt+3":     "merge.acceptBoth",
+	"alt+s":     "git.stageHunk",
+	"alt+u":     "git.unstageHunk",
+	"alt+z":     "git.revertHunk",
+	"alt+N":     "git.nextHunk",
+	"alt+P":     "git.previousHunk",
+	"alt+v":     "git.panel",
+	"alt+h":     "git.fileLog",
+	"alt+o":     "git.branches",
+	"alt+\\":    "editor.splitGroup",
+	"alt+`":     "editor.nextGroup",
+	"alt+-":     "editor.fold",
+	"alt+=":     "editor.unfold",
+	"alt+_":     "editor.foldAll",
+	"alt++":     "editor.unfoldAll",
+	"alt+K":     "keymap.edit",
+	"alt+B":     "editor.toggleBlock",
+	"ctrl+/":    "editor.toggleComment",
+	"ctrl+_":    "editor.toggleComment", // ctrl+/ without the kitty protocol
+	"alt+j":     "layout.floatTerminal",
+	"alt+J":     "layout.floatNotes",
+	"alt+M":     "editor.toggleMinimap",
+	"alt+f2":    "bookmarks.toggle",
+	"alt+f9":    "editor.sortLinesAscending",
+	"alt+U":     "editor.upperCase",
+	"alt+L":     "editor.lowerCase",
+	"alt+'":     "bookmarks.next",
+	"alt+;":     "bookmarks.previous",
+	"ctrl+o":    "nav.back",
+	"ctrl+i":    "nav.forward",      // kitty protocol only; legacy terminals send tab
+	"ctrl+tab":  "tabs.quickSwitch", // kitty protocol only, like ctrl+i
+}
+
+func commandSpecs() []command.Spec {
+	specs := make([]command.Spec, 0, len(commandIndex))
+	for _, entry := range commandIndex {
+		spec := entry.spec
+		if title, ok := i18n.Lookup("command." + spec.Name); ok {
+			spec.Title = title
+		}
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
+// keybindingsPath is the user's keybindings.json. It is never read from a
+// workspace, where a cloned repository could bind an everyday key to
+// terminal.run.
+func keybindingsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tron", "keybindings.json")
+}
+
+// loadKeymap merges the user's keybindings.json over the defaults. Each
+// value is an invocation such as "editor.goToLine {line: 1}"; an empty
+// value unbinds the key.
+func loadKeymap() map[string]command.Invocation {
+	bindings := make(map[string]string, len(defaultKeymap))
+	for key, line := range defaultKeymap {
+		bindings[key] = line
+	}
+	if data, err := os.ReadFile(keybindingsPath()); err == nil {
+		var user map[string]string
+		if json.Unmarshal(data, &user) == nil {
+			for key, line := range user {
+				bindings[key] = line
+			}
+		}
+	}
+
+	keymap := make(map[string]command.Invocation, len(bindings))
+	for key, line := range bindings {
+		if line == "" {
+			continue
+		}
+		if inv, err := command.Parse(line); err == nil {
+			keymap[key] = inv
+		}
+	}
+	return keymap
+}
+
+// saveKeybindings writes bindings over the user's keybindings file and
+// reloads the keymap.
+func (m *Model) saveKeybindings(bindings map[string]string) error {
+	path := keybindingsPath()
+	if path == "" {
+		return errors.New(i18n.T("keymap.noConfigDir"))
+	}
+	user := make(map[string]string)
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &user); err != nil {
+			return err
+		}
+	}
+	for key, line := range bindings {
+		if _, ok := defaultKeymap[key]; !ok && line == "" {
+			delete(user, key)
+			continue
+		}
+		user[key] = line
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(user, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	m.keymap = loadKeymap()
+	m.groups.setKeymap(m.keymap)
+	m.keymapPanel.SetKeymap(m.keymap)
+	return nil
+}
+
+// builtinKeys maps the keys the editor and terminal handle themselves to
+// their names, for flagging bindings that shadow them.
+func builtinKeys() map[string]string {
+	keys := make(map[string]string)
+	add := func(context string, list []string) {
+		for _, key := range list {
+			if keys[key] != "" {
+				keys[key] += ", "
+			}
+			keys[key] += context
+		}
+	}
+	add(i18n.T("keymap.editor"), editor.BuiltinKeys)
+	add(i18n.T("keymap.terminal"), terminal.BuiltinKeys)
+	return keys
+}
+
+// execute is the single dispatcher behind the palette, keybindings, macros
+// and command.RunMsg.
+func (m *Model) execute(inv command.Invocation) tea.Cmd {
+	entry, ok := commandIndex[inv.Name]
+	if !ok {
+		m.reportCommandError(errors.New(i18n.T("command.unknown", inv.Name)))
+		return nil
+	}
+	args, err := entry.spec.Validate(inv.Args)
+	if err != nil {
+		m.reportCommandError(err)
+		return nil
+	}
+	if m.macroDepth == 0 && inv.Name != "palette.open" {
+		_ = m.usage.Record(inv.Name)
+	}
+	return entry.run(m, args)
+}
+
+func (m *Model) runMacro(path string) tea.Cmd {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.RootPath, path)
+	}
+	if m.macroDepth >= maxMacroDepth {
+		m.reportCommandError(errors.New(i18n.T("command.macroTooDeep", path)))
+		return nil
+	}
+	invocations, err := command.LoadMacro(path)
+	if err != nil {
+		m.reportCommandError(err)
+		return nil
+	}
+
+	m.macroDepth++
+	defer func() { m.macroDepth-- }()
+	cmds := make([]tea.Cmd, 0, len(invocations))
+	for _, inv := range invocations {
+		cmds = append(cmds, m.execute(inv))
+	}
+	return tea.Sequence(cmds...)
+}
+
+func (m *Model) runConfigNamed(name string) tea.Cmd {
+	config, ok := m.findRunConfig(name)
+	if !ok {
+		return nil
+	}
+	return m.handleRunCommand(runconfig.RunCommandMsg{Config: config})
+}
+
+// findRunConfig returns the run configuration called name, or the selected
+// one when name is empty, reporting a name that matches none.
+func (m *Model) findRunConfig(name string) (*runconfig.RunConfig, bool) {
+	manager := m.RunBar.GetManager()
+	if name == "" {
+		return manager.GetSelected(), true
+	}
+	for _, c := range manager.Configs {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	m.reportCommandError(errors.New(i18n.T("command.noRunConfig", name)))
+	return nil, false
+}
+
+func (m *Model) reportCommandError(err error) {
+	m.commandErr = err
+	text := err.Error()
+	if m.accessibility.Enabled {
+		text = i18n.T("command.errorPrefix", text)
+	}
+	m.Terminal.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render(text))
+}
+
+func (m *Model) setLocale(locale string) {
+	if !i18n.SetLocale(locale) {
+		m.reportCommandError(errors.New(i18n.T("command.unknownLocale", locale, strings.Join(i18n.Locales(), ", "))))
+		return
+	}
+	m.applyAppearance()
+	if err := i18n.Save(m.RootPath, i18n.Locale()); err != nil {
+		m.reportCommandError(err)
+	}
+}
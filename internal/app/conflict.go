@@ -0,0 +1,83 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/editor"
+	"tron/internal/i18n"
+	"tron/internal/merge"
+)
+
+// openMerge replaces the buffer with conflict blocks for every region
+// where the unsaved edits and the file on disk differ.
+func (m *Model) openMerge(msg editor.ConflictMergeMsg) tea.Cmd {
+	if msg.Path != m.Editor.FilePath {
+		return nil
+	}
+	merged := merge.Markers(msg.Local, msg.Disk, i18n.T("conflict.unsaved"), i18n.T("conflict.disk"))
+	cmd := m.Editor.LoadMerge(merged, msg.Disk)
+	m.jumpToConflict(1)
+	return cmd
+}
+
+func (m *Model) refreshMergeMarkers() {
+	markers := make(map[int]editor.GutterMarker)
+	for _, c := range merge.Parse(m.Editor.Buffer.Lines()) {
+		line := c.Start
+		markers[line] = editor.GutterMarker{Symbol: "!", Color: "#f9e2af"}
+		for _, section := range []struct {
+			lines  []string
+			marker editor.GutterMarker
+		}{
+			{c.Ours, editor.GutterMarker{Symbol: "<", Color: "#a6e3a1"}},
+			{c.Base, editor.GutterMarker{Symbol: "|", Color: "#6c7086"}},
+			{c.Theirs, editor.GutterMarker{Symbol: ">", Color: "#89b4fa"}},
+		} {
+			if section.lines == nil {
+				continue
+			}
+			for range section.lines {
+				line++
+				markers[line] = section.marker
+			}
+			line++
+			markers[line] = editor.GutterMarker{Symbol: "!", Color: "#f9e2af"}
+		}
+	}
+	m.Editor.SetGutterMarkers("merge", markers)
+}
+
+// jumpToConflict moves the cursor to the next (dir > 0) or previous conflict
+// block, wrapping around the file.
+func (m *Model) jumpToConflict(dir int) {
+	conflicts := merge.Parse(m.Editor.Buffer.Lines())
+	if len(conflicts) == 0 {
+		return
+	}
+	line := m.Editor.Cursor.Line
+	target := conflicts[0]
+	if dir < 0 {
+		target = conflicts[len(conflicts)-1]
+	}
+	for i := range conflicts {
+		c := conflicts[i]
+		if dir < 0 {
+			c = conflicts[len(conflicts)-1-i]
+		}
+		if (dir > 0 && c.Start > line) || (dir < 0 && c.End < line) {
+			target = c
+			break
+		}
+	}
+	m.Editor.GoToLine(target.Start)
+}
+
+func (m *Model) resolveConflict(r merge.Resolution) tea.Cmd {
+	conflicts := merge.Parse(m.Editor.Buffer.Lines())
+	i := merge.At(conflicts, m.Editor.Cursor.Line)
+	if i < 0 {
+		return nil
+	}
+	c := conflicts[i]
+	return m.Editor.ReplaceLines(c.Start, c.End+1, c.Lines(r))
+}
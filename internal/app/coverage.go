@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/coverage"
+	"tron/internal/editor"
+	"tron/internal/i18n"
+)
+
+const coveragePollInterval = 500 * time.Millisecond
+
+type coverageState struct {
+	rootPath string
+	report   *coverage.Report
+	visible  bool
+	running  bool
+}
+
+type coveragePollMsg struct{}
+
+type coverageLoadedMsg struct {
+	report *coverage.Report
+}
+
+func newCoverageState(rootPath string) *coverageState {
+	return &coverageState{rootPath: rootPath, visible: true}
+}
+
+func (c *coverageState) load() tea.Cmd {
+	rootPath := c.rootPath
+	return func() tea.Msg {
+		report, err := coverage.Load(rootPath)
+		if err != nil {
+			report = nil
+		}
+		return coverageLoadedMsg{report: report}
+	}
+}
+
+func (c *coverageState) reload() {
+	report, err := coverage.Load(c.rootPath)
+	if err != nil {
+		report = nil
+	}
+	c.report = report
+}
+
+func (c *coverageState) statusText(path string) string {
+	if c.running {
+		return i18n.T("coverage.running")
+	}
+	if !c.visible {
+		return ""
+	}
+	f := c.report.ForFile(path)
+	if f == nil {
+		return ""
+	}
+	return fmt.Sprintf("cov %.1f%%", f.Percent())
+}
+
+func (m *Model) refreshCoverageMarkers() {
+	markers := make(map[int]editor.GutterMarker)
+	if f := m.coverage.report.ForFile(m.Editor.FilePath); f != nil && m.coverage.visible {
+		for line, covered := range f.Lines {
+			marker := editor.GutterMarker{Symbol: "▎", Color: "#a6e3a1"}
+			if !covered {
+				marker.Color = "#f38ba8"
+			}
+			if m.accessibility.Enabled {
+				marker.Symbol = "+"
+				if !covered {
+					marker.Symbol = "-"
+				}
+			}
+			markers[line] = marker
+		}
+	}
+	m.Editor.SetGutterMarkers("coverage", markers)
+}
+
+func (m *Model) toggleCoverage() {
+	m.coverage.visible = !m.coverage.visible
+	if m.coverage.visible {
+		m.coverage.reload()
+	}
+	m.refreshCoverageMarkers()
+}
+
+func (m *Model) rerunCoverage() tea.Cmd {
+	if m.coverage.running {
+		return nil
+	}
+	m.coverage.running = true
+	m.runShell(coverage.Command(m.RootPath), m.RootPath)
+	return pollCoverage()
+}
+
+func (m *Model) handleCoverageLoaded(msg coverageLoadedMsg) {
+	if m.coverage.report == nil {
+		m.coverage.report = msg.report
+		m.refreshCoverageMarkers()
+	}
+}
+
+func (m *Model) handleCoveragePoll() tea.Cmd {
+	if m.Terminal.IsRunning() {
+		return pollCoverage()
+	}
+	m.coverage.running = false
+	m.coverage.visible = true
+	m.coverage.reload()
+	m.refreshCoverageMarkers()
+	return nil
+}
+
+func pollCoverage() tea.Cmd {
+	return tea.Tick(coveragePollInterval, func(time.Time) tea.Msg {
+		return coveragePollMsg{}
+	})
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/dbconsole"
+	"tron/internal/editor"
+)
+
+func (m *Model) executeSQL(wholeFile bool) tea.Cmd {
+	stmts := dbconsole.SplitStatements(m.Editor.Content())
+	if !wholeFile {
+		stmt := dbconsole.StatementAt(stmts, m.Editor.Cursor.Line)
+		if stmt == nil {
+			return nil
+		}
+		stmts = []dbconsole.Statement{*stmt}
+	}
+	return m.dbPanel.Execute(m.Editor.FilePath, stmts)
+}
+
+func (m *Model) refreshSQLMarkers() {
+	markers := make(map[int]editor.GutterMarker)
+	if dbconsole.IsSQLFile(m.Editor.FilePath) && m.sqlErrorsPath == m.Editor.FilePath {
+		for _, r := range m.dbPanel.Results() {
+			if r.Err != nil {
+				markers[r.ErrLine] = editor.GutterMarker{Symbol: "✖", Color: "#f38ba8"}
+			}
+		}
+	}
+	m.Editor.SetGutterMarkers("sql", markers)
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/command"
+	"tron/internal/i18n"
+	"tron/internal/jobs"
+)
+
+// defaultDriverTimeout bounds how long the work one message starts may
+// take in a Driver.
+const defaultDriverTimeout = 5 * time.Minute
+
+var cmdType = reflect.TypeOf(tea.Cmd(nil))
+
+// driverResult is what a goroutine running commands sends: each message
+// they return, then done.
+type driverResult struct {
+	msg  tea.Msg
+	done bool
+}
+
+// Driver runs the app without a terminal, for scripts and tests. It feeds
+// the app messages and runs the commands it returns until they, and any
+// process started in the terminal, have finished. The refresh loops Init
+// would start are left out, since they never finish.
+type Driver struct {
+	m       Model
+	results chan driverResult
+	pending int
+	// Timeout bounds how long the work started by one message or command
+	// may take.
+	Timeout time.Duration
+}
+
+// NewDriver opens the workspace at the current directory in a window of
+// the given size.
+func NewDriver(width, height int) (*Driver, error) {
+	d := &Driver{m: New(), results: make(chan driverResult, 64), Timeout: defaultDriverTimeout}
+	// Like a macro's, the commands run don't count as palette usage.
+	d.m.macroDepth++
+	d.update(tea.WindowSizeMsg{Width: width, Height: height})
+	if msg, ok := openGitRepo(d.m.RootPath)().(gitRepoMsg); ok {
+		d.m.git.repo = msg.repo
+	}
+	d.start(d.m.FileTree.Load(), d.m.RunBar.GetManager().Detect())
+	return d, d.settle()
+}
+
+// Send hands msg to the app and waits for the work it starts.
+func (d *Driver) Send(msg tea.Msg) error {
+	d.update(msg)
+	return d.settle()
+}
+
+// Execute runs a command and waits for the work it starts, returning the
+// error the command reported, if any.
+func (d *Driver) Execute(inv command.Invocation) error {
+	d.m.commandErr = nil
+	d.start(d.m.execute(inv))
+	if err := d.settle(); err != nil {
+		return err
+	}
+	return d.m.commandErr
+}
+
+// View renders the app as the terminal would show it.
+func (d *Driver) View() string {
+	return d.m.View()
+}
+
+// Close stops the language servers and background jobs.
+func (d *Driver) Close() {
+	d.m.lsp.shutdown()
+	d.m.jobs.Close()
+}
+
+func (d *Driver) update(msg tea.Msg) {
+	next, cmd := d.m.Update(msg)
+	d.m = next.(Model)
+	d.start(cmd)
+}
+
+// start runs cmds one after the other in the background, as tea.Sequence
+// would.
+func (d *Driver) start(cmds ...tea.Cmd) {
+	d.pending++
+	go func() {
+		for _, cmd := range cmds {
+			if cmd != nil {
+				d.results <- driverResult{msg: cmd()}
+			}
+		}
+		d.results <- driverResult{done: true}
+	}()
+}
+
+// settle handles messages until every command started has finished and
+// the terminals are idle.
+func (d *Driver) settle() error {
+	timeout := time.After(d.Timeout)
+	poll := time.NewTicker(50 * time.Millisecond)
+	defer poll.Stop()
+	for d.pending > 0 || d.terminalRunning() {
+		select {
+		case r := <-d.results:
+			if r.done {
+				d.pending--
+			} else {
+				d.handle(r.msg)
+			}
+		case <-poll.C:
+		case <-timeout:
+			return errors.New(i18n.T("script.timeout", d.Timeout))
+		}
+	}
+	return nil
+}
+
+func (d *Driver) handle(msg tea.Msg) {
+	switch msg.(type) {
+	case nil, tea.QuitMsg:
+		return
+	case gitStatusTickMsg, followTickMsg, remoteTickMsg, coveragePollMsg, jobs.ProgressMsg:
+		// The refresh loops started by these have no end.
+		return
+	}
+	// tea.Batch and tea.Sequence both return a slice of commands, the
+	// latter of an unexported type.
+	if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice && v.Type().Elem() == cmdType {
+		cmds := make([]tea.Cmd, v.Len())
+		for i := range cmds {
+			cmds[i] = v.Index(i).Interface().(tea.Cmd)
+		}
+		if _, batch := msg.(tea.BatchMsg); batch {
+			for _, cmd := range cmds {
+				d.start(cmd)
+			}
+		} else {
+			d.start(cmds...)
+		}
+		return
+	}
+	d.update(msg)
+}
+
+func (d *Driver) terminals() []*TerminalPanel {
+	return []*TerminalPanel{d.m.Terminal, d.m.floats.term}
+}
+
+func (d *Driver) terminalRunning() bool {
+	for _, t := range d.terminals() {
+		if t.IsRunning() {
+			return true
+		}
+	}
+	return false
+}
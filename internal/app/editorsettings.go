@@ -0,0 +1,143 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tron/internal/editor"
+)
+
+type editorSettings struct {
+	// Vim switches the editors to vim's modal editing.
+	Vim bool `json:"vim"`
+	// Minimap shows an overview of the buffer beside each editor.
+	Minimap bool `json:"minimap,omitempty"`
+	// ShowWhitespace marks spaces and tabs and draws indentation guides.
+	ShowWhitespace bool `json:"showWhitespace,omitempty"`
+	// TrimTrailingWhitespace and InsertFinalNewline tidy files as they
+	// are saved.
+	TrimTrailingWhitespace bool `json:"trimTrailingWhitespace,omitempty"`
+	InsertFinalNewline     bool `json:"insertFinalNewline,omitempty"`
+	// AutosaveDelay is how many seconds after the last edit unsaved
+	// changes are written; 0 turns this off. AutosaveOnFocusLoss also
+	// writes them when the terminal loses focus or the editor switches
+	// to another tab.
+	AutosaveDelay       int  `json:"autosaveDelay,omitempty"`
+	AutosaveOnFocusLoss bool `json:"autosaveOnFocusLoss,omitempty"`
+	// Backup keeps the previous content of each saved file beside it,
+	// with ~ appended to its name.
+	Backup bool `json:"backup,omitempty"`
+	// WordSeparators are the characters that end words for word motion,
+	// deletion and selection, keyed by file extension such as ".css", or
+	// "*" for every file.
+	WordSeparators map[string]string `json:"wordSeparators,omitempty"`
+	// ScrollMargin is how many lines are kept in view above and below
+	// the cursor. ScrollPastEnd lets the last line scroll up to the top.
+	ScrollMargin  int  `json:"scrollMargin,omitempty"`
+	ScrollPastEnd bool `json:"scrollPastEnd,omitempty"`
+	// TabWidth is how many cells apart tab stops are drawn.
+	TabWidth int `json:"tabWidth,omitempty"`
+	// DebounceDelay is how many milliseconds of idle typing pass before
+	// highlighting, language servers and the dirty marker catch up; 0
+	// keeps the default of 150, and a negative value catches up after
+	// every key.
+	DebounceDelay int `json:"debounceDelay,omitempty"`
+}
+
+func editorSettingsPath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "editor.json")
+}
+
+func loadEditorSettings(rootPath string) *editorSettings {
+	s := &editorSettings{}
+	if data, err := os.ReadFile(editorSettingsPath(rootPath)); err == nil {
+		_ = json.Unmarshal(data, s)
+	}
+	return s
+}
+
+func (s *editorSettings) save(rootPath string) error {
+	path := editorSettingsPath(rootPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (m *Model) applyEditorSettings() {
+	s := m.editing
+	for _, ed := range append(m.groups.editors(), m.floats.notes) {
+		if ed.Vim != s.Vim {
+			ed.SetVim(s.Vim)
+		}
+		if ed.Minimap != s.Minimap {
+			ed.SetMinimap(s.Minimap)
+		}
+		ed.ShowWhitespace = s.ShowWhitespace
+		ed.TrimTrailingWhitespace = s.TrimTrailingWhitespace
+		ed.InsertFinalNewline = s.InsertFinalNewline
+		ed.Backup = s.Backup
+		ed.WordSeparators = s.WordSeparators
+		ed.Viewport.Margin = s.ScrollMargin
+		ed.Viewport.PastEnd = s.ScrollPastEnd
+		ed.TabWidth = s.TabWidth
+		ed.DebounceDelay = editor.DefaultDebounceDelay
+		if s.DebounceDelay != 0 {
+			ed.DebounceDelay = time.Duration(s.DebounceDelay) * time.Millisecond
+		}
+	}
+}
+
+func (m *Model) toggleVim() {
+	m.editing.Vim = !m.editing.Vim
+	m.applyEditorSettings()
+	if err := m.editing.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
+
+func (m *Model) toggleMinimap() {
+	m.editing.Minimap = !m.editing.Minimap
+	m.applyEditorSettings()
+	if err := m.editing.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
+
+func (m *Model) toggleWhitespace() {
+	m.editing.ShowWhitespace = !m.editing.ShowWhitespace
+	m.applyEditorSettings()
+	if err := m.editing.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
+
+func (m *Model) setScrollMargin(lines int) {
+	m.editing.ScrollMargin = max(lines, 0)
+	m.applyEditorSettings()
+	if err := m.editing.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
+
+func (m *Model) setTabWidth(width int) {
+	m.editing.TabWidth = max(width, 0)
+	m.applyEditorSettings()
+	if err := m.editing.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
+
+func (m *Model) toggleScrollPastEnd() {
+	m.editing.ScrollPastEnd = !m.editing.ScrollPastEnd
+	m.applyEditorSettings()
+	if err := m.editing.save(m.RootPath); err != nil {
+		m.reportCommandError(err)
+	}
+}
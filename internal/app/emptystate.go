@@ -0,0 +1,72 @@
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"tron/internal/command"
+	"tron/internal/i18n"
+)
+
+// emptyShortcuts are the commands an editor group with no file offers, in
+// the order shown. Commands the user left unbound are skipped.
+var emptyShortcuts = []string{"file.recent", "palette.open", "file.new", "workspace.open"}
+
+var (
+	emptyTitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4")).Bold(true)
+	emptyKeyStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+	emptyTextStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+)
+
+// commandTitle is the display title of the command name.
+func commandTitle(name string) string {
+	if title, ok := i18n.Lookup("command." + name); ok {
+		return title
+	}
+	return commandIndex[name].spec.Title
+}
+
+// firstKey returns the first of the keys bound to the command name in
+// sorted order.
+func firstKey(keymap map[string]command.Invocation, name string) (string, bool) {
+	var keys []string
+	for key, inv := range keymap {
+		if inv.Name == name && len(inv.Args) == 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return "", false
+	}
+	sort.Strings(keys)
+	return keys[0], true
+}
+
+// emptyView fills the pane's editor area when no file is open, listing the
+// keys that get one open instead of an editable buffer that belongs to no
+// file.
+func (p *editorPane) emptyView() string {
+	type row struct{ key, title string }
+	var rows []row
+	keyWidth := 0
+	for _, name := range emptyShortcuts {
+		key, ok := firstKey(p.keymap, name)
+		if !ok {
+			continue
+		}
+		rows = append(rows, row{key, commandTitle(name)})
+		keyWidth = max(keyWidth, ansi.StringWidth(key))
+	}
+
+	lines := []string{emptyTitleStyle.Render(i18n.T("pane.empty")), ""}
+	for _, r := range rows {
+		pad := strings.Repeat(" ", keyWidth-ansi.StringWidth(r.key)+2)
+		lines = append(lines, emptyKeyStyle.Render(r.key)+pad+emptyTextStyle.Render(r.title))
+	}
+	height := max(p.height-1, 0)
+	block := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return lipgloss.Place(p.width, height, lipgloss.Center, lipgloss.Center, block)
+}
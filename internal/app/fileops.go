@@ -0,0 +1,214 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/editor"
+	"tron/internal/i18n"
+	"tron/pkg/pathutil"
+	"tron/pkg/trash"
+)
+
+// fileOpLimit is how many file operations can be undone.
+const fileOpLimit = 20
+
+// fileOp is a delete or rename done from the editor, kept to undo it. A
+// deleted file went to the trash as item; a renamed or moved one went from
+// from to to.
+type fileOp struct {
+	item     trash.Item
+	from, to string
+}
+
+func (op fileOp) deleted() bool {
+	return op.item.Path != ""
+}
+
+// fileOps is the undo history of file operations, oldest first.
+type fileOps struct {
+	done []fileOp
+}
+
+func (h *fileOps) push(op fileOp) {
+	h.done = append(h.done, op)
+	if len(h.done) > fileOpLimit {
+		h.done = h.done[len(h.done)-fileOpLimit:]
+	}
+}
+
+func (m *Model) resolvePath(path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.RootPath, path)
+	}
+	return pathutil.Canonical(path)
+}
+
+// trashFile moves path to the trash and closes the tabs of the files that
+// went with it. Nothing is deleted for good: where there is no trash, the
+// file stays.
+func (m *Model) trashFile(path string) tea.Cmd {
+	path = m.resolvePath(path)
+	item, err := trash.Move(path)
+	if err != nil {
+		m.reportCommandError(errors.New(i18n.T("fileops.trashFailed", m.relPath(path), err)))
+		return nil
+	}
+	m.fileOps.push(fileOp{item: item})
+	m.FileTree.Refresh()
+	m.Terminal.Println(i18n.T("fileops.trashed", m.relPath(path)))
+	var gone []string
+	for _, tab := range m.Tabs.GetTabs() {
+		if pathutil.Within(tab.Path, path) {
+			gone = append(gone, tab.Path)
+		}
+	}
+	var cmds []tea.Cmd
+	for _, p := range gone {
+		cmds = append(cmds, m.closeTabFor(p))
+	}
+	return tea.Batch(cmds...)
+}
+
+// renameFile renames or moves from to to, creating to's directory, and
+// keeps the tabs open on it or on files beneath it.
+func (m *Model) renameFile(from, to string) tea.Cmd {
+	from, to = m.resolvePath(from), m.resolvePath(to)
+	if err := m.movePath(from, to); err != nil {
+		m.reportCommandError(err)
+		return nil
+	}
+	m.fileOps.push(fileOp{from: from, to: to})
+	cmd := m.remapPaths(from, to)
+	m.FileTree.Refresh()
+	return cmd
+}
+
+func (m *Model) movePath(from, to string) error {
+	if _, err := os.Lstat(to); err == nil {
+		return errors.New(i18n.T("fileops.exists", m.relPath(to)))
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+// undoFileOp restores the last file trashed, or renames the last file
+// renamed back.
+func (m *Model) undoFileOp() tea.Cmd {
+	if len(m.fileOps.done) == 0 {
+		m.reportCommandError(errors.New(i18n.T("fileops.nothingToUndo")))
+		return nil
+	}
+	op := m.fileOps.done[len(m.fileOps.done)-1]
+	var err error
+	if op.deleted() {
+		err = op.item.Restore()
+	} else {
+		err = m.movePath(op.to, op.from)
+	}
+	if err != nil {
+		m.reportCommandError(fmt.Errorf("%s: %w", i18n.T("fileops.undoFailed"), err))
+		return nil
+	}
+	m.fileOps.done = m.fileOps.done[:len(m.fileOps.done)-1]
+	var cmd tea.Cmd
+	if op.deleted() {
+		m.Terminal.Println(i18n.T("fileops.restored", m.relPath(op.item.Path)))
+	} else {
+		cmd = m.remapPaths(op.to, op.from)
+	}
+	m.FileTree.Refresh()
+	return cmd
+}
+
+// remapPaths moves the tabs, documents and editors of the files at or
+// beneath from over to to after it was renamed, keeping unsaved edits.
+// Language servers see the old documents closed and the ones shown opened
+// under their new names, and the git status is read again.
+func (m *Model) remapPaths(from, to string) tea.Cmd {
+	moved := func(path string) (string, bool) {
+		if !pathutil.Within(path, from) {
+			return path, false
+		}
+		return to + strings.TrimPrefix(path, from), true
+	}
+	var cmds []tea.Cmd
+	for i, tab := range m.Tabs.GetTabs() {
+		path, ok := moved(tab.Path)
+		if !ok {
+			continue
+		}
+		old := tab.Path
+		m.Tabs.UpdateTabPath(i, path)
+		cmds = append(cmds, m.lsp.closeDocument(old))
+		if content, ok := m.tabDocs.journal.Read(old); ok {
+			if m.tabDocs.journal.Write(path, content) == nil {
+				m.tabDocs.journal.Remove(old)
+			}
+		}
+	}
+	docs := make(map[string]*editor.Document, len(m.tabDocs.docs))
+	for old, doc := range m.tabDocs.docs {
+		if path, ok := moved(old); ok {
+			doc.Rename(path)
+			docs[path] = doc
+		} else {
+			docs[old] = doc
+		}
+	}
+	m.tabDocs.docs = docs
+	for _, ed := range m.groups.editors() {
+		if path, ok := moved(ed.FilePath); ok {
+			cmds = append(cmds, m.lsp.closeDocument(ed.FilePath))
+			ed.FilePath = path
+			ed.SetFilePath(path)
+			cmds = append(cmds, m.lsp.openDocument(path, ed.Content()))
+		}
+	}
+	for i, loc := range m.jumps.entries {
+		m.jumps.entries[i].path, _ = moved(loc.path)
+	}
+	expanded := make(map[string]bool, len(m.FileTree.Expanded))
+	for path, open := range m.FileTree.Expanded {
+		path, _ = moved(path)
+		expanded[path] = open
+	}
+	m.FileTree.Expanded = expanded
+	return tea.Batch(append(cmds, m.loadGitHunks(), m.loadBranchStatus())...)
+}
+
+// closeTabFor closes path's tab, showing the tab left active in its place
+// when the editor had path open.
+func (m *Model) closeTabFor(path string) tea.Cmd {
+	idx := m.Tabs.FindTab(path)
+	if idx < 0 {
+		return nil
+	}
+	m.Tabs.CloseTab(idx)
+	m.tabDocs.forget(path)
+	m.jobs.Cancel(path)
+	if m.Editor.FilePath != path {
+		return nil
+	}
+	m.Editor.Detach()
+	m.refreshGutter()
+	if tab := m.Tabs.GetActive(); tab != nil {
+		return m.switchToTab(tab.Index)
+	}
+	return nil
+}
+
+// relPath is path relative to the workspace, for messages.
+func (m *Model) relPath(path string) string {
+	if rel, err := filepath.Rel(pathutil.Canonical(m.RootPath), path); err == nil {
+		return rel
+	}
+	return path
+}
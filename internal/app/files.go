@@ -0,0 +1,30 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newFile creates an empty file at path, relative to the workspace, along
+// with its missing directories, and opens it. An existing file is opened
+// as it is.
+func (m *Model) newFile(path string) tea.Cmd {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.RootPath, path)
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			m.reportCommandError(err)
+			return nil
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			m.reportCommandError(err)
+			return nil
+		}
+		m.FileTree.Refresh()
+	}
+	return m.openFile(path)
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+
+	"tron/internal/editor"
+	"tron/internal/i18n"
+	"tron/internal/terminal"
+	"tron/pkg/layout"
+)
+
+// floatingPanels are the windows that can hover over the layout: a small
+// terminal and a notes file.
+type floatingPanels struct {
+	stack    *layout.FloatStack
+	term     *TerminalPanel
+	notes    *EditorPanel
+	termWin  *layout.Float
+	notesWin *layout.Float
+}
+
+func newFloatingPanels() *floatingPanels {
+	f := &floatingPanels{
+		stack: layout.NewFloatStack(),
+		term:  &TerminalPanel{terminal.New()},
+		notes: &EditorPanel{editor.New()},
+	}
+	// Out-of-range positions are clamped into the bottom and top right
+	// corners once the screen size is known.
+	f.termWin = f.stack.NewFloat(f.term, "", math.MaxInt32, math.MaxInt32, 60, 12)
+	f.notesWin = f.stack.NewFloat(f.notes, "", math.MaxInt32, 1, 48, 16)
+	return f
+}
+
+func (f *floatingPanels) applyTitles() {
+	f.termWin.Title = i18n.T("float.terminal")
+	f.notesWin.Title = i18n.T("float.notes")
+}
+
+// shell returns the terminal commands run in: the floating one while it is
+// shown, the docked one otherwise.
+func (f *floatingPanels) shell(docked *TerminalPanel) *TerminalPanel {
+	if !f.termWin.Hidden {
+		return f.term
+	}
+	return docked
+}
+
+func notesPath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "notes.md")
+}
+
+func (m *Model) toggleFloatingTerminal() {
+	m.floats.stack.Toggle(m.floats.termWin)
+}
+
+func (m *Model) toggleFloatingNotes() {
+	f := m.floats
+	if f.notes.FilePath == "" {
+		path := notesPath(m.RootPath)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			m.reportCommandError(err)
+			return
+		}
+		if file, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0644); err == nil {
+			file.Close()
+		}
+		if err := f.notes.LoadFile(path); err != nil {
+			m.reportCommandError(err)
+			return
+		}
+	}
+	f.stack.Toggle(f.notesWin)
+}
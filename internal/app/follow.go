@@ -0,0 +1,47 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/i18n"
+)
+
+// followInterval is how often an open log file is checked for appended
+// lines.
+const followInterval = time.Second
+
+type followTickMsg struct{}
+
+// followState tells the header whether the active log file is being
+// followed.
+type followState struct {
+	path   string
+	active bool
+}
+
+func followTick() tea.Cmd {
+	return tea.Tick(followInterval, func(time.Time) tea.Msg {
+		return followTickMsg{}
+	})
+}
+
+func (f *followState) update(ed *EditorPanel) {
+	f.path, f.active = ed.FilePath, ed.Following()
+}
+
+func (f *followState) statusText(path string) string {
+	if !f.active || f.path != path {
+		return ""
+	}
+	return i18n.T("follow.status")
+}
+
+func (m *Model) handleFollowTick() tea.Cmd {
+	if m.Editor.FollowFile() {
+		m.refreshGutter()
+	}
+	m.follow.update(m.Editor)
+	return followTick()
+}
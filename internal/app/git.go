@@ -0,0 +1,263 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/editor"
+	"tron/internal/git"
+	"tron/internal/i18n"
+	"tron/internal/jobs"
+	"tron/internal/termcaps"
+	"tron/pkg/pathutil"
+)
+
+// gitState holds the hunks of the file in the editor, refreshed whenever
+// it is opened or saved, periodically and after every hunk action. While
+// the buffer has unsaved changes the unstaged hunks are diffed from it
+// against base, the index version of the file.
+type gitState struct {
+	repo     *git.Repo
+	path     string
+	base     []string
+	unstaged []git.Hunk
+	staged   []git.Hunk
+	panel    *git.Panel
+	log      *git.LogPanel
+	branches *git.BranchPanel
+	branch   git.BranchStatus
+	branchOK bool
+	remote   *gitOperation
+}
+
+type gitRepoMsg struct {
+	repo *git.Repo
+}
+
+type gitHunksMsg struct {
+	path     string
+	base     []string
+	unstaged []git.Hunk
+	staged   []git.Hunk
+}
+
+type gitActionDoneMsg struct {
+	err error
+}
+
+func openGitRepo(rootPath string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), git.CommandTimeout)
+		defer cancel()
+		repo, err := git.Open(ctx, rootPath)
+		if err != nil {
+			return nil
+		}
+		return gitRepoMsg{repo: repo}
+	}
+}
+
+func (m *Model) loadGitHunks() tea.Cmd {
+	repo, path := m.git.repo, m.Editor.FilePath
+	if repo == nil || path == "" || pathutil.IsVirtual(path) {
+		return nil
+	}
+	m.jobs.Cancel(gitHunksJob)
+	return m.jobs.Submit(jobs.Job{Owner: gitHunksJob, Priority: jobs.Normal, Run: func(ctx context.Context, _ func(int, int)) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, git.CommandTimeout)
+		defer cancel()
+		msg := gitHunksMsg{path: path}
+		msg.unstaged, _ = repo.Unstaged(ctx, path)
+		msg.staged, _ = repo.Staged(ctx, path)
+		if content, err := repo.ShowFile(ctx, "", path); err == nil {
+			msg.base = strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+		}
+		return msg
+	}})
+}
+
+func (m *Model) handleGitHunks(msg gitHunksMsg) {
+	if msg.path != m.Editor.FilePath {
+		return
+	}
+	m.git.path, m.git.base, m.git.unstaged, m.git.staged = msg.path, msg.base, msg.unstaged, msg.staged
+	m.diffBuffer()
+}
+
+// diffBuffer updates the unstaged hunks for edits not yet on disk. Saved
+// buffers keep the hunks git computed, which the hunk actions rely on.
+func (m *Model) diffBuffer() {
+	if m.git.path != m.Editor.FilePath {
+		return
+	}
+	if m.git.base != nil && m.Editor.IsDirty() {
+		m.git.unstaged = git.DiffLines(m.git.base, m.Editor.Buffer.Lines())
+	}
+	m.refreshGitMarkers()
+}
+
+func (m *Model) refreshGitMarkers() {
+	markers := make(map[int]editor.GutterMarker)
+	if m.git.path == m.Editor.FilePath {
+		for _, h := range m.git.staged {
+			m.markHunk(markers, h.InWorktree(m.git.unstaged), "#6c7086")
+		}
+		for _, h := range m.git.unstaged {
+			color := map[git.HunkKind]string{git.HunkAdded: "#a6e3a1", git.HunkModified: "#f9e2af", git.HunkDeleted: "#f38ba8"}[h.Kind()]
+			m.markHunk(markers, h, color)
+		}
+	}
+	m.Editor.SetGutterMarkers("git", markers)
+}
+
+func (m *Model) markHunk(markers map[int]editor.GutterMarker, h git.Hunk, color string) {
+	symbol := "▎"
+	ascii := m.accessibility.Enabled || !termcaps.Current().Unicode
+	if ascii {
+		symbol = map[git.HunkKind]string{git.HunkAdded: "+", git.HunkModified: "~", git.HunkDeleted: "_"}[h.Kind()]
+	}
+	if h.Kind() == git.HunkDeleted {
+		if !ascii {
+			symbol = "▁"
+		}
+		markers[max(h.NewStart-1, 0)] = editor.GutterMarker{Symbol: symbol, Color: color}
+		return
+	}
+	for line := h.NewStart - 1; line < h.NewStart-1+h.NewLines; line++ {
+		markers[line] = editor.GutterMarker{Symbol: symbol, Color: color}
+	}
+}
+
+// jumpToHunk moves the cursor to the next changed region after the cursor
+// line, or the previous one before it when dir is negative, wrapping
+// around at the ends of the file.
+func (m *Model) jumpToHunk(dir int) {
+	if m.git.path != m.Editor.FilePath {
+		return
+	}
+	var starts []int
+	for _, h := range m.git.unstaged {
+		starts = append(starts, max(h.NewStart-1, 0))
+	}
+	for _, h := range m.git.staged {
+		starts = append(starts, max(h.InWorktree(m.git.unstaged).NewStart-1, 0))
+	}
+	if len(starts) == 0 {
+		return
+	}
+	sort.Ints(starts)
+	line := m.Editor.Cursor.Line
+	target := starts[0]
+	if dir < 0 {
+		target = starts[len(starts)-1]
+	}
+	for i := range starts {
+		s := starts[i]
+		if dir < 0 {
+			s = starts[len(starts)-1-i]
+		}
+		if (dir > 0 && s > line) || (dir < 0 && s < line) {
+			target = s
+			break
+		}
+	}
+	m.Editor.GoToLine(target)
+}
+
+func hunkAt(hunks []git.Hunk, line int) (int, bool) {
+	for i, h := range hunks {
+		if h.Contains(line) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// gitHunkAction runs fn on the hunk under the cursor. Hunk positions come
+// from the file on disk, so the buffer has to be saved first.
+func (m *Model) gitHunkAction(staged bool, fn func(ctx context.Context, repo *git.Repo, path string, h git.Hunk) error) tea.Cmd {
+	if m.git.repo == nil || m.git.path != m.Editor.FilePath {
+		return nil
+	}
+	if m.Editor.IsDirty() {
+		m.reportCommandError(errors.New(i18n.T("git.saveFirst")))
+		return nil
+	}
+	line := m.Editor.Cursor.Line
+	var h git.Hunk
+	if staged {
+		for _, s := range m.git.staged {
+			if s.InWorktree(m.git.unstaged).Contains(line) {
+				h = s
+				staged = false
+				break
+			}
+		}
+		if staged {
+			return nil
+		}
+	} else {
+		i, ok := hunkAt(m.git.unstaged, line)
+		if !ok {
+			return nil
+		}
+		h = m.git.unstaged[i]
+	}
+	repo, path := m.git.repo, m.git.path
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), git.CommandTimeout)
+		defer cancel()
+		return gitActionDoneMsg{err: fn(ctx, repo, path, h)}
+	}
+}
+
+func (m *Model) stageHunk() tea.Cmd {
+	return m.gitHunkAction(false, func(ctx context.Context, repo *git.Repo, path string, h git.Hunk) error {
+		return repo.StageHunk(ctx, path, h)
+	})
+}
+
+func (m *Model) unstageHunk() tea.Cmd {
+	return m.gitHunkAction(true, func(ctx context.Context, repo *git.Repo, path string, h git.Hunk) error {
+		return repo.UnstageHunk(ctx, path, h)
+	})
+}
+
+// revertHunk restores the index version of the hunk under the cursor in the
+// buffer, leaving the change unsaved.
+func (m *Model) revertHunk() tea.Cmd {
+	if m.git.path != m.Editor.FilePath || m.Editor.IsDirty() {
+		return nil
+	}
+	i, ok := hunkAt(m.git.unstaged, m.Editor.Cursor.Line)
+	if !ok {
+		return nil
+	}
+	h := m.git.unstaged[i]
+	start := h.NewStart - 1
+	if h.Kind() == git.HunkDeleted {
+		start = h.NewStart
+	}
+	cmd := m.Editor.ReplaceLines(start, start+h.NewLines, h.Removed())
+
+	shift := h.OldLines - h.NewLines
+	hunks := append([]git.Hunk{}, m.git.unstaged[:i]...)
+	for _, later := range m.git.unstaged[i+1:] {
+		later.NewStart += shift
+		hunks = append(hunks, later)
+	}
+	m.git.unstaged = hunks
+	m.refreshGitMarkers()
+	return cmd
+}
+
+func (m *Model) handleGitActionDone(msg gitActionDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		m.reportCommandError(msg.err)
+	}
+	return m.loadGitHunks()
+}
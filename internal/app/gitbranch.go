@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/git"
+	"tron/internal/i18n"
+)
+
+// gitStatusInterval is how often the branch status is refreshed to catch
+// commits and checkouts made outside the editor.
+const gitStatusInterval = 10 * time.Second
+
+type gitStatusMsg struct {
+	status git.BranchStatus
+	ok     bool
+}
+
+type gitStatusTickMsg struct{}
+
+type openBranchPickerMsg struct{}
+
+type gitBranchesMsg struct {
+	branches []git.Branch
+}
+
+type gitBranchDoneMsg struct {
+	err error
+}
+
+func (g *gitState) branchText() string {
+	if !g.branchOK {
+		return ""
+	}
+	return g.branch.String()
+}
+
+func gitStatusTick() tea.Cmd {
+	return tea.Tick(gitStatusInterval, func(time.Time) tea.Msg {
+		return gitStatusTickMsg{}
+	})
+}
+
+func (m *Model) loadBranchStatus() tea.Cmd {
+	repo := m.git.repo
+	if repo == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), git.CommandTimeout)
+		defer cancel()
+		status, err := repo.Status(ctx)
+		return gitStatusMsg{status: status, ok: err == nil}
+	}
+}
+
+func (m *Model) openBranchPicker() tea.Cmd {
+	repo := m.git.repo
+	if repo == nil {
+		m.reportCommandError(errors.New(i18n.T("git.noRepo")))
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), git.CommandTimeout)
+		defer cancel()
+		branches, err := repo.Branches(ctx)
+		if err != nil {
+			return gitBranchDoneMsg{err: err}
+		}
+		return gitBranchesMsg{branches: branches}
+	}
+}
+
+func (m *Model) runBranchAction(msg git.BranchMsg) tea.Cmd {
+	repo := m.git.repo
+	if repo == nil || msg.Name == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), git.CommandTimeout)
+		defer cancel()
+		var err error
+		switch msg.Action {
+		case git.CheckoutBranch:
+			err = repo.Checkout(ctx, msg.Name)
+		case git.CreateBranch:
+			err = repo.CreateBranch(ctx, msg.Name)
+		case git.DeleteBranch:
+			err = repo.DeleteBranch(ctx, msg.Name)
+		}
+		return gitBranchDoneMsg{err: err}
+	}
+}
+
+func (m *Model) handleBranchDone(msg gitBranchDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		m.reportCommandError(msg.err)
+	}
+	return m.afterWorktreeChange()
+}
@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/git"
+	"tron/internal/i18n"
+)
+
+// gitOperation is a running fetch, pull or push. Its goroutine reports
+// progress and credential prompts on events and closes it when git exits.
+type gitOperation struct {
+	op       git.RemoteOp
+	events   chan tea.Msg
+	progress string
+}
+
+type gitStashesMsg struct {
+	stashes []git.Stash
+}
+
+type gitStashDoneMsg struct {
+	err error
+}
+
+type gitProgressMsg struct {
+	text string
+}
+
+type gitPromptMsg struct {
+	prompt git.Prompt
+}
+
+type gitRemoteDoneMsg struct {
+	op  git.RemoteOp
+	err error
+}
+
+func (g *gitState) statusText() string {
+	if g.remote == nil {
+		return ""
+	}
+	if g.remote.progress == "" {
+		return i18n.T("git.running", g.remote.op)
+	}
+	return "git " + string(g.remote.op) + ": " + g.remote.progress
+}
+
+func (m *Model) toggleGitPanel() tea.Cmd {
+	if m.git.repo == nil {
+		m.reportCommandError(errors.New(i18n.T("git.noRepo")))
+		return nil
+	}
+	m.git.panel.Toggle()
+	if !m.git.panel.Visible() {
+		return nil
+	}
+	return m.loadStashes()
+}
+
+// openGitLog shows the history of the open file, or of the repository when
+// no file is open.
+func (m *Model) openGitLog(fileOnly bool) tea.Cmd {
+	if m.git.repo == nil {
+		m.reportCommandError(errors.New(i18n.T("git.noRepo")))
+		return nil
+	}
+	file := m.Editor.FilePath
+	if !fileOnly {
+		file = ""
+	}
+	return m.git.log.Open(m.git.repo, file)
+}
+
+func (m *Model) loadStashes() tea.Cmd {
+	repo := m.git.repo
+	if repo == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), git.CommandTimeout)
+		defer cancel()
+		stashes, _ := repo.Stashes(ctx)
+		return gitStashesMsg{stashes: stashes}
+	}
+}
+
+func (m *Model) runStashAction(msg git.StashMsg) tea.Cmd {
+	repo := m.git.repo
+	if repo == nil {
+		return nil
+	}
+	if msg.Action == git.SaveStash && m.Editor.IsDirty() {
+		m.reportCommandError(errors.New(i18n.T("git.saveFirstStash")))
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), git.CommandTimeout)
+		defer cancel()
+		var err error
+		switch msg.Action {
+		case git.SaveStash:
+			err = repo.StashSave(ctx, msg.Message)
+		case git.PopStash:
+			err = repo.StashPop(ctx, msg.Ref)
+		case git.ApplyStash:
+			err = repo.StashApply(ctx, msg.Ref)
+		case git.DropStash:
+			err = repo.StashDrop(ctx, msg.Ref)
+		}
+		return gitStashDoneMsg{err: err}
+	}
+}
+
+func (m *Model) handleStashDone(msg gitStashDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		m.reportCommandError(msg.err)
+	}
+	return m.afterWorktreeChange()
+}
+
+// afterWorktreeChange picks up files git rewrote, reloading open files
+// unless they have unsaved edits.
+func (m *Model) afterWorktreeChange() tea.Cmd {
+	m.tabDocs.dropStale()
+	return tea.Batch(m.Editor.ReloadChanged(), m.FileTree.Load(), m.loadStashes(), m.loadGitHunks(), m.loadBranchStatus())
+}
+
+// runRemote starts op unless another remote operation is still running.
+// Credential prompts are answered in the git panel.
+func (m *Model) runRemote(op git.RemoteOp) tea.Cmd {
+	repo := m.git.repo
+	if repo == nil {
+		m.reportCommandError(errors.New(i18n.T("git.noRepo")))
+		return nil
+	}
+	if m.git.remote != nil {
+		m.reportCommandError(errors.New(i18n.T("git.busy", m.git.remote.op)))
+		return nil
+	}
+	askpass, err := git.NewAskpass()
+	if err != nil {
+		m.reportCommandError(err)
+		return nil
+	}
+
+	events := make(chan tea.Msg, 64)
+	m.git.remote = &gitOperation{op: op, events: events}
+	m.git.panel.SetProgress(i18n.T("git.running", op))
+	go func() {
+		defer close(events)
+		defer askpass.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), git.RemoteTimeout)
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- repo.Remote(ctx, op, askpass.Env(), func(line string) {
+				events <- gitProgressMsg{text: line}
+			})
+		}()
+		for {
+			select {
+			case prompt := <-askpass.Prompts():
+				events <- gitPromptMsg{prompt: prompt}
+			case err := <-done:
+				events <- gitRemoteDoneMsg{op: op, err: err}
+				return
+			}
+		}
+	}()
+	return listenGitRemote(events)
+}
+
+func listenGitRemote(events chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+func (m *Model) handleGitRemoteEvent(msg tea.Msg) tea.Cmd {
+	if m.git.remote == nil {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case gitProgressMsg:
+		m.git.remote.progress = msg.text
+		m.git.panel.SetProgress(msg.text)
+	case gitPromptMsg:
+		m.git.panel.Ask(msg.prompt)
+	case gitRemoteDoneMsg:
+		m.git.remote = nil
+		m.git.panel.CancelPrompt()
+		if msg.err != nil {
+			m.git.panel.SetProgress(i18n.T("git.failed", msg.op))
+			m.reportCommandError(msg.err)
+		} else {
+			m.git.panel.SetProgress(i18n.T("git.finished", msg.op))
+		}
+		if msg.op == git.Pull {
+			return m.afterWorktreeChange()
+		}
+		return m.loadBranchStatus()
+	}
+	return listenGitRemote(m.git.remote.events)
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"path/filepath"
+
+	"tron/internal/runconfig"
+)
+
+func (h *headerPanel) goStatusText(path string) string {
+	if h.goModule == "" || (filepath.Ext(path) != ".go" && filepath.Base(path) != "go.mod") {
+		return ""
+	}
+	text := "go " + h.goModule
+	if platform := h.runBar.GetManager().GoEnv().Platform(); platform != "" {
+		text += " " + platform
+	}
+	return text
+}
+
+func (m *Model) openGoEnv() {
+	if m.header.goModule == "" {
+		return
+	}
+	m.goEnvPanel.Open(m.RunBar.GetManager().GoEnv())
+}
+
+func (m *Model) setGoEnv(env runconfig.GoEnv) {
+	m.RunBar.GetManager().SetGoEnv(env)
+	_ = runconfig.SaveGoEnv(m.RootPath, env)
+}
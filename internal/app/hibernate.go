@@ -0,0 +1,162 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/editor"
+	"tron/internal/recovery"
+	"tron/pkg/pathutil"
+)
+
+const defaultTabMemoryLimit = 8
+
+// tabDocuments holds the in-memory documents of inactive tabs. Only the
+// most recently used tabs keep theirs; the rest are hibernated and reloaded
+// from disk, or from the recovery journal when they had unsaved changes.
+type tabDocuments struct {
+	rootPath string
+	docs     map[string]*editor.Document
+	virtual  map[string]virtualDoc
+	limit    int
+	journal  *recovery.Journal
+}
+
+type tabSettings struct {
+	MemoryLimit int `json:"memoryLimit"`
+}
+
+func tabSettingsPath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "tabs.json")
+}
+
+func newTabDocuments(rootPath string) *tabDocuments {
+	t := &tabDocuments{
+		rootPath: rootPath,
+		docs:     make(map[string]*editor.Document),
+		virtual:  make(map[string]virtualDoc),
+		limit:    defaultTabMemoryLimit,
+		journal:  recovery.New(rootPath),
+	}
+	if data, err := os.ReadFile(tabSettingsPath(rootPath)); err == nil {
+		var s tabSettings
+		if json.Unmarshal(data, &s) == nil && s.MemoryLimit > 0 {
+			t.limit = s.MemoryLimit
+		}
+	}
+	return t
+}
+
+func (t *tabDocuments) setLimit(limit int) error {
+	t.limit = max(limit, 1)
+	path := tabSettingsPath(t.rootPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tabSettings{MemoryLimit: t.limit}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (t *tabDocuments) forget(path string) {
+	path = pathutil.Canonical(path)
+	delete(t.docs, path)
+	delete(t.virtual, path)
+	t.journal.Remove(path)
+}
+
+// dropStale forgets unedited documents whose file changed on disk, so
+// their tabs load the new content when next shown.
+func (t *tabDocuments) dropStale() {
+	for path, doc := range t.docs {
+		if doc.Dirty() {
+			continue
+		}
+		if data, err := os.ReadFile(path); err == nil && string(data) != doc.FileContent() {
+			delete(t.docs, path)
+		}
+	}
+}
+
+// stashActiveDocument detaches the editor's document before another file is
+// loaded, keeping it in memory while its tab is open.
+func (m *Model) stashActiveDocument() tea.Cmd {
+	return m.stashDocument(m.Editor)
+}
+
+// stashDocument detaches ed's document, keeping it in memory while its tab
+// is open.
+func (m *Model) stashDocument(ed *EditorPanel) tea.Cmd {
+	path := ed.FilePath
+	if path == "" {
+		return nil
+	}
+	var cmd tea.Cmd
+	if ed.Flush() != nil {
+		cmd = m.lsp.changeDocument(path, ed.Content(), ed.TakeChanges())
+	}
+	if m.editing.AutosaveOnFocusLoss {
+		cmd = tea.Batch(cmd, ed.AutoSave())
+	}
+	doc := ed.Detach()
+	if m.Tabs.FindTab(path) >= 0 {
+		m.tabDocs.docs[path] = doc
+	}
+	return cmd
+}
+
+// loadDocument makes path the editor's document, preferring the in-memory
+// copy, then journaled unsaved content, then the file on disk.
+func (m *Model) loadDocument(path string) error {
+	path = pathutil.Canonical(path)
+	defer m.hibernateTabs()
+
+	if doc, ok := m.tabDocs.docs[path]; ok {
+		delete(m.tabDocs.docs, path)
+		m.Editor.Attach(doc)
+		return nil
+	}
+	if v, ok := m.tabDocs.virtual[path]; ok {
+		m.Editor.LoadVirtual(path, v.name, v.content)
+		return nil
+	}
+	if content, ok := m.tabDocs.journal.Read(path); ok {
+		m.Editor.LoadRecovered(path, content)
+		m.tabDocs.journal.Remove(path)
+		m.syncEditorDirtyState()
+		return nil
+	}
+	if err := m.Editor.LoadFile(path); err != nil {
+		m.Editor.SetContent("")
+		m.Editor.FilePath = path
+		return err
+	}
+	return nil
+}
+
+// hibernateTabs drops the documents of tabs beyond the memory limit in MRU
+// order, journaling unsaved content first. A document whose content cannot
+// be journaled stays in memory. Virtual documents are shown afresh from
+// their content, losing any edits.
+func (m *Model) hibernateTabs() {
+	for i, tab := range m.Tabs.MRU() {
+		if i < m.tabDocs.limit {
+			continue
+		}
+		doc, ok := m.tabDocs.docs[tab.Path]
+		if !ok {
+			continue
+		}
+		if doc.Dirty() && !pathutil.IsVirtual(tab.Path) {
+			if err := m.tabDocs.journal.Write(doc.Path(), doc.Content()); err != nil {
+				continue
+			}
+		}
+		delete(m.tabDocs.docs, tab.Path)
+	}
+}
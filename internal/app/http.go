@@ -0,0 +1,23 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/editor"
+	"tron/internal/httprunner"
+)
+
+func (m *Model) refreshHTTPMarkers() {
+	markers := make(map[int]editor.GutterMarker)
+	if httprunner.IsHTTPFile(m.Editor.FilePath) {
+		for _, req := range httprunner.Parse(m.Editor.Content()) {
+			markers[req.StartLine] = editor.GutterMarker{Symbol: "▶", Color: "#a6e3a1"}
+		}
+	}
+	m.Editor.SetGutterMarkers("http", markers)
+}
+
+func (m *Model) sendHTTPRequest() tea.Cmd {
+	req := httprunner.RequestAt(httprunner.Parse(m.Editor.Content()), m.Editor.Cursor.Line)
+	return m.httpPanel.Send(req)
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tron/internal/jobs"
+)
+
+// Owners of background jobs that are not tied to a document. Submitting
+// one cancels the previous, whose result would be stale.
+const (
+	gitHunksJob   = "git.hunks"
+	suggestionJob = "suggest"
+)
+
+// jobsStatusText lists the titled jobs running in the background, with
+// their progress when they report it.
+func jobsStatusText(pool *jobs.Pool) string {
+	var parts []string
+	for _, s := range pool.Running() {
+		switch {
+		case s.Title == "":
+		case s.Total > 0:
+			parts = append(parts, fmt.Sprintf("%s %d/%d", s.Title, s.Done, s.Total))
+		default:
+			parts = append(parts, s.Title+"…")
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/editor"
+)
+
+// jumpLimit is how many locations the jump list keeps.
+const jumpLimit = 100
+
+type jumpLocation struct {
+	path string
+	pos  editor.Position
+}
+
+// jumpList is the cursor locations jumps left, across files, oldest
+// first. index is the entry back and forward step from; it is past the
+// end until the first step back.
+type jumpList struct {
+	entries []jumpLocation
+	index   int
+}
+
+// push records loc as the place a jump leaves, dropping the locations
+// stepped back past. A location on the same line as the newest is not
+// recorded twice.
+func (j *jumpList) push(loc jumpLocation) {
+	j.entries = j.entries[:min(j.index, len(j.entries))]
+	if n := len(j.entries); n > 0 && j.entries[n-1].path == loc.path && j.entries[n-1].pos.Line == loc.pos.Line {
+		j.index = n
+		return
+	}
+	j.entries = append(j.entries, loc)
+	if len(j.entries) > jumpLimit {
+		j.entries = j.entries[len(j.entries)-jumpLimit:]
+	}
+	j.index = len(j.entries)
+}
+
+// back steps to the previous location. Stepping back from the newest
+// records cur first so forward can return to it.
+func (j *jumpList) back(cur jumpLocation) (jumpLocation, bool) {
+	if j.index >= len(j.entries) {
+		j.push(cur)
+		j.index--
+	}
+	if j.index <= 0 {
+		return jumpLocation{}, false
+	}
+	j.index--
+	return j.entries[j.index], true
+}
+
+func (j *jumpList) forward() (jumpLocation, bool) {
+	if j.index >= len(j.entries)-1 {
+		return jumpLocation{}, false
+	}
+	j.index++
+	return j.entries[j.index], true
+}
+
+func (m *Model) cursorLocation() jumpLocation {
+	return jumpLocation{path: m.Editor.FilePath, pos: m.Editor.Cursor}
+}
+
+// recordJump remembers the cursor's location before a jump moves it.
+func (m *Model) recordJump() {
+	if m.Editor.FilePath != "" {
+		m.jumps.push(m.cursorLocation())
+	}
+}
+
+// jumpBack returns to the location before the last jump.
+func (m *Model) jumpBack() tea.Cmd {
+	loc, ok := m.jumps.back(m.cursorLocation())
+	if !ok {
+		return nil
+	}
+	return m.showLocation(loc)
+}
+
+func (m *Model) jumpForward() tea.Cmd {
+	loc, ok := m.jumps.forward()
+	if !ok {
+		return nil
+	}
+	return m.showLocation(loc)
+}
+
+// showLocation shows loc without recording a jump, switching to its tab
+// or reopening the file when the tab was closed.
+func (m *Model) showLocation(loc jumpLocation) tea.Cmd {
+	var cmd tea.Cmd
+	if loc.path != m.Editor.FilePath {
+		cmd = m.showFile(loc.path)
+	}
+	m.Editor.GoTo(loc.pos)
+	return cmd
+}
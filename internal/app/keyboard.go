@@ -0,0 +1,71 @@
+package app
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/i18n"
+	"tron/internal/keyboard"
+	"tron/internal/termcaps"
+)
+
+// keyboardState is what the terminal said about the kitty keyboard
+// protocol. Legacy input is assumed until it answers.
+type keyboardState struct {
+	answered  bool
+	supported bool
+	flags     int
+}
+
+func (m *Model) handleKeyboardReport(msg keyboard.ReportMsg) {
+	// The device attributes reply always follows the protocol one, so it
+	// only settles the question when nothing came before it.
+	if m.keyboard.answered && !msg.Supported {
+		return
+	}
+	m.keyboard = keyboardState{answered: true, supported: msg.Supported, flags: msg.Flags}
+}
+
+// handleExtendedKey runs the command bound to an extended key, or handles
+// the key a legacy terminal would have sent in its place.
+func (m Model) handleExtendedKey(msg keyboard.ExtendedKeyMsg) (tea.Model, tea.Cmd) {
+	inv, ok := m.keymap[msg.Key]
+	// The pending switcher takes keys, but moves on when its own key is
+	// pressed again.
+	if ok && (!m.keysCaptured(msg.Base) || m.Tabs.SwitcherActive() && inv.Name == "tabs.quickSwitch") {
+		return m, m.execute(inv)
+	}
+	if !msg.HasBase {
+		return m, nil
+	}
+	return m.Update(msg.Base)
+}
+
+// keysCaptured reports whether a panel or prompt takes keys before the
+// keymap.
+func (m Model) keysCaptured(msg tea.KeyMsg) bool {
+	if m.Tabs.CapturesInput() || m.FileTree.CapturesInput() || m.floats.stack.Focused() != nil || m.Editor.CapturesKey(msg) {
+		return true
+	}
+	for _, panel := range m.overlays() {
+		if panel.Visible() {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Model) showDiagnostics() {
+	k := m.keyboard
+	switch {
+	case !k.answered:
+		m.Terminal.Println(i18n.T("diagnostics.keyboardUnknown"))
+	case k.supported:
+		m.Terminal.Println(i18n.T("diagnostics.keyboardKitty", k.flags, keyboard.Flags))
+	default:
+		m.Terminal.Println(i18n.T("diagnostics.keyboardLegacy"))
+	}
+	m.Terminal.Println(i18n.T("diagnostics.term", os.Getenv("TERM"), m.Width, m.Height))
+	m.Terminal.Println(describeCaps(termcaps.Current()))
+}
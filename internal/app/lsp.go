@@ -0,0 +1,253 @@
+package app
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/editor"
+	"tron/internal/lsp"
+	"tron/internal/remote"
+	"tron/pkg/pathutil"
+)
+
+// openVersion is a document's version when it is opened; each change sent
+// after counts up from it.
+const openVersion = 1
+
+type lspStartedMsg struct {
+	languageID string
+	client     *lsp.Client
+	path       string
+	err        error
+}
+
+type lspState struct {
+	rootPath string
+	remote   *remote.Remote
+	clients  map[string]*lsp.Client
+	starting map[string]bool
+	versions map[string]int
+	settings map[string]map[string]interface{}
+	// compileDBTried is set once CMake was run to write a compilation
+	// database for clangd.
+	compileDBTried bool
+}
+
+func newLSPState(rootPath string, r *remote.Remote) *lspState {
+	return &lspState{
+		rootPath: rootPath,
+		remote:   r,
+		clients:  make(map[string]*lsp.Client),
+		starting: make(map[string]bool),
+		versions: make(map[string]int),
+		settings: make(map[string]map[string]interface{}),
+	}
+}
+
+func (l *lspState) clientFor(path string) *lsp.Client {
+	if pathutil.IsVirtual(path) {
+		return nil
+	}
+	return l.clients[lsp.LanguageID(path)]
+}
+
+func (l *lspState) openDocument(path, content string) tea.Cmd {
+	if pathutil.IsVirtual(path) {
+		return nil
+	}
+	languageID := lsp.LanguageID(path)
+	if client := l.clients[languageID]; client != nil {
+		if _, opened := l.versions[path]; opened {
+			return nil
+		}
+		l.versions[path] = openVersion
+		return func() tea.Msg {
+			_ = client.OpenDocument(path, content, openVersion)
+			return nil
+		}
+	}
+
+	if l.starting[languageID] {
+		return nil
+	}
+	args, ok := lsp.ServerCommand(languageID)
+	if !ok {
+		return nil
+	}
+	clangd := args[0] == "clangd"
+	if clangd {
+		if cmd := l.generateCompileDB(languageID); cmd != nil {
+			return cmd
+		}
+	}
+	l.starting[languageID] = true
+
+	rootPath := l.rootPath
+	r := l.remote
+	settings := l.settings[languageID]
+	if r != nil {
+		args = r.ExecArgs(args, rootPath)
+	} else if clangd {
+		args = clangdArgs(args, rootPath)
+	}
+	return func() tea.Msg {
+		client := lsp.NewWithArgs(args[0], args[1:])
+		if r != nil {
+			client.SetPathMapper(r)
+		}
+		if settings != nil {
+			client.SetSettings(settings)
+		}
+		if err := client.Start(rootPath); err != nil {
+			return lspStartedMsg{languageID: languageID, err: err}
+		}
+		if err := client.Initialize(rootPath); err != nil {
+			client.Stop()
+			return lspStartedMsg{languageID: languageID, err: err}
+		}
+		_ = client.OpenDocument(path, content, openVersion)
+		return lspStartedMsg{languageID: languageID, client: client, path: path}
+	}
+}
+
+// closeDocument tells path's language server the document is closed, so
+// the next openDocument opens it afresh.
+func (l *lspState) closeDocument(path string) tea.Cmd {
+	if _, opened := l.versions[path]; !opened {
+		return nil
+	}
+	delete(l.versions, path)
+	client := l.clientFor(path)
+	if client == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = client.CloseDocument(path)
+		return nil
+	}
+}
+
+func (l *lspState) handleStarted(msg lspStartedMsg) {
+	delete(l.starting, msg.languageID)
+	if msg.err != nil || msg.client == nil {
+		return
+	}
+	l.clients[msg.languageID] = msg.client
+	l.versions[msg.path] = openVersion
+}
+
+// changeDocument sends changes to the language server, or the whole
+// content when changes is nil or the server wants it. The first change
+// after opening always sends the content, since the open may have raced
+// edits already in changes.
+func (l *lspState) changeDocument(path, content string, changes []editor.ChangeEvent) tea.Cmd {
+	client := l.clientFor(path)
+	if client == nil {
+		return nil
+	}
+	l.versions[path]++
+	version := l.versions[path]
+	if changes == nil || version <= openVersion+1 || !client.IncrementalSync() {
+		return func() tea.Msg {
+			_ = client.DidChangeDocument(path, content, version)
+			return nil
+		}
+	}
+	events := make([]lsp.TextDocumentContentChangeEvent, len(changes))
+	for i, c := range changes {
+		r := c.UTF16Range
+		events[i] = lsp.TextDocumentContentChangeEvent{
+			Range: &lsp.Range{
+				Start: lsp.Position{Line: r.Start.Line, Character: r.Start.Column},
+				End:   lsp.Position{Line: r.End.Line, Character: r.End.Column},
+			},
+			Text: c.NewText,
+		}
+	}
+	return func() tea.Msg {
+		_ = client.DidChangeRanges(path, events, version)
+		return nil
+	}
+}
+
+func (l *lspState) hover(msg editor.HoverRequestMsg) tea.Cmd {
+	client := l.clientFor(msg.Path)
+	if client == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		line, col := msg.Position.Line, msg.Position.Column
+		for _, d := range client.CurrentDiagnostics(msg.Path) {
+			if d.Contains(line, col) {
+				return editor.HoverResultMsg{Seq: msg.Seq, Text: d.Message}
+			}
+		}
+		text, err := client.Hover(msg.Path, line, col)
+		if err != nil {
+			return nil
+		}
+		return editor.HoverResultMsg{Seq: msg.Seq, Text: text}
+	}
+}
+
+// complete asks req's language server for completions. Run it after the
+// edit that called for them was sent, so the server has the text the
+// version says; results for older versions are dropped.
+func (l *lspState) complete(req editor.CompletionRequest) tea.Cmd {
+	client := l.clientFor(req.Path)
+	if client == nil {
+		return nil
+	}
+	version := l.versions[req.Path]
+	return func() tea.Msg {
+		res, err := client.RequestCompletions(req.Path, req.Position.Line, req.Character, version)
+		if err != nil {
+			return nil
+		}
+		sort.SliceStable(res.Items, func(i, j int) bool {
+			return sortText(res.Items[i]) < sortText(res.Items[j])
+		})
+		items := make([]editor.CompletionItem, len(res.Items))
+		for i, item := range res.Items {
+			items[i] = editor.CompletionItem{Label: item.Label, Detail: item.Detail, Text: item.Text()}
+		}
+		return editor.CompletionResultMsg{Path: req.Path, Seq: req.Seq, Items: items}
+	}
+}
+
+func sortText(item lsp.CompletionItem) string {
+	if item.SortText != "" {
+		return item.SortText
+	}
+	return item.Label
+}
+
+func (l *lspState) shutdown() {
+	for _, client := range l.clients {
+		client.Stop()
+	}
+}
+
+func (l *lspState) restart() {
+	l.shutdown()
+	l.clients = make(map[string]*lsp.Client)
+	l.versions = make(map[string]int)
+}
+
+// configure sets the settings for a language's server, restarting it if it
+// is already running so they take effect.
+func (l *lspState) configure(languageID string, settings map[string]interface{}) {
+	l.settings[languageID] = settings
+	client := l.clients[languageID]
+	if client == nil {
+		return
+	}
+	client.Stop()
+	delete(l.clients, languageID)
+	for path := range l.versions {
+		if lsp.LanguageID(path) == languageID {
+			delete(l.versions, path)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package app
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"tron/internal/command"
+	"tron/internal/editor"
+	"tron/internal/i18n"
+	"tron/internal/termcaps"
+	"tron/pkg/layout"
+	"tron/pkg/pathutil"
+)
+
+const (
+	splitButton = "⧉"
+	closeButton = "×"
+)
+
+var (
+	paneTitleStyle       = lipgloss.NewStyle().Background(lipgloss.Color("#181825")).Foreground(lipgloss.Color("#6c7086"))
+	activePaneTitleStyle = lipgloss.NewStyle().Background(lipgloss.Color("#313244")).Foreground(lipgloss.Color("#cdd6f4"))
+	dropPaneTitleStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	paneModifiedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+	paneBadgeStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	paneModeStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).Bold(true)
+)
+
+type groupFocusMsg struct {
+	index int
+}
+
+type groupSplitMsg struct {
+	index int
+}
+
+type groupCloseMsg struct {
+	index int
+}
+
+type groupMoveMsg struct {
+	from int
+	to   int
+}
+
+// editorPane is one editor group: an editor under a title bar with the
+// file's path and state and buttons to split and close the group.
+type editorPane struct {
+	editor   *EditorPanel
+	rootPath string
+	width    int
+	height   int
+	active   bool
+	dropping bool
+	closable bool
+	keymap   map[string]command.Invocation
+}
+
+func (p *editorPane) SetSize(w, h int) {
+	p.width, p.height = w, h
+	p.editor.SetSize(w, max(h-1, 0))
+}
+
+func (p *editorPane) Update(msg tea.Msg) tea.Cmd {
+	// Without a file there is nothing to edit; typing into the bare buffer
+	// would only produce text that cannot be saved anywhere.
+	if p.editor.FilePath == "" {
+		switch msg.(type) {
+		case tea.KeyMsg, tea.MouseMsg:
+			return nil
+		}
+	}
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		mouse.Y--
+		msg = mouse
+	}
+	return p.editor.Update(msg)
+}
+
+func (p *editorPane) View() string {
+	if p.editor.FilePath == "" {
+		return p.titleBar() + "\n" + p.emptyView()
+	}
+	return p.titleBar() + "\n" + p.editor.View()
+}
+
+func (p *editorPane) titleBar() string {
+	style := paneTitleStyle
+	switch {
+	case p.dropping:
+		style = dropPaneTitleStyle
+	case p.active:
+		style = activePaneTitleStyle
+	}
+
+	ed := p.editor
+	title := i18n.T("pane.untitled")
+	if ed.FilePath != "" {
+		title = ed.FilePath
+		if rel, err := filepath.Rel(p.rootPath, ed.FilePath); err == nil && !strings.HasPrefix(rel, "..") {
+			title = rel
+		}
+	}
+	left := " " + title
+	if ed.IsDirty() {
+		left += " " + paneModifiedStyle.Inherit(style).Render(termcaps.Symbol("●", "*"))
+	}
+	if ed.ReadOnly {
+		left += " " + paneBadgeStyle.Inherit(style).Render(i18n.T("pane.readOnly"))
+	}
+	if ed.BlockSelection {
+		left += " " + paneModeStyle.Inherit(style).Render(i18n.T("pane.block"))
+	}
+	if ed.ScrollLocked() {
+		left += " " + paneModeStyle.Inherit(style).Render(i18n.T("pane.scrollLocked"))
+	}
+	if mode := ed.VimMode(); mode != "" {
+		left += " " + paneModeStyle.Inherit(style).Render(i18n.T("vim."+mode))
+	}
+
+	right := termcaps.Symbol(splitButton, "+") + " "
+	if ed.FilePath != "" && !pathutil.IsVirtual(ed.FilePath) {
+		right = indentLabel(ed.Editor) + "  " + ed.LineEnding.String() + "  " + right
+	}
+	if p.closable {
+		right += termcaps.Symbol(closeButton, "x") + " "
+	}
+	room := p.width - ansi.StringWidth(right) - 1
+	if ansi.StringWidth(left) > room {
+		left = ansi.Truncate(left, max(room, 0), "…")
+	}
+	gap := max(p.width-ansi.StringWidth(left)-ansi.StringWidth(right), 0)
+	return style.Render(left + strings.Repeat(" ", gap) + right)
+}
+
+// indentLabel says what the Tab key inserts in ed.
+func indentLabel(ed *editor.Editor) string {
+	if ed.Indent.UseTabs {
+		return i18n.T("pane.tabs")
+	}
+	return i18n.T("pane.spaces", ed.Indent.Size)
+}
+
+// button returns the title bar button at column x, if any.
+func (p *editorPane) button(x int) string {
+	closeX, splitX := p.width-2, p.width-2
+	if p.closable {
+		splitX = p.width - 4
+	}
+	switch {
+	case p.closable && x == closeX:
+		return closeButton
+	case x == splitX:
+		return splitButton
+	}
+	return ""
+}
+
+// editorGroups lays editor groups out side by side. The focused group's
+// editor is the one the rest of the app works on as Model.Editor; title
+// bars are drag handles that move a group's file into another group.
+type editorGroups struct {
+	rootPath string
+	panes    []*editorPane
+	keymap   map[string]command.Invocation
+	active   int
+	root     layout.Panel
+	width    int
+	height   int
+	dragging int
+}
+
+func newEditorGroups(rootPath string, ed *EditorPanel) *editorGroups {
+	if abs, err := filepath.Abs(rootPath); err == nil {
+		rootPath = abs
+	}
+	g := &editorGroups{rootPath: rootPath, dragging: -1}
+	g.panes = []*editorPane{{editor: ed, rootPath: rootPath, active: true}}
+	g.rebuild()
+	return g
+}
+
+func (g *editorGroups) editors() []*EditorPanel {
+	eds := make([]*EditorPanel, len(g.panes))
+	for i, p := range g.panes {
+		eds[i] = p.editor
+	}
+	return eds
+}
+
+// showing returns the group displaying path, or -1.
+func (g *editorGroups) showing(path string) int {
+	for i, p := range g.panes {
+		if p.editor.FilePath != "" && p.editor.FilePath == path {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *editorGroups) setActive(index int) {
+	g.active = index
+	for i, p := range g.panes {
+		p.active = i == index
+		if p.active {
+			p.editor.Focus()
+		} else {
+			p.editor.Blur()
+		}
+	}
+}
+
+func (g *editorGroups) insert(index int, ed *EditorPanel) {
+	pane := &editorPane{editor: ed, rootPath: g.rootPath, keymap: g.keymap}
+	g.panes = append(g.panes[:index], append([]*editorPane{pane}, g.panes[index:]...)...)
+	g.rebuild()
+}
+
+// setKeymap gives the groups the keymap their empty state lists keys from.
+func (g *editorGroups) setKeymap(keymap map[string]command.Invocation) {
+	g.keymap = keymap
+	for _, p := range g.panes {
+		p.keymap = keymap
+	}
+}
+
+func (g *editorGroups) remove(index int) {
+	g.panes[index].editor.UnlinkScroll()
+	g.panes = append(g.panes[:index], g.panes[index+1:]...)
+	g.rebuild()
+}
+
+func (g *editorGroups) rebuild() {
+	for _, p := range g.panes {
+		p.closable = len(g.panes) > 1
+	}
+	g.root = chainPanes(g.panes)
+	if g.width > 0 && g.height > 0 {
+		g.root.SetSize(g.width, g.height)
+	}
+}
+
+func chainPanes(panes []*editorPane) layout.Panel {
+	if len(panes) == 1 {
+		return panes[0]
+	}
+	split := layout.NewHorizontalSplit(panes[0], chainPanes(panes[1:]), 1/float64(len(panes)))
+	split.SetMinSizes(10, 10)
+	return split
+}
+
+func (g *editorGroups) SetSize(w, h int) {
+	g.width, g.height = w, h
+	g.root.SetSize(w, h)
+}
+
+func (g *editorGroups) View() string {
+	return g.root.View()
+}
+
+// paneAt returns the group under column x and x relative to it, following
+// the chain of splits built by chainPanes.
+func (g *editorGroups) paneAt(x int) (int, int) {
+	left := 0
+	for i, p := range g.panes {
+		if x < left+p.width || i == len(g.panes)-1 {
+			return i, x - left
+		}
+		left += p.width + 1
+	}
+	return -1, 0
+}
+
+func (g *editorGroups) Update(msg tea.Msg) tea.Cmd {
+	mouse, ok := msg.(tea.MouseMsg)
+	if !ok {
+		return g.root.Update(msg)
+	}
+
+	index, x := g.paneAt(mouse.X)
+	if g.dragging >= 0 {
+		for i, p := range g.panes {
+			p.dropping = i == index && i != g.dragging
+		}
+		if mouse.Type != tea.MouseRelease {
+			return nil
+		}
+		from := g.dragging
+		g.dragging = -1
+		for _, p := range g.panes {
+			p.dropping = false
+		}
+		if index < 0 || index == from {
+			return nil
+		}
+		return func() tea.Msg { return groupMoveMsg{from: from, to: index} }
+	}
+
+	if mouse.Type == tea.MouseLeft && index >= 0 {
+		if mouse.Y == 0 {
+			switch g.panes[index].button(x) {
+			case splitButton:
+				return func() tea.Msg { return groupSplitMsg{index: index} }
+			case closeButton:
+				return func() tea.Msg { return groupCloseMsg{index: index} }
+			}
+			g.dragging = index
+		}
+		if index != g.active {
+			focus := func() tea.Msg { return groupFocusMsg{index: index} }
+			if mouse.Y == 0 {
+				return focus
+			}
+			return tea.Batch(focus, g.root.Update(msg))
+		}
+		if mouse.Y == 0 {
+			return nil
+		}
+	}
+	return g.root.Update(msg)
+}
+
+// focusGroup makes group index the one Model.Editor and the tab bar follow.
+func (m *Model) focusGroup(index int) tea.Cmd {
+	if index < 0 || index >= len(m.groups.panes) || index == m.groups.active {
+		return nil
+	}
+	cmd := m.Editor.Flush()
+	m.groups.setActive(index)
+	m.Editor = m.groups.panes[index].editor
+	m.refreshGutter()
+	if tab := m.Tabs.FindTab(m.Editor.FilePath); tab >= 0 {
+		m.Tabs.SetActive(tab)
+	}
+	return tea.Batch(cmd, m.loadGitHunks())
+}
+
+// splitGroup opens an empty group to the right of index and focuses it.
+func (m *Model) splitGroup(index int) tea.Cmd {
+	source := m.groups.panes[index].editor
+	ed := &EditorPanel{editor.New()}
+	ed.Suggestions = source.Suggestions
+	m.groups.insert(index+1, ed)
+	m.groups.active = -1
+	cmd := m.focusGroup(index + 1)
+	m.applyAccessibility()
+	m.applyEditorSettings()
+	return cmd
+}
+
+// linkScroll locks the scrolling of the active group to the next group's,
+// so that editor.toggleScrollLock has a pair to lock and unlock.
+func (m *Model) linkScroll() {
+	if len(m.groups.panes) < 2 {
+		m.reportCommandError(errors.New(i18n.T("scroll.needsSplit")))
+		return
+	}
+	other := m.groups.panes[(m.groups.active+1)%len(m.groups.panes)].editor
+	if !m.Editor.LinkedWith(other.Editor) {
+		editor.LinkScroll(m.Editor.Editor, other.Editor)
+	}
+}
+
+// closeGroup keeps the group's document with its tab and removes the
+// group; the last group cannot be closed.
+func (m *Model) closeGroup(index int) tea.Cmd {
+	if len(m.groups.panes) < 2 || index < 0 || index >= len(m.groups.panes) {
+		return nil
+	}
+	cmd := m.stashDocument(m.groups.panes[index].editor)
+	m.groups.remove(index)
+	next := m.groups.active
+	if next >= index {
+		next = max(next-1, 0)
+	}
+	if index == m.groups.active {
+		next = min(index, len(m.groups.panes)-1)
+	}
+	m.groups.active = -1
+	return tea.Batch(cmd, m.focusGroup(next))
+}
+
+// moveToGroup shows the file of group from in group to, whose own file
+// goes back to its tab, and closes the emptied group.
+func (m *Model) moveToGroup(from, to int) tea.Cmd {
+	if from < 0 || to < 0 || from >= len(m.groups.panes) || to >= len(m.groups.panes) {
+		return nil
+	}
+	src, dst := m.groups.panes[from].editor, m.groups.panes[to].editor
+	if src.FilePath == "" {
+		return nil
+	}
+	flush := src.Flush()
+	doc := src.Detach()
+	park := m.stashDocument(dst)
+	dst.Attach(doc)
+
+	m.groups.remove(from)
+	if from < to {
+		to--
+	}
+	m.groups.active = -1
+	return tea.Batch(flush, park, m.focusGroup(to))
+}
+
+// openInSplit shows tab index in a new group to the right of the active
+// one, or focuses the group already showing it.
+func (m *Model) openInSplit(index int) tea.Cmd {
+	tab := m.Tabs.GetTab(index)
+	if tab == nil {
+		return nil
+	}
+	if group := m.groups.showing(tab.Path); group >= 0 {
+		return m.focusGroup(group)
+	}
+	split := m.splitGroup(m.groups.active)
+	m.Tabs.SetActive(index)
+	return tea.Batch(split, m.switchToTab(index))
+}
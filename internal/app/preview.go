@@ -0,0 +1,38 @@
+package app
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+	"tron/internal/preview"
+)
+
+func (m *Model) togglePreview() {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#89b4fa"))
+	if m.preview != nil {
+		m.preview.Close()
+		m.preview = nil
+		m.Terminal.Println(style.Render(i18n.T("preview.stopped")))
+		return
+	}
+
+	server, err := preview.Start(m.RootPath)
+	if err != nil {
+		m.Terminal.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render(i18n.T("preview.error", err.Error())))
+		return
+	}
+	m.preview = server
+	m.Terminal.Println(style.Render(i18n.T("preview.serving", server.URL())))
+	if m.Editor.FilePath != "" {
+		server.SetPreview(m.Editor.FilePath, m.Editor.Content())
+		m.Terminal.Println(style.Render(i18n.T("preview.file", server.URL()+preview.PreviewPath)))
+	}
+}
+
+func (m *Model) reloadPreview() {
+	if m.preview == nil {
+		return
+	}
+	m.preview.SetPreview(m.Editor.FilePath, m.Editor.Content())
+	m.preview.Reload()
+}
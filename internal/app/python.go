@@ -0,0 +1,76 @@
+package app
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/pyenv"
+)
+
+type pythonState struct {
+	rootPath string
+	env      pyenv.Env
+	ok       bool
+	panel    *pyenv.Panel
+}
+
+type pythonDetectedMsg struct {
+	env pyenv.Env
+	ok  bool
+}
+
+func newPythonState(rootPath string) *pythonState {
+	return &pythonState{rootPath: rootPath, panel: pyenv.NewPanel()}
+}
+
+// detect runs in the background because poetry environments are found by
+// shelling out.
+func (p *pythonState) detect() tea.Cmd {
+	rootPath := p.rootPath
+	return func() tea.Msg {
+		env, ok := pyenv.Selected(rootPath, pyenv.Detect(rootPath))
+		return pythonDetectedMsg{env: env, ok: ok}
+	}
+}
+
+func (p *pythonState) statusText(path string) string {
+	if !p.ok || filepath.Ext(path) != ".py" {
+		return ""
+	}
+	return "py " + p.env.Name
+}
+
+func (m *Model) openPythonPicker() {
+	m.python.panel.Open(pyenv.Detect(m.RootPath), m.python.env.Interpreter)
+}
+
+// applyPythonEnv points run configurations, the REPL and the Python
+// language server at the selected interpreter.
+func (m *Model) applyPythonEnv() {
+	if !m.python.ok {
+		return
+	}
+	env := m.python.env
+	m.RunBar.GetManager().SetPythonInterpreter(env.Interpreter)
+	m.replPanel.Python = env.Interpreter
+	m.lsp.configure("python", env.LSPSettings())
+}
+
+func (m *Model) handlePythonDetected(msg pythonDetectedMsg) {
+	if m.python.ok {
+		return
+	}
+	m.python.env, m.python.ok = msg.env, msg.ok
+	m.applyPythonEnv()
+}
+
+func (m *Model) selectPythonEnv(env pyenv.Env) tea.Cmd {
+	m.python.env, m.python.ok = env, true
+	_ = pyenv.Save(m.RootPath, env)
+	m.applyPythonEnv()
+	if filepath.Ext(m.Editor.FilePath) == ".py" {
+		return m.lsp.openDocument(m.Editor.FilePath, m.Editor.Content())
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/remote"
+	"tron/internal/terminal"
+)
+
+type remoteCheckMsg struct {
+	err error
+}
+
+type remoteTickMsg struct{}
+
+func loadRemote(rootPath string) *remote.Remote {
+	config, err := remote.LoadConfig(rootPath)
+	if err != nil || config == nil {
+		return nil
+	}
+	return remote.New(rootPath, config)
+}
+
+// runShell runs cmdStr in a new terminal session, set up as the project's
+// terminal settings say. The settings are read afresh for each session, so
+// edits to them apply to the next command. Broken settings are reported
+// and the command runs without them.
+func (m *Model) runShell(cmdStr, cwd string) {
+	shell, script, cwd := m.shellSession(cmdStr, cwd)
+	term := m.floats.shell(m.Terminal)
+	term.Shell = shell
+	term.RunScript(cmdStr, script, cwd)
+}
+
+// shellSession returns the shell to run cmdStr in, the script that sets
+// the session up and runs it, and the directory to run the script in.
+func (m *Model) shellSession(cmdStr, cwd string) (string, string, string) {
+	settings, err := terminal.LoadSettings(m.RootPath)
+	if err != nil {
+		m.reportCommandError(err)
+	}
+	script := settings.Script(cmdStr)
+	if !filepath.IsAbs(cwd) {
+		cwd = filepath.Join(m.RootPath, cwd)
+	}
+	if m.remote != nil {
+		script = m.remote.ShellCommand(script, cwd)
+		cwd = m.RootPath
+	}
+	shell := settings.Shell
+	if shell == "" {
+		shell = "sh"
+	}
+	return shell, script, cwd
+}
+
+func (m *Model) checkRemote() tea.Cmd {
+	if m.remote == nil {
+		return nil
+	}
+	r := m.remote
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), remote.CheckTimeout)
+		defer cancel()
+		return remoteCheckMsg{err: r.Check(ctx)}
+	}
+}
+
+func (m *Model) handleRemoteCheck(msg remoteCheckMsg) tea.Cmd {
+	wasDisconnected := m.remote.Status == remote.StatusDisconnected
+	delay := m.remote.SetResult(msg.err)
+
+	var cmds []tea.Cmd
+	if wasDisconnected && msg.err == nil {
+		// Language servers died with the old connection; start them again.
+		m.lsp.restart()
+		if m.Editor.FilePath != "" {
+			cmds = append(cmds, m.lsp.openDocument(m.Editor.FilePath, m.Editor.Content()))
+		}
+	}
+	cmds = append(cmds, tea.Tick(delay, func(time.Time) tea.Msg {
+		return remoteTickMsg{}
+	}))
+	return tea.Batch(cmds...)
+}
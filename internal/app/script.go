@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"tron/internal/command"
+	"tron/internal/terminal"
+)
+
+// Scripts run tron without its interface, for automation. Each line is a
+// command as typed in the palette. It runs once the work the line before
+// started has finished, including any process it started in the
+// terminal, whose output is copied to the script's output.
+
+// scriptWidth and scriptHeight are the window size scripts run at, for
+// commands that depend on layout.
+const (
+	scriptWidth  = 120
+	scriptHeight = 40
+)
+
+// RunScript runs the commands read from r in a workspace at the current
+// directory, writing what they print to out. It stops at the first
+// command that fails. name labels r in errors.
+func RunScript(r io.Reader, name string, out io.Writer) error {
+	invocations, err := command.ReadMacro(r, name)
+	if err != nil {
+		return err
+	}
+	d, err := NewDriver(scriptWidth, scriptHeight)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	// printed counts the terminal lines already copied to out.
+	printed := make(map[*TerminalPanel]int)
+	for _, t := range d.terminals() {
+		printed[t] = len(t.LinesSince(0))
+	}
+	for _, inv := range invocations {
+		err := d.Execute(inv)
+		for _, t := range d.terminals() {
+			lines := t.LinesSince(printed[t])
+			printed[t] += len(lines)
+			for _, line := range lines {
+				fmt.Fprintln(out, strings.TrimRight(terminal.StripANSI(line), " "))
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", inv.Name, err)
+		}
+	}
+	return nil
+}
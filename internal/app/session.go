@@ -0,0 +1,89 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"tron/pkg/layout"
+)
+
+type SessionTab struct {
+	Path  string `json:"path"`
+	Title string `json:"title,omitempty"`
+}
+
+type Session struct {
+	Tabs      []SessionTab        `json:"tabs"`
+	ActiveTab int                 `json:"activeTab"`
+	Layout    []layout.SplitState `json:"layout"`
+}
+
+func sessionPath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "session.json")
+}
+
+func LoadSession(rootPath string) (*Session, error) {
+	data, err := os.ReadFile(sessionPath(rootPath))
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func SaveSession(rootPath string, s *Session) error {
+	path := sessionPath(rootPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (m *Model) captureSession() *Session {
+	s := &Session{
+		Layout: layout.CollectStates(m.Root),
+	}
+	for _, tab := range m.Tabs.GetTabs() {
+		s.Tabs = append(s.Tabs, SessionTab{Path: tab.Path, Title: tab.Title})
+	}
+	if active := m.Tabs.GetActive(); active != nil {
+		s.ActiveTab = active.Index
+	}
+	return s
+}
+
+func (m *Model) restoreSession(s *Session) {
+	layout.RestoreStates(m.Root, s.Layout)
+
+	for _, tab := range s.Tabs {
+		// Paths saved while the workspace was the working directory are
+		// relative to its root.
+		path := tab.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(m.RootPath, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		idx := m.Tabs.AddTab(path)
+		m.Tabs.SetTitle(idx, tab.Title)
+	}
+	if m.Tabs.TabCount() == 0 {
+		return
+	}
+
+	active := s.ActiveTab
+	if active < 0 || active >= m.Tabs.TabCount() {
+		active = 0
+	}
+	m.Tabs.SetActive(active)
+	m.switchToTab(active)
+}
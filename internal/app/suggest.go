@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/editor"
+	"tron/internal/jobs"
+	"tron/internal/suggest"
+)
+
+func (m *Model) toggleSuggestions() {
+	if m.suggestions == nil {
+		return
+	}
+	m.Editor.Suggestions = !m.Editor.Suggestions
+}
+
+func (m *Model) requestSuggestion(msg editor.SuggestionRequestMsg) tea.Cmd {
+	provider := m.suggestions
+	if provider == nil || !m.Editor.Suggestions {
+		return nil
+	}
+	req := suggest.Request{
+		Path:     msg.Path,
+		Language: strings.TrimPrefix(filepath.Ext(msg.Path), "."),
+		Prefix:   msg.Prefix,
+		Suffix:   msg.Suffix,
+	}
+	m.jobs.Cancel(suggestionJob)
+	return m.jobs.Submit(jobs.Job{Owner: suggestionJob, Priority: jobs.High, Run: func(ctx context.Context, _ func(int, int)) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, suggest.Timeout)
+		defer cancel()
+		text, err := provider.Suggest(ctx, req)
+		if err != nil {
+			text = ""
+		}
+		return editor.SuggestionResultMsg{Seq: msg.Seq, Text: text}
+	}})
+}
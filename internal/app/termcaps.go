@@ -0,0 +1,39 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+	"tron/internal/termcaps"
+)
+
+// warnDegraded says once, at startup, what the terminal lacks, since the
+// fallbacks would otherwise look like rendering bugs.
+func warnDegraded(term *TerminalPanel, caps termcaps.Caps) {
+	degraded := caps.Degraded()
+	if len(degraded) == 0 {
+		return
+	}
+	parts := make([]string, len(degraded))
+	for i, d := range degraded {
+		parts[i] = i18n.T("termcaps." + d)
+	}
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+	term.Println(style.Render(i18n.T("termcaps.warning", strings.Join(parts, ", "))))
+}
+
+func describeCaps(caps termcaps.Caps) string {
+	yesNo := func(b bool) string {
+		if b {
+			return i18n.T("diagnostics.yes")
+		}
+		return i18n.T("diagnostics.no")
+	}
+	width := 1
+	if caps.AmbiguousWide {
+		width = 2
+	}
+	return i18n.T("diagnostics.display", caps.Colors, yesNo(caps.Unicode), yesNo(caps.NerdFont), width)
+}
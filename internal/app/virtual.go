@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/git"
+	"tron/internal/i18n"
+	"tron/internal/jobs"
+	"tron/internal/lsp"
+)
+
+// Virtual documents show text without a file behind it in ordinary
+// read-only tabs, so it can be searched, selected and copied like any
+// file. Their paths are URIs whose scheme says where the text came from.
+const (
+	outputScheme = "output:"
+	gitScheme    = "git:"
+	lspLogScheme = "lsp-log:"
+	hexScheme    = "hex:"
+	binaryScheme = "binary:"
+)
+
+const outputTimeout = 5 * time.Minute
+
+// virtualDoc is kept while its tab is open, since there is no file to load
+// it from again once the tab is hibernated.
+type virtualDoc struct {
+	name    string
+	content string
+}
+
+type outputDoneMsg struct {
+	command string
+	output  string
+}
+
+// openVirtual shows content in the tab for uri, replacing what the tab
+// showed before. name is what the content would be called as a file.
+func (m *Model) openVirtual(uri, name, content string) tea.Cmd {
+	m.tabDocs.virtual[uri] = virtualDoc{name: name, content: content}
+	delete(m.tabDocs.docs, uri)
+	if m.Editor.FilePath == uri {
+		m.Editor.LoadVirtual(uri, name, content)
+		m.refreshGutter()
+		return nil
+	}
+	return m.openFile(uri)
+}
+
+// runOutput runs a shell command in the project and opens what it prints
+// as output:<command>. Running it again, or closing its tab, cancels a run
+// still going.
+func (m *Model) runOutput(command string) tea.Cmd {
+	if strings.TrimSpace(command) == "" {
+		return nil
+	}
+	cmdStr, root := command, m.RootPath
+	if m.remote != nil {
+		cmdStr = m.remote.ShellCommand(command, root)
+	}
+	uri := outputScheme + command
+	m.jobs.Cancel(uri)
+	return m.jobs.Submit(jobs.Job{Owner: uri, Title: command, Priority: jobs.Normal, Run: func(ctx context.Context, _ func(int, int)) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, outputTimeout)
+		defer cancel()
+		c := exec.CommandContext(ctx, "sh", "-c", cmdStr)
+		c.Dir = root
+		// Children of the killed shell may hold its output open.
+		c.WaitDelay = time.Second
+		out, err := c.CombinedOutput()
+		text := string(out)
+		if err != nil {
+			text = strings.TrimRight(text, "\n") + "\n\n" + i18n.T("output.failed", err)
+		}
+		return outputDoneMsg{command: command, output: text}
+	}})
+}
+
+// showLSPLog opens the trace of the language server for the current file,
+// or refreshes it when an lsp-log: document is already showing.
+func (m *Model) showLSPLog() tea.Cmd {
+	path := m.Editor.FilePath
+	languageID := lsp.LanguageID(path)
+	if id, ok := strings.CutPrefix(path, lspLogScheme); ok {
+		languageID = id
+	}
+	client := m.lsp.clients[languageID]
+	if client == nil {
+		m.reportCommandError(errors.New(i18n.T("lsp.noServer")))
+		return nil
+	}
+	return m.openVirtual(lspLogScheme+languageID, "", client.Log())
+}
+
+func (m *Model) openRevision(msg git.OpenRevisionMsg) tea.Cmd {
+	return m.openVirtual(gitScheme+msg.Title, msg.Name, msg.Text)
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/internal/i18n"
+	"tron/internal/scaffold"
+)
+
+// closeWorkspace saves the session and releases everything tied to the
+// current root before quitting or switching workspaces.
+func (m *Model) closeWorkspace() {
+	_ = SaveSession(m.RootPath, m.captureSession())
+	m.lsp.shutdown()
+	m.jobs.Close()
+	m.leaveCollab()
+	if m.preview != nil {
+		m.preview.Close()
+		m.preview = nil
+	}
+	if m.Terminal.IsRunning() {
+		m.Terminal.Stop()
+	}
+}
+
+func (m Model) createProject(msg scaffold.CreateMsg) (tea.Model, tea.Cmd) {
+	if err := scaffold.Create(msg.Template, msg.Dir); err != nil {
+		m.scaffoldPanel.Done(err)
+		return m, nil
+	}
+	m.scaffoldPanel.Done(nil)
+	postCreate := ""
+	if msg.RunPostCreate {
+		postCreate = msg.Template.PostCreateCommand()
+	}
+	return m.openWorkspace(msg.Dir, postCreate)
+}
+
+// openWorkspace replaces the model with one rooted at dir, starting over
+// with the new project's session. The process keeps its working
+// directory; everything in the new model works from its root. postCreate
+// runs there only once the user agreed to it.
+func (m Model) openWorkspace(dir, postCreate string) (tea.Model, tea.Cmd) {
+	root, err := filepath.Abs(dir)
+	if err == nil {
+		_, err = os.ReadDir(root)
+	}
+	if err != nil {
+		m.Terminal.Println(i18n.T("workspace.openError", err.Error()))
+		return m, nil
+	}
+	m.closeWorkspace()
+
+	next := newAt(root)
+	next.Width, next.Height = m.Width, m.Height
+	_, sizeCmd := next.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.Height})
+	next.Terminal.Println(i18n.T("workspace.opened", dir))
+	if postCreate != "" {
+		next.runShell(postCreate, root)
+	}
+	return next, tea.Batch(sizeCmd, next.Init())
+}
+
+// openWorkspaceMsg switches the workspace to dir once the command that
+// asked for it has returned.
+type openWorkspaceMsg struct {
+	dir string
+}
@@ -0,0 +1,203 @@
+// Package apptest drives the whole app with synthetic key, mouse and
+// window events, for tests of the editor, file tree, tabs and layout. It
+// compares rendered frames with golden files in the test's testdata
+// directory; go test -update rewrites them from what the app renders.
+package apptest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"tron/internal/app"
+	"tron/internal/command"
+	"tron/internal/termcaps"
+	"tron/pkg/diff"
+)
+
+var update = flag.Bool("update", false, "rewrite golden frames with what the app renders")
+
+// Timeout bounds how long the work one event starts may take.
+const Timeout = 10 * time.Second
+
+// keyTypes maps key names such as "ctrl+s" and "pgdown" to their types.
+var keyTypes = func() map[string]tea.KeyType {
+	types := map[string]tea.KeyType{"space": tea.KeySpace}
+	for k := tea.KeyType(-100); k <= 127; k++ {
+		if name := k.String(); name != "" {
+			types[name] = k
+		}
+	}
+	return types
+}()
+
+// Harness is the app running on a copy of a fixture workspace.
+type Harness struct {
+	t      testing.TB
+	driver *app.Driver
+	golden string
+}
+
+// New copies the workspace in dir, relative to the test's package
+// directory, and opens the copy in a window of the given size. Being a
+// copy, the fixture is left as it was whatever the test saves. The
+// terminal is pinned to a true-color UTF-8 one in English without a Nerd
+// Font, and the user's config directory is an empty one, so frames match
+// wherever the test runs.
+func New(t testing.TB, dir string, width, height int) *Harness {
+	t.Helper()
+	for key, value := range map[string]string{
+		"TERM":                "xterm-256color",
+		"COLORTERM":           "truecolor",
+		termcaps.ColorsEnv:    "truecolor",
+		termcaps.NerdFontEnv:  "0",
+		"RUNEWIDTH_EASTASIAN": "0",
+		"LC_ALL":              "en_US.UTF-8",
+		"SSH_CONNECTION":      "",
+	} {
+		t.Setenv(key, value)
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	workspace := t.TempDir()
+	if err := os.CopyFS(workspace, os.DirFS(dir)); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(workspace)
+
+	d, err := app.NewDriver(width, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Timeout = Timeout
+	t.Cleanup(d.Close)
+	return &Harness{t: t, driver: d, golden: filepath.Join(wd, "testdata")}
+}
+
+// Send hands msg to the app and waits for the work it starts.
+func (h *Harness) Send(msg tea.Msg) {
+	h.t.Helper()
+	if err := h.driver.Send(msg); err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+// Type sends text one key at a time.
+func (h *Harness) Type(text string) {
+	h.t.Helper()
+	for _, r := range text {
+		if r == ' ' {
+			h.Send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}})
+		} else {
+			h.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+}
+
+// Press sends keys named as in keybindings, such as "ctrl+s", "alt+x" or
+// "enter".
+func (h *Harness) Press(keys ...string) {
+	h.t.Helper()
+	for _, key := range keys {
+		msg := tea.KeyMsg{}
+		name := key
+		if rest, ok := strings.CutPrefix(key, "alt+"); ok {
+			msg.Alt, name = true, rest
+		}
+		if t, ok := keyTypes[name]; ok {
+			msg.Type = t
+		} else if r := []rune(name); len(r) == 1 {
+			msg.Type, msg.Runes = tea.KeyRunes, r
+		} else {
+			h.t.Fatalf("apptest: unknown key %q", key)
+		}
+		h.Send(msg)
+	}
+}
+
+// Click presses and releases the left mouse button at column x, row y.
+func (h *Harness) Click(x, y int) {
+	h.t.Helper()
+	h.Send(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft, Type: tea.MouseLeft})
+	h.Send(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft, Type: tea.MouseRelease})
+}
+
+// Run runs a command line, as typed in the palette, such as
+// `file.open {path: main.go}`.
+func (h *Harness) Run(line string) {
+	h.t.Helper()
+	inv, err := command.Parse(line)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	if err := h.driver.Execute(inv); err != nil {
+		h.t.Fatalf("%s: %v", line, err)
+	}
+}
+
+// Resize changes the window size.
+func (h *Harness) Resize(width, height int) {
+	h.t.Helper()
+	h.Send(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// Frame returns what the app renders, without colors and with trailing
+// spaces trimmed from each line.
+func (h *Harness) Frame() string {
+	lines := strings.Split(ansi.Strip(h.driver.View()), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// AssertFrame compares the frame with testdata/name.golden, failing the
+// test with the lines that differ.
+func (h *Harness) AssertFrame(name string) {
+	h.t.Helper()
+	path := filepath.Join(h.golden, name+".golden")
+	got := h.Frame()
+	if *update {
+		if err := os.MkdirAll(h.golden, 0755); err != nil {
+			h.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			h.t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		h.t.Fatalf("apptest: %v; run go test -update to create it", err)
+	}
+	if string(want) != got {
+		h.t.Errorf("frame differs from %s:\n%s", path, frameDiff(string(want), got))
+	}
+}
+
+// frameDiff lists the lines that differ between two frames, numbered.
+func frameDiff(want, got string) string {
+	wantLines, gotLines, edits := diff.Text(want, got, diff.Histogram)
+	var sb strings.Builder
+	for _, e := range edits {
+		for i := e.OldStart; i < e.OldEnd; i++ {
+			fmt.Fprintf(&sb, "-%4d | %s\n", i+1, wantLines[i])
+		}
+		for i := e.NewStart; i < e.NewEnd; i++ {
+			fmt.Fprintf(&sb, "+%4d | %s\n", i+1, gotLines[i])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,53 @@
+package apptest_test
+
+import (
+	"testing"
+
+	"tron/internal/apptest"
+)
+
+const workspace = "testdata/workspace"
+
+func TestEditor(t *testing.T) {
+	h := apptest.New(t, workspace, 80, 16)
+	h.Run("file.open {path: main.go}")
+	h.AssertFrame("editor_open")
+
+	h.Press("down", "down", "down", "down", "down", "end", "enter")
+	h.Type(`fmt.Println("bye")`)
+	h.Press("ctrl+s")
+	h.AssertFrame("editor_edited")
+}
+
+func TestFileTree(t *testing.T) {
+	h := apptest.New(t, workspace, 80, 16)
+	h.AssertFrame("filetree_collapsed")
+
+	// A double click on a folder expands it, on a file opens it.
+	h.Click(3, 2)
+	h.Click(3, 2)
+	h.AssertFrame("filetree_expanded")
+	h.Click(6, 3)
+	h.Click(6, 3)
+	h.AssertFrame("filetree_opened")
+}
+
+func TestTabs(t *testing.T) {
+	h := apptest.New(t, workspace, 80, 16)
+	h.Run("file.open {path: README.md}")
+	h.Run("file.open {path: main.go}")
+	h.AssertFrame("tabs_two")
+
+	h.Click(3, 0)
+	h.AssertFrame("tabs_switched")
+}
+
+func TestLayout(t *testing.T) {
+	h := apptest.New(t, workspace, 80, 16)
+	h.Run("file.open {path: main.go}")
+	h.Run("editor.splitGroup")
+	h.AssertFrame("layout_split")
+
+	h.Resize(100, 14)
+	h.AssertFrame("layout_resized")
+}
@@ -0,0 +1,6 @@
+package src
+
+// Add returns a plus b.
+func Add(a, b int) int {
+	return a + b
+}
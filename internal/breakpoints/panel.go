@@ -0,0 +1,171 @@
+package breakpoints
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+type Panel struct {
+	store    *Store
+	visible  bool
+	selected int
+	editing  bool
+	input    []rune
+	width    int
+}
+
+func NewPanel(store *Store) *Panel {
+	return &Panel{
+		store: store,
+		width: 60,
+	}
+}
+
+func (p *Panel) Toggle() {
+	p.visible = !p.visible
+	p.editing = false
+	p.clampSelection()
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+func (p *Panel) selectedBreakpoint() *Breakpoint {
+	all := p.store.All()
+	if p.selected < 0 || p.selected >= len(all) {
+		return nil
+	}
+	return all[p.selected]
+}
+
+func (p *Panel) clampSelection() {
+	if p.selected >= len(p.store.All()) {
+		p.selected = len(p.store.All()) - 1
+	}
+	if p.selected < 0 {
+		p.selected = 0
+	}
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+	if p.editing {
+		return p.handleEditKey(keyMsg)
+	}
+
+	bp := p.selectedBreakpoint()
+	switch keyMsg.String() {
+	case "esc", "alt+b":
+		p.visible = false
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.store.All())-1 {
+			p.selected++
+		}
+	case " ":
+		if bp != nil {
+			p.store.SetEnabled(bp, !bp.Enabled)
+			return changedCmd
+		}
+	case "d", "delete":
+		if bp != nil {
+			p.store.Remove(bp)
+			p.clampSelection()
+			return changedCmd
+		}
+	case "c":
+		if bp != nil {
+			p.editing = true
+			p.input = []rune(bp.Condition)
+		}
+	case "enter":
+		if bp != nil {
+			p.visible = false
+			path, line := bp.Path, bp.Line
+			return func() tea.Msg {
+				return JumpMsg{Path: path, Line: line}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Panel) handleEditKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		p.editing = false
+		if bp := p.selectedBreakpoint(); bp != nil {
+			p.store.SetCondition(bp, strings.TrimSpace(string(p.input)))
+			return changedCmd
+		}
+	case tea.KeyEsc:
+		p.editing = false
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		p.input = append(p.input, msg.Runes...)
+	}
+	return nil
+}
+
+func changedCmd() tea.Msg {
+	return ChangedMsg{}
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4")).Width(p.width)
+	selectedStyle := itemStyle.
+		Background(lipgloss.Color("#313244"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+
+	lines := []string{titleStyle.Render(i18n.T("breakpoints.title"))}
+	all := p.store.All()
+	if len(all) == 0 {
+		lines = append(lines, hintStyle.Render(i18n.T("breakpoints.empty")))
+	}
+	for i, bp := range all {
+		dot := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("●")
+		if !bp.Enabled {
+			dot = hintStyle.Render("○")
+		}
+		text := fmt.Sprintf("%s %s:%d", dot, filepath.Base(bp.Path), bp.Line+1)
+		if i == p.selected && p.editing {
+			text += " " + i18n.T("breakpoints.if") + " " + string(p.input) + "▏"
+		} else if bp.Condition != "" {
+			text += hintStyle.Render(" " + i18n.T("breakpoints.if") + " " + bp.Condition)
+		}
+		if i == p.selected {
+			lines = append(lines, selectedStyle.Render(text))
+		} else {
+			lines = append(lines, itemStyle.Render(text))
+		}
+	}
+	lines = append(lines, "", hintStyle.Render(i18n.T("breakpoints.hint")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#f38ba8")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,132 @@
+package breakpoints
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"tron/pkg/pathutil"
+)
+
+type Store struct {
+	rootPath    string
+	breakpoints []*Breakpoint
+}
+
+func NewStore(rootPath string) *Store {
+	s := &Store{rootPath: rootPath}
+	s.Load()
+	return s
+}
+
+func (s *Store) configPath() string {
+	return filepath.Join(s.rootPath, ".tron", "breakpoints.json")
+}
+
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.configPath())
+	if err != nil {
+		return err
+	}
+	var bps []*Breakpoint
+	if err := json.Unmarshal(data, &bps); err != nil {
+		return err
+	}
+	for _, bp := range bps {
+		bp.Path = s.absPath(bp.Path)
+	}
+	s.breakpoints = bps
+	s.sort()
+	return nil
+}
+
+func (s *Store) Save() error {
+	path := s.configPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	out := make([]Breakpoint, 0, len(s.breakpoints))
+	for _, bp := range s.breakpoints {
+		saved := *bp
+		saved.Path = s.relPath(bp.Path)
+		out = append(out, saved)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (s *Store) absPath(path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.rootPath, path)
+	}
+	return pathutil.Canonical(path)
+}
+
+func (s *Store) relPath(path string) string {
+	rel, err := filepath.Rel(pathutil.Canonical(s.rootPath), path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+func (s *Store) Toggle(path string, line int) *Breakpoint {
+	path = pathutil.Canonical(path)
+	for i, bp := range s.breakpoints {
+		if bp.Path == path && bp.Line == line {
+			s.breakpoints = append(s.breakpoints[:i], s.breakpoints[i+1:]...)
+			return nil
+		}
+	}
+	bp := &Breakpoint{Path: path, Line: line, Enabled: true}
+	s.breakpoints = append(s.breakpoints, bp)
+	s.sort()
+	return bp
+}
+
+func (s *Store) Remove(bp *Breakpoint) {
+	for i, b := range s.breakpoints {
+		if b == bp {
+			s.breakpoints = append(s.breakpoints[:i], s.breakpoints[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Store) SetEnabled(bp *Breakpoint, enabled bool) {
+	bp.Enabled = enabled
+}
+
+func (s *Store) SetCondition(bp *Breakpoint, condition string) {
+	bp.Condition = condition
+}
+
+func (s *Store) All() []*Breakpoint {
+	return s.breakpoints
+}
+
+func (s *Store) ForFile(path string) []*Breakpoint {
+	path = pathutil.Canonical(path)
+	var result []*Breakpoint
+	for _, bp := range s.breakpoints {
+		if bp.Path == path {
+			result = append(result, bp)
+		}
+	}
+	return result
+}
+
+func (s *Store) sort() {
+	sort.SliceStable(s.breakpoints, func(i, j int) bool {
+		if s.breakpoints[i].Path != s.breakpoints[j].Path {
+			return s.breakpoints[i].Path < s.breakpoints[j].Path
+		}
+		return s.breakpoints[i].Line < s.breakpoints[j].Line
+	})
+}
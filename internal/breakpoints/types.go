@@ -0,0 +1,15 @@
+package breakpoints
+
+type Breakpoint struct {
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Enabled   bool   `json:"enabled"`
+	Condition string `json:"condition,omitempty"`
+}
+
+type ChangedMsg struct{}
+
+type JumpMsg struct {
+	Path string
+	Line int
+}
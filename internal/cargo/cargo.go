@@ -0,0 +1,174 @@
+// Package cargo reads what cargo reports about a Rust project: the
+// diagnostics of a check and the toolchain it builds with.
+package cargo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"tron/internal/problems"
+	"tron/pkg/pathutil"
+)
+
+// HasManifest reports whether rootPath is a cargo package or workspace.
+func HasManifest(rootPath string) bool {
+	_, err := os.Stat(filepath.Join(rootPath, "Cargo.toml"))
+	return err == nil
+}
+
+// CheckCommand checks the project, with clippy's lints when clippy is set,
+// printing the diagnostics as JSON lines on stdout.
+func CheckCommand(clippy bool) string {
+	if clippy {
+		return "cargo clippy --all-targets --message-format=json"
+	}
+	return "cargo check --all-targets --message-format=json"
+}
+
+type message struct {
+	Reason  string           `json:"reason"`
+	Message *compilerMessage `json:"message"`
+}
+
+type compilerMessage struct {
+	Message string `json:"message"`
+	Code    *struct {
+		Code string `json:"code"`
+	} `json:"code"`
+	Level string `json:"level"`
+	Spans []span `json:"spans"`
+}
+
+type span struct {
+	FileName    string `json:"file_name"`
+	LineStart   int    `json:"line_start"`
+	LineEnd     int    `json:"line_end"`
+	ColumnStart int    `json:"column_start"`
+	ColumnEnd   int    `json:"column_end"`
+	IsPrimary   bool   `json:"is_primary"`
+	Text        []struct {
+		Text string `json:"text"`
+	} `json:"text"`
+	Expansion *struct {
+		Span span `json:"span"`
+	} `json:"expansion"`
+}
+
+// ParseDiagnostics returns the compiler messages in the JSON lines cargo
+// printed for a check run in rootPath, located at their primary spans.
+// Lines that are not compiler messages, such as build script output, are
+// skipped, as are messages a build reports more than once.
+func ParseDiagnostics(output []byte, rootPath string) []problems.Problem {
+	var list []problems.Problem
+	seen := make(map[problems.Problem]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var msg message
+		if json.Unmarshal(scanner.Bytes(), &msg) != nil || msg.Reason != "compiler-message" || msg.Message == nil {
+			continue
+		}
+		p, ok := toProblem(msg.Message, rootPath)
+		if ok && !seen[p] {
+			seen[p] = true
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+func toProblem(m *compilerMessage, rootPath string) (problems.Problem, bool) {
+	var primary *span
+	for i := range m.Spans {
+		if m.Spans[i].IsPrimary {
+			primary = &m.Spans[i]
+			break
+		}
+	}
+	if primary == nil {
+		// Summaries such as "aborting due to 2 previous errors".
+		return problems.Problem{}, false
+	}
+	// Spans inside macros from other crates point at the macro's
+	// definition; the call site is where it was expanded from.
+	s := *primary
+	for strings.HasPrefix(s.FileName, "<") && s.Expansion != nil {
+		s = s.Expansion.Span
+	}
+	path := s.FileName
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(rootPath, path)
+	}
+	p := problems.Problem{
+		Path:      pathutil.Canonical(path),
+		Line:      s.LineStart - 1,
+		Column:    s.byteColumn(0, s.ColumnStart),
+		EndLine:   s.LineEnd - 1,
+		EndColumn: s.byteColumn(len(s.Text)-1, s.ColumnEnd),
+		Severity:  severity(m.Level),
+		Message:   m.Message,
+	}
+	if m.Code != nil {
+		p.Code = m.Code.Code
+	}
+	return p, true
+}
+
+// byteColumn converts col, which counts characters from one as rustc
+// does, to a byte offset in the text of the span's line i.
+func (s span) byteColumn(i, col int) int {
+	col = max(col-1, 0)
+	if i < 0 || i >= len(s.Text) {
+		return col
+	}
+	text, offset := s.Text[i].Text, 0
+	for ; col > 0 && offset < len(text); col-- {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return offset + col
+}
+
+func severity(level string) problems.Severity {
+	switch {
+	case strings.HasPrefix(level, "error"):
+		return problems.Error
+	case level == "warning":
+		return problems.Warning
+	}
+	return problems.Note
+}
+
+var hostTriple = regexp.MustCompile(`-(x86_64|i686|aarch64|arm|armv7|riscv64gc|powerpc64le|s390x)-.*$`)
+
+// Toolchain returns the name of the toolchain cargo uses in rootPath, such
+// as "stable" or "nightly-2024-05-01", falling back to the rustc version
+// where rustup is not installed.
+func Toolchain(ctx context.Context, rootPath string) (string, error) {
+	c := exec.CommandContext(ctx, "rustup", "show", "active-toolchain")
+	c.Dir = rootPath
+	if out, err := c.Output(); err == nil {
+		if fields := strings.Fields(string(out)); len(fields) > 0 {
+			return hostTriple.ReplaceAllString(fields[0], ""), nil
+		}
+	}
+	c = exec.CommandContext(ctx, "rustc", "--version")
+	c.Dir = rootPath
+	out, err := c.Output()
+	if err != nil {
+		return "", err
+	}
+	// rustc 1.78.0 (9b00956e5 2024-04-29)
+	if fields := strings.Fields(string(out)); len(fields) > 1 {
+		return fields[1], nil
+	}
+	return "", nil
+}
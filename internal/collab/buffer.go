@@ -0,0 +1,72 @@
+package collab
+
+import (
+	"tron/internal/editor"
+)
+
+// SharedBuffer wraps an editor buffer and submits every local change to the
+// session as an operation.
+type SharedBuffer struct {
+	editor.Buffer
+	client *Client
+}
+
+func NewSharedBuffer(inner editor.Buffer, client *Client) *SharedBuffer {
+	return &SharedBuffer{Buffer: inner, client: client}
+}
+
+func (b *SharedBuffer) Inner() editor.Buffer {
+	return b.Buffer
+}
+
+func (b *SharedBuffer) track(mutate func()) {
+	before := b.Buffer.Content()
+	mutate()
+	b.client.Submit(Diff(before, b.Buffer.Content()))
+}
+
+func (b *SharedBuffer) Insert(pos editor.Position, text string) {
+	b.track(func() { b.Buffer.Insert(pos, text) })
+}
+
+func (b *SharedBuffer) Delete(start, end editor.Position) {
+	b.track(func() { b.Buffer.Delete(start, end) })
+}
+
+func (b *SharedBuffer) DeleteChar(pos editor.Position, forward bool) {
+	b.track(func() { b.Buffer.DeleteChar(pos, forward) })
+}
+
+func (b *SharedBuffer) SetContent(content string) {
+	b.track(func() { b.Buffer.SetContent(content) })
+}
+
+func (b *SharedBuffer) ApplyRemote(op Operation) error {
+	content, err := op.Apply(b.Buffer.Content())
+	if err != nil {
+		return err
+	}
+	b.Buffer.SetContent(content)
+	return nil
+}
+
+func Offset(buf editor.Buffer, pos editor.Position) int {
+	lines := buf.Lines()
+	offset := buf.LineOffset(pos.Line)
+	if pos.Line < len(lines) {
+		offset += min(pos.Column, len(lines[pos.Line]))
+	}
+	return offset
+}
+
+func PositionAt(buf editor.Buffer, offset int) editor.Position {
+	lines := buf.Lines()
+	for i, line := range lines {
+		if offset <= len(line) {
+			return editor.Position{Line: i, Column: max(0, offset)}
+		}
+		offset -= len(line) + 1
+	}
+	last := len(lines) - 1
+	return editor.Position{Line: last, Column: len(lines[last])}
+}
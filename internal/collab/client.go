@@ -0,0 +1,186 @@
+package collab
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const dialTimeout = 5 * time.Second
+
+type EventMsg struct {
+	Message Message
+}
+
+type DisconnectedMsg struct {
+	Err error
+}
+
+// Client tracks one participant's view of the session. Handle, Submit and
+// SendCursor must all be called from the same goroutine (the tea update
+// loop), so local and remote edits are ordered consistently.
+type Client struct {
+	conn    net.Conn
+	enc     *json.Encoder
+	writeMu sync.Mutex
+	events  chan tea.Msg
+
+	ID           int
+	Color        string
+	DocName      string
+	Participants map[int]*Participant
+
+	rev     int
+	pending []Operation
+}
+
+// ParseInvite splits an invite, token@host:port, into the session's token
+// and address.
+func ParseInvite(invite string) (token, addr string, err error) {
+	token, addr, ok := strings.Cut(invite, "@")
+	if !ok || token == "" || addr == "" {
+		return "", "", errors.New("expected an invite of the form token@host:port")
+	}
+	return token, addr, nil
+}
+
+// Dial joins the session at addr, proving with token that it was invited.
+func Dial(addr, token, name string) (*Client, Message, error) {
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, Message{}, err
+	}
+	c := &Client{
+		conn:         conn,
+		enc:          json.NewEncoder(conn),
+		events:       make(chan tea.Msg, 64),
+		Participants: make(map[int]*Participant),
+	}
+	c.send(Message{Type: MsgHello, Name: name, Token: token})
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	if !scanner.Scan() {
+		conn.Close()
+		return nil, Message{}, errors.New("session closed before welcome; check the invite token")
+	}
+	var welcome Message
+	if err := json.Unmarshal(scanner.Bytes(), &welcome); err != nil || welcome.Type != MsgWelcome {
+		conn.Close()
+		return nil, Message{}, errors.New("unexpected handshake from host")
+	}
+
+	c.ID = welcome.ID
+	c.Color = welcome.Color
+	c.DocName = welcome.DocName
+	c.rev = welcome.Rev
+	for _, p := range welcome.Participants {
+		p := p
+		c.Participants[p.ID] = &p
+	}
+
+	go c.readLoop(scanner)
+	return c, welcome, nil
+}
+
+func (c *Client) readLoop(scanner *bufio.Scanner) {
+	for scanner.Scan() {
+		var msg Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+		c.events <- EventMsg{Message: msg}
+	}
+	c.events <- DisconnectedMsg{Err: scanner.Err()}
+	close(c.events)
+}
+
+// Listen waits for the next message from the session.
+func (c *Client) Listen() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-c.events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) send(msg Message) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.enc.Encode(msg)
+}
+
+func (c *Client) Submit(op Operation) {
+	if op.IsNoop() {
+		return
+	}
+	c.pending = append(c.pending, op)
+	if len(c.pending) == 1 {
+		c.send(Message{Type: MsgOp, Rev: c.rev, Op: op})
+	}
+}
+
+func (c *Client) SendCursor(cursor, anchor int) {
+	// Offsets include unacknowledged local edits the server has not seen,
+	// which is close enough for presence.
+	c.send(Message{Type: MsgCursor, Rev: c.rev, Cursor: cursor, Anchor: anchor})
+}
+
+// Handle updates session state for msg and returns the operation to apply
+// to the local document, if any.
+func (c *Client) Handle(msg Message) (Operation, error) {
+	switch msg.Type {
+	case MsgAck:
+		c.rev = msg.Rev
+		if len(c.pending) > 0 {
+			c.pending = c.pending[1:]
+		}
+		if len(c.pending) > 0 {
+			c.send(Message{Type: MsgOp, Rev: c.rev, Op: c.pending[0]})
+		}
+	case MsgOp:
+		op := msg.Op
+		if err := op.Validate(); err != nil {
+			return nil, err
+		}
+		c.rev = msg.Rev
+		for i := range c.pending {
+			var err error
+			if c.pending[i], op, err = Transform(c.pending[i], op); err != nil {
+				return nil, err
+			}
+		}
+		for _, p := range c.Participants {
+			p.Cursor = TransformIndex(op, p.Cursor)
+			p.Anchor = TransformIndex(op, p.Anchor)
+		}
+		return op, nil
+	case MsgCursor:
+		p, ok := c.Participants[msg.ID]
+		if !ok {
+			return nil, nil
+		}
+		p.Cursor, p.Anchor = msg.Cursor, msg.Anchor
+		for _, op := range c.pending {
+			p.Cursor = TransformIndex(op, p.Cursor)
+			p.Anchor = TransformIndex(op, p.Anchor)
+		}
+	case MsgJoin:
+		c.Participants[msg.ID] = &Participant{ID: msg.ID, Name: msg.Name, Color: msg.Color}
+	case MsgLeave:
+		delete(c.Participants, msg.ID)
+	}
+	return nil, nil
+}
@@ -0,0 +1,252 @@
+package collab
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// Component is one step of an Operation: exactly one field is set.
+type Component struct {
+	Retain int    `json:"r,omitempty"`
+	Insert string `json:"i,omitempty"`
+	Delete int    `json:"d,omitempty"`
+}
+
+// Operation is a sequence of retain/insert/delete steps spanning a whole
+// document, in the style of ot.js text operations. Offsets are in bytes.
+type Operation []Component
+
+var (
+	ErrBaseLength = errors.New("operation does not match document length")
+	ErrMalformed  = errors.New("malformed operation")
+)
+
+// Validate checks that each component sets exactly one field, to a
+// positive count or a non-empty insert. Operations from the network must
+// pass it before they are transformed or applied.
+func (o Operation) Validate() error {
+	for _, c := range o {
+		set := 0
+		if c.Retain != 0 {
+			set++
+		}
+		if c.Insert != "" {
+			set++
+		}
+		if c.Delete != 0 {
+			set++
+		}
+		if set != 1 || c.Retain < 0 || c.Delete < 0 {
+			return ErrMalformed
+		}
+	}
+	return nil
+}
+
+func (o Operation) retain(n int) Operation {
+	if n <= 0 {
+		return o
+	}
+	if len(o) > 0 && o[len(o)-1].Retain > 0 {
+		o[len(o)-1].Retain += n
+		return o
+	}
+	return append(o, Component{Retain: n})
+}
+
+func (o Operation) insert(s string) Operation {
+	if s == "" {
+		return o
+	}
+	last := len(o) - 1
+	switch {
+	case last >= 0 && o[last].Insert != "":
+		o[last].Insert += s
+		return o
+	case last >= 0 && o[last].Delete > 0:
+		// Keep inserts before deletes so equivalent operations compare equal.
+		if last > 0 && o[last-1].Insert != "" {
+			o[last-1].Insert += s
+			return o
+		}
+		o = append(o, o[last])
+		o[last] = Component{Insert: s}
+		return o
+	}
+	return append(o, Component{Insert: s})
+}
+
+func (o Operation) delete(n int) Operation {
+	if n <= 0 {
+		return o
+	}
+	if len(o) > 0 && o[len(o)-1].Delete > 0 {
+		o[len(o)-1].Delete += n
+		return o
+	}
+	return append(o, Component{Delete: n})
+}
+
+// BaseLen is the length of the document o applies to, or -1 when the
+// counts add up past the largest int, which no document matches.
+func (o Operation) BaseLen() int {
+	n := 0
+	for _, c := range o {
+		k := c.Retain + c.Delete
+		if k > math.MaxInt-n {
+			return -1
+		}
+		n += k
+	}
+	return n
+}
+
+func (o Operation) IsNoop() bool {
+	for _, c := range o {
+		if c.Insert != "" || c.Delete > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (o Operation) Apply(doc string) (string, error) {
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+	if len(doc) != o.BaseLen() {
+		return "", ErrBaseLength
+	}
+	var sb strings.Builder
+	pos := 0
+	for _, c := range o {
+		// BaseLen already matched; this keeps a bad count from ever
+		// slicing past the end.
+		if c.Retain+c.Delete > len(doc)-pos {
+			return "", ErrBaseLength
+		}
+		switch {
+		case c.Retain > 0:
+			sb.WriteString(doc[pos : pos+c.Retain])
+			pos += c.Retain
+		case c.Insert != "":
+			sb.WriteString(c.Insert)
+		case c.Delete > 0:
+			pos += c.Delete
+		}
+	}
+	return sb.String(), nil
+}
+
+// Diff builds the operation turning before into after by trimming the
+// common prefix and suffix.
+func Diff(before, after string) Operation {
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+
+	var op Operation
+	op = op.retain(prefix)
+	op = op.insert(after[prefix : len(after)-suffix])
+	op = op.delete(len(before) - prefix - suffix)
+	op = op.retain(suffix)
+	return op
+}
+
+// Transform returns a' and b' such that applying a then b' equals applying
+// b then a'. When both insert at the same place, a's text goes first.
+func Transform(a, b Operation) (Operation, Operation, error) {
+	if a.BaseLen() != b.BaseLen() {
+		return nil, nil, ErrBaseLength
+	}
+
+	var aPrime, bPrime Operation
+	i, j := 0, 0
+	var ca, cb Component
+	next := func(op Operation, idx *int) Component {
+		if *idx >= len(op) {
+			return Component{}
+		}
+		c := op[*idx]
+		*idx++
+		return c
+	}
+	ca, cb = next(a, &i), next(b, &j)
+
+	for ca != (Component{}) || cb != (Component{}) {
+		if ca.Insert != "" {
+			aPrime = aPrime.insert(ca.Insert)
+			bPrime = bPrime.retain(len(ca.Insert))
+			ca = next(a, &i)
+			continue
+		}
+		if cb.Insert != "" {
+			aPrime = aPrime.retain(len(cb.Insert))
+			bPrime = bPrime.insert(cb.Insert)
+			cb = next(b, &j)
+			continue
+		}
+		if ca == (Component{}) || cb == (Component{}) {
+			return nil, nil, ErrBaseLength
+		}
+
+		n := min(ca.Retain+ca.Delete, cb.Retain+cb.Delete)
+		switch {
+		case ca.Retain > 0 && cb.Retain > 0:
+			aPrime = aPrime.retain(n)
+			bPrime = bPrime.retain(n)
+		case ca.Delete > 0 && cb.Retain > 0:
+			aPrime = aPrime.delete(n)
+		case ca.Retain > 0 && cb.Delete > 0:
+			bPrime = bPrime.delete(n)
+		}
+		// Both deleting the same range needs nothing in either result.
+
+		ca = shrink(ca, n)
+		cb = shrink(cb, n)
+		if ca == (Component{}) {
+			ca = next(a, &i)
+		}
+		if cb == (Component{}) {
+			cb = next(b, &j)
+		}
+	}
+	return aPrime, bPrime, nil
+}
+
+func shrink(c Component, n int) Component {
+	if c.Retain > 0 {
+		c.Retain -= n
+	} else {
+		c.Delete -= n
+	}
+	return c
+}
+
+// TransformIndex maps a document offset through op, used to keep cursors in
+// place when other participants edit.
+func TransformIndex(op Operation, index int) int {
+	newIndex, pos := index, 0
+	for _, c := range op {
+		if pos > index {
+			break
+		}
+		switch {
+		case c.Retain > 0:
+			pos += c.Retain
+		case c.Insert != "":
+			newIndex += len(c.Insert)
+		case c.Delete > 0:
+			newIndex -= min(c.Delete, index-pos)
+			pos += c.Delete
+		}
+	}
+	return newIndex
+}
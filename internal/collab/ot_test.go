@@ -0,0 +1,69 @@
+package collab
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestApplyRejectsMalformedOperations(t *testing.T) {
+	for _, raw := range []string{
+		`[{"r":-1},{"r":4}]`,
+		`[{"d":-2},{"r":5}]`,
+		`[{"r":1,"i":"x"},{"r":2}]`,
+		`[{},{"r":3}]`,
+	} {
+		var op Operation
+		if err := json.Unmarshal([]byte(raw), &op); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := op.Apply("abc"); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Apply(%s) error = %v, want ErrMalformed", raw, err)
+		}
+	}
+}
+
+func TestApplyRejectsOverflowingCounts(t *testing.T) {
+	const doc = "abc"
+	for _, op := range []Operation{
+		{{Retain: math.MaxInt}, {Retain: math.MaxInt}, {Retain: len(doc) + 2}},
+		{{Delete: math.MaxInt}, {Retain: math.MaxInt}, {Delete: len(doc) + 2}},
+		{{Retain: math.MaxInt}, {Insert: "x"}, {Retain: 1}},
+	} {
+		if op.BaseLen() != -1 {
+			t.Errorf("BaseLen(%v) = %d, want -1", op, op.BaseLen())
+		}
+		if _, err := op.Apply(doc); !errors.Is(err, ErrBaseLength) {
+			t.Errorf("Apply(%v) error = %v, want ErrBaseLength", op, err)
+		}
+	}
+
+	s := &Server{doc: doc, conns: make(map[int]*serverConn)}
+	c := &serverConn{out: make(chan Message, sendQueue)}
+	s.conns[1] = c
+	s.applyOp(c, Message{Type: MsgOp, Op: Operation{{Retain: math.MaxInt}, {Retain: math.MaxInt}, {Retain: len(doc) + 2}}})
+	if s.doc != doc || len(s.history) != 0 {
+		t.Fatalf("overflowing op changed the document to %q", s.doc)
+	}
+}
+
+func TestServerDropsMalformedOperation(t *testing.T) {
+	s := &Server{doc: "abc", conns: make(map[int]*serverConn)}
+	c := &serverConn{out: make(chan Message, sendQueue)}
+	s.conns[1] = c
+
+	var bad Operation
+	if err := json.Unmarshal([]byte(`[{"r":-1},{"r":4}]`), &bad); err != nil {
+		t.Fatal(err)
+	}
+	s.applyOp(c, Message{Type: MsgOp, Op: bad})
+	if s.doc != "abc" || len(s.history) != 0 {
+		t.Fatalf("malformed op changed the document to %q", s.doc)
+	}
+
+	s.applyOp(c, Message{Type: MsgOp, Op: Operation{{Retain: 3}, {Insert: "d"}}})
+	if s.doc != "abcd" {
+		t.Fatalf("doc = %q, want %q", s.doc, "abcd")
+	}
+}
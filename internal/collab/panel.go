@@ -0,0 +1,174 @@
+package collab
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+// DefaultAddr only accepts peers on this machine; hosting for others means
+// choosing an interface explicitly.
+const DefaultAddr = "127.0.0.1:7878"
+
+type HostMsg struct {
+	Addr string
+}
+
+type JoinMsg struct {
+	Addr string
+}
+
+type LeaveMsg struct{}
+
+type panelMode int
+
+const (
+	modeMenu panelMode = iota
+	modeHost
+	modeJoin
+)
+
+type Panel struct {
+	visible bool
+	mode    panelMode
+	input   []rune
+	Status  string
+	Err     error
+	Client  *Client
+	Hosting string
+	Invite  string
+}
+
+func NewPanel() *Panel {
+	return &Panel{}
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+func (p *Panel) Toggle() {
+	p.visible = !p.visible
+	p.mode = modeMenu
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+	if p.mode != modeMenu {
+		return p.handleInputKey(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "esc", "alt+m":
+		p.visible = false
+	case "h":
+		if p.Client == nil {
+			p.mode = modeHost
+			p.input = []rune(DefaultAddr)
+		}
+	case "j":
+		if p.Client == nil {
+			p.mode = modeJoin
+			p.input = nil
+		}
+	case "l":
+		if p.Client != nil {
+			return func() tea.Msg { return LeaveMsg{} }
+		}
+	}
+	return nil
+}
+
+func (p *Panel) handleInputKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		addr := strings.TrimSpace(string(p.input))
+		mode := p.mode
+		p.mode = modeMenu
+		if addr == "" {
+			return nil
+		}
+		if mode == modeHost {
+			return func() tea.Msg { return HostMsg{Addr: addr} }
+		}
+		return func() tea.Msg { return JoinMsg{Addr: addr} }
+	case tea.KeyEsc:
+		p.mode = modeMenu
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case tea.KeyRunes:
+		p.input = append(p.input, msg.Runes...)
+	}
+	return nil
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+
+	lines := []string{titleStyle.Render(i18n.T("collab.title")) + hintStyle.Render("  "+i18n.T("collab.experimental"))}
+
+	switch {
+	case p.mode == modeHost:
+		lines = append(lines, textStyle.Render(i18n.T("collab.listenOn", string(p.input)+"▏")))
+	case p.mode == modeJoin:
+		lines = append(lines, textStyle.Render(i18n.T("collab.joinHost", string(p.input)+"▏")))
+	case p.Client == nil:
+		lines = append(lines, hintStyle.Render(i18n.T("collab.notInSession")))
+	default:
+		status := i18n.T("collab.joined", p.Client.DocName)
+		if p.Hosting != "" {
+			status = i18n.T("collab.hosting", p.Client.DocName, p.Hosting)
+		}
+		lines = append(lines, textStyle.Render(status))
+		if p.Invite != "" {
+			lines = append(lines, textStyle.Render(i18n.T("collab.invite", p.Invite)))
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color(p.Client.Color)).Render("● "+i18n.T("collab.you")))
+
+		ids := make([]int, 0, len(p.Client.Participants))
+		for id := range p.Client.Participants {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			participant := p.Client.Participants[id]
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color(participant.Color)).Render("● "+participant.Name))
+		}
+	}
+	if p.Status != "" {
+		lines = append(lines, hintStyle.Render(p.Status))
+	}
+	if p.Err != nil {
+		lines = append(lines, errorStyle.Render(p.Err.Error()))
+	}
+
+	hint := i18n.T("collab.hint")
+	if p.Client != nil {
+		hint = i18n.T("collab.hintInSession")
+	}
+	lines = append(lines, "", hintStyle.Render(hint))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#cba6f7")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(50).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,39 @@
+package collab
+
+const (
+	MsgHello   = "hello"
+	MsgWelcome = "welcome"
+	MsgOp      = "op"
+	MsgAck     = "ack"
+	MsgCursor  = "cursor"
+	MsgJoin    = "join"
+	MsgLeave   = "leave"
+)
+
+type Participant struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Color  string `json:"color"`
+	Cursor int    `json:"cursor"`
+	Anchor int    `json:"anchor"`
+}
+
+// Message is the single wire type; messages are newline-delimited JSON.
+type Message struct {
+	Type         string        `json:"type"`
+	ID           int           `json:"id,omitempty"`
+	Name         string        `json:"name,omitempty"`
+	Token        string        `json:"token,omitempty"`
+	Color        string        `json:"color,omitempty"`
+	Rev          int           `json:"rev"`
+	Op           Operation     `json:"op,omitempty"`
+	Doc          string        `json:"doc,omitempty"`
+	DocName      string        `json:"docName,omitempty"`
+	Cursor       int           `json:"cursor,omitempty"`
+	Anchor       int           `json:"anchor,omitempty"`
+	Participants []Participant `json:"participants,omitempty"`
+}
+
+var participantColors = []string{
+	"#f38ba8", "#a6e3a1", "#f9e2af", "#89b4fa", "#cba6f7", "#94e2d5", "#fab387",
+}
@@ -0,0 +1,247 @@
+package collab
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"sync"
+)
+
+// sendQueue is how many messages may wait for a slow peer before the
+// server gives up on it.
+const sendQueue = 256
+
+// Server owns the authoritative document. Clients send operations against
+// the revision they last saw; the server transforms them over everything
+// applied since and broadcasts the result. Only peers whose hello
+// carries the session's token may join.
+type Server struct {
+	listener net.Listener
+	docName  string
+	token    string
+
+	mu      sync.Mutex
+	doc     string
+	history []Operation
+	conns   map[int]*serverConn
+	nextID  int
+}
+
+// serverConn is one peer. Messages for it queue in out and are written by
+// its own goroutine, so a slow peer never holds up the others.
+type serverConn struct {
+	conn        net.Conn
+	out         chan Message
+	participant Participant
+}
+
+// send queues msg without blocking. A peer too slow to drain its queue is
+// disconnected rather than waited for.
+func (c *serverConn) send(msg Message) {
+	select {
+	case c.out <- msg:
+	default:
+		c.conn.Close()
+	}
+}
+
+func (c *serverConn) writeLoop() {
+	enc := json.NewEncoder(c.conn)
+	for msg := range c.out {
+		if err := enc.Encode(msg); err != nil {
+			c.conn.Close()
+		}
+	}
+}
+
+func Host(addr, docName, doc string) (*Server, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		listener: ln,
+		docName:  docName,
+		token:    hex.EncodeToString(token),
+		doc:      doc,
+		conns:    make(map[int]*serverConn),
+	}
+	go s.acceptLoop()
+	return s, nil
+}
+
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Token is the secret peers must send to join.
+func (s *Server) Token() string {
+	return s.token
+}
+
+func (s *Server) authorized(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	for _, c := range s.conns {
+		c.conn.Close()
+	}
+	s.mu.Unlock()
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	var c *serverConn
+	defer func() {
+		if c != nil {
+			s.leave(c)
+		}
+	}()
+
+	for scanner.Scan() {
+		var msg Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+		switch {
+		case msg.Type == MsgHello && c == nil:
+			if !s.authorized(msg.Token) {
+				return
+			}
+			c = s.join(conn, msg.Name)
+		case c == nil:
+			return
+		case msg.Type == MsgOp:
+			s.applyOp(c, msg)
+		case msg.Type == MsgCursor:
+			s.moveCursor(c, msg)
+		}
+	}
+}
+
+func (s *Server) join(conn net.Conn, name string) *serverConn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	c := &serverConn{
+		conn: conn,
+		out:  make(chan Message, sendQueue),
+		participant: Participant{
+			ID:    s.nextID,
+			Name:  name,
+			Color: participantColors[(s.nextID-1)%len(participantColors)],
+		},
+	}
+
+	participants := make([]Participant, 0, len(s.conns))
+	for _, other := range s.conns {
+		participants = append(participants, other.participant)
+		other.send(Message{Type: MsgJoin, ID: c.participant.ID, Name: name, Color: c.participant.Color})
+	}
+	s.conns[c.participant.ID] = c
+
+	c.send(Message{
+		Type:         MsgWelcome,
+		ID:           c.participant.ID,
+		Color:        c.participant.Color,
+		Rev:          len(s.history),
+		Doc:          s.doc,
+		DocName:      s.docName,
+		Participants: participants,
+	})
+	go c.writeLoop()
+	return c
+}
+
+func (s *Server) leave(c *serverConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, c.participant.ID)
+	close(c.out)
+	for _, other := range s.conns {
+		other.send(Message{Type: MsgLeave, ID: c.participant.ID})
+	}
+}
+
+func (s *Server) applyOp(c *serverConn, msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg.Rev < 0 || msg.Rev > len(s.history) {
+		return
+	}
+	op := msg.Op
+	if op.Validate() != nil {
+		return
+	}
+	for _, applied := range s.history[msg.Rev:] {
+		var err error
+		if op, _, err = Transform(op, applied); err != nil {
+			return
+		}
+	}
+	doc, err := op.Apply(s.doc)
+	if err != nil {
+		return
+	}
+	s.doc = doc
+	s.history = append(s.history, op)
+	rev := len(s.history)
+
+	for _, other := range s.conns {
+		other.participant.Cursor = TransformIndex(op, other.participant.Cursor)
+		other.participant.Anchor = TransformIndex(op, other.participant.Anchor)
+		if other == c {
+			other.send(Message{Type: MsgAck, Rev: rev})
+		} else {
+			other.send(Message{Type: MsgOp, ID: c.participant.ID, Rev: rev, Op: op})
+		}
+	}
+}
+
+func (s *Server) moveCursor(c *serverConn, msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg.Rev < 0 || msg.Rev > len(s.history) {
+		return
+	}
+	cursor, anchor := msg.Cursor, msg.Anchor
+	for _, applied := range s.history[msg.Rev:] {
+		cursor = TransformIndex(applied, cursor)
+		anchor = TransformIndex(applied, anchor)
+	}
+	c.participant.Cursor = cursor
+	c.participant.Anchor = anchor
+
+	for _, other := range s.conns {
+		if other != c {
+			other.send(Message{Type: MsgCursor, ID: c.participant.ID, Rev: len(s.history), Cursor: cursor, Anchor: anchor})
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package collab
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestServerRejectsHelloWithoutToken(t *testing.T) {
+	s := &Server{token: "secret", doc: "abc", conns: make(map[int]*serverConn)}
+	for _, token := range []string{"", "wrong"} {
+		client, conn := net.Pipe()
+		go s.serve(conn)
+		go json.NewEncoder(client).Encode(Message{Type: MsgHello, Name: "eve", Token: token})
+
+		client.SetReadDeadline(time.Now().Add(time.Second))
+		if bufio.NewScanner(client).Scan() {
+			t.Errorf("token %q: got a reply, want the connection closed", token)
+		}
+		client.Close()
+	}
+	if len(s.conns) != 0 {
+		t.Fatalf("%d peers joined without the token", len(s.conns))
+	}
+}
+
+func TestServerDisconnectsSlowPeer(t *testing.T) {
+	s := &Server{doc: "abc", conns: make(map[int]*serverConn)}
+	fast := &serverConn{out: make(chan Message, sendQueue), participant: Participant{ID: 1}}
+	slowClient, slowConn := net.Pipe()
+	defer slowClient.Close()
+	slow := &serverConn{conn: slowConn, out: make(chan Message, 1), participant: Participant{ID: 2}}
+	s.conns[1], s.conns[2] = fast, slow
+
+	done := make(chan struct{})
+	go func() {
+		s.applyOp(fast, Message{Type: MsgOp, Op: Operation{{Retain: 3}, {Insert: "d"}}})
+		s.applyOp(fast, Message{Type: MsgOp, Rev: 1, Op: Operation{{Retain: 4}, {Insert: "e"}}})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a peer that never reads held up the server")
+	}
+	if s.doc != "abcde" {
+		t.Fatalf("doc = %q, want %q", s.doc, "abcde")
+	}
+	if _, err := slowClient.Read(make([]byte, 1)); err == nil {
+		t.Fatal("slow peer still connected")
+	}
+}
@@ -0,0 +1,164 @@
+package command
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Bool
+)
+
+func (t Type) String() string {
+	switch t {
+	case Int:
+		return "int"
+	case Bool:
+		return "bool"
+	}
+	return "string"
+}
+
+type Param struct {
+	Name     string
+	Type     Type
+	Optional bool
+}
+
+// Spec describes a command that can be invoked by name, from the palette,
+// a keybinding, a macro file or a plugin.
+type Spec struct {
+	Name   string
+	Title  string
+	Params []Param
+}
+
+type Args map[string]interface{}
+
+func (a Args) String(name string) string {
+	s, _ := a[name].(string)
+	return s
+}
+
+func (a Args) Int(name string) int {
+	n, _ := a[name].(int)
+	return n
+}
+
+func (a Args) Bool(name string) bool {
+	b, _ := a[name].(bool)
+	return b
+}
+
+func (a Args) Has(name string) bool {
+	_, ok := a[name]
+	return ok
+}
+
+// Invocation is a command name with its arguments, as written in
+// `editor.goToLine {line: 42}`.
+type Invocation struct {
+	Name string
+	Args Args
+}
+
+// RunMsg asks the app to execute an invocation through the dispatcher.
+type RunMsg struct {
+	Invocation Invocation
+}
+
+// Validate checks args against the spec and converts them to the declared
+// types, so handlers can rely on Args.Int and friends.
+func (s Spec) Validate(args Args) (Args, error) {
+	known := make(map[string]bool, len(s.Params))
+	out := make(Args, len(args))
+	for _, p := range s.Params {
+		known[p.Name] = true
+		v, ok := args[p.Name]
+		if !ok {
+			if !p.Optional {
+				return nil, fmt.Errorf("%s: missing argument %q", s.Name, p.Name)
+			}
+			continue
+		}
+		converted, err := convert(v, p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: argument %q: %w", s.Name, p.Name, err)
+		}
+		out[p.Name] = converted
+	}
+
+	var unknown []string
+	for name := range args {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%s: unknown argument %q", s.Name, unknown[0])
+	}
+	return out, nil
+}
+
+func convert(v interface{}, t Type) (interface{}, error) {
+	switch t {
+	case Int:
+		switch n := v.(type) {
+		case int:
+			return n, nil
+		case float64:
+			if n != math.Trunc(n) {
+				return nil, fmt.Errorf("want int, got %v", n)
+			}
+			return int(n), nil
+		case string:
+			i, err := strconv.Atoi(strings.TrimSpace(n))
+			if err != nil {
+				return nil, fmt.Errorf("want int, got %q", n)
+			}
+			return i, nil
+		}
+	case Bool:
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			parsed, err := strconv.ParseBool(b)
+			if err != nil {
+				return nil, fmt.Errorf("want bool, got %q", b)
+			}
+			return parsed, nil
+		}
+	case String:
+		switch s := v.(type) {
+		case string:
+			return s, nil
+		case float64, int, bool:
+			return fmt.Sprint(s), nil
+		}
+	}
+	return nil, fmt.Errorf("want %s, got %T", t, v)
+}
+
+// Usage renders the spec as it would be typed, e.g. `editor.goToLine {line: int}`.
+func (s Spec) Usage() string {
+	if len(s.Params) == 0 {
+		return s.Name
+	}
+	parts := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		parts[i] = p.Name + ": " + p.Type.String()
+		if p.Optional {
+			parts[i] = p.Name + "?: " + p.Type.String()
+		}
+	}
+	return s.Name + " {" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,245 @@
+package command
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"tron/internal/i18n"
+)
+
+const keymapRows = 14
+
+// KeymapChangeMsg asks the app to write bindings to the keybindings file.
+// An empty value unbinds the key.
+type KeymapChangeMsg struct {
+	Bindings map[string]string
+}
+
+// KeymapPanel lists every command with the keys bound to it. Typing
+// filters the list, enter waits for a new key for the selected command and
+// delete unbinds it. Keys that are also handled inside a panel, such as
+// the editor's own shortcuts, are flagged because the binding wins.
+type KeymapPanel struct {
+	visible  bool
+	specs    []Spec
+	keymap   map[string]Invocation
+	builtin  map[string]string
+	filter   []rune
+	selected int
+	// capturing waits for the key to bind; pending holds one that is
+	// already bound elsewhere until the change is confirmed.
+	capturing bool
+	pending   string
+}
+
+func NewKeymapPanel() *KeymapPanel {
+	return &KeymapPanel{}
+}
+
+func (p *KeymapPanel) Visible() bool {
+	return p.visible
+}
+
+// Open shows the panel for specs and keymap. builtin maps keys handled
+// inside panels to the names of those panels.
+func (p *KeymapPanel) Open(specs []Spec, keymap map[string]Invocation, builtin map[string]string) {
+	p.visible = true
+	p.specs = specs
+	p.keymap = keymap
+	p.builtin = builtin
+	p.filter = nil
+	p.selected = 0
+	p.capturing = false
+	p.pending = ""
+}
+
+func (p *KeymapPanel) SetKeymap(keymap map[string]Invocation) {
+	p.keymap = keymap
+}
+
+func (p *KeymapPanel) matches() []Spec {
+	var out []Spec
+	for _, match := range filterSpecs(string(p.filter), p.specs) {
+		out = append(out, match.Spec)
+	}
+	return out
+}
+
+// keysFor returns the keys bound to the command name, sorted.
+func (p *KeymapPanel) keysFor(name string) []string {
+	var keys []string
+	for key, inv := range p.keymap {
+		if inv.Name == name {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p *KeymapPanel) current() (Spec, bool) {
+	matches := p.matches()
+	if p.selected >= len(matches) {
+		return Spec{}, false
+	}
+	return matches[p.selected], true
+}
+
+func (p *KeymapPanel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+	if p.pending != "" {
+		return p.handleConfirm(keyMsg)
+	}
+	if p.capturing {
+		return p.handleCapture(keyMsg)
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		p.visible = false
+	case tea.KeyUp:
+		if p.selected > 0 {
+			p.selected--
+		}
+	case tea.KeyDown:
+		if p.selected < len(p.matches())-1 {
+			p.selected++
+		}
+	case tea.KeyEnter:
+		if _, ok := p.current(); ok {
+			p.capturing = true
+		}
+	case tea.KeyDelete:
+		if spec, ok := p.current(); ok {
+			return p.bind(spec, "")
+		}
+	case tea.KeyBackspace:
+		if len(p.filter) > 0 {
+			p.filter = p.filter[:len(p.filter)-1]
+		}
+		p.selected = 0
+	case tea.KeySpace:
+		p.filter = append(p.filter, ' ')
+		p.selected = 0
+	case tea.KeyRunes:
+		p.filter = append(p.filter, keyMsg.Runes...)
+		p.selected = 0
+	}
+	return nil
+}
+
+func (p *KeymapPanel) handleCapture(msg tea.KeyMsg) tea.Cmd {
+	p.capturing = false
+	key := msg.String()
+	if msg.Type == tea.KeyEsc {
+		return nil
+	}
+	spec, ok := p.current()
+	if !ok {
+		return nil
+	}
+	if inv, bound := p.keymap[key]; bound && inv.Name != spec.Name {
+		p.pending = key
+		return nil
+	}
+	return p.bind(spec, key)
+}
+
+func (p *KeymapPanel) handleConfirm(msg tea.KeyMsg) tea.Cmd {
+	key := p.pending
+	p.pending = ""
+	spec, ok := p.current()
+	if msg.Type != tea.KeyEnter || !ok {
+		return nil
+	}
+	return p.bind(spec, key)
+}
+
+// bind makes key the only binding of spec, or removes its bindings when key
+// is empty.
+func (p *KeymapPanel) bind(spec Spec, key string) tea.Cmd {
+	bindings := make(map[string]string)
+	for _, old := range p.keysFor(spec.Name) {
+		bindings[old] = ""
+	}
+	if key != "" {
+		bindings[key] = spec.Name
+	}
+	if len(bindings) == 0 {
+		return nil
+	}
+	return func() tea.Msg { return KeymapChangeMsg{Bindings: bindings} }
+}
+
+func (p *KeymapPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := lipgloss.NewStyle().Background(lipgloss.Color("#313244")).Foreground(lipgloss.Color("#cdd6f4"))
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#fab387"))
+
+	lines := []string{
+		textStyle.Bold(true).Render(i18n.T("keymap.title")),
+		textStyle.Render("> " + string(p.filter) + "▏"),
+	}
+	matches := p.matches()
+	start := 0
+	if p.selected >= keymapRows {
+		start = p.selected - keymapRows + 1
+	}
+	for i := start; i < len(matches) && i < start+keymapRows; i++ {
+		spec := matches[i]
+		title := ansi.Truncate(spec.Title, 32, "…")
+		row := title + strings.Repeat(" ", 34-ansi.StringWidth(title))
+		if i == p.selected {
+			row = selectedStyle.Render(row)
+		} else {
+			row = textStyle.Render(row)
+		}
+		var keys []string
+		for _, key := range p.keysFor(spec.Name) {
+			k := keyStyle.Render(key)
+			if ctx := p.builtin[key]; ctx != "" {
+				k += warnStyle.Render(" ⚠ " + i18n.T("keymap.shadows", ctx))
+			}
+			keys = append(keys, k)
+		}
+		lines = append(lines, ansi.Truncate(row+strings.Join(keys, hintStyle.Render(", ")), 76, "…"))
+	}
+	if len(matches) == 0 {
+		lines = append(lines, hintStyle.Render(i18n.T("palette.empty")))
+	}
+
+	spec, _ := p.current()
+	switch {
+	case p.pending != "":
+		msg := i18n.T("keymap.conflict", p.pending, p.keymap[p.pending].Name)
+		if ctx := p.builtin[p.pending]; ctx != "" {
+			msg += " · " + i18n.T("keymap.shadows", ctx)
+		}
+		lines = append(lines, warnStyle.Render(msg))
+	case p.capturing:
+		lines = append(lines, keyStyle.Render(i18n.T("keymap.press", spec.Title)))
+	default:
+		lines = append(lines, hintStyle.Render(i18n.T("keymap.hint")))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(80).
+		Render(strings.Join(lines, "\n"))
+}
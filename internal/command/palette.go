@@ -0,0 +1,226 @@
+package command
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"tron/internal/i18n"
+	"tron/pkg/fuzzy"
+)
+
+const paletteRows = 12
+
+// Palette lists commands filtered by the typed name. Enter runs the
+// selected command, or fills in its argument template when it takes
+// arguments; a typed line with arguments runs as written. Before anything
+// is typed, the digits 1-9 pick one of the quick actions shown above the
+// list.
+type Palette struct {
+	visible  bool
+	specs    []Spec
+	quick    []Spec
+	input    []rune
+	selected int
+	Err      error
+}
+
+func NewPalette() *Palette {
+	return &Palette{}
+}
+
+func (p *Palette) Visible() bool {
+	return p.visible
+}
+
+func (p *Palette) Open(specs, quick []Spec) {
+	p.visible = true
+	p.specs = specs
+	p.quick = quick
+	p.input = nil
+	p.selected = 0
+	p.Err = nil
+}
+
+// Prompt opens the palette with the argument template of the command name
+// filled in, for commands run without an argument they need.
+func (p *Palette) Prompt(specs []Spec, name, param string) {
+	p.Open(specs, nil)
+	p.input = []rune(name + " {" + param + ": ")
+}
+
+// specMatch is a spec a typed filter matched, with the byte offsets of
+// the matched characters in its title.
+type specMatch struct {
+	Spec
+	score     int
+	positions []int
+}
+
+// filterSpecs returns the specs query fuzzily matches by name or title,
+// best first.
+func filterSpecs(query string, specs []Spec) []specMatch {
+	var out []specMatch
+	for _, spec := range specs {
+		nameScore, _, nameOK := fuzzy.Score(query, spec.Name)
+		titleScore, positions, titleOK := fuzzy.Score(query, spec.Title)
+		if !nameOK && !titleOK {
+			continue
+		}
+		score := titleScore
+		if nameOK && (!titleOK || nameScore > titleScore) {
+			score = nameScore
+		}
+		out = append(out, specMatch{Spec: spec, score: score, positions: positions})
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].score > out[b].score })
+	return out
+}
+
+func (p *Palette) matches() []specMatch {
+	query := strings.TrimSpace(string(p.input))
+	if i := strings.IndexAny(query, " {"); i >= 0 {
+		query = query[:i]
+	}
+	return filterSpecs(query, p.specs)
+}
+
+func (p *Palette) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		p.visible = false
+	case tea.KeyUp:
+		if p.selected > 0 {
+			p.selected--
+		}
+	case tea.KeyDown:
+		if p.selected < len(p.matches())-1 {
+			p.selected++
+		}
+	case tea.KeyEnter:
+		return p.submit()
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+		p.selected = 0
+	case tea.KeySpace:
+		p.input = append(p.input, ' ')
+	case tea.KeyRunes:
+		if spec, ok := p.quickAction(keyMsg.Runes); ok {
+			return p.choose(spec)
+		}
+		p.input = append(p.input, keyMsg.Runes...)
+		p.selected = 0
+	}
+	return nil
+}
+
+func (p *Palette) submit() tea.Cmd {
+	line := strings.TrimSpace(string(p.input))
+	if strings.Contains(line, "{") {
+		inv, err := Parse(line)
+		if err != nil {
+			p.Err = err
+			return nil
+		}
+		return p.run(inv)
+	}
+
+	matches := p.matches()
+	if p.selected >= len(matches) {
+		return nil
+	}
+	return p.choose(matches[p.selected].Spec)
+}
+
+// quickAction returns the quick action picked by typing runes into the
+// empty input.
+func (p *Palette) quickAction(runes []rune) (Spec, bool) {
+	if len(p.input) > 0 || len(runes) != 1 || runes[0] < '1' || runes[0] > '9' {
+		return Spec{}, false
+	}
+	i := int(runes[0] - '1')
+	if i >= len(p.quick) {
+		return Spec{}, false
+	}
+	return p.quick[i], true
+}
+
+// choose runs spec, or fills in its argument template when it has required
+// arguments.
+func (p *Palette) choose(spec Spec) tea.Cmd {
+	for _, param := range spec.Params {
+		if !param.Optional {
+			p.input = []rune(spec.Name + " {" + param.Name + ": ")
+			p.Err = nil
+			return nil
+		}
+	}
+	return p.run(Invocation{Name: spec.Name, Args: Args{}})
+}
+
+func (p *Palette) run(inv Invocation) tea.Cmd {
+	p.visible = false
+	return func() tea.Msg { return RunMsg{Invocation: inv} }
+}
+
+func (p *Palette) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	matchStyle := textStyle.Foreground(lipgloss.Color("#f9e2af")).Bold(true)
+
+	lines := []string{textStyle.Render("> " + string(p.input) + "▏")}
+	if len(p.input) == 0 && len(p.quick) > 0 {
+		keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+		var actions []string
+		for i, spec := range p.quick {
+			actions = append(actions, keyStyle.Render(string(rune('1'+i)))+" "+textStyle.Render(spec.Title))
+		}
+		lines = append(lines, ansi.Truncate(strings.Join(actions, hintStyle.Render(" · ")), 70, "…"))
+	}
+	matches := p.matches()
+	start := 0
+	if p.selected >= paletteRows {
+		start = p.selected - paletteRows + 1
+	}
+	for i := start; i < len(matches) && i < start+paletteRows; i++ {
+		spec := matches[i]
+		if i == p.selected {
+			lines = append(lines, selectedStyle.Render(spec.Title+"  "+spec.Usage()))
+		} else {
+			title := fuzzy.Highlight(spec.Title, spec.positions,
+				func(s string) string { return matchStyle.Render(s) },
+				func(s string) string { return textStyle.Render(s) })
+			lines = append(lines, title+hintStyle.Render("  "+spec.Usage()))
+		}
+	}
+	if len(matches) == 0 {
+		lines = append(lines, hintStyle.Render(i18n.T("palette.empty")))
+	}
+	if p.Err != nil {
+		lines = append(lines, errorStyle.Render(p.Err.Error()))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(72).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,207 @@
+package command
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Parse reads an invocation such as `editor.goToLine {line: 42}`. The
+// arguments may be strict JSON or a relaxed object with bare keys and
+// unquoted words.
+func Parse(line string) (Invocation, error) {
+	line = strings.TrimSpace(line)
+	end := strings.IndexAny(line, " \t{")
+	if end < 0 {
+		end = len(line)
+	}
+	inv := Invocation{Name: line[:end], Args: Args{}}
+	if inv.Name == "" {
+		return inv, fmt.Errorf("empty command")
+	}
+
+	rest := strings.TrimSpace(line[end:])
+	if rest == "" {
+		return inv, nil
+	}
+	if err := json.Unmarshal([]byte(rest), &inv.Args); err == nil {
+		return inv, nil
+	}
+	args, err := parseRelaxed(rest)
+	if err != nil {
+		return inv, fmt.Errorf("%s: %w", inv.Name, err)
+	}
+	inv.Args = args
+	return inv, nil
+}
+
+type argParser struct {
+	s   string
+	pos int
+}
+
+func parseRelaxed(s string) (Args, error) {
+	p := &argParser{s: s}
+	p.skipSpace()
+	if !p.consume('{') {
+		return nil, fmt.Errorf("arguments must start with {")
+	}
+
+	args := Args{}
+	for {
+		p.skipSpace()
+		if p.consume('}') {
+			break
+		}
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(':') {
+			return nil, fmt.Errorf("expected : after %q", key)
+		}
+		p.skipSpace()
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args[key] = value
+		p.skipSpace()
+		if p.consume(',') {
+			continue
+		}
+		if !p.consume('}') {
+			return nil, fmt.Errorf("expected , or } after %q", key)
+		}
+		break
+	}
+
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q after arguments", p.s[p.pos:])
+	}
+	return args, nil
+}
+
+func (p *argParser) skipSpace() {
+	for p.pos < len(p.s) && strings.ContainsRune(" \t\r\n", rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *argParser) consume(c byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *argParser) key() (string, error) {
+	if p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+		return p.quoted()
+	}
+	start := p.pos
+	for p.pos < len(p.s) && isWordByte(p.s[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", fmt.Errorf("expected argument name at %q", p.s[p.pos:])
+	}
+	return p.s[start:p.pos], nil
+}
+
+func (p *argParser) value() (interface{}, error) {
+	if p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+		return p.quoted()
+	}
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != ',' && p.s[p.pos] != '}' {
+		p.pos++
+	}
+	word := strings.TrimSpace(p.s[start:p.pos])
+	if word == "" {
+		return nil, fmt.Errorf("missing value")
+	}
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if n, err := strconv.Atoi(word); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, nil
+	}
+	return word, nil
+}
+
+func (p *argParser) quoted() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return sb.String(), nil
+		case c == '\\' && p.pos < len(p.s):
+			next := p.s[p.pos]
+			p.pos++
+			switch next {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(next)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// LoadMacro reads a macro file: one invocation per line, with blank lines
+// and lines starting with # ignored.
+func LoadMacro(path string) ([]Invocation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadMacro(f, path)
+}
+
+// ReadMacro reads a macro like LoadMacro from r; name labels its errors.
+func ReadMacro(r io.Reader, name string) ([]Invocation, error) {
+	var invocations []Invocation
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		inv, err := Parse(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, lineNum, err)
+		}
+		invocations = append(invocations, inv)
+	}
+	return invocations, scanner.Err()
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Usage counts how often each command was run, so the most used ones can
+// be offered as quick actions.
+type Usage struct {
+	path   string
+	Counts map[string]int `json:"counts"`
+}
+
+// LoadUsage reads the counts stored at path, starting empty when there are
+// none yet.
+func LoadUsage(path string) *Usage {
+	u := &Usage{path: path}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, u)
+	}
+	if u.Counts == nil {
+		u.Counts = make(map[string]int)
+	}
+	return u
+}
+
+// Record counts one run of name and writes the counts back.
+func (u *Usage) Record(name string) error {
+	u.Counts[name]++
+	if err := os.MkdirAll(filepath.Dir(u.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(u.path, data, 0644)
+}
+
+// Top returns up to n of specs, most used first, leaving out those never
+// run. Ties keep the order of specs.
+func (u *Usage) Top(specs []Spec, n int) []Spec {
+	var used []Spec
+	for _, spec := range specs {
+		if u.Counts[spec.Name] > 0 {
+			used = append(used, spec)
+		}
+	}
+	sort.SliceStable(used, func(i, j int) bool {
+		return u.Counts[used[i].Name] > u.Counts[used[j].Name]
+	})
+	if len(used) > n {
+		used = used[:n]
+	}
+	return used
+}
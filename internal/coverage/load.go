@@ -0,0 +1,51 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+var ReportFiles = []string{
+	"coverage.out",
+	"cover.out",
+	"coverage.xml",
+	"lcov.info",
+	filepath.Join("coverage", "lcov.info"),
+}
+
+func Load(rootPath string) (*Report, error) {
+	report := NewReport()
+	found := false
+	for _, name := range ReportFiles {
+		path := filepath.Join(rootPath, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		r, err := parseFile(path, rootPath)
+		if err != nil {
+			return nil, err
+		}
+		report.merge(r)
+		found = true
+	}
+	if !found {
+		return nil, os.ErrNotExist
+	}
+	return report, nil
+}
+
+func Command(rootPath string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(rootPath, name))
+		return err == nil
+	}
+
+	switch {
+	case exists("go.mod"):
+		return "go test -coverprofile=coverage.out ./..."
+	case exists("package.json"):
+		return "npx jest --coverage --coverageReporters=lcov"
+	default:
+		return "python -m coverage run -m pytest; python -m coverage xml -o coverage.xml"
+	}
+}
@@ -0,0 +1,143 @@
+package coverage
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"tron/internal/runconfig"
+)
+
+// ParseGoProfile reads the output of go test -coverprofile. Profile entries
+// use import paths, so modulePath is stripped to map them under rootPath.
+func ParseGoProfile(r io.Reader, rootPath, modulePath string) (*Report, error) {
+	report := NewReport()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("invalid coverage line: %q", line)
+		}
+		var startLine, startCol, endLine, endCol, stmts, count int
+		_, err := fmt.Sscanf(line[colon+1:], "%d.%d,%d.%d %d %d", &startLine, &startCol, &endLine, &endCol, &stmts, &count)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coverage line: %q", line)
+		}
+
+		name := line[:colon]
+		if modulePath != "" && strings.HasPrefix(name, modulePath+"/") {
+			name = strings.TrimPrefix(name, modulePath+"/")
+		}
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(rootPath, filepath.FromSlash(name))
+		}
+
+		f := report.file(name)
+		for l := startLine; l <= endLine; l++ {
+			f.mark(l-1, count > 0)
+		}
+	}
+	return report, scanner.Err()
+}
+
+func ParseLCOV(r io.Reader, rootPath string) (*Report, error) {
+	report := NewReport()
+	var current *FileCoverage
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			name := strings.TrimPrefix(line, "SF:")
+			if !filepath.IsAbs(name) {
+				name = filepath.Join(rootPath, name)
+			}
+			current = report.file(name)
+		case strings.HasPrefix(line, "DA:") && current != nil:
+			parts := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(parts) < 2 {
+				continue
+			}
+			lineNum, err1 := strconv.Atoi(parts[0])
+			hits, err2 := strconv.Atoi(parts[1])
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			current.mark(lineNum-1, hits > 0)
+		case line == "end_of_record":
+			current = nil
+		}
+	}
+	return report, scanner.Err()
+}
+
+type coberturaReport struct {
+	Sources []string         `xml:"sources>source"`
+	Classes []coberturaClass `xml:"packages>package>classes>class"`
+}
+
+type coberturaClass struct {
+	Filename string          `xml:"filename,attr"`
+	Lines    []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// ParseCoberturaXML reads the XML report written by coverage.py.
+func ParseCoberturaXML(r io.Reader, rootPath string) (*Report, error) {
+	var doc coberturaReport
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	base := rootPath
+	if len(doc.Sources) > 0 {
+		base = doc.Sources[0]
+		if !filepath.IsAbs(base) {
+			base = filepath.Join(rootPath, base)
+		}
+	}
+
+	report := NewReport()
+	for _, class := range doc.Classes {
+		name := class.Filename
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(base, name)
+		}
+		f := report.file(name)
+		for _, l := range class.Lines {
+			f.mark(l.Number-1, l.Hits > 0)
+		}
+	}
+	return report, nil
+}
+
+func parseFile(path, rootPath string) (*Report, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch {
+	case strings.HasSuffix(path, ".xml"):
+		return ParseCoberturaXML(file, rootPath)
+	case strings.HasSuffix(path, ".info"):
+		return ParseLCOV(file, rootPath)
+	default:
+		return ParseGoProfile(file, rootPath, runconfig.GoModulePath(rootPath))
+	}
+}
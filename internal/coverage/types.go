@@ -0,0 +1,68 @@
+package coverage
+
+import (
+	"tron/pkg/pathutil"
+)
+
+type FileCoverage struct {
+	Path  string
+	Lines map[int]bool
+}
+
+func newFileCoverage(path string) *FileCoverage {
+	return &FileCoverage{Path: path, Lines: make(map[int]bool)}
+}
+
+func (f *FileCoverage) mark(line int, covered bool) {
+	if line < 0 {
+		return
+	}
+	f.Lines[line] = f.Lines[line] || covered
+}
+
+func (f *FileCoverage) Percent() float64 {
+	if len(f.Lines) == 0 {
+		return 0
+	}
+	covered := 0
+	for _, c := range f.Lines {
+		if c {
+			covered++
+		}
+	}
+	return float64(covered) * 100 / float64(len(f.Lines))
+}
+
+type Report struct {
+	Files map[string]*FileCoverage
+}
+
+func NewReport() *Report {
+	return &Report{Files: make(map[string]*FileCoverage)}
+}
+
+func (r *Report) file(path string) *FileCoverage {
+	path = pathutil.Canonical(path)
+	f, ok := r.Files[path]
+	if !ok {
+		f = newFileCoverage(path)
+		r.Files[path] = f
+	}
+	return f
+}
+
+func (r *Report) ForFile(path string) *FileCoverage {
+	if r == nil {
+		return nil
+	}
+	return r.Files[pathutil.Canonical(path)]
+}
+
+func (r *Report) merge(other *Report) {
+	for _, f := range other.Files {
+		dst := r.file(f.Path)
+		for line, covered := range f.Lines {
+			dst.mark(line, covered)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package dbconsole
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+type Config struct {
+	Connection string `json:"connection"`
+}
+
+// configPath is the user's databases.json, which holds each workspace's
+// connection by its absolute path. Connections carry passwords, so they
+// live outside the workspace, readable only by the user.
+func configPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tron", "databases.json")
+}
+
+func loadConfigs() map[string]*Config {
+	configs := make(map[string]*Config)
+	if path := configPath(); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			_ = json.Unmarshal(data, &configs)
+		}
+	}
+	return configs
+}
+
+func workspaceKey(rootPath string) string {
+	if abs, err := filepath.Abs(rootPath); err == nil {
+		return abs
+	}
+	return rootPath
+}
+
+func LoadConfig(rootPath string) *Config {
+	if c := loadConfigs()[workspaceKey(rootPath)]; c != nil {
+		return c
+	}
+	return &Config{}
+}
+
+func SaveConfig(rootPath string, c *Config) error {
+	path := configPath()
+	if path == "" {
+		return os.ErrNotExist
+	}
+	configs := loadConfigs()
+	if c.Connection == "" {
+		delete(configs, workspaceKey(rootPath))
+	} else {
+		configs[workspaceKey(rootPath)] = c
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(configs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of a file that already existed.
+	return os.Chmod(path, 0600)
+}
@@ -0,0 +1,34 @@
+package dbconsole
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigIsSavedOutsideTheWorkspace(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	root := t.TempDir()
+
+	if err := SaveConfig(root, &Config{Connection: "postgres://app:s3cret@db/shop"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadConfig(root).Connection; got != "postgres://app:s3cret@db/shop" {
+		t.Fatalf("LoadConfig = %q", got)
+	}
+	if got := LoadConfig(t.TempDir()).Connection; got != "" {
+		t.Fatalf("another workspace got %q", got)
+	}
+	info, err := os.Stat(configPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("config mode = %v, want 0600", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(root, ".tron")); err == nil {
+		t.Error("SaveConfig wrote into the workspace")
+	}
+}
@@ -0,0 +1,161 @@
+package dbconsole
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const QueryTimeout = 30 * time.Second
+
+var ErrNoConnection = errors.New("no database connection configured")
+
+type Result struct {
+	Statement Statement
+	Columns   []string
+	Rows      [][]string
+	Message   string
+	Err       error
+	ErrLine   int
+	Duration  time.Duration
+}
+
+var errorLineRegex = regexp.MustCompile(`(?i)\bline (\d+)`)
+
+// command builds a CLI invocation for the connection string. The scheme
+// selects the client: postgres(ql)://, mysql:// or sqlite:// (a bare path
+// is treated as an sqlite database file). Passwords go to the client in
+// its environment, never on the command line where ps shows them.
+func command(ctx context.Context, conn, sql string) (*exec.Cmd, error) {
+	if conn == "" {
+		return nil, ErrNoConnection
+	}
+
+	scheme, rest, hasScheme := strings.Cut(conn, "://")
+	if !hasScheme {
+		return exec.CommandContext(ctx, "sqlite3", "-batch", "-header", "-separator", "\t", conn, sql), nil
+	}
+
+	switch scheme {
+	case "postgres", "postgresql":
+		u, err := url.Parse(conn)
+		if err != nil {
+			return nil, err
+		}
+		pw, hasPw := u.User.Password()
+		if hasPw {
+			u.User = url.User(u.User.Username())
+		}
+		cmd := exec.CommandContext(ctx, "psql", u.String(), "-X", "-A", "-F", "\t", "-P", "footer=off", "-v", "ON_ERROR_STOP=1", "-c", sql)
+		if hasPw {
+			cmd.Env = append(os.Environ(), "PGPASSWORD="+pw)
+		}
+		return cmd, nil
+	case "sqlite", "sqlite3", "file":
+		return exec.CommandContext(ctx, "sqlite3", "-batch", "-header", "-separator", "\t", rest, sql), nil
+	case "mysql":
+		u, err := url.Parse(conn)
+		if err != nil {
+			return nil, err
+		}
+		args := []string{"--batch"}
+		pw, hasPw := u.User.Password()
+		if u.User != nil {
+			args = append(args, "-u", u.User.Username())
+		}
+		if host := u.Hostname(); host != "" {
+			args = append(args, "-h", host)
+		}
+		if port := u.Port(); port != "" {
+			args = append(args, "-P", port)
+		}
+		args = append(args, "-e", sql)
+		if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+			args = append(args, db)
+		}
+		cmd := exec.CommandContext(ctx, "mysql", args...)
+		if hasPw {
+			cmd.Env = append(os.Environ(), "MYSQL_PWD="+pw)
+		}
+		return cmd, nil
+	}
+	return nil, fmt.Errorf("unsupported database scheme %q", scheme)
+}
+
+func Execute(ctx context.Context, conn string, stmt Statement) *Result {
+	result := &Result{Statement: stmt}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	cmd, err := command(ctx, conn, stmt.Text)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+
+	if msg := strings.TrimSpace(stderr.String()); err != nil || isErrorOutput(msg) {
+		if msg == "" && err != nil {
+			msg = err.Error()
+		}
+		result.Err = errors.New(msg)
+		if m := errorLineRegex.FindStringSubmatch(msg); m != nil {
+			if n, convErr := strconv.Atoi(m[1]); convErr == nil {
+				result.ErrLine = stmt.StartLine + n - 1
+			}
+		} else {
+			result.ErrLine = stmt.StartLine
+		}
+		return result
+	}
+
+	parseTSV(result, stdout.String())
+	return result
+}
+
+func isErrorOutput(stderr string) bool {
+	lower := strings.ToLower(stderr)
+	return strings.HasPrefix(lower, "error") || strings.Contains(lower, "parse error")
+}
+
+func parseTSV(result *Result, out string) {
+	out = strings.TrimRight(out, "\n")
+	if out == "" {
+		result.Message = "OK"
+		return
+	}
+	lines := strings.Split(out, "\n")
+	result.Columns = strings.Split(lines[0], "\t")
+	for _, line := range lines[1:] {
+		result.Rows = append(result.Rows, strings.Split(line, "\t"))
+	}
+	// psql reports command tags like "INSERT 0 1" as a single line.
+	if len(lines) == 1 && len(result.Columns) == 1 && !strings.Contains(strings.ToLower(result.Statement.Text), "select") {
+		result.Message = lines[0]
+		result.Columns = nil
+	}
+}
+
+func ExecuteAll(ctx context.Context, conn string, stmts []Statement) []*Result {
+	var results []*Result
+	for _, stmt := range stmts {
+		r := Execute(ctx, conn, stmt)
+		results = append(results, r)
+		if r.Err != nil {
+			break
+		}
+	}
+	return results
+}
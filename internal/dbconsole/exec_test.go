@@ -0,0 +1,34 @@
+package dbconsole
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCommandKeepsPasswordsOffTheCommandLine(t *testing.T) {
+	for conn, env := range map[string]string{
+		"postgres://app:s3cret@db:5432/shop?sslmode=disable": "PGPASSWORD=s3cret",
+		"mysql://app:s3cret@db:3306/shop":                    "MYSQL_PWD=s3cret",
+	} {
+		cmd, err := command(context.Background(), conn, "select 1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if args := strings.Join(cmd.Args, " "); strings.Contains(args, "s3cret") {
+			t.Errorf("%s: password on the command line: %s", conn, args)
+		}
+		if !slices.Contains(cmd.Env, env) {
+			t.Errorf("%s: environment lacks %s", conn, env)
+		}
+	}
+
+	cmd, err := command(context.Background(), "postgres://app@db/shop", "select 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Args[1] != "postgres://app@db/shop" || cmd.Env != nil {
+		t.Errorf("without a password: args %q, env set %v", cmd.Args, cmd.Env != nil)
+	}
+}
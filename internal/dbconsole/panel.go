@@ -0,0 +1,299 @@
+package dbconsole
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+const maxCellWidth = 40
+
+type ResultsMsg struct {
+	Path    string
+	Results []*Result
+}
+
+type Panel struct {
+	rootPath string
+	config   *Config
+	visible  bool
+	running  bool
+	editing  bool
+	input    []rune
+	pending  []Statement
+	path     string
+	results  []*Result
+	index    int
+	scroll   int
+	column   int
+	width    int
+	height   int
+}
+
+func NewPanel(rootPath string) *Panel {
+	return &Panel{
+		rootPath: rootPath,
+		config:   LoadConfig(rootPath),
+		width:    80,
+		height:   20,
+	}
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+func (p *Panel) Toggle() {
+	p.visible = !p.visible
+	p.editing = false
+}
+
+func (p *Panel) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+func (p *Panel) Results() []*Result {
+	return p.results
+}
+
+func (p *Panel) Execute(path string, stmts []Statement) tea.Cmd {
+	if len(stmts) == 0 {
+		return nil
+	}
+	p.visible = true
+	p.path = path
+	if p.config.Connection == "" {
+		p.pending = stmts
+		p.startEditing()
+		return nil
+	}
+	return p.run(stmts)
+}
+
+func (p *Panel) run(stmts []Statement) tea.Cmd {
+	p.running = true
+	conn := p.config.Connection
+	path := p.path
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+		defer cancel()
+		return ResultsMsg{Path: path, Results: ExecuteAll(ctx, conn, stmts)}
+	}
+}
+
+func (p *Panel) startEditing() {
+	p.editing = true
+	p.input = []rune(p.config.Connection)
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case ResultsMsg:
+		p.running = false
+		p.results = msg.Results
+		p.index = len(p.results) - 1
+		p.scroll = 0
+		p.column = 0
+	case tea.KeyMsg:
+		if !p.visible {
+			return nil
+		}
+		if p.editing {
+			return p.handleEditKey(msg)
+		}
+		p.handleKey(msg)
+	}
+	return nil
+}
+
+func (p *Panel) handleEditKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		p.editing = false
+		p.config.Connection = strings.TrimSpace(string(p.input))
+		_ = SaveConfig(p.rootPath, p.config)
+		if len(p.pending) > 0 && p.config.Connection != "" {
+			stmts := p.pending
+			p.pending = nil
+			return p.run(stmts)
+		}
+	case tea.KeyEsc:
+		p.editing = false
+		p.pending = nil
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case tea.KeySpace:
+		p.input = append(p.input, ' ')
+	case tea.KeyRunes:
+		p.input = append(p.input, msg.Runes...)
+	}
+	return nil
+}
+
+func (p *Panel) handleKey(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "esc", "q":
+		p.visible = false
+	case "c":
+		p.startEditing()
+	case "up", "k":
+		if p.scroll > 0 {
+			p.scroll--
+		}
+	case "down", "j":
+		p.scroll++
+	case "pgup":
+		p.scroll = max(0, p.scroll-p.tableHeight())
+	case "pgdown":
+		p.scroll += p.tableHeight()
+	case "left", "h":
+		if p.column > 0 {
+			p.column--
+		}
+	case "right", "l":
+		p.column++
+	case "[":
+		if p.index > 0 {
+			p.index--
+			p.scroll, p.column = 0, 0
+		}
+	case "]":
+		if p.index < len(p.results)-1 {
+			p.index++
+			p.scroll, p.column = 0, 0
+		}
+	}
+}
+
+func (p *Panel) tableHeight() int {
+	return max(3, p.height-9)
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#89b4fa"))
+
+	title := i18n.T("sql.title")
+	if len(p.results) > 1 {
+		title += " · " + i18n.T("sql.statement", p.index+1, len(p.results))
+	}
+	lines := []string{titleStyle.Render(title)}
+
+	conn := p.config.Connection
+	if p.editing {
+		lines = append(lines, textStyle.Render(i18n.T("sql.connection", string(p.input)+"▏")))
+		lines = append(lines, hintStyle.Render(i18n.T("sql.connectionHint")))
+	} else if conn == "" {
+		lines = append(lines, hintStyle.Render(i18n.T("sql.noConnection")))
+	} else {
+		lines = append(lines, hintStyle.Render(redact(conn)))
+	}
+
+	switch {
+	case p.running:
+		lines = append(lines, "", hintStyle.Render(i18n.T("sql.running")))
+	case len(p.results) > 0:
+		r := p.results[p.index]
+		lines = append(lines, "", hintStyle.Render(firstLine(r.Statement.Text)))
+		if r.Err != nil {
+			lines = append(lines, errorStyle.Render(i18n.T("sql.lineError", r.ErrLine+1, r.Err.Error())))
+		} else if r.Columns == nil {
+			lines = append(lines, textStyle.Render(r.Message)+hintStyle.Render("  "+r.Duration.Round(time.Millisecond).String()))
+		} else {
+			lines = append(lines, p.renderTable(r, headerStyle, textStyle)...)
+			lines = append(lines, hintStyle.Render(i18n.T("sql.rows", len(r.Rows), r.Duration.Round(time.Millisecond))))
+		}
+	}
+
+	lines = append(lines, "", hintStyle.Render(i18n.T("sql.hint")))
+
+	width := max(20, p.width-4)
+	for i, line := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(width).Render(line)
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
+
+func (p *Panel) renderTable(r *Result, headerStyle, textStyle lipgloss.Style) []string {
+	if p.column >= len(r.Columns) {
+		p.column = max(0, len(r.Columns)-1)
+	}
+	height := p.tableHeight()
+	if p.scroll > len(r.Rows)-height {
+		p.scroll = max(0, len(r.Rows)-height)
+	}
+
+	widths := make([]int, len(r.Columns))
+	for i, col := range r.Columns {
+		widths[i] = lipgloss.Width(col)
+	}
+	for _, row := range r.Rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = min(maxCellWidth, max(widths[i], lipgloss.Width(cell)))
+			}
+		}
+	}
+
+	renderRow := func(cells []string, style lipgloss.Style) string {
+		var parts []string
+		for i := p.column; i < len(widths); i++ {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			parts = append(parts, style.Width(widths[i]).MaxWidth(widths[i]).Render(cell))
+		}
+		return strings.Join(parts, " │ ")
+	}
+
+	lines := []string{renderRow(r.Columns, headerStyle)}
+	end := min(len(r.Rows), p.scroll+height)
+	for _, row := range r.Rows[p.scroll:end] {
+		lines = append(lines, renderRow(row, textStyle))
+	}
+	return lines
+}
+
+func redact(conn string) string {
+	scheme, rest, ok := strings.Cut(conn, "://")
+	if !ok {
+		return conn
+	}
+	at := strings.LastIndex(rest, "@")
+	if at < 0 {
+		return conn
+	}
+	userinfo := rest[:at]
+	if user, _, hasPw := strings.Cut(userinfo, ":"); hasPw {
+		userinfo = user + ":****"
+	}
+	return scheme + "://" + userinfo + rest[at:]
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,99 @@
+package dbconsole
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+type Statement struct {
+	Text      string
+	StartLine int
+	EndLine   int
+}
+
+func IsSQLFile(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".sql"
+}
+
+// SplitStatements splits a script on semicolons that are outside of quotes
+// and comments, recording the lines each statement spans.
+func SplitStatements(content string) []Statement {
+	var stmts []Statement
+	var current strings.Builder
+	line, start := 0, -1
+	var quote rune
+	lineComment, blockComment := false, false
+
+	runes := []rune(content)
+	flush := func(endLine int) {
+		text := strings.TrimSpace(current.String())
+		if text != "" && start >= 0 {
+			stmts = append(stmts, Statement{Text: text, StartLine: start, EndLine: endLine})
+		}
+		current.Reset()
+		start = -1
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case lineComment:
+			if r == '\n' {
+				lineComment = false
+			}
+		case blockComment:
+			if r == '*' && next == '/' {
+				blockComment = false
+				current.WriteRune(r)
+				r = next
+				i++
+			}
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '-' && next == '-':
+			lineComment = true
+		case r == '/' && next == '*':
+			blockComment = true
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == ';':
+			current.WriteRune(r)
+			flush(line)
+			continue
+		}
+
+		if start < 0 && !lineComment && !blockComment && r != '\n' && r != ' ' && r != '\t' && r != '\r' {
+			start = line
+		}
+		if start >= 0 {
+			current.WriteRune(r)
+		}
+		if r == '\n' {
+			line++
+		}
+	}
+	flush(line)
+	return stmts
+}
+
+func StatementAt(stmts []Statement, line int) *Statement {
+	for i := range stmts {
+		if line >= stmts[i].StartLine && line <= stmts[i].EndLine {
+			return &stmts[i]
+		}
+	}
+	// Fall back to the statement that ends just above the cursor.
+	for i := len(stmts) - 1; i >= 0; i-- {
+		if stmts[i].EndLine < line {
+			return &stmts[i]
+		}
+	}
+	return nil
+}
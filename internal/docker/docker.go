@@ -0,0 +1,95 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const CommandTimeout = 30 * time.Second
+
+type Container struct {
+	ID      string `json:"ID"`
+	Names   string `json:"Names"`
+	Image   string `json:"Image"`
+	State   string `json:"State"`
+	Status  string `json:"Status"`
+	Service string `json:"-"`
+	Labels  string `json:"Labels"`
+}
+
+func (c *Container) Running() bool {
+	return c.State == "running"
+}
+
+// ListContainers returns the containers belonging to the compose project,
+// falling back to containers built from the project image.
+func ListContainers(ctx context.Context, project string) ([]*Container, error) {
+	containers, err := list(ctx, "label=com.docker.compose.project="+project)
+	if err != nil || len(containers) > 0 {
+		return containers, err
+	}
+	return list(ctx, "ancestor="+project)
+}
+
+func list(ctx context.Context, filter string) ([]*Container, error) {
+	out, err := run(ctx, "ps", "-a", "--filter", filter, "--format", "{{json .}}")
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []*Container
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		var c Container
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			continue
+		}
+		c.Service = labelValue(c.Labels, "com.docker.compose.service")
+		containers = append(containers, &c)
+	}
+	return containers, scanner.Err()
+}
+
+func labelValue(labels, key string) string {
+	for _, label := range strings.Split(labels, ",") {
+		if k, v, ok := strings.Cut(label, "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+func Start(ctx context.Context, id string) error {
+	_, err := run(ctx, "start", id)
+	return err
+}
+
+func Stop(ctx context.Context, id string) error {
+	_, err := run(ctx, "stop", id)
+	return err
+}
+
+func Restart(ctx context.Context, id string) error {
+	_, err := run(ctx, "restart", id)
+	return err
+}
+
+func run(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,200 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+	"tron/internal/runconfig"
+)
+
+type ContainersMsg struct {
+	Containers []*Container
+	Err        error
+}
+
+type ActionDoneMsg struct {
+	Action string
+	Err    error
+}
+
+type Panel struct {
+	rootPath   string
+	project    string
+	visible    bool
+	loading    bool
+	containers []*Container
+	selected   int
+	err        error
+	width      int
+}
+
+func NewPanel(rootPath string) *Panel {
+	return &Panel{
+		rootPath: rootPath,
+		project:  runconfig.ComposeProjectName(rootPath),
+		width:    70,
+	}
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+func (p *Panel) Toggle() tea.Cmd {
+	p.visible = !p.visible
+	if p.visible {
+		return p.refresh()
+	}
+	return nil
+}
+
+func (p *Panel) refresh() tea.Cmd {
+	p.loading = true
+	project := p.project
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+		defer cancel()
+		containers, err := ListContainers(ctx, project)
+		return ContainersMsg{Containers: containers, Err: err}
+	}
+}
+
+func (p *Panel) action(name string, fn func(context.Context, string) error) tea.Cmd {
+	c := p.selectedContainer()
+	if c == nil {
+		return nil
+	}
+	p.loading = true
+	id := c.ID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+		defer cancel()
+		return ActionDoneMsg{Action: name, Err: fn(ctx, id)}
+	}
+}
+
+func (p *Panel) selectedContainer() *Container {
+	if p.selected < 0 || p.selected >= len(p.containers) {
+		return nil
+	}
+	return p.containers[p.selected]
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case ContainersMsg:
+		p.loading = false
+		p.containers = msg.Containers
+		p.err = msg.Err
+		if p.selected >= len(p.containers) {
+			p.selected = max(0, len(p.containers)-1)
+		}
+	case ActionDoneMsg:
+		p.err = msg.Err
+		return p.refresh()
+	case tea.KeyMsg:
+		if p.visible {
+			return p.handleKey(msg)
+		}
+	}
+	return nil
+}
+
+func (p *Panel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "alt+k":
+		p.visible = false
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.containers)-1 {
+			p.selected++
+		}
+	case "r":
+		return p.refresh()
+	case "s":
+		return p.action("start", Start)
+	case "x":
+		return p.action("stop", Stop)
+	case "R":
+		return p.action("restart", Restart)
+	case "l", "enter":
+		c := p.selectedContainer()
+		if c == nil {
+			return nil
+		}
+		p.visible = false
+		config := &runconfig.RunConfig{
+			Name:       i18n.T("docker.logs", c.Names),
+			Command:    "docker",
+			Args:       []string{"logs", "-f", "--tail", "200", c.ID},
+			WorkingDir: p.rootPath,
+		}
+		return func() tea.Msg {
+			return runconfig.RunCommandMsg{Config: config}
+		}
+	}
+	return nil
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4")).Width(p.width)
+	selectedStyle := itemStyle.Background(lipgloss.Color("#313244"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	runningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1"))
+
+	title := i18n.T("docker.title", p.project)
+	if p.loading {
+		title += hintStyle.Render("  " + i18n.T("docker.loading"))
+	}
+	lines := []string{titleStyle.Render(title)}
+
+	if p.err != nil {
+		lines = append(lines, errorStyle.Render(firstLine(p.err.Error())))
+	}
+	if len(p.containers) == 0 && !p.loading {
+		lines = append(lines, hintStyle.Render(i18n.T("docker.empty")))
+	}
+	for i, c := range p.containers {
+		dot := hintStyle.Render("○")
+		if c.Running() {
+			dot = runningStyle.Render("●")
+		}
+		name := c.Names
+		if c.Service != "" {
+			name = c.Service
+		}
+		text := fmt.Sprintf("%s %-20s %-24s %s", dot, name, c.Image, hintStyle.Render(c.Status))
+		if i == p.selected {
+			lines = append(lines, selectedStyle.Render(text))
+		} else {
+			lines = append(lines, itemStyle.Render(text))
+		}
+	}
+	lines = append(lines, "", hintStyle.Render(i18n.T("docker.hint")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
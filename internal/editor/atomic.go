@@ -0,0 +1,86 @@
+package editor
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// writeFile replaces the file at path with data. An existing file is
+// replaced atomically: data goes to a temporary file beside it, which
+// takes the original's mode and owner and is then renamed over it, so a
+// crash mid-write leaves the old content whole. Symlinks are followed.
+// With backup, the old content is kept at path~.
+func writeFile(path string, data []byte, backup bool) (err error) {
+	target, err := filepath.EvalSymlinks(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	// A file that cannot be written stays unwritten, even where its
+	// directory would let it be replaced.
+	probe, err := os.OpenFile(target, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	probe.Close()
+
+	f, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tron-*")
+	if errors.Is(err, fs.ErrPermission) {
+		// The directory is not writable, so there is nowhere to put a
+		// temporary file; the file itself is.
+		return os.WriteFile(target, data, info.Mode())
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+	if err = f.Chmod(info.Mode() & (fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky)); err != nil {
+		return err
+	}
+	chownLike(f, info)
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	if backup {
+		if err = backupFile(target, info); err != nil {
+			return err
+		}
+	}
+	return os.Rename(f.Name(), target)
+}
+
+// backupFile keeps the content of path at path~, linking to it where the
+// file system allows so the rename that follows leaves it in place.
+func backupFile(path string, info fs.FileInfo) error {
+	backup := path + "~"
+	if err := os.Remove(backup); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if os.Link(path, backup) == nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(backup, data, info.Mode().Perm())
+}
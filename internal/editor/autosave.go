@@ -0,0 +1,29 @@
+package editor
+
+import (
+	"errors"
+	"io/fs"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tron/pkg/pathutil"
+)
+
+// AutoSave writes unsaved changes without the prompts an explicit save may
+// show: a file changed on disk or not writable is left for the user to
+// save. Nor does it trim the buffer, which would eat a space typed just
+// before a pause.
+func (e *Editor) AutoSave() tea.Cmd {
+	if !e.Dirty || e.FilePath == "" || e.ReadOnly || pathutil.IsVirtual(e.FilePath) {
+		return nil
+	}
+	flush := e.Flush()
+	if !e.Dirty || e.checkConflict() != nil {
+		return flush
+	}
+	err := e.write()
+	if errors.Is(err, fs.ErrPermission) {
+		return flush
+	}
+	return tea.Batch(flush, e.writeCmd(err))
+}
@@ -0,0 +1,110 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// LargeFileSize is the size from which a file is confirmed before it
+	// is opened and is shown without highlighting.
+	LargeFileSize = 16 << 20
+	// sniffSize is how much of a file is searched for NUL bytes, as git
+	// does, to tell binary files from text.
+	sniffSize = 8000
+)
+
+// FileKind is what InspectFile found a file to be.
+type FileKind int
+
+const (
+	TextFile FileKind = iota
+	BinaryFile
+	LargeFile
+)
+
+// InspectFile reads the start of path to tell whether it is text, binary
+// or too large to open without asking, and returns its size.
+func InspectFile(path string) (FileKind, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return TextFile, 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return TextFile, 0, err
+	}
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return TextFile, 0, err
+	}
+	switch {
+	case bytes.IndexByte(head[:n], 0) >= 0:
+		return BinaryFile, info.Size(), nil
+	case info.Size() >= LargeFileSize:
+		return LargeFile, info.Size(), nil
+	}
+	return TextFile, info.Size(), nil
+}
+
+// ReadPrefix returns at most limit bytes from the start of path and
+// whether the file went on past them.
+func ReadPrefix(path string, limit int) ([]byte, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, int64(limit)+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(data) > limit {
+		return data[:limit], true, nil
+	}
+	return data, false, nil
+}
+
+// HexDump lays data out as offsets, hex bytes and their printable
+// characters, sixteen bytes a line.
+func HexDump(data []byte) string {
+	return hex.Dump(data)
+}
+
+// Printable turns binary data into text that is safe to draw: control
+// characters other than newlines and tabs become their control pictures
+// and bytes that are not UTF-8 become the replacement character.
+func Printable(data []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(data))
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		switch {
+		case r == '\n' || r == '\t':
+			sb.WriteRune(r)
+		case r < 0x20:
+			sb.WriteRune(0x2400 + r)
+		case r == 0x7f:
+			sb.WriteRune('␡')
+		case r >= 0x80 && r < 0xa0:
+			sb.WriteRune(utf8.RuneError)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// highlightsContent reports whether the buffer is small enough to
+// highlight; highlighting a large file would stall every edit.
+func (e *Editor) highlightsContent() bool {
+	last := e.Buffer.LineCount() - 1
+	return last < 0 || e.Buffer.LineOffset(last)+e.Buffer.LineLength(last) < LargeFileSize
+}
@@ -0,0 +1,116 @@
+package editor
+
+import "strings"
+
+// blockState is a rectangular selection, kept in display cells so it
+// lines up across tabs and wide characters and can reach past the end of
+// short lines. It is laid out as one caret per line, so typing and
+// deleting already apply to every line of the block.
+type blockState struct {
+	anchorLine, anchorCell int
+	headLine, headCell     int
+	active                 bool
+	dragging               bool
+}
+
+// ToggleBlockSelection switches shift+arrows and mouse drags between
+// making ordinary selections and rectangular ones.
+func (e *Editor) ToggleBlockSelection() {
+	e.BlockSelection = !e.BlockSelection
+	e.block = blockState{}
+}
+
+// startBlock anchors a new block at line and cell.
+func (e *Editor) startBlock(line, cell int) {
+	e.block = blockState{anchorLine: line, anchorCell: cell, headLine: line, headCell: cell, active: true}
+	e.layOutBlock()
+}
+
+// extendBlock moves the corner of the block opposite its anchor.
+func (e *Editor) extendBlock(line, cell int) {
+	e.block.headLine = max(0, min(line, e.Buffer.LineCount()-1))
+	e.block.headCell = max(cell, 0)
+	e.layOutBlock()
+}
+
+// layOutBlock turns the block into a selection per line. The head's line
+// gets the primary cursor and the rest get carets.
+func (e *Editor) layOutBlock() {
+	b := e.block
+	e.carets = nil
+	lo, hi := min(b.anchorLine, b.headLine), max(b.anchorLine, b.headLine)
+	for l := lo; l <= hi; l++ {
+		text := e.Buffer.Line(l)
+		sel := Selection{
+			Start: Position{Line: l, Column: e.byteColumn(text, b.anchorCell)},
+			End:   Position{Line: l, Column: e.byteColumn(text, b.headCell)},
+		}
+		if l == b.headLine {
+			e.Cursor, e.Selection = sel.End, sel
+			continue
+		}
+		e.carets = append(e.carets, caret{pos: sel.End, sel: sel})
+	}
+}
+
+// handleBlockKey grows the block with shift+arrows while block selection
+// is on.
+func (e *Editor) handleBlockKey(key string) bool {
+	if !e.BlockSelection {
+		return false
+	}
+	dl, dc := 0, 0
+	switch key {
+	case "shift+up":
+		dl = -1
+	case "shift+down":
+		dl = 1
+	case "shift+left":
+		dc = -1
+	case "shift+right":
+		dc = 1
+	default:
+		e.block.active = false
+		return false
+	}
+	if !e.block.active {
+		e.anchorSelection()
+		e.startBlock(e.anchor.Line, e.cell(e.anchor.Line, e.anchor.Column))
+		e.block.headLine, e.block.headCell = e.Cursor.Line, e.cell(e.Cursor.Line, e.Cursor.Column)
+	}
+	e.extendBlock(e.block.headLine+dl, e.block.headCell+dc)
+	return true
+}
+
+// cellAt returns the line and display cell under the mouse. Without
+// wrapping the cell can lie past the end of the line.
+func (e *Editor) cellAt(x, y int) (int, int) {
+	line, col := e.positionAt(x, y)
+	line = max(0, min(line, e.Buffer.LineCount()-1))
+	if e.WrapLines {
+		return line, e.cell(line, max(col, 0))
+	}
+	return line, max(x-e.lineNumWidth()+e.Viewport.X, 0)
+}
+
+// pasteBlock puts text copied from several carets back as a block: each
+// of its lines at the cursor's column on successive lines, padding short
+// lines and adding lines at the end of the buffer as needed.
+func (e *Editor) pasteBlock(text string) bool {
+	if text != e.blockClip || !strings.Contains(text, "\n") || e.hasSelection() {
+		return false
+	}
+	cell := e.cell(e.Cursor.Line, e.Cursor.Column)
+	for i, part := range strings.Split(text, "\n") {
+		l := e.Cursor.Line + i
+		if l >= e.Buffer.LineCount() {
+			last := e.Buffer.LineCount() - 1
+			e.Buffer.Insert(Position{Line: last, Column: e.Buffer.LineLength(last)}, "\n")
+		}
+		col := e.byteColumn(e.Buffer.Line(l), cell)
+		pad := cell - e.cell(l, col)
+		e.Buffer.Insert(Position{Line: l, Column: col}, strings.Repeat(" ", pad)+part)
+	}
+	e.clearSelection()
+	return true
+}
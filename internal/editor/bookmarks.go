@@ -0,0 +1,77 @@
+package editor
+
+import (
+	"slices"
+
+	"tron/internal/termcaps"
+)
+
+const bookmarkColor = "#89b4fa"
+
+// ToggleBookmark bookmarks the cursor's line, or removes its bookmark.
+// Bookmarks follow their lines through edits.
+func (e *Editor) ToggleBookmark() {
+	line := e.Cursor.Line
+	if i, found := slices.BinarySearch(e.bookmarks, line); found {
+		e.bookmarks = slices.Delete(e.bookmarks, i, i+1)
+	} else {
+		e.bookmarks = slices.Insert(e.bookmarks, i, line)
+	}
+}
+
+// Bookmarks returns the bookmarked lines in order.
+func (e *Editor) Bookmarks() []int {
+	return slices.Clone(e.bookmarks)
+}
+
+// NextBookmark moves to the first bookmark below the cursor, wrapping
+// round to the top. It reports false when there are no bookmarks.
+func (e *Editor) NextBookmark() bool {
+	if len(e.bookmarks) == 0 {
+		return false
+	}
+	i, found := slices.BinarySearch(e.bookmarks, e.Cursor.Line)
+	if found {
+		i++
+	}
+	e.GoToLine(e.bookmarks[i%len(e.bookmarks)])
+	return true
+}
+
+// PrevBookmark moves to the last bookmark above the cursor, wrapping round
+// to the bottom.
+func (e *Editor) PrevBookmark() bool {
+	if len(e.bookmarks) == 0 {
+		return false
+	}
+	i, _ := slices.BinarySearch(e.bookmarks, e.Cursor.Line)
+	e.GoToLine(e.bookmarks[(i-1+len(e.bookmarks))%len(e.bookmarks)])
+	return true
+}
+
+func (e *Editor) bookmarked(line int) bool {
+	_, found := slices.BinarySearch(e.bookmarks, line)
+	return found
+}
+
+func (e *Editor) renderBookmark() string {
+	return foregroundStyle(bookmarkColor).Render(termcaps.Symbol("◆", "#"))
+}
+
+// shiftBookmarks keeps bookmarks on their lines after an edit of lines lo
+// to hi changed the line count by delta. Bookmarks on lines the edit
+// removed move up to the last line left of it.
+func (e *Editor) shiftBookmarks(lo, hi, delta int) {
+	if len(e.bookmarks) == 0 || delta == 0 {
+		return
+	}
+	for i, line := range e.bookmarks {
+		switch {
+		case line > hi:
+			e.bookmarks[i] = line + delta
+		case line > lo:
+			e.bookmarks[i] = max(lo, min(line, hi+delta))
+		}
+	}
+	e.bookmarks = slices.Compact(e.bookmarks)
+}
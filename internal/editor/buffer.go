@@ -1,5 +1,12 @@
 package editor
 
+import (
+	"hash/fnv"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
 type CursorStyle int
 
 const (
@@ -32,6 +39,8 @@ func (s Selection) Normalized() Selection {
 type Buffer interface {
 	Content() string
 	Lines() []string
+	// Line returns one line without building the whole Lines slice.
+	Line(line int) string
 	LineCount() int
 	LineLength(line int) int
 	CharAt(line, col int) rune
@@ -40,10 +49,28 @@ type Buffer interface {
 	DeleteChar(pos Position, forward bool)
 	GetText(start, end Position) string
 	SetContent(content string)
+	// Version counts the edits made to the buffer.
+	Version() int
+	// Subscribe calls fn after every edit until the returned function is
+	// called.
+	Subscribe(fn func(ChangeEvent)) func()
+	// LineOffset returns the byte offset of the start of line in Content.
+	LineOffset(line int) int
+	// Hash returns a hash of Content that only rehashes edited lines.
+	Hash() uint64
 }
 
 type SimpleBuffer struct {
+	changeFeed
 	lines []string
+	// hashes caches the hash of each line, zero until computed.
+	hashes []uint64
+	// offsets[i] is the start of line i and prefixes[i] the hash of the
+	// lines before it; only the first validOffsets entries are current.
+	// Edits truncate that prefix and it is extended on demand.
+	offsets      []int
+	prefixes     []uint64
+	validOffsets int
 }
 
 func NewSimpleBuffer() *SimpleBuffer {
@@ -57,20 +84,93 @@ func NewSimpleBufferWithContent(content string) *SimpleBuffer {
 }
 
 func (b *SimpleBuffer) Content() string {
-	result := ""
-	for i, line := range b.lines {
-		if i > 0 {
-			result += "\n"
-		}
-		result += line
+	return strings.Join(b.lines, "\n")
+}
+
+func (b *SimpleBuffer) LineOffset(line int) int {
+	line = max(0, min(line, len(b.lines)))
+	b.extendOffsets(line)
+	return b.offsets[line]
+}
+
+func (b *SimpleBuffer) Hash() uint64 {
+	b.extendOffsets(len(b.lines))
+	return b.prefixes[len(b.lines)]
+}
+
+const hashMultiplier = 1099511628211
+
+func (b *SimpleBuffer) extendOffsets(line int) {
+	if n := len(b.lines) + 1; len(b.offsets) < n {
+		b.offsets = append(b.offsets, make([]int, n-len(b.offsets))...)
+		b.prefixes = append(b.prefixes, make([]uint64, n-len(b.prefixes))...)
+	}
+	if b.validOffsets == 0 {
+		b.offsets[0] = 0
+		b.prefixes[0] = 0
+		b.validOffsets = 1
+	}
+	for i := b.validOffsets; i <= line; i++ {
+		b.offsets[i] = b.offsets[i-1] + len(b.lines[i-1]) + 1
+		b.prefixes[i] = b.prefixes[i-1]*hashMultiplier + b.lineHash(i-1)
+		b.validOffsets = i + 1
+	}
+}
+
+func (b *SimpleBuffer) lineHash(i int) uint64 {
+	if len(b.hashes) != len(b.lines) {
+		b.hashes = make([]uint64, len(b.lines))
+	}
+	if b.hashes[i] == 0 {
+		b.hashes[i] = hashLine(b.lines[i])
+	}
+	return b.hashes[i]
+}
+
+// hashLine is the per-line hash Buffer.Hash implementations combine; it is
+// never zero.
+func hashLine(line string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(line))
+	return h.Sum64() | 1
+}
+
+// invalidate marks offsets after line as stale; an edit within a line
+// shifts only the lines that follow it.
+func (b *SimpleBuffer) invalidate(line int) {
+	b.validOffsets = max(0, min(b.validOffsets, line+1))
+}
+
+func (b *SimpleBuffer) setLine(i int, text string) {
+	b.lines[i] = text
+	if i < len(b.hashes) {
+		b.hashes[i] = 0
 	}
-	return result
+	b.invalidate(i)
+}
+
+// splice replaces lines [start, end) with repl, keeping the line hash cache
+// aligned.
+func (b *SimpleBuffer) splice(start, end int, repl ...string) {
+	aligned := len(b.hashes) == len(b.lines)
+	b.lines = slices.Replace(b.lines, start, end, repl...)
+	if aligned {
+		b.hashes = slices.Replace(b.hashes, start, end, make([]uint64, len(repl))...)
+	}
+	b.invalidate(start)
 }
 
 func (b *SimpleBuffer) Lines() []string {
 	return b.lines
 }
 
+func (b *SimpleBuffer) Line(line int) string {
+	if line < 0 || line >= len(b.lines) {
+		return ""
+	}
+	return b.lines[line]
+}
+
 func (b *SimpleBuffer) LineCount() int {
 	return len(b.lines)
 }
@@ -89,83 +189,79 @@ func (b *SimpleBuffer) CharAt(line, col int) rune {
 	if col < 0 || col >= len(b.lines[line]) {
 		return 0
 	}
-	return rune(b.lines[line][col])
+	r, _ := utf8.DecodeRuneInString(b.lines[line][col:])
+	return r
 }
 
 func (b *SimpleBuffer) Insert(pos Position, text string) {
-	for pos.Line >= len(b.lines) {
-		b.lines = append(b.lines, "")
+	if text == "\r\n" {
+		text = "\n"
 	}
-
-	if text == "\n" || text == "\r\n" {
-		currentLine := b.lines[pos.Line]
-		before := currentLine[:min(pos.Column, len(currentLine))]
-		after := ""
-		if pos.Column < len(currentLine) {
-			after = currentLine[pos.Column:]
-		}
-		b.lines = append(b.lines, "")
-		copy(b.lines[pos.Line+2:], b.lines[pos.Line+1:])
-		b.lines[pos.Line] = before
-		b.lines[pos.Line+1] = after
-		return
+	if last := len(b.lines) - 1; pos.Line > last {
+		text = strings.Repeat("\n", pos.Line-last) + text
+		pos = Position{Line: last, Column: len(b.lines[last])}
 	}
 
 	currentLine := b.lines[pos.Line]
 	col := min(pos.Column, len(currentLine))
-	b.lines[pos.Line] = currentLine[:col] + text + currentLine[col:]
+	defer b.publish(b.prepare(b, Position{Line: pos.Line, Column: col}, Position{Line: pos.Line, Column: col}, text))
+	before := currentLine[:col]
+	after := currentLine[col:]
+
+	parts := strings.Split(text, "\n")
+	if len(parts) == 1 {
+		b.setLine(pos.Line, before+text+after)
+		return
+	}
+
+	parts[0] = before + parts[0]
+	parts[len(parts)-1] += after
+	b.splice(pos.Line, pos.Line+1, parts...)
 }
 
 func (b *SimpleBuffer) Delete(start, end Position) {
 	start, end = normalizeRange(start, end)
-
-	if start.Line == end.Line {
-		if start.Line < len(b.lines) {
-			line := b.lines[start.Line]
-			b.lines[start.Line] = line[:start.Column] + line[end.Column:]
-		}
+	if start.Line >= len(b.lines) || end.Line >= len(b.lines) {
 		return
 	}
+	defer b.publish(b.prepare(b, start, end, ""))
 
-	if start.Line >= len(b.lines) || end.Line >= len(b.lines) {
+	if start.Line == end.Line {
+		line := b.lines[start.Line]
+		b.setLine(start.Line, line[:start.Column]+line[end.Column:])
 		return
 	}
 
 	firstLine := b.lines[start.Line]
 	lastLine := b.lines[end.Line]
-	newLine := firstLine[:start.Column] + lastLine[end.Column:]
-
-	newLines := make([]string, 0, len(b.lines)-(end.Line-start.Line))
-	newLines = append(newLines, b.lines[:start.Line]...)
-	newLines = append(newLines, newLine)
-	newLines = append(newLines, b.lines[end.Line+1:]...)
-
-	b.lines = newLines
+	b.splice(start.Line, end.Line+1, firstLine[:start.Column]+lastLine[end.Column:])
 }
 
 func (b *SimpleBuffer) DeleteChar(pos Position, forward bool) {
-	if pos.Line < 0 || pos.Line >= len(b.lines) {
-		return
+	if start, end, ok := charRange(b, pos, forward); ok {
+		b.Delete(start, end)
 	}
+}
 
-	line := b.lines[pos.Line]
-
-	if forward {
-		if pos.Column < len(line) {
-			b.lines[pos.Line] = line[:pos.Column] + line[pos.Column+1:]
-		} else if pos.Line < len(b.lines)-1 {
-			b.lines[pos.Line] = line + b.lines[pos.Line+1]
-			b.lines = append(b.lines[:pos.Line+1], b.lines[pos.Line+2:]...)
-		}
-	} else {
-		if pos.Column > 0 {
-			b.lines[pos.Line] = line[:pos.Column-1] + line[pos.Column:]
-		} else if pos.Line > 0 {
-			prevLine := b.lines[pos.Line-1]
-			b.lines[pos.Line-1] = prevLine + line
-			b.lines = append(b.lines[:pos.Line], b.lines[pos.Line+1:]...)
-		}
+// charRange returns what DeleteChar removes at pos: the character before
+// or after it, or the line break at either end of its line.
+func charRange(b Buffer, pos Position, forward bool) (Position, Position, bool) {
+	if pos.Line < 0 || pos.Line >= b.LineCount() {
+		return pos, pos, false
+	}
+	line := b.Line(pos.Line)
+	col := max(0, min(pos.Column, len(line)))
+	switch {
+	case forward && col < len(line):
+		return Position{Line: pos.Line, Column: col}, Position{Line: pos.Line, Column: nextBoundary(line, col)}, true
+	case forward && pos.Line < b.LineCount()-1:
+		return Position{Line: pos.Line, Column: col}, Position{Line: pos.Line + 1}, true
+	case !forward && col > 0:
+		return Position{Line: pos.Line, Column: prevBoundary(line, col)}, Position{Line: pos.Line, Column: col}, true
+	case !forward && pos.Line > 0:
+		return Position{Line: pos.Line - 1, Column: b.LineLength(pos.Line - 1)}, Position{Line: pos.Line}, true
 	}
+	return pos, pos, false
 }
 
 func (b *SimpleBuffer) GetText(start, end Position) string {
@@ -181,29 +277,36 @@ func (b *SimpleBuffer) GetText(start, end Position) string {
 		return ""
 	}
 
-	var result string
+	var result strings.Builder
 	if start.Line < len(b.lines) {
-		result = b.lines[start.Line][min(start.Column, len(b.lines[start.Line])):] + "\n"
+		result.WriteString(b.lines[start.Line][min(start.Column, len(b.lines[start.Line])):])
+		result.WriteByte('\n')
 	}
 
 	for i := start.Line + 1; i < end.Line && i < len(b.lines); i++ {
-		result += b.lines[i] + "\n"
+		result.WriteString(b.lines[i])
+		result.WriteByte('\n')
 	}
 
 	if end.Line < len(b.lines) {
-		result += b.lines[end.Line][:min(end.Column, len(b.lines[end.Line]))]
+		result.WriteString(b.lines[end.Line][:min(end.Column, len(b.lines[end.Line]))])
 	}
 
-	return result
+	return result.String()
 }
 
 func (b *SimpleBuffer) SetContent(content string) {
+	if last := len(b.lines) - 1; last >= 0 {
+		defer b.publish(b.prepare(b, Position{}, Position{Line: last, Column: len(b.lines[last])}, content))
+	}
+	b.validOffsets = 0
+	b.hashes = nil
 	if content == "" {
 		b.lines = []string{""}
 		return
 	}
 
-	lines := []string{}
+	lines := make([]string, 0, strings.Count(content, "\n")+1)
 	start := 0
 	for i := 0; i < len(content); i++ {
 		if content[i] == '\n' {
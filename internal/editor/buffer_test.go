@@ -0,0 +1,77 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// benchSource is a Go file of n lines, for the benchmarks of both buffers.
+func benchSource(n int) string {
+	var sb strings.Builder
+	sb.WriteString("package bench\n")
+	for i := 1; i < n; i++ {
+		switch i % 4 {
+		case 0:
+			fmt.Fprintf(&sb, "func f%d(a, b int) int {\n", i)
+		case 1:
+			fmt.Fprintf(&sb, "\t// Sum %d of the two values.\n", i)
+		case 2:
+			sb.WriteString("\treturn a + b\n")
+		default:
+			sb.WriteString("}\n")
+		}
+	}
+	return sb.String()
+}
+
+// benchBuffers runs fn on a SimpleBuffer and a PieceBuffer of n lines.
+func benchBuffers(b *testing.B, n int, fn func(b *testing.B, buf Buffer)) {
+	content := benchSource(n)
+	b.Run("simple", func(b *testing.B) { fn(b, NewSimpleBufferWithContent(content)) })
+	b.Run("piece", func(b *testing.B) { fn(b, NewPieceBufferWithContent(content)) })
+}
+
+func BenchmarkContent(b *testing.B) {
+	benchBuffers(b, 10000, func(b *testing.B, buf Buffer) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = buf.Content()
+		}
+	})
+}
+
+// BenchmarkLineOffset types a character near the top of the buffer, then
+// asks for the offsets of a screen of lines further down, as rendering
+// after each keystroke does.
+func BenchmarkLineOffset(b *testing.B) {
+	benchBuffers(b, 10000, func(b *testing.B, buf Buffer) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if i%2 == 0 {
+				buf.Insert(Position{Line: 10}, "x")
+			} else {
+				buf.Delete(Position{Line: 10}, Position{Line: 10, Column: 1})
+			}
+			for line := 5000; line < 5050; line++ {
+				_ = buf.LineOffset(line)
+			}
+		}
+	})
+}
+
+// BenchmarkView renders a highlighted screen of the editor in the middle
+// of the file.
+func BenchmarkView(b *testing.B) {
+	benchBuffers(b, 10000, func(b *testing.B, buf Buffer) {
+		e := NewWithBuffer(buf)
+		e.SetFileExtension(".go")
+		e.SetSize(120, 50)
+		e.GoToLine(5000)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = e.View()
+		}
+	})
+}
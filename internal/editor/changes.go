@@ -0,0 +1,138 @@
+package editor
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// ChangeEvent describes one edit of a buffer: the text in Range, as it was
+// before the edit, was replaced with NewText.
+type ChangeEvent struct {
+	Range   Selection
+	OldText string
+	NewText string
+	// UTF16Range is Range with columns counted in UTF-16 code units, as
+	// language servers count them.
+	UTF16Range Selection
+	// Version counts the buffer's edits; the first is version 1.
+	Version int
+}
+
+// maxPendingChanges bounds the changes the editor keeps for TakeChanges;
+// past it listeners are told to take the whole content instead.
+const maxPendingChanges = 256
+
+type subscriber struct {
+	id int
+	fn func(ChangeEvent)
+}
+
+// changeFeed publishes a buffer's edits. Buffers embed it and wrap each
+// edit as
+//
+//	defer b.publish(b.prepare(b, start, end, text))
+//
+// so the old text is read before the edit and listeners run after it.
+type changeFeed struct {
+	version     int
+	subscribers []subscriber
+	nextID      int
+}
+
+func (f *changeFeed) Version() int {
+	return f.version
+}
+
+// Subscribe calls fn after every edit of the buffer until the returned
+// function is called.
+func (f *changeFeed) Subscribe(fn func(ChangeEvent)) func() {
+	id := f.nextID
+	f.nextID++
+	f.subscribers = append(f.subscribers, subscriber{id, fn})
+	return func() {
+		for i, s := range f.subscribers {
+			if s.id == id {
+				f.subscribers = append(f.subscribers[:i:i], f.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// prepare describes replacing start to end of b with text. The old text is
+// only copied when someone is listening.
+func (f *changeFeed) prepare(b Buffer, start, end Position, text string) ChangeEvent {
+	ev := ChangeEvent{Range: Selection{Start: start, End: end}, NewText: text}
+	if len(f.subscribers) == 0 || start == end && text == "" {
+		return ev
+	}
+	ev.OldText = b.GetText(start, end)
+	startCol := utf16Len(b.Line(start.Line)[:start.Column])
+	endCol := startCol + utf16Len(ev.OldText)
+	if end.Line != start.Line {
+		endCol = utf16Len(b.Line(end.Line)[:end.Column])
+	}
+	ev.UTF16Range = Selection{
+		Start: Position{Line: start.Line, Column: startCol},
+		End:   Position{Line: end.Line, Column: endCol},
+	}
+	return ev
+}
+
+func (f *changeFeed) publish(ev ChangeEvent) {
+	if ev.Range.Start == ev.Range.End && ev.NewText == "" {
+		return
+	}
+	f.version++
+	ev.Version = f.version
+	for _, s := range f.subscribers {
+		s.fn(ev)
+	}
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// SetBuffer makes b the editor's buffer and follows its changes.
+func (e *Editor) SetBuffer(b Buffer) {
+	if e.unwatch != nil {
+		e.unwatch()
+	}
+	e.Buffer = b
+	e.unwatch = b.Subscribe(e.bufferChanged)
+	e.changes, e.changesLost = nil, true
+	e.highlightedVersion = -1
+}
+
+func (e *Editor) bufferChanged(ev ChangeEvent) {
+	if !e.changesLost {
+		e.changes = append(e.changes, ev)
+		if len(e.changes) > maxPendingChanges {
+			e.changes, e.changesLost = nil, true
+		}
+	}
+	lines := ev.Range.End.Line - ev.Range.Start.Line
+	delta := strings.Count(ev.NewText, "\n") - lines
+	e.shiftFolds(ev.Range.Start.Line, ev.Range.End.Line, delta)
+	e.shiftBookmarks(ev.Range.Start.Line, ev.Range.End.Line, delta)
+	e.recordUndo(ev)
+}
+
+// TakeChanges returns the edits made since it was last called, oldest
+// first. It returns nil when they are not all known, as after a new file
+// was loaded, and listeners should take the whole content instead.
+func (e *Editor) TakeChanges() []ChangeEvent {
+	changes := e.changes
+	if e.changesLost {
+		changes = nil
+	} else if changes == nil {
+		changes = []ChangeEvent{}
+	}
+	e.changes, e.changesLost = nil, false
+	return changes
+}
@@ -0,0 +1,96 @@
+package editor
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The methods below are the editor's entry points for the command
+// dispatcher; each leaves the editor in the same state a key press would.
+
+func (e *Editor) InsertText(text string) tea.Cmd {
+	if e.ReadOnly {
+		return nil
+	}
+	e.insertText(normalizeLineEndings(text))
+	e.markDirty()
+	return e.afterCommand()
+}
+
+func (e *Editor) SelectAll() {
+	e.selectAll()
+}
+
+func (e *Editor) Copy() {
+	e.copySelection()
+}
+
+func (e *Editor) Cut() tea.Cmd {
+	if e.ReadOnly {
+		return nil
+	}
+	e.cutSelection()
+	e.markDirty()
+	return e.afterCommand()
+}
+
+func (e *Editor) Paste() tea.Cmd {
+	if e.ReadOnly {
+		return nil
+	}
+	e.paste()
+	e.markDirty()
+	return e.afterCommand()
+}
+
+// ReplaceLines replaces lines [start, end) with lines and moves the cursor
+// to the start of the replacement.
+func (e *Editor) ReplaceLines(start, end int, lines []string) tea.Cmd {
+	if e.ReadOnly {
+		return nil
+	}
+	e.replaceLines(start, end, lines)
+	e.markDirty()
+	return e.afterCommand()
+}
+
+func (e *Editor) replaceLines(start, end int, lines []string) {
+	count := e.Buffer.LineCount()
+	start = max(0, min(start, count))
+	end = max(start, min(end, count))
+	from, to := Position{Line: start}, Position{Line: end}
+	text := strings.Join(lines, "\n")
+	if end < count {
+		if len(lines) > 0 {
+			text += "\n"
+		}
+	} else if start > 0 {
+		// The range runs to the end of the buffer, so take the newline that
+		// precedes it instead of the one that follows.
+		from = Position{Line: start - 1, Column: e.Buffer.LineLength(start - 1)}
+		to = Position{Line: count - 1, Column: e.Buffer.LineLength(count - 1)}
+		if len(lines) > 0 {
+			text = "\n" + text
+		}
+	} else {
+		to = Position{Line: count - 1, Column: e.Buffer.LineLength(count - 1)}
+	}
+	e.Buffer.Delete(from, to)
+	e.Buffer.Insert(from, text)
+	e.Cursor = Position{Line: min(start, e.Buffer.LineCount()-1)}
+	e.clearSelection()
+}
+
+func (e *Editor) SaveFile() tea.Cmd {
+	if e.FilePath == "" {
+		return nil
+	}
+	return tea.Batch(e.Flush(), e.saveCmd())
+}
+
+func (e *Editor) afterCommand() tea.Cmd {
+	e.ensureCursorValid()
+	e.revealCursor()
+	return e.scheduleFlush()
+}
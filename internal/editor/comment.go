@@ -0,0 +1,83 @@
+package editor
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"tron/internal/syntax"
+)
+
+// ToggleComment comments out the cursor line or the selected lines, or
+// uncomments them when they all are already. Line comments go at the
+// shallowest indentation so the block stays aligned; languages with only
+// block comments get one around each line.
+func (e *Editor) ToggleComment() tea.Cmd {
+	lang, ok := syntax.LanguageFor(strings.ToLower(e.fileExt))
+	if !ok || e.ReadOnly {
+		return nil
+	}
+	lead, trail := lang.LineComment, ""
+	if lead == "" {
+		lead, trail = lang.BlockStart, lang.BlockEnd
+	}
+
+	first, last := e.selectedLines()
+
+	indent, commented := -1, true
+	for l := first; l <= last; l++ {
+		line := e.Buffer.Line(l)
+		body := strings.TrimLeft(line, " \t")
+		if body == "" {
+			continue
+		}
+		if n := len(line) - len(body); indent < 0 || n < indent {
+			indent = n
+		}
+		if !strings.HasPrefix(body, lead) || !strings.HasSuffix(body, trail) || len(body) < len(lead)+len(trail) {
+			commented = false
+		}
+	}
+	if indent < 0 {
+		return nil
+	}
+
+	var edits []TextEdit
+	for l := first; l <= last; l++ {
+		line := e.Buffer.Line(l)
+		body := strings.TrimLeft(line, " \t")
+		if body == "" {
+			continue
+		}
+		start := len(line) - len(body)
+		if commented {
+			edits = append(edits, uncommentEdits(l, start, body, lead, trail)...)
+			continue
+		}
+		edits = append(edits, TextEdit{Range: Selection{Start: Position{Line: l, Column: indent}, End: Position{Line: l, Column: indent}}, NewText: lead + " "})
+		if trail != "" {
+			end := Position{Line: l, Column: len(line)}
+			edits = append(edits, TextEdit{Range: Selection{Start: end, End: end}, NewText: " " + trail})
+		}
+	}
+	cmd, _ := e.ApplyEdits(edits)
+	return cmd
+}
+
+// uncommentEdits removes lead and trail from body, which starts at column
+// start of line, along with a space next to each.
+func uncommentEdits(line, start int, body, lead, trail string) []TextEdit {
+	n := len(lead)
+	if strings.HasPrefix(body[n:], " ") {
+		n++
+	}
+	edits := []TextEdit{{Range: Selection{Start: Position{Line: line, Column: start}, End: Position{Line: line, Column: start + n}}}}
+	if trail != "" {
+		end := start + len(body)
+		m := len(trail)
+		if strings.HasSuffix(body[:len(body)-m], " ") && len(body)-m-1 >= n {
+			m++
+		}
+		edits = append(edits, TextEdit{Range: Selection{Start: Position{Line: line, Column: end - m}, End: Position{Line: line, Column: end}}})
+	}
+	return edits
+}
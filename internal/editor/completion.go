@@ -0,0 +1,257 @@
+package editor
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/pkg/fuzzy"
+	"tron/pkg/layout"
+)
+
+const (
+	completionRows        = 8
+	completionLabelWidth  = 40
+	completionDetailWidth = 30
+)
+
+// CompletionItem is one of a language server's suggestions for the word
+// at the cursor. Accepting it replaces the part of the word already typed
+// with Text.
+type CompletionItem struct {
+	Label  string
+	Detail string
+	Text   string
+}
+
+// CompletionRequest asks for completions at Position. Character is its
+// column in UTF-16 code units, as language servers count them.
+type CompletionRequest struct {
+	Path      string
+	Position  Position
+	Character int
+	Seq       int
+}
+
+type CompletionResultMsg struct {
+	Path  string
+	Seq   int
+	Items []CompletionItem
+}
+
+// completionPopup lists the items matching the word being typed. Typing a
+// word character or a dot asks for new items once the edit is flushed;
+// until they come the ones already shown are filtered by the longer word.
+type completionPopup struct {
+	wanted   bool
+	pending  bool
+	at       Position
+	items    []CompletionItem
+	matches  []fuzzy.Match
+	selected int
+	top      int
+}
+
+func isCompletionRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// completionStart is where the word before the cursor starts.
+func (e *Editor) completionStart() Position {
+	line := e.Buffer.Line(e.Cursor.Line)
+	col := min(e.Cursor.Column, len(line))
+	for col > 0 {
+		r, size := utf8.DecodeLastRuneInString(line[:col])
+		if !isCompletionRune(r) {
+			break
+		}
+		col -= size
+	}
+	return Position{Line: e.Cursor.Line, Column: col}
+}
+
+func (e *Editor) completing() bool {
+	return len(e.completion.matches) > 0
+}
+
+func (e *Editor) closeCompletion() {
+	e.completion = completionPopup{}
+}
+
+// followCompletion keeps the popup open while a key typed part of a word,
+// or a dot, and closes it after anything else. version is the buffer's
+// version before the key, so keys that changed nothing close it too.
+func (e *Editor) followCompletion(msg tea.KeyMsg, version int) {
+	c := &e.completion
+	changed := e.Buffer.Version() != version && len(e.carets) == 0 && !e.hasSelection()
+	switch {
+	case changed && msg.Type == tea.KeyRunes && !msg.Paste && len(msg.Runes) > 0:
+		r := msg.Runes[len(msg.Runes)-1]
+		if r == '.' {
+			// Members of what is before the dot replace the items.
+			c.items = nil
+		} else if !isCompletionRune(r) {
+			e.closeCompletion()
+			return
+		}
+	case changed && msg.Type == tea.KeyBackspace && c.items != nil && e.completionStart() != e.Cursor:
+	default:
+		e.closeCompletion()
+		return
+	}
+	c.wanted = true
+	e.filterCompletions()
+}
+
+func (e *Editor) filterCompletions() {
+	c := &e.completion
+	labels := make([]string, len(c.items))
+	for i, item := range c.items {
+		labels[i] = item.Label
+	}
+	start := e.completionStart()
+	c.matches = fuzzy.Filter(e.Buffer.GetText(start, e.Cursor), labels)
+	c.selected, c.top = 0, 0
+}
+
+// TakeCompletionRequest returns the request for completions the last
+// typed keys called for, once. Ask for it after the edit reached the
+// language server, so the items are for the text as it is now.
+func (e *Editor) TakeCompletionRequest() (CompletionRequest, bool) {
+	c := &e.completion
+	if !c.wanted || e.FilePath == "" {
+		return CompletionRequest{}, false
+	}
+	c.wanted, c.pending, c.at = false, true, e.Cursor
+	line := e.Buffer.Line(e.Cursor.Line)
+	return CompletionRequest{
+		Path:      e.FilePath,
+		Position:  e.Cursor,
+		Character: utf16Len(line[:min(e.Cursor.Column, len(line))]),
+		Seq:       e.editSeq,
+	}, true
+}
+
+func (e *Editor) handleCompletionResult(msg CompletionResultMsg) {
+	c := &e.completion
+	if !c.pending || msg.Path != e.FilePath || msg.Seq != e.editSeq || e.Cursor != c.at {
+		return
+	}
+	c.pending = false
+	c.items = msg.Items
+	e.filterCompletions()
+}
+
+// handleCompletionKey moves through the popup, accepts an item with tab
+// or enter, or closes it with esc. Other keys go to the editor.
+func (e *Editor) handleCompletionKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if !e.focused || !e.completing() {
+		return nil, false
+	}
+	c := &e.completion
+	n := len(c.matches)
+	switch msg.Type {
+	case tea.KeyUp:
+		c.selected = (c.selected + n - 1) % n
+	case tea.KeyDown:
+		c.selected = (c.selected + 1) % n
+	case tea.KeyTab, tea.KeyEnter:
+		return e.acceptCompletion(), true
+	case tea.KeyEsc:
+		e.closeCompletion()
+	default:
+		return nil, false
+	}
+	if c.selected < c.top {
+		c.top = c.selected
+	} else if c.selected >= c.top+completionRows {
+		c.top = c.selected - completionRows + 1
+	}
+	return nil, true
+}
+
+func (e *Editor) acceptCompletion() tea.Cmd {
+	c := &e.completion
+	item := c.items[c.matches[c.selected].Index]
+	start := e.completionStart()
+	e.closeCompletion()
+	cmd, _ := e.ReplaceRange(start, e.Cursor, item.Text)
+	return cmd
+}
+
+// screenPosition returns the cell p is drawn in, relative to the text
+// area's top left.
+func (e *Editor) screenPosition(p Position) (x, y int) {
+	if !e.WrapLines {
+		for line := e.Viewport.Y; line < p.Line; line = e.nextVisibleLine(line) {
+			y++
+		}
+		return e.cell(p.Line, p.Column) - e.Viewport.X, y
+	}
+	for line := e.Viewport.Y; line < p.Line; line = e.nextVisibleLine(line) {
+		y += e.wrapRows(line)
+	}
+	breaks := e.wrapBreaks(p.Line)
+	row := wrapRow(breaks, p.Column)
+	return e.cell(p.Line, p.Column) - e.cell(p.Line, breaks[row]), y + row
+}
+
+func (e *Editor) renderCompletion(view string) string {
+	if !e.completing() {
+		return view
+	}
+	c := &e.completion
+	var labels, details []string
+	labelWidth, detailWidth := 0, 0
+	for i := c.top; i < min(c.top+completionRows, len(c.matches)); i++ {
+		item := c.items[c.matches[i].Index]
+		label, detail := truncate(item.Label, completionLabelWidth), truncate(item.Detail, completionDetailWidth)
+		labels, details = append(labels, label), append(details, detail)
+		labelWidth = max(labelWidth, lipgloss.Width(label))
+		detailWidth = max(detailWidth, lipgloss.Width(detail))
+	}
+
+	rowStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4")).Background(lipgloss.Color("#1e1e2e"))
+	selectedStyle := rowStyle.Background(lipgloss.Color("#45475a"))
+	detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	rows := make([]string, len(labels))
+	for i, label := range labels {
+		style := rowStyle
+		if c.top+i == c.selected {
+			style = selectedStyle
+		}
+		text := label + strings.Repeat(" ", labelWidth-lipgloss.Width(label))
+		if detailWidth > 0 {
+			text += "  " + detailStyle.Inherit(style).Render(details[i]+strings.Repeat(" ", detailWidth-lipgloss.Width(details[i])))
+		}
+		rows[i] = style.Render(" " + text + " ")
+	}
+	popup := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#6c7086")).
+		Render(strings.Join(rows, "\n"))
+
+	// Labels line up with the word, past the border and padding.
+	x, y := e.screenPosition(e.completionStart())
+	x += e.lineNumWidth() - 2
+	popupHeight, popupWidth := lipgloss.Height(popup), lipgloss.Width(popup)
+	if y+1+popupHeight > e.Viewport.Height && y-popupHeight >= 0 {
+		y -= popupHeight
+	} else {
+		y++
+	}
+	if x+popupWidth > e.Width {
+		x = max(0, e.Width-popupWidth)
+	}
+	return layout.Overlay(view, popup, x, y)
+}
+
+func truncate(s string, width int) string {
+	if r := []rune(s); len(r) > width {
+		return string(r[:width-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package editor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typeKeys(e *Editor, text string) {
+	for _, r := range text {
+		e.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestCompletionAcceptReplacesWord(t *testing.T) {
+	e := NewWithContent("")
+	e.FilePath = "/work/main.go"
+	typeKeys(e, "fmt.Pr")
+
+	req, ok := e.TakeCompletionRequest()
+	if !ok || req.Position != (Position{Column: 6}) || req.Character != 6 {
+		t.Fatalf("TakeCompletionRequest = %+v, %v", req, ok)
+	}
+	if _, ok := e.TakeCompletionRequest(); ok {
+		t.Fatal("the request was returned twice")
+	}
+
+	e.Update(CompletionResultMsg{Path: req.Path, Seq: req.Seq, Items: []CompletionItem{
+		{Label: "Printf", Text: "Printf"},
+		{Label: "Sprint", Text: "Sprint"},
+		{Label: "Println", Text: "Println"},
+	}})
+	if !e.completing() || len(e.completion.matches) != 2 {
+		t.Fatalf("matches for Pr = %+v", e.completion.matches)
+	}
+
+	typeKeys(e, "intl")
+	if len(e.completion.matches) != 1 {
+		t.Fatalf("matches for Printl = %+v", e.completion.matches)
+	}
+	e.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := e.Buffer.Content(); got != "fmt.Println" {
+		t.Fatalf("after accepting: %q", got)
+	}
+	if e.completing() || e.Cursor != (Position{Column: 11}) {
+		t.Errorf("completing = %v, cursor = %+v", e.completing(), e.Cursor)
+	}
+}
+
+func TestCompletionDropsStaleResults(t *testing.T) {
+	e := NewWithContent("")
+	e.FilePath = "/work/main.go"
+	typeKeys(e, "fm")
+	req, _ := e.TakeCompletionRequest()
+	typeKeys(e, "t")
+
+	e.Update(CompletionResultMsg{Path: req.Path, Seq: req.Seq, Items: []CompletionItem{{Label: "fmt", Text: "fmt"}}})
+	if e.completing() {
+		t.Fatal("showed items for text that has changed since")
+	}
+
+	e.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	if _, ok := e.TakeCompletionRequest(); ok {
+		t.Fatal("a space asked for completions")
+	}
+}
@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/text/unicode/norm"
+)
+
+// deadKeys maps the spacing accents that dead keys produce to the combining
+// marks they put on the following letter.
+var deadKeys = map[rune]rune{
+	'´': '́',
+	'`': '̀',
+	'^': '̂',
+	'¨': '̈',
+	'~': '̃',
+	'¸': '̧',
+	'ˇ': '̌',
+	'˘': '̆',
+	'˚': '̊',
+}
+
+// ToggleDeadKeys switches composing accents typed before a letter, for
+// terminals that pass dead keys through instead of composing them.
+func (e *Editor) ToggleDeadKeys() {
+	e.DeadKeys = !e.DeadKeys
+	e.preedit = 0
+}
+
+// typeRunes inserts text typed at the cursor. Input methods commit whole
+// words in one event and some send accents as separate combining marks, so
+// the text goes in as one piece, composed with the character before it.
+func (e *Editor) typeRunes(runes []rune) {
+	if e.DeadKeys {
+		if runes = e.composeDeadKey(runes); len(runes) == 0 {
+			return
+		}
+	}
+	e.insertTyped(runes)
+}
+
+func (e *Editor) insertTyped(runes []rune) {
+	if e.hasSelection() {
+		e.deleteSelection()
+	}
+	text := string(runes)
+	if r, _ := utf8.DecodeRuneInString(text); unicode.Is(unicode.Mn, r) && e.Cursor.Column > 0 {
+		line := e.Buffer.Line(e.Cursor.Line)
+		start := prevBoundary(line, e.Cursor.Column)
+		text = line[start:e.Cursor.Column] + text
+		e.Buffer.Delete(Position{Line: e.Cursor.Line, Column: start}, e.Cursor)
+		e.Cursor.Column = start
+	}
+	text = norm.NFC.String(text)
+	e.Buffer.Insert(e.Cursor, text)
+	e.Cursor.Column += len(text)
+	e.clearSelection()
+}
+
+// composeDeadKey holds a lone accent as preedit until the next key and
+// returns what is left to insert.
+func (e *Editor) composeDeadKey(runes []rune) []rune {
+	accent := e.preedit
+	if accent == 0 {
+		if len(runes) == 1 {
+			if _, ok := deadKeys[runes[0]]; ok {
+				e.preedit = runes[0]
+				return nil
+			}
+		}
+		return runes
+	}
+	e.preedit = 0
+	if runes[0] == ' ' || runes[0] == accent {
+		// Space or the accent again gives the accent itself.
+		return append([]rune{accent}, runes[1:]...)
+	}
+	if composed := []rune(norm.NFC.String(string([]rune{runes[0], deadKeys[accent]}))); len(composed) == 1 {
+		return append(composed, runes[1:]...)
+	}
+	return append([]rune{accent}, runes...)
+}
+
+// flushPreedit ends a pending composition before a key that is not text:
+// backspace drops the accent, anything else types it.
+func (e *Editor) flushPreedit(msg tea.KeyMsg) bool {
+	if e.preedit == 0 || msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+		return false
+	}
+	accent := e.preedit
+	e.preedit = 0
+	if msg.Type == tea.KeyBackspace {
+		return true
+	}
+	e.insertTyped([]rune{accent})
+	e.markDirty()
+	return false
+}
+
+func (e *Editor) renderPreedit() string {
+	if e.preedit == 0 {
+		return ""
+	}
+	return preeditStyle.Render(string(e.preedit))
+}
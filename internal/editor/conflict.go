@@ -0,0 +1,114 @@
+package editor
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrConflict is returned by Save when the file changed on disk after it
+// was loaded or last saved.
+var ErrConflict = errors.New("file changed on disk since it was loaded")
+
+// ConflictMergeMsg asks for the buffer to be merged with the file on disk
+// after a save was blocked by ErrConflict.
+type ConflictMergeMsg struct {
+	Path  string
+	Local string
+	Disk  string
+}
+
+func (e *Editor) recordDiskState() {
+	e.diskModTime, e.diskSize = time.Time{}, 0
+	if info, err := os.Stat(e.FilePath); err == nil {
+		e.diskModTime, e.diskSize = info.ModTime(), info.Size()
+	}
+}
+
+// checkConflict compares the file on disk with the version the buffer was
+// loaded from. A changed mtime alone, as after a touch, is not a conflict.
+func (e *Editor) checkConflict() error {
+	info, err := os.Stat(e.FilePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(e.diskModTime) {
+		return nil
+	}
+	content, err := os.ReadFile(e.FilePath)
+	if err != nil {
+		return err
+	}
+	if e.savedEnding.hash(string(content)) != e.savedHash {
+		return ErrConflict
+	}
+	e.diskModTime = info.ModTime()
+	return nil
+}
+
+func (e *Editor) handleConflictChoice(msg tea.KeyMsg) tea.Cmd {
+	e.confirmConflict = false
+	switch msg.String() {
+	case "o", "O":
+		return e.writeCmd(e.write())
+	case "r", "R":
+		return e.reload()
+	case "m", "M":
+		path, local, ending := e.FilePath, e.Buffer.Content(), e.savedEnding
+		return func() tea.Msg {
+			disk, err := os.ReadFile(path)
+			if err != nil {
+				return EditorSaveFailedMsg{Path: path, Err: err}
+			}
+			return ConflictMergeMsg{Path: path, Local: local, Disk: ending.read(string(disk))}
+		}
+	}
+	return nil
+}
+
+// reload replaces the buffer with the file on disk, keeping the cursor
+// where it was as far as the new content allows.
+func (e *Editor) reload() tea.Cmd {
+	cursor, viewX, viewY := e.Cursor, e.Viewport.X, e.Viewport.Y
+	if err := e.LoadFile(e.FilePath); err != nil {
+		path := e.FilePath
+		return func() tea.Msg {
+			return EditorSaveFailedMsg{Path: path, Err: err}
+		}
+	}
+	e.Cursor = cursor
+	e.Viewport.X, e.Viewport.Y = viewX, viewY
+	e.ensureCursorValid()
+	e.editSeq++
+	e.pendingFlush = true
+	return e.Flush()
+}
+
+// ReloadChanged reloads the file if it changed on disk and the buffer has
+// no unsaved edits, as after a git pull or stash pop.
+func (e *Editor) ReloadChanged() tea.Cmd {
+	if e.FilePath == "" || e.Dirty {
+		return nil
+	}
+	if !errors.Is(e.checkConflict(), ErrConflict) {
+		return nil
+	}
+	return e.reload()
+}
+
+// LoadMerge replaces the buffer with merged, a merge of the unsaved edits
+// with disk. The disk version becomes the baseline, so saving the resolved
+// buffer no longer conflicts.
+func (e *Editor) LoadMerge(merged, disk string) tea.Cmd {
+	e.savedHash = NewSimpleBufferWithContent(disk).Hash()
+	e.recordDiskState()
+	e.SetContent(merged)
+	e.markDirty()
+	return e.afterCommand()
+}
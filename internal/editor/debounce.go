@@ -0,0 +1,58 @@
+package editor
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const DefaultDebounceDelay = 150 * time.Millisecond
+
+type EditorChangedMsg struct {
+	Path string
+}
+
+type editorFlushMsg struct {
+	seq int
+}
+
+func (e *Editor) scheduleFlush() tea.Cmd {
+	if !e.pendingFlush {
+		return nil
+	}
+	if e.DebounceDelay <= 0 {
+		return e.Flush()
+	}
+	seq := e.editSeq
+	return tea.Tick(e.DebounceDelay, func(time.Time) tea.Msg {
+		return editorFlushMsg{seq: seq}
+	})
+}
+
+func (e *Editor) handleFlush(msg editorFlushMsg) tea.Cmd {
+	if msg.seq != e.editSeq {
+		return nil
+	}
+	return e.Flush()
+}
+
+func (e *Editor) Flush() tea.Cmd {
+	if !e.pendingFlush {
+		return nil
+	}
+	e.pendingFlush = false
+	e.updateDirty()
+	e.updateHighlighting()
+	path := e.FilePath
+	changed := func() tea.Msg {
+		return EditorChangedMsg{Path: path}
+	}
+	return tea.Batch(changed, e.requestSuggestion())
+}
+
+// NotifyExternalChange treats a buffer change made outside the editor, such
+// as a collaborator's edit, like a local one.
+func (e *Editor) NotifyExternalChange() tea.Cmd {
+	e.markDirty()
+	return e.scheduleFlush()
+}
@@ -0,0 +1,141 @@
+package editor
+
+import (
+	"path/filepath"
+	"time"
+
+	"tron/internal/syntax"
+	"tron/pkg/pathutil"
+)
+
+// Document is the per-file state the editor swaps out when another tab
+// becomes active, so switching back keeps edits, cursor and scroll.
+type Document struct {
+	path               string
+	fileExt            string
+	buffer             Buffer
+	cursor             Position
+	selection          Selection
+	anchor             Position
+	selectMode         selectionMode
+	viewX              int
+	viewY              int
+	highlightedVersion int
+	highlightSpans     []syntax.HighlightSpan
+	dirty              bool
+	savedHash          uint64
+	lineEnding         LineEnding
+	savedEnding        LineEnding
+	diskModTime        time.Time
+	diskSize           int64
+	readOnly           bool
+	indent             IndentSettings
+	folds              []fold
+	bookmarks          []int
+	history            undoHistory
+}
+
+func (d *Document) Path() string {
+	return d.path
+}
+
+// Rename points the document at path after its file was renamed.
+func (d *Document) Rename(path string) {
+	d.path = pathutil.Canonical(path)
+	d.fileExt = filepath.Ext(path)
+	d.highlightedVersion = -1
+}
+
+func (d *Document) Dirty() bool {
+	return d.dirty
+}
+
+func (d *Document) Content() string {
+	return d.buffer.Content()
+}
+
+// FileContent is the content as saving the document would write it.
+func (d *Document) FileContent() string {
+	return d.lineEnding.Apply(d.buffer.Content())
+}
+
+// Detach hands the current file's state to the caller and leaves the editor
+// with a fresh, empty buffer. Flush first so listeners see the last edits.
+func (e *Editor) Detach() *Document {
+	e.commitUndo()
+	d := &Document{
+		path:               e.FilePath,
+		fileExt:            e.fileExt,
+		buffer:             e.Buffer,
+		cursor:             e.Cursor,
+		selection:          e.Selection,
+		anchor:             e.anchor,
+		selectMode:         e.selectMode,
+		viewX:              e.Viewport.X,
+		viewY:              e.Viewport.Y,
+		highlightedVersion: e.highlightedVersion,
+		highlightSpans:     e.highlightSpans,
+		dirty:              e.Dirty,
+		savedHash:          e.savedHash,
+		lineEnding:         e.LineEnding,
+		savedEnding:        e.savedEnding,
+		diskModTime:        e.diskModTime,
+		diskSize:           e.diskSize,
+		readOnly:           e.ReadOnly,
+		indent:             e.Indent,
+		folds:              e.folds,
+		bookmarks:          e.bookmarks,
+		history:            e.history,
+	}
+	e.SetBuffer(NewSimpleBuffer())
+	e.FilePath = ""
+	e.highlightSpans = nil
+	e.Indent = defaultIndent
+	e.LineEnding, e.savedEnding = LF, LF
+	e.folds = nil
+	e.bookmarks = nil
+	e.clearUndo()
+	e.closeCompletion()
+	e.carets = nil
+	e.clearGhost()
+	e.dismissHover()
+	return d
+}
+
+func (e *Editor) Attach(d *Document) {
+	e.FilePath = d.path
+	e.fileExt = d.fileExt
+	e.SetBuffer(d.buffer)
+	e.Cursor = d.cursor
+	e.Selection = d.selection
+	e.anchor = d.anchor
+	e.selectMode = d.selectMode
+	e.Viewport.X = d.viewX
+	e.Viewport.Y = d.viewY
+	e.highlightedVersion = d.highlightedVersion
+	e.highlightSpans = d.highlightSpans
+	e.Dirty = d.dirty
+	e.savedHash = d.savedHash
+	e.LineEnding, e.savedEnding = d.lineEnding, d.savedEnding
+	e.diskModTime = d.diskModTime
+	e.diskSize = d.diskSize
+	e.ReadOnly = d.readOnly
+	e.Indent = d.indent
+	e.folds = d.folds
+	e.bookmarks = d.bookmarks
+	e.history = d.history
+	e.pendingFlush = false
+}
+
+// LoadRecovered opens path with content recovered from a journal in place
+// of what is on disk, marking the buffer dirty when they differ.
+func (e *Editor) LoadRecovered(path, content string) {
+	if err := e.LoadFile(path); err != nil {
+		e.FilePath = pathutil.Canonical(path)
+		e.savedHash = NewSimpleBuffer().Hash()
+		e.LineEnding, e.savedEnding = LF, LF
+		e.ReadOnly = false
+	}
+	e.SetContent(content)
+	e.updateDirty()
+}
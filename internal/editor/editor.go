@@ -5,11 +5,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"tron/internal/i18n"
 	"tron/internal/syntax"
+	"tron/pkg/pathutil"
 )
 
 type Editor struct {
@@ -26,14 +30,93 @@ type Editor struct {
 	ShowCursor         bool
 	focused            bool
 	anchor             Position
-	selectionActive    bool
+	bookmarks          []int
+	widest             struct {
+		buffer         Buffer
+		version, cells int
+	}
+	selectMode         selectionMode
+	dragging           bool
+	clicks             clickCounter
 	fileExt            string
-	highlightedContent string
+	highlightedVersion int
 	highlightSpans     []syntax.HighlightSpan
 	theme              *syntax.Theme
 	FilePath           string
 	Dirty              bool
-	originalContent    string
+	savedHash          uint64
+	// LineEnding is what the file is written with; savedEnding is what
+	// it has on disk.
+	LineEnding         LineEnding
+	savedEnding        LineEnding
+	scrollLinks        []*ScrollLink
+	DebounceDelay      time.Duration
+	editSeq            int
+	pendingFlush       bool
+	ReadOnly           bool
+	ElevateCommand     []string
+	confirmElevate     bool
+	confirmConflict    bool
+	diskModTime        time.Time
+	diskSize           int64
+	hoverPos           Position
+	hoverValid         bool
+	hoverSeq           int
+	hoverText          string
+	hoverX             int
+	hoverY             int
+	gutterMarkers      map[string]map[int]GutterMarker
+	gutterOrder        []string
+	remoteCursors      []RemoteCursor
+	Suggestions        bool
+	ghostText          string
+	ghostPos           Position
+	search             searchState
+	carets             []caret
+	// WrapLines wraps long lines at the viewport width instead of
+	// scrolling horizontally.
+	WrapLines bool
+	Indent    IndentSettings
+	// TabWidth is how many cells apart tab stops are drawn.
+	TabWidth int
+	folds     []fold
+	// DeadKeys composes an accent typed on its own with the next letter.
+	DeadKeys bool
+	preedit  rune
+	// Vim layers vim's normal, insert and visual modes over the usual
+	// keys; see SetVim.
+	Vim bool
+	vim vimState
+	// BlockSelection makes shift+arrows and mouse drags select rectangles;
+	// alt+shift+drag does so regardless.
+	BlockSelection bool
+	block          blockState
+	blockClip      string
+	// Accessible marks the cursor line and selection in the sign column
+	// and renders the selection in reverse video rather than by color.
+	Accessible bool
+	// Minimap draws a condensed overview of the buffer along the right
+	// edge; see SetMinimap.
+	Minimap bool
+	// ShowWhitespace marks spaces and tabs and draws indentation guides.
+	ShowWhitespace bool
+	// TrimTrailingWhitespace and InsertFinalNewline tidy the buffer each
+	// time it is saved; see applySaveTransforms.
+	TrimTrailingWhitespace bool
+	InsertFinalNewline     bool
+	// Backup keeps the previous content of a saved file at its path
+	// with ~ appended.
+	Backup bool
+	// WordSeparators overrides the characters that end words, keyed by
+	// file extension such as ".css", or "*" for every file.
+	WordSeparators map[string]string
+	// unwatch stops following the buffer's changes, which are kept for
+	// TakeChanges; see SetBuffer.
+	unwatch     func()
+	changes     []ChangeEvent
+	changesLost bool
+	history     undoHistory
+	completion  completionPopup
 }
 
 type EditorSavedMsg struct {
@@ -48,8 +131,7 @@ type EditorFocusMsg struct{}
 type EditorBlurMsg struct{}
 
 func New() *Editor {
-	return &Editor{
-		Buffer:          NewSimpleBuffer(),
+	e := &Editor{
 		Viewport:        NewViewport(),
 		Cursor:          Position{Line: 0, Column: 0},
 		Selection:       Selection{},
@@ -62,12 +144,17 @@ func New() *Editor {
 		ShowCursor:      true,
 		focused:         true,
 		theme:           syntax.GetTheme(),
+		DebounceDelay:   DefaultDebounceDelay,
+		ElevateCommand:  DefaultElevateCommand,
+		Indent:          defaultIndent,
 	}
+	e.SetBuffer(NewSimpleBuffer())
+	return e
 }
 
 func NewWithBuffer(b Buffer) *Editor {
 	e := New()
-	e.Buffer = b
+	e.SetBuffer(b)
 	return e
 }
 
@@ -78,34 +165,48 @@ func NewWithContent(content string) *Editor {
 func (e *Editor) SetSize(width, height int) {
 	e.Width = width
 	e.Height = height
-	e.Viewport.Width = width - e.lineNumWidth()
-	e.Viewport.Height = height
+	e.Viewport.Width = width - e.lineNumWidth() - e.minimapWidth()
+	e.fitViewHeight()
 }
 
 func (e *Editor) SetContent(content string) {
 	e.Buffer.SetContent(content)
+	e.clearUndo()
+	e.closeCompletion()
 	e.Cursor = Position{Line: 0, Column: 0}
 	e.Viewport.Y = 0
 	e.Viewport.X = 0
 	e.clearSelection()
+	e.carets = nil
+	e.folds = nil
+	e.bookmarks = nil
 	e.updateHighlighting()
 }
 
 func (e *Editor) SetFileExtension(ext string) {
 	e.fileExt = ext
+	e.highlightedVersion = -1
 	e.updateHighlighting()
 }
 
+func (e *Editor) SetTheme(theme *syntax.Theme) {
+	e.theme = theme
+}
+
 func (e *Editor) SetFilePath(path string) {
 	e.fileExt = filepath.Ext(path)
+	e.highlightedVersion = -1
 	e.updateHighlighting()
 }
 
 func (e *Editor) updateHighlighting() {
-	content := e.Buffer.Content()
-	if content != e.highlightedContent {
-		e.highlightedContent = content
-		e.highlightSpans = syntax.Highlight(content, e.fileExt)
+	if version := e.Buffer.Version(); version != e.highlightedVersion {
+		e.highlightedVersion = version
+		if !e.highlightsContent() {
+			e.highlightSpans = nil
+			return
+		}
+		e.highlightSpans = syntax.Highlight(e.Buffer.Content(), e.fileExt)
 	}
 }
 
@@ -119,6 +220,7 @@ func (e *Editor) Focus() {
 
 func (e *Editor) Blur() {
 	e.focused = false
+	e.closeCompletion()
 }
 
 func (e *Editor) Focused() bool {
@@ -132,15 +234,47 @@ func (e *Editor) Init() tea.Cmd {
 func (e *Editor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		return e.handleKeyPress(msg)
+		e.commitUndo()
+		if cmd, ok := e.handleCompletionKey(msg); ok {
+			e.commitUndo()
+			return e, cmd
+		}
+		version := e.Buffer.Version()
+		m, cmd := e.handleKeyPress(msg)
+		e.commitUndo()
+		e.followCompletion(msg, version)
+		if e.lineHidden(e.Cursor.Line) {
+			e.revealCursor()
+		}
+		e.fitWrappedView()
+		e.syncScrollLinks()
+		return m, cmd
 	case tea.MouseMsg:
-		return e.handleMouse(msg)
+		m, cmd := e.handleMouse(msg)
+		e.commitUndo()
+		e.syncScrollLinks()
+		return m, cmd
 	case EditorFocusMsg:
 		e.Focus()
 		return e, nil
 	case EditorBlurMsg:
 		e.Blur()
 		return e, nil
+	case editorFlushMsg:
+		return e, e.handleFlush(msg)
+	case elevatedSaveDoneMsg:
+		return e, e.handleElevatedSaveDone(msg)
+	case hoverTickMsg:
+		return e, e.handleHoverTick(msg)
+	case HoverResultMsg:
+		e.handleHoverResult(msg)
+		return e, nil
+	case CompletionResultMsg:
+		e.handleCompletionResult(msg)
+		return e, nil
+	case SuggestionResultMsg:
+		e.handleSuggestionResult(msg)
+		return e, nil
 	}
 	return e, nil
 }
@@ -149,36 +283,77 @@ func (e *Editor) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if !e.focused {
 		return e, nil
 	}
+	e.dismissHover()
+	if e.confirmElevate {
+		return e, e.handleElevateConfirm(msg)
+	}
+	if e.confirmConflict {
+		return e, e.handleConflictChoice(msg)
+	}
+	if e.search.active {
+		return e, e.handleSearchKey(msg)
+	}
+	if e.ghostText != "" && msg.Type == tea.KeyTab && !e.ReadOnly {
+		e.acceptGhost()
+		e.revealCursor()
+		return e, e.scheduleFlush()
+	}
+	e.clearGhost()
+	if msg.String() == "ctrl+d" {
+		e.addNextOccurrence()
+		e.revealCursor()
+		return e, nil
+	}
+	if e.handleAltKey(msg) {
+		return e, nil
+	}
+	if e.Vim && e.handleVimKey(msg) {
+		e.ensureCursorValid()
+		e.revealCursor()
+		return e, e.scheduleFlush()
+	}
+	if e.ReadOnly && isEditKey(msg) {
+		return e, nil
+	}
+	if e.flushPreedit(msg) {
+		return e, nil
+	}
+	if e.handleBlockKey(msg.String()) {
+		e.revealCursor()
+		return e, nil
+	}
+	if e.handleWordKey(msg) || len(e.carets) > 0 && e.handleCaretKey(msg) {
+		e.ensureCursorValid()
+		e.revealCursor()
+		return e, e.scheduleFlush()
+	}
 
 	switch msg.Type {
-	case tea.KeyRunes:
-		if len(msg.Runes) > 0 {
-			if e.hasSelection() {
-				e.deleteSelection()
-			}
-			for _, r := range msg.Runes {
-				e.Buffer.Insert(e.Cursor, string(r))
-				e.Cursor.Column++
-			}
-			e.clearSelection()
+	case tea.KeyRunes, tea.KeySpace:
+		if msg.Paste {
+			e.insertText(normalizeLineEndings(string(msg.Runes)))
+			e.markDirty()
+		} else if len(msg.Runes) > 0 {
+			e.typeRunes(msg.Runes)
 			e.markDirty()
 		}
 	case tea.KeyEnter:
 		if e.hasSelection() {
 			e.deleteSelection()
 		}
-		e.Buffer.Insert(e.Cursor, "\n")
-		e.Cursor.Line++
-		e.Cursor.Column = 0
+		text := e.lineBreak(e.Cursor)
+		e.Buffer.Insert(e.Cursor, text)
+		e.moveCursorAfterInsert(text)
 		e.clearSelection()
 		e.markDirty()
 	case tea.KeyBackspace:
 		if e.hasSelection() {
 			e.deleteSelection()
 		} else {
+			prev := prevBoundary(e.Buffer.Line(e.Cursor.Line), e.Cursor.Column)
 			e.Buffer.DeleteChar(e.Cursor, false)
 			if e.Cursor.Column > 0 {
-				e.Cursor.Column--
+				e.Cursor.Column = prev
 			} else if e.Cursor.Line > 0 {
 				e.Cursor.Line--
 				e.Cursor.Column = e.Buffer.LineLength(e.Cursor.Line)
@@ -186,6 +361,9 @@ func (e *Editor) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		e.clearSelection()
 		e.markDirty()
+	case tea.KeyTab:
+		e.insertTab()
+		e.markDirty()
 	case tea.KeyDelete:
 		if e.hasSelection() {
 			e.deleteSelection()
@@ -194,15 +372,18 @@ func (e *Editor) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		e.clearSelection()
 		e.markDirty()
-	case tea.KeyLeft:
+	case tea.KeyLeft, tea.KeyShiftLeft:
 		e.moveCursor(-1, 0, e.isShiftPressed(msg))
-	case tea.KeyRight:
+	case tea.KeyRight, tea.KeyShiftRight:
 		e.moveCursor(1, 0, e.isShiftPressed(msg))
-	case tea.KeyUp:
+	case tea.KeyUp, tea.KeyShiftUp:
 		e.moveCursor(0, -1, e.isShiftPressed(msg))
-	case tea.KeyDown:
+	case tea.KeyDown, tea.KeyShiftDown:
 		e.moveCursor(0, 1, e.isShiftPressed(msg))
-	case tea.KeyHome:
+	case tea.KeyHome, tea.KeyShiftHome:
+		if e.isShiftPressed(msg) {
+			e.anchorSelection()
+		}
 		if msg.Alt {
 			e.Cursor.Line = 0
 			e.Cursor.Column = 0
@@ -210,11 +391,14 @@ func (e *Editor) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			e.Cursor.Column = 0
 		}
 		if e.isShiftPressed(msg) {
-			e.extendSelection()
+			e.selectTo(e.Cursor)
 		} else {
 			e.clearSelection()
 		}
-	case tea.KeyEnd:
+	case tea.KeyEnd, tea.KeyShiftEnd:
+		if e.isShiftPressed(msg) {
+			e.anchorSelection()
+		}
 		if msg.Alt {
 			e.Cursor.Line = e.Buffer.LineCount() - 1
 			e.Cursor.Column = e.Buffer.LineLength(e.Cursor.Line)
@@ -222,7 +406,7 @@ func (e *Editor) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			e.Cursor.Column = e.Buffer.LineLength(e.Cursor.Line)
 		}
 		if e.isShiftPressed(msg) {
-			e.extendSelection()
+			e.selectTo(e.Cursor)
 		} else {
 			e.clearSelection()
 		}
@@ -230,6 +414,8 @@ func (e *Editor) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "ctrl+a":
 			e.selectAll()
+		case "ctrl+l":
+			e.selectLine()
 		case "ctrl+c":
 			e.copySelection()
 		case "ctrl+v":
@@ -238,96 +424,182 @@ func (e *Editor) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "ctrl+x":
 			e.cutSelection()
 			e.markDirty()
-		case "ctrl+s":
+		case "f9":
 			if e.FilePath != "" {
-				if err := e.Save(); err == nil {
-					return e, func() tea.Msg {
-						return EditorSavedMsg{Path: e.FilePath}
-					}
+				path, line := e.FilePath, e.Cursor.Line
+				return e, func() tea.Msg {
+					return ToggleBreakpointMsg{Path: path, Line: line}
 				}
 			}
+		case "ctrl+u":
+			return e, e.Undo()
+		case "ctrl+r":
+			return e, e.Redo()
+		case "ctrl+s":
+			if e.FilePath != "" {
+				return e, e.SaveFile()
+			}
+		case "ctrl+f":
+			e.OpenSearch(false)
+			return e, nil
+		case "ctrl+h":
+			e.OpenSearch(true)
+			return e, nil
 		}
 	}
 
 	e.ensureCursorValid()
-	e.Viewport.EnsureCursorVisible(e.Cursor, e.Buffer.LineLength(e.Cursor.Line))
-	e.updateHighlighting()
-	return e, nil
+	e.revealCursor()
+	return e, e.scheduleFlush()
+}
+
+// handleAltKey runs the alt chords the editor binds itself and reports
+// whether msg was one; other chords go on to the rest of the handlers.
+func (e *Editor) handleAltKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "alt+l":
+		e.ToggleScrollLock()
+	case "alt+W":
+		e.ToggleWrap()
+	default:
+		return false
+	}
+	return true
 }
 
 func (e *Editor) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Type != tea.MouseMotion {
+		e.dismissHover()
+		e.closeCompletion()
+		e.clearGhost()
+	}
+
 	switch msg.Type {
 	case tea.MouseLeft:
-		line := msg.Y - 1 + e.Viewport.Y
-		col := msg.X - e.lineNumWidth() + e.Viewport.X
+		if w := e.minimapWidth(); w > 0 && msg.X >= e.Width-w {
+			e.minimapClicked(msg.Y)
+			return e, nil
+		}
+		if msg.Y >= e.Viewport.Height && e.showsScrollbar() {
+			e.scrollbarClicked(msg.X)
+			return e, nil
+		}
+		line, col := e.positionAt(msg.X, msg.Y)
+		if area := e.gutterAt(msg.X); area != gutterNone {
+			if line >= 0 && line < e.Buffer.LineCount() {
+				if area == gutterFold {
+					e.ToggleFold(line)
+				} else {
+					e.startLineDrag(line)
+				}
+			}
+			return e, nil
+		}
+		if msg.Alt && msg.Shift || e.BlockSelection {
+			e.startBlock(e.cellAt(msg.X, msg.Y))
+			e.block.dragging = true
+			return e, nil
+		}
+		if msg.Alt {
+			if line >= 0 && line < e.Buffer.LineCount() {
+				e.addCaret(Position{Line: line, Column: max(0, min(col, e.Buffer.LineLength(line)))}, Selection{})
+			}
+			return e, nil
+		}
+		e.carets = nil
+		if msg.Shift && line >= 0 && line < e.Buffer.LineCount() {
+			e.anchorSelection()
+			e.dragTo(Position{Line: line, Column: max(0, min(col, e.Buffer.LineLength(line)))})
+			e.dragging = true
+			return e, nil
+		}
 		if line >= 0 && line < e.Buffer.LineCount() {
 			e.Cursor.Line = line
 			e.Cursor.Column = max(0, min(col, e.Buffer.LineLength(line)))
+			switch e.countClick(msg.X, msg.Y) {
+			case 2:
+				e.startWordDrag(e.Cursor)
+				return e, nil
+			case 3:
+				e.startLineDrag(line)
+				return e, nil
+			}
 		}
 		e.clearSelection()
-		e.selectionActive = true
 		e.anchor = e.Cursor
+		e.dragging = true
 	case tea.MouseRelease:
-		e.selectionActive = false
+		e.dragging = false
+		e.block.dragging = false
 	case tea.MouseMotion:
-		if e.selectionActive {
-			line := msg.Y - 1 + e.Viewport.Y
-			col := msg.X - e.lineNumWidth() + e.Viewport.X
-			if line >= 0 && line < e.Buffer.LineCount() {
-				e.Cursor.Line = line
-				e.Cursor.Column = max(0, min(col, e.Buffer.LineLength(line)))
-				e.Selection.Start = e.anchor
-				e.Selection.End = e.Cursor
-			}
+		if e.block.dragging {
+			e.extendBlock(e.cellAt(msg.X, msg.Y))
+			return e, nil
 		}
+		if !e.dragging {
+			return e, e.trackHover(msg)
+		}
+		line, col := e.positionAt(msg.X, msg.Y)
+		if line >= 0 && line < e.Buffer.LineCount() {
+			e.dragTo(Position{Line: line, Column: max(0, min(col, e.Buffer.LineLength(line)))})
+		}
+	case tea.MouseWheelLeft:
+		e.scrollColumns(-hscrollStep)
+	case tea.MouseWheelRight:
+		e.scrollColumns(hscrollStep)
 	case tea.MouseWheelUp:
-		e.Viewport.ScrollUp()
+		if msg.Shift {
+			e.scrollColumns(-hscrollStep)
+			return e, nil
+		}
+		if prev := e.prevVisibleLine(e.Viewport.Y); prev >= 0 {
+			e.Viewport.Y = prev
+		}
 	case tea.MouseWheelDown:
-		e.Viewport.ScrollDown(e.Buffer.LineCount())
+		if msg.Shift {
+			e.scrollColumns(hscrollStep)
+			return e, nil
+		}
+		if next := e.nextVisibleLine(e.Viewport.Y); next < e.Buffer.LineCount() && e.Viewport.Y < e.Viewport.MaxY(e.Buffer.LineCount()) {
+			e.Viewport.Y = next
+		}
 	}
 	return e, nil
 }
 
 func (e *Editor) moveCursor(dx, dy int, shift bool) {
 	if shift {
-		if !e.hasSelection() {
-			e.Selection.Start = e.Cursor
-		}
+		e.anchorSelection()
 	}
 
 	if dx != 0 {
 		if dx < 0 {
 			if e.Cursor.Column > 0 {
-				e.Cursor.Column--
+				e.Cursor.Column = prevBoundary(e.Buffer.Line(e.Cursor.Line), e.Cursor.Column)
 			} else if e.Cursor.Line > 0 {
-				e.Cursor.Line--
+				e.Cursor.Line = e.stepVisible(e.Cursor.Line, -1)
 				e.Cursor.Column = e.Buffer.LineLength(e.Cursor.Line)
 			}
 		} else {
 			if e.Cursor.Column < e.Buffer.LineLength(e.Cursor.Line) {
-				e.Cursor.Column++
-			} else if e.Cursor.Line < e.Buffer.LineCount()-1 {
-				e.Cursor.Line++
+				e.Cursor.Column = nextBoundary(e.Buffer.Line(e.Cursor.Line), e.Cursor.Column)
+			} else if next := e.stepVisible(e.Cursor.Line, 1); next != e.Cursor.Line {
+				e.Cursor.Line = next
 				e.Cursor.Column = 0
 			}
 		}
 	}
 
-	if dy != 0 {
-		e.Cursor.Line += dy
-		if e.Cursor.Line < 0 {
-			e.Cursor.Line = 0
-		} else if e.Cursor.Line >= e.Buffer.LineCount() {
-			e.Cursor.Line = e.Buffer.LineCount() - 1
-		}
-		maxCol := e.Buffer.LineLength(e.Cursor.Line)
-		if e.Cursor.Column > maxCol {
-			e.Cursor.Column = maxCol
-		}
+	if dy != 0 && e.WrapLines {
+		e.moveVisualRow(dy)
+	} else if dy != 0 {
+		cell := e.cell(e.Cursor.Line, e.Cursor.Column)
+		e.Cursor.Line = e.stepVisible(max(0, min(e.Cursor.Line, e.Buffer.LineCount()-1)), dy)
+		e.Cursor.Column = e.byteColumn(e.Buffer.Line(e.Cursor.Line), cell)
 	}
 
 	if shift {
-		e.Selection.End = e.Cursor
+		e.selectTo(e.Cursor)
 	} else {
 		e.clearSelection()
 	}
@@ -365,14 +637,8 @@ func (e *Editor) hasSelection() bool {
 
 func (e *Editor) clearSelection() {
 	e.Selection = Selection{}
-	e.selectionActive = false
-}
-
-func (e *Editor) extendSelection() {
-	if !e.hasSelection() {
-		e.Selection.Start = e.anchor
-	}
-	e.Selection.End = e.Cursor
+	e.selectMode = charSelection
+	e.dragging = false
 }
 
 func (e *Editor) selectAll() {
@@ -406,6 +672,14 @@ func (e *Editor) paste() {
 	if err != nil {
 		return
 	}
+	text = normalizeLineEndings(text)
+	if e.pasteBlock(text) {
+		return
+	}
+	e.insertText(text)
+}
+
+func (e *Editor) insertText(text string) {
 	if e.hasSelection() {
 		e.deleteSelection()
 	}
@@ -414,9 +688,14 @@ func (e *Editor) paste() {
 	e.clearSelection()
 }
 
+func normalizeLineEndings(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n")
+}
+
 func (e *Editor) cutSelection() {
 	if !e.hasSelection() {
-		line := e.Buffer.Lines()[e.Cursor.Line]
+		line := e.Buffer.Line(e.Cursor.Line)
 		e.Selection.Start = Position{Line: e.Cursor.Line, Column: 0}
 		e.Selection.End = Position{Line: e.Cursor.Line, Column: len(line)}
 	}
@@ -425,41 +704,76 @@ func (e *Editor) cutSelection() {
 }
 
 func (e *Editor) LoadFile(path string) error {
+	path = pathutil.Canonical(path)
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
 	e.FilePath = path
-	e.SetContent(string(content))
-	e.originalContent = string(content)
+	switch e.Buffer.(type) {
+	case *SimpleBuffer, *PieceBuffer:
+		e.SetBuffer(newBufferForSize(len(content)))
+	}
+	e.LineEnding = DetectLineEnding(string(content))
+	e.savedEnding = e.LineEnding
+	e.SetContent(e.LineEnding.read(string(content)))
+	e.Indent = DetectIndent(path, string(content))
+	e.savedHash = e.Buffer.Hash()
 	e.Dirty = false
-	e.SetFileExtension(path)
+	e.recordDiskState()
+	e.ReadOnly = !isWritable(path)
+	e.SetFilePath(path)
+	if isLogFile(path) {
+		e.GoToLine(e.Buffer.LineCount() - 1)
+	}
 	return nil
 }
 
+// Save writes the buffer to its file, refusing with ErrConflict when the
+// file was changed by someone else since it was loaded.
 func (e *Editor) Save() error {
 	if e.FilePath == "" {
 		return fmt.Errorf("no file path set")
 	}
-	content := e.Buffer.Content()
-	err := os.WriteFile(e.FilePath, []byte(content), 0644)
+	if pathutil.IsVirtual(e.FilePath) {
+		return ErrVirtualDocument
+	}
+	if err := e.checkConflict(); err != nil {
+		return err
+	}
+	e.applySaveTransforms()
+	return e.write()
+}
+
+func (e *Editor) SaveAs(path string) error {
+	e.FilePath = pathutil.Canonical(path)
+	e.applySaveTransforms()
+	return e.write()
+}
+
+func (e *Editor) write() error {
+	content := e.LineEnding.Apply(e.Buffer.Content())
+	err := writeFile(e.FilePath, []byte(content), e.Backup)
 	if err != nil {
 		return err
 	}
-	e.originalContent = content
+	e.savedHash = e.Buffer.Hash()
+	e.savedEnding = e.LineEnding
 	e.Dirty = false
+	e.recordDiskState()
 	return nil
 }
 
-func (e *Editor) SaveAs(path string) error {
-	e.FilePath = path
-	return e.Save()
+// markDirty flags the buffer as edited. Whether it still differs from the
+// saved version is settled by updateDirty once the edit burst is flushed.
+func (e *Editor) markDirty() {
+	e.Dirty = true
+	e.editSeq++
+	e.pendingFlush = true
 }
 
-func (e *Editor) markDirty() {
-	if !e.Dirty {
-		e.Dirty = true
-	}
+func (e *Editor) updateDirty() {
+	e.Dirty = e.Buffer.Hash() != e.savedHash || e.LineEnding != e.savedEnding
 }
 
 func (e *Editor) IsDirty() bool {
@@ -478,140 +792,178 @@ func (e *Editor) moveCursorAfterInsert(text string) {
 
 func (e *Editor) lineNumWidth() int {
 	if !e.ShowLineNumbers {
-		return 0
+		return signColumnWidth
 	}
-	return e.LineNumWidth
+	return e.LineNumWidth + signColumnWidth
+}
+
+func (e *Editor) GoToLine(line int) {
+	e.GoTo(Position{Line: line})
+}
+
+// GoTo moves the cursor to pos, kept within the buffer, and scrolls to it.
+func (e *Editor) GoTo(pos Position) {
+	e.Cursor = pos
+	e.clearSelection()
+	e.ensureCursorValid()
+	e.revealCursor()
 }
 
 func (e *Editor) View() string {
 	var sb strings.Builder
+	e.refreshSearch()
 
-	startLine, endLine := e.Viewport.VisibleLineRange()
-	if endLine > e.Buffer.LineCount() {
-		endLine = e.Buffer.LineCount()
+	e.fitViewHeight()
+	var rows int
+	if e.WrapLines {
+		rows = e.renderWrapped(&sb)
+	} else {
+		e.showFoldHeader()
+		for i := e.Viewport.Y; i < e.Buffer.LineCount() && rows < e.Viewport.Height; i = e.nextVisibleLine(i) {
+			if rows > 0 {
+				sb.WriteString("\n")
+			}
+			e.renderLine(&sb, i, e.Viewport.X)
+			rows++
+		}
 	}
 
-	for i := startLine; i < endLine; i++ {
-		e.renderLine(&sb, i)
-		if i < endLine-1 {
+	for i := rows; i < e.Viewport.Height; i++ {
+		if i > 0 {
 			sb.WriteString("\n")
 		}
-	}
-
-	for i := endLine - startLine; i < e.Height; i++ {
+		sb.WriteString(" ")
 		if e.ShowLineNumbers {
 			sb.WriteString(fmt.Sprintf("%*s  ", e.LineNumWidth-1, "~"))
 		}
+	}
+	if e.showsScrollbar() {
 		sb.WriteString("\n")
+		sb.WriteString(e.renderScrollbar())
+	}
+
+	view := e.withMinimap(sb.String())
+	if e.confirmElevate {
+		return e.withPrompt(view, i18n.T("editor.elevatePrompt", strings.Join(e.ElevateCommand, " ")))
 	}
+	if e.confirmConflict {
+		return e.withPrompt(view, i18n.T("editor.conflictPrompt", filepath.Base(e.FilePath)))
+	}
+	if e.search.active {
+		return e.withSearchBar(view)
+	}
+	return e.renderHover(e.renderCompletion(view))
+}
 
-	return sb.String()
+func (e *Editor) withPrompt(view, prompt string) string {
+	lines := strings.Split(view, "\n")
+	row := min(len(lines), e.Height) - 1
+	if row < 0 {
+		return view
+	}
+	lines[row] = promptStyle.Width(e.Width).MaxHeight(1).Render(prompt)
+	return strings.Join(lines, "\n")
 }
 
-func (e *Editor) renderLine(sb *strings.Builder, lineNum int) {
-	if e.ShowLineNumbers {
-		lineNumStr := fmt.Sprintf("%*d ", e.LineNumWidth-1, lineNum+1)
+// renderLine writes the part of a line starting at startCol, which is past
+// zero only for the continuation rows of a wrapped line.
+func (e *Editor) renderLine(sb *strings.Builder, lineNum, startCol int) {
+	if e.WrapLines && startCol > 0 {
+		sb.WriteString(spaces(e.lineNumWidth()))
+	} else {
+		sb.WriteString(e.renderSign(lineNum))
+	}
+	if e.ShowLineNumbers && (!e.WrapLines || startCol == 0) {
+		lineNumStr := fmt.Sprintf("%*d", e.LineNumWidth-1, lineNum+1) + e.foldMarker(lineNum)
 		if e.Cursor.Line == lineNum && e.focused {
-			sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render(lineNumStr))
+			sb.WriteString(activeLineNumStyle.Render(lineNumStr))
 		} else {
-			sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#555555")).Render(lineNumStr))
+			sb.WriteString(lineNumStyle.Render(lineNumStr))
 		}
 	}
 
-	line := ""
-	if lineNum < e.Buffer.LineCount() {
-		line = e.Buffer.Lines()[lineNum]
+	raw := e.Buffer.Line(lineNum)
+	from := e.byteColumn(raw, startCol)
+	lead := ""
+	if cell := e.displayColumn(raw, from); cell < startCol && from < len(raw) {
+		// A wide character cut by the left edge shows as padding.
+		from = nextBoundary(raw, from)
+		lead = spaces(e.displayColumn(raw, from) - startCol)
 	}
+	to := max(from, e.byteColumn(raw, startCol+e.Viewport.Width))
 
-	startCol, _ := e.Viewport.VisibleColumnRange()
+	line := lead + e.applyHighlighting(raw[from:to], e.lineOffset(lineNum)+from, e.displayColumn(raw, from), e.indentWidth(raw))
 
-	lineStart := e.lineOffset(lineNum)
-	lineEnd := lineStart + len(line)
+	if e.hasSelection() && e.isLineInSelection(lineNum) {
+		line = e.renderLineWithSelectionRaw(line, lineNum, startCol)
+	}
 
-	line = e.applyHighlighting(line, lineStart, lineEnd, startCol)
+	if len(e.carets) > 0 {
+		line = e.renderCarets(line, lineNum, startCol)
+	}
 
-	if len(line) > e.Viewport.Width {
-		line = line[:e.Viewport.Width]
+	if len(e.remoteCursors) > 0 {
+		line = e.renderRemoteCursors(line, lineNum, startCol)
 	}
 
-	if e.hasSelection() && e.isLineInSelection(lineNum) {
-		line = e.renderLineWithSelectionRaw(line, lineNum, startCol)
+	if e.search.active {
+		line = e.renderSearchMatches(line, lineNum, startCol)
 	}
 
-	if e.Cursor.Line == lineNum && e.ShowCursor && e.focused {
-		line = e.renderLineWithCursor(line, lineNum, startCol)
+	if e.Cursor.Line == lineNum && e.ShowCursor && e.focused && e.cursorInRow(startCol) {
+		line = e.renderLineWithCursor(line, lineNum, startCol) + e.renderGhost(lineNum)
 	}
 
-	sb.WriteString(line)
+	sb.WriteString(line + e.foldedSuffix(lineNum, startCol))
 }
 
 func (e *Editor) lineOffset(lineNum int) int {
-	offset := 0
-	lines := e.Buffer.Lines()
-	for i := 0; i < lineNum && i < len(lines); i++ {
-		offset += len(lines[i]) + 1
-	}
-	return offset
+	return e.Buffer.LineOffset(lineNum)
 }
 
-func (e *Editor) applyHighlighting(line string, lineStart, lineEnd, startCol int) string {
+// applyHighlighting styles text, which starts at offset start of the
+// content the highlight spans were computed for and is drawn from cell of
+// a line indented by indent cells. Tabs are expanded as it goes, since
+// where they stop depends on the cell.
+func (e *Editor) applyHighlighting(text string, start, cell, indent int) string {
+	draw := func(s string, style *lipgloss.Style) string {
+		s, cell = e.drawText(s, cell, indent, style)
+		return s
+	}
 	if len(e.highlightSpans) == 0 {
-		if startCol > 0 && startCol < len(line) {
-			return line[startCol:]
-		} else if startCol >= len(line) {
-			return ""
-		}
-		return line
+		return draw(text, nil)
 	}
 
 	var result strings.Builder
-	linePos := 0
+	end := start + len(text)
+	pos := 0
 
 	for _, span := range e.highlightSpans {
-		if span.End <= lineStart {
+		if span.End <= start {
 			continue
 		}
-		if span.Start >= lineEnd {
+		if span.Start >= end {
 			break
 		}
 
-		spanStartInLine := span.Start - lineStart
-		spanEndInLine := span.End - lineStart
-
-		if spanStartInLine < 0 {
-			spanStartInLine = 0
-		}
-		if spanEndInLine > len(line) {
-			spanEndInLine = len(line)
-		}
-
-		if spanStartInLine >= len(line) {
+		spanStart := max(span.Start-start, pos)
+		spanEnd := min(span.End-start, len(text))
+		if spanStart >= spanEnd {
 			continue
 		}
 
-		if spanStartInLine > linePos {
-			result.WriteString(line[linePos:spanStartInLine])
+		if spanStart > pos {
+			result.WriteString(draw(text[pos:spanStart], nil))
 		}
-
-		text := line[spanStartInLine:spanEndInLine]
 		style := e.theme.StyleForToken(span.TokenType)
-		result.WriteString(style.Render(text))
-
-		linePos = spanEndInLine
+		result.WriteString(draw(text[spanStart:spanEnd], &style))
+		pos = spanEnd
 	}
 
-	if linePos < len(line) {
-		result.WriteString(line[linePos:])
+	if pos < len(text) {
+		result.WriteString(draw(text[pos:], nil))
 	}
-
-	highlighted := result.String()
-
-	if startCol > 0 && startCol < len(highlighted) {
-		return highlighted[startCol:]
-	} else if startCol >= len(highlighted) {
-		return ""
-	}
-	return highlighted
+	return result.String()
 }
 
 func (e *Editor) isLineInSelection(lineNum int) bool {
@@ -623,44 +975,52 @@ func (e *Editor) renderLineWithSelectionRaw(line string, lineNum, startCol int)
 	norm := e.Selection.Normalized()
 
 	start := 0
-	end := len(line)
+	end := e.Buffer.LineLength(lineNum)
 
 	if lineNum == norm.Start.Line {
-		start = max(0, norm.Start.Column-startCol)
+		start = norm.Start.Column
 	}
 	if lineNum == norm.End.Line {
-		end = min(len(line), norm.End.Column-startCol)
+		end = norm.End.Column
 	}
 
-	if start >= end {
+	from, to := e.visibleCells(lineNum, start, end, startCol)
+	to = min(to, ansi.StringWidth(line))
+	if from >= to {
 		return line
 	}
 
-	highlightStyle := lipgloss.NewStyle().Background(lipgloss.Color(e.SelectionColor))
-	return line[:start] + highlightStyle.Render(line[start:end]) + line[end:]
+	style := backgroundStyle(e.SelectionColor)
+	if e.Accessible {
+		style = accessibleSelectionStyle
+	}
+	return styleColumns(line, from, to, style)
 }
 
 func (e *Editor) renderLineWithCursor(line string, lineNum, startCol int) string {
-	cursorCol := e.Cursor.Column - startCol
-	if cursorCol < 0 || cursorCol > len(line) {
+	cell, width := e.clusterCells(lineNum, e.Cursor.Column)
+	cell -= startCol
+	lineWidth := ansi.StringWidth(line)
+	if cell < 0 || cell > lineWidth {
 		return line
 	}
 
-	if cursorCol == len(line) {
-		return line + e.renderCursor(" ")
+	if cell == lineWidth {
+		return line + e.renderPreedit() + e.renderCursor(" ")
 	}
 
-	return line[:cursorCol] + e.renderCursor(string(line[cursorCol])) + line[cursorCol+1:]
+	char := ansi.Strip(ansi.Cut(line, cell, cell+width))
+	return ansi.Truncate(line, cell, "") + e.renderPreedit() + e.renderCursor(char) + ansi.TruncateLeft(line, cell+width, "")
 }
 
 func (e *Editor) renderCursor(char string) string {
 	switch e.CursorStyle {
 	case CursorBlock:
-		return lipgloss.NewStyle().Background(lipgloss.Color("#ffffff")).Foreground(lipgloss.Color("#000000")).Render(char)
+		return blockCursorStyle.Render(char)
 	case CursorLine:
-		return lipgloss.NewStyle().Background(lipgloss.Color("#ffffff")).Render(" ") + char
+		return lineCursorStyle.Render(" ") + char
 	case CursorUnderline:
-		return lipgloss.NewStyle().Underline(true).Render(char)
+		return underlineStyle.Render(char)
 	}
 	return char
 }
@@ -0,0 +1,127 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrOverlappingEdits is returned by ApplyEdits when two ranges overlap.
+var ErrOverlappingEdits = errors.New("edits overlap")
+
+// TextEdit replaces the text in Range with NewText; an empty range inserts.
+type TextEdit struct {
+	Range   Selection
+	NewText string
+}
+
+// ApplyEdits makes edits to b as one change. Every range refers to the
+// buffer as it was before any edit, as in LSP workspace edits. They are all
+// checked first, so either each edit is made or none is. Inserts at the
+// same position keep their order.
+func ApplyEdits(b Buffer, edits []TextEdit) error {
+	sorted, err := sortEdits(b, edits)
+	if err != nil {
+		return err
+	}
+	// Back to front, so earlier ranges are still where they were.
+	for i := len(sorted) - 1; i >= 0; i-- {
+		r := sorted[i].Range
+		if r.Start != r.End {
+			b.Delete(r.Start, r.End)
+		}
+		if sorted[i].NewText != "" {
+			b.Insert(r.Start, sorted[i].NewText)
+		}
+	}
+	return nil
+}
+
+func sortEdits(b Buffer, edits []TextEdit) ([]TextEdit, error) {
+	sorted := make([]TextEdit, len(edits))
+	for i, ed := range edits {
+		ed.Range = ed.Range.Normalized()
+		for _, p := range []Position{ed.Range.Start, ed.Range.End} {
+			if p.Line < 0 || p.Line >= b.LineCount() || p.Column < 0 || p.Column > b.LineLength(p.Line) {
+				return nil, fmt.Errorf("edit %d: position %d:%d is outside the buffer", i, p.Line+1, p.Column+1)
+			}
+		}
+		sorted[i] = ed
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return positionBefore(sorted[i].Range.Start, sorted[j].Range.Start)
+	})
+	for i := 1; i < len(sorted); i++ {
+		if positionBefore(sorted[i].Range.Start, sorted[i-1].Range.End) {
+			return nil, ErrOverlappingEdits
+		}
+	}
+	return sorted, nil
+}
+
+// ReplaceRange replaces the text from start to end with text.
+func (e *Editor) ReplaceRange(start, end Position, text string) (tea.Cmd, error) {
+	return e.ApplyEdits([]TextEdit{{Range: Selection{Start: start, End: end}, NewText: text}})
+}
+
+// ApplyEdits makes edits as a single change, for formatters, refactorings
+// and replace-all, which one Undo takes back. The cursor, selection and
+// carets stay on the text they were on; one inside a replaced range moves
+// to its start.
+func (e *Editor) ApplyEdits(edits []TextEdit) (tea.Cmd, error) {
+	if e.ReadOnly || len(edits) == 0 {
+		return nil, nil
+	}
+	normalized := make([]TextEdit, len(edits))
+	for i, ed := range edits {
+		normalized[i] = TextEdit{Range: ed.Range, NewText: normalizeLineEndings(ed.NewText)}
+	}
+	sorted, err := sortEdits(e.Buffer, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	type span struct {
+		start, end int
+		text       string
+	}
+	spans := make([]span, len(sorted))
+	for i, ed := range sorted {
+		spans[i] = span{e.positionOffset(ed.Range.Start), e.positionOffset(ed.Range.End), ed.NewText}
+	}
+	// Positions are carried across as offsets, worked out before the
+	// buffer changes and turned back into positions after.
+	var marks []*Position
+	marks = append(marks, &e.Cursor, &e.Selection.Start, &e.Selection.End, &e.anchor)
+	for i := range e.carets {
+		c := &e.carets[i]
+		marks = append(marks, &c.pos, &c.sel.Start, &c.sel.End)
+	}
+	offsets := make([]int, len(marks))
+	for i, p := range marks {
+		off := e.positionOffset(*p)
+		delta := 0
+		for _, s := range spans {
+			if off < s.end {
+				off = min(off, s.start)
+				break
+			}
+			delta += len(s.text) - (s.end - s.start)
+		}
+		offsets[i] = off + delta
+	}
+
+	e.commitUndo()
+	if err := ApplyEdits(e.Buffer, sorted); err != nil {
+		return nil, err
+	}
+	for i, p := range marks {
+		*p = e.offsetPosition(offsets[i])
+	}
+	e.commitUndo()
+	e.block.active = false
+	e.markDirty()
+	return e.afterCommand(), nil
+}
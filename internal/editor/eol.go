@@ -0,0 +1,66 @@
+package editor
+
+import "strings"
+
+// LineEnding is how a file ends its lines. Buffers always hold "\n"; the
+// file's own style is put back when it is written.
+type LineEnding int
+
+const (
+	LF LineEnding = iota
+	CRLF
+)
+
+func (l LineEnding) String() string {
+	if l == CRLF {
+		return "CRLF"
+	}
+	return "LF"
+}
+
+// DetectLineEnding returns the line ending most of content's lines use.
+func DetectLineEnding(content string) LineEnding {
+	crlf := strings.Count(content, "\r\n")
+	if crlf > 0 && crlf >= strings.Count(content, "\n")-crlf {
+		return CRLF
+	}
+	return LF
+}
+
+// Apply converts content, as a buffer holds it, to the line ending.
+func (l LineEnding) Apply(content string) string {
+	if l == CRLF {
+		return strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+	}
+	return content
+}
+
+// read converts content read from a file with the line ending to what a
+// buffer holds. Stray CRs in a file of LF lines are left alone, so saving
+// does not change lines that were not edited.
+func (l LineEnding) read(content string) string {
+	if l == CRLF {
+		return strings.ReplaceAll(content, "\r\n", "\n")
+	}
+	return content
+}
+
+// hash is the hash of a buffer holding content read from a file with the
+// line ending.
+func (l LineEnding) hash(content string) uint64 {
+	return NewSimpleBufferWithContent(l.read(content)).Hash()
+}
+
+// ToggleLineEnding converts the file between LF and CRLF. The change
+// counts as an unsaved edit until the file is written.
+func (e *Editor) ToggleLineEnding() {
+	if e.ReadOnly {
+		return
+	}
+	if e.LineEnding == CRLF {
+		e.LineEnding = LF
+	} else {
+		e.LineEnding = CRLF
+	}
+	e.updateDirty()
+}
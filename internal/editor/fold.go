@@ -0,0 +1,319 @@
+package editor
+
+import (
+	"sort"
+	"strings"
+
+	"tron/internal/termcaps"
+)
+
+const (
+	foldOpenMarker   = "▾"
+	foldClosedMarker = "▸"
+)
+
+// fold hides the lines after start up to and including end. The start line
+// stays visible as the fold's header.
+type fold struct {
+	start int
+	end   int
+}
+
+func (e *Editor) indentWidth(text string) int {
+	width := 0
+	for _, c := range text {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width = e.tabStop(width)
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// foldRange returns the last line of the block headed by line: up to the
+// line before the matching bracket when line ends with one, otherwise the
+// lines indented deeper than it.
+func (e *Editor) foldRange(line int) (int, bool) {
+	text := strings.TrimRight(e.Buffer.Line(line), " \t")
+	if text == "" {
+		return 0, false
+	}
+	if open := text[len(text)-1]; strings.IndexByte("{([", open) >= 0 {
+		if end, ok := e.matchingLine(line, open); ok {
+			return end - 1, end-1 > line
+		}
+	}
+	indent, end := e.indentWidth(text), line
+	for l := line + 1; l < e.Buffer.LineCount(); l++ {
+		next := e.Buffer.Line(l)
+		if strings.TrimSpace(next) == "" {
+			continue
+		}
+		if e.indentWidth(next) <= indent {
+			break
+		}
+		end = l
+	}
+	return end, end > line
+}
+
+// matchingLine finds the line holding the bracket that closes the one
+// ending line.
+func (e *Editor) matchingLine(line int, open byte) (int, bool) {
+	closer := map[byte]byte{'{': '}', '(': ')', '[': ']'}[open]
+	depth := 1
+	for l := line + 1; l < e.Buffer.LineCount(); l++ {
+		text := e.Buffer.Line(l)
+		for i := 0; i < len(text); i++ {
+			switch text[i] {
+			case open:
+				depth++
+			case closer:
+				if depth--; depth == 0 {
+					return l, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// foldable is a cheap guess at whether line heads a block, used for the
+// gutter indicator.
+func (e *Editor) foldable(line int) bool {
+	if line+1 >= e.Buffer.LineCount() {
+		return false
+	}
+	text := strings.TrimRight(e.Buffer.Line(line), " \t")
+	if text == "" {
+		return false
+	}
+	next := e.Buffer.Line(line + 1)
+	if strings.IndexByte("{([", text[len(text)-1]) >= 0 {
+		trimmed := strings.TrimSpace(next)
+		return trimmed == "" || strings.IndexByte("})]", trimmed[0]) < 0
+	}
+	return strings.TrimSpace(next) != "" && e.indentWidth(next) > e.indentWidth(text)
+}
+
+func (e *Editor) foldAt(start int) int {
+	for i, f := range e.folds {
+		if f.start == start {
+			return i
+		}
+	}
+	return -1
+}
+
+// hiddenBy returns a fold hiding line.
+func (e *Editor) hiddenBy(line int) (fold, bool) {
+	for _, f := range e.folds {
+		if line > f.start && line <= f.end {
+			return f, true
+		}
+	}
+	return fold{}, false
+}
+
+func (e *Editor) lineHidden(line int) bool {
+	_, hidden := e.hiddenBy(line)
+	return hidden
+}
+
+// nextVisibleLine returns the first line after line that is not folded
+// away, which may be past the end of the buffer.
+func (e *Editor) nextVisibleLine(line int) int {
+	line++
+	for {
+		f, hidden := e.hiddenBy(line)
+		if !hidden {
+			return line
+		}
+		line = f.end + 1
+	}
+}
+
+// prevVisibleLine returns the last line before line that is not folded
+// away, or -1.
+func (e *Editor) prevVisibleLine(line int) int {
+	line--
+	for line >= 0 {
+		f, hidden := e.hiddenBy(line)
+		if !hidden {
+			return line
+		}
+		line = f.start
+	}
+	return line
+}
+
+// stepVisible moves n visible lines from line, stopping at either end of
+// the buffer.
+func (e *Editor) stepVisible(line, n int) int {
+	for ; n > 0; n-- {
+		next := e.nextVisibleLine(line)
+		if next >= e.Buffer.LineCount() {
+			break
+		}
+		line = next
+	}
+	for ; n < 0; n++ {
+		prev := e.prevVisibleLine(line)
+		if prev < 0 {
+			break
+		}
+		line = prev
+	}
+	return line
+}
+
+func (e *Editor) addFold(start, end int) {
+	if i := e.foldAt(start); i >= 0 {
+		e.folds[i].end = end
+		return
+	}
+	e.folds = append(e.folds, fold{start: start, end: end})
+	sort.Slice(e.folds, func(i, j int) bool { return e.folds[i].start < e.folds[j].start })
+}
+
+// Fold collapses the block headed by the cursor line or, failing that, the
+// innermost block around it.
+func (e *Editor) Fold() {
+	for line := e.Cursor.Line; line >= 0; line-- {
+		if end, ok := e.foldRange(line); ok && end >= e.Cursor.Line {
+			e.addFold(line, end)
+			e.hideCursorFromFolds()
+			return
+		}
+	}
+}
+
+// Unfold expands the fold headed by the cursor line.
+func (e *Editor) Unfold() {
+	if i := e.foldAt(e.Cursor.Line); i >= 0 {
+		e.folds = append(e.folds[:i], e.folds[i+1:]...)
+	}
+}
+
+// ToggleFold folds or unfolds the block headed by line.
+func (e *Editor) ToggleFold(line int) {
+	if i := e.foldAt(line); i >= 0 {
+		e.folds = append(e.folds[:i], e.folds[i+1:]...)
+		return
+	}
+	if end, ok := e.foldRange(line); ok {
+		e.addFold(line, end)
+		e.hideCursorFromFolds()
+	}
+}
+
+func (e *Editor) FoldAll() {
+	for line := 0; line < e.Buffer.LineCount(); line++ {
+		if end, ok := e.foldRange(line); ok {
+			e.addFold(line, end)
+		}
+	}
+	e.hideCursorFromFolds()
+}
+
+func (e *Editor) UnfoldAll() {
+	e.folds = nil
+}
+
+// hideCursorFromFolds moves the cursor to the header of a fold that just
+// hid it.
+func (e *Editor) hideCursorFromFolds() {
+	moved := false
+	for {
+		f, hidden := e.hiddenBy(e.Cursor.Line)
+		if !hidden {
+			break
+		}
+		e.Cursor.Line = f.start
+		moved = true
+	}
+	if moved {
+		e.Cursor.Column = min(e.Cursor.Column, e.Buffer.LineLength(e.Cursor.Line))
+		e.clearSelection()
+	}
+	e.revealCursor()
+}
+
+// showFoldHeader scrolls up to the header when the top of the viewport is
+// folded away.
+func (e *Editor) showFoldHeader() {
+	for {
+		f, hidden := e.hiddenBy(e.Viewport.Y)
+		if !hidden {
+			return
+		}
+		e.Viewport.Y = f.start
+	}
+}
+
+// revealFolds opens the folds hiding the cursor.
+func (e *Editor) revealFolds() {
+	kept := e.folds[:0]
+	for _, f := range e.folds {
+		if e.Cursor.Line <= f.start || e.Cursor.Line > f.end {
+			kept = append(kept, f)
+		}
+	}
+	e.folds = kept
+}
+
+// shiftFolds keeps folds on their blocks after an edit of lines lo to hi
+// changed the line count by delta. Folds touching the edit are dropped.
+func (e *Editor) shiftFolds(lo, hi, delta int) {
+	if len(e.folds) == 0 {
+		return
+	}
+	kept := e.folds[:0]
+	for _, f := range e.folds {
+		switch {
+		case delta != 0 && f.start > hi:
+			f.start += delta
+			f.end += delta
+		case delta != 0 && f.end >= lo:
+			continue
+		}
+		if f.end < e.Buffer.LineCount() {
+			kept = append(kept, f)
+		}
+	}
+	e.folds = kept
+}
+
+// foldMarker returns the gutter indicator for line.
+func (e *Editor) foldMarker(line int) string {
+	switch {
+	case e.foldAt(line) >= 0:
+		return termcaps.Symbol(foldClosedMarker, ">")
+	case e.foldable(line):
+		return termcaps.Symbol(foldOpenMarker, "v")
+	}
+	return " "
+}
+
+// foldedSuffix is drawn after the header of a fold.
+func (e *Editor) foldedSuffix(lineNum, startCol int) string {
+	i := e.foldAt(lineNum)
+	if i < 0 {
+		return ""
+	}
+	if e.WrapLines {
+		breaks := e.wrapBreaks(lineNum)
+		if e.cell(lineNum, breaks[len(breaks)-1]) != startCol {
+			return ""
+		}
+	}
+	suffix := termcaps.Symbol(" ⋯ ", " ... ")
+	if e.cell(lineNum, e.Buffer.LineLength(lineNum))-startCol+displayWidth(suffix) > e.Viewport.Width {
+		return ""
+	}
+	return foldedStyle.Render(suffix)
+}
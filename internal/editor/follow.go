@@ -0,0 +1,73 @@
+package editor
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func isLogFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".log")
+}
+
+// Following reports whether the editor tails its log file: the file is
+// unedited and its end is in view. Scrolling up pauses following and
+// scrolling back to the end resumes it.
+func (e *Editor) Following() bool {
+	return isLogFile(e.FilePath) && !e.Dirty && e.Viewport.Y+e.Viewport.Height >= e.Buffer.LineCount()
+}
+
+// FollowFile loads what was appended to an unedited log file since it was
+// read, or all of it again when it shrank, as after rotation. The end
+// stays in view if it was before. It reports whether the buffer changed.
+func (e *Editor) FollowFile() bool {
+	if !isLogFile(e.FilePath) || e.Dirty {
+		return false
+	}
+	info, err := os.Stat(e.FilePath)
+	if err != nil || info.Size() == e.diskSize && info.ModTime().Equal(e.diskModTime) {
+		return false
+	}
+	following := e.Following()
+	if info.Size() < e.diskSize {
+		content, err := os.ReadFile(e.FilePath)
+		if err != nil {
+			return false
+		}
+		e.SetContent(e.LineEnding.read(string(content)))
+		e.diskSize = int64(len(content))
+	} else {
+		text, err := readFrom(e.FilePath, e.diskSize)
+		if err != nil {
+			return false
+		}
+		// A CRLF may be split between writes; leave the CR for next time.
+		text = strings.TrimSuffix(text, "\r")
+		last := e.Buffer.LineCount() - 1
+		e.withoutUndo(func() {
+			e.Buffer.Insert(Position{Line: last, Column: e.Buffer.LineLength(last)}, normalizeLineEndings(text))
+		})
+		e.diskSize += int64(len(text))
+	}
+	e.diskModTime = info.ModTime()
+	e.savedHash = e.Buffer.Hash()
+	e.ensureCursorValid()
+	if following {
+		e.GoToLine(e.Buffer.LineCount() - 1)
+	}
+	return true
+}
+
+func readFrom(path string, offset int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(f)
+	return string(data), err
+}
@@ -0,0 +1,71 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	suggestPrefixBytes = 4000
+	suggestSuffixBytes = 1000
+)
+
+type SuggestionRequestMsg struct {
+	Path   string
+	Prefix string
+	Suffix string
+	Seq    int
+}
+
+type SuggestionResultMsg struct {
+	Seq  int
+	Text string
+}
+
+// requestSuggestion asks for ghost text when the cursor sits at the end of
+// a line with no selection; results for older edits are discarded.
+func (e *Editor) requestSuggestion() tea.Cmd {
+	if !e.Suggestions || e.hasSelection() || e.Cursor.Column != e.Buffer.LineLength(e.Cursor.Line) {
+		return nil
+	}
+	content := e.Buffer.Content()
+	offset := e.lineOffset(e.Cursor.Line) + e.Cursor.Column
+	prefix := content[max(0, offset-suggestPrefixBytes):offset]
+	suffix := content[offset:min(len(content), offset+suggestSuffixBytes)]
+
+	e.ghostPos = e.Cursor
+	msg := SuggestionRequestMsg{Path: e.FilePath, Prefix: prefix, Suffix: suffix, Seq: e.editSeq}
+	return func() tea.Msg { return msg }
+}
+
+func (e *Editor) handleSuggestionResult(msg SuggestionResultMsg) {
+	if msg.Seq != e.editSeq || e.Cursor != e.ghostPos || msg.Text == "" {
+		return
+	}
+	e.ghostText = msg.Text
+}
+
+func (e *Editor) clearGhost() {
+	e.ghostText = ""
+}
+
+func (e *Editor) acceptGhost() {
+	text := e.ghostText
+	e.ghostText = ""
+	e.insertText(text)
+	e.markDirty()
+}
+
+func (e *Editor) renderGhost(lineNum int) string {
+	if e.ghostText == "" || lineNum != e.ghostPos.Line {
+		return ""
+	}
+	lines := strings.Split(e.ghostText, "\n")
+	text := lines[0]
+	if len(lines) > 1 {
+		text += fmt.Sprintf(" …+%d lines", len(lines)-1)
+	}
+	return ghostStyle.Render(text)
+}
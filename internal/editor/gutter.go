@@ -0,0 +1,89 @@
+package editor
+
+const signColumnWidth = 1
+
+type GutterMarker struct {
+	Symbol string
+	Color  string
+}
+
+type ToggleBreakpointMsg struct {
+	Path string
+	Line int
+}
+
+func (e *Editor) SetGutterMarkers(source string, markers map[int]GutterMarker) {
+	if e.gutterMarkers == nil {
+		e.gutterMarkers = make(map[string]map[int]GutterMarker)
+	}
+	if _, ok := e.gutterMarkers[source]; !ok {
+		e.gutterOrder = append(e.gutterOrder, source)
+	}
+	e.gutterMarkers[source] = markers
+}
+
+func (e *Editor) gutterMarker(line int) (GutterMarker, bool) {
+	for _, source := range e.gutterOrder {
+		if m, ok := e.gutterMarkers[source][line]; ok {
+			return m, true
+		}
+	}
+	return GutterMarker{}, false
+}
+
+func (e *Editor) renderSign(line int) string {
+	m, ok := e.gutterMarker(line)
+	if !ok && e.bookmarked(line) {
+		return e.renderBookmark()
+	}
+	if !ok {
+		return e.accessibleSign(line)
+	}
+	return foregroundStyle(m.Color).Render(m.Symbol)
+}
+
+func (e *Editor) accessibleSign(line int) string {
+	if !e.Accessible {
+		return " "
+	}
+	switch {
+	case line == e.Cursor.Line && e.focused:
+		return ">"
+	case e.hasSelection() && e.isLineInSelection(line):
+		return "|"
+	}
+	return " "
+}
+
+// gutterArea is the part of the gutter a mouse column falls in.
+type gutterArea int
+
+const (
+	gutterNone gutterArea = iota
+	gutterSign
+	gutterNumbers
+	gutterFold
+)
+
+// gutterAt hit-tests column x of the editor against the gutter: the sign
+// column, then the line numbers and their fold marker when shown.
+func (e *Editor) gutterAt(x int) gutterArea {
+	switch {
+	case x < 0 || x >= e.lineNumWidth():
+		return gutterNone
+	case x < signColumnWidth:
+		return gutterSign
+	case x == e.lineNumWidth()-1:
+		return gutterFold
+	}
+	return gutterNumbers
+}
+
+// startLineDrag selects line and lets a drag down the gutter grow the
+// selection a line at a time.
+func (e *Editor) startLineDrag(line int) {
+	e.carets = nil
+	e.anchor, e.selectMode = Position{Line: line}, lineSelection
+	e.selectTo(e.anchor)
+	e.dragging = true
+}
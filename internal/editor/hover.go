@@ -0,0 +1,120 @@
+package editor
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/pkg/layout"
+)
+
+const (
+	HoverDelay     = 500 * time.Millisecond
+	hoverMaxWidth  = 60
+	hoverMaxHeight = 10
+)
+
+type HoverRequestMsg struct {
+	Path     string
+	Position Position
+	Seq      int
+}
+
+type HoverResultMsg struct {
+	Seq  int
+	Text string
+}
+
+type hoverTickMsg struct {
+	seq int
+}
+
+func (e *Editor) bufferPosition(x, y int) (Position, bool) {
+	line, col := e.positionAt(x, y)
+	if y < 0 || line >= e.Buffer.LineCount() || col < 0 || col >= e.Buffer.LineLength(line) {
+		return Position{}, false
+	}
+	return Position{Line: line, Column: col}, true
+}
+
+func (e *Editor) trackHover(msg tea.MouseMsg) tea.Cmd {
+	pos, ok := e.bufferPosition(msg.X, msg.Y)
+	if ok && e.hoverValid && pos == e.hoverPos {
+		return nil
+	}
+
+	e.dismissHover()
+	e.hoverValid = ok
+	e.hoverPos = pos
+	e.hoverX = msg.X
+	e.hoverY = msg.Y
+	if !ok || e.FilePath == "" {
+		return nil
+	}
+
+	seq := e.hoverSeq
+	return tea.Tick(HoverDelay, func(time.Time) tea.Msg {
+		return hoverTickMsg{seq: seq}
+	})
+}
+
+func (e *Editor) dismissHover() {
+	e.hoverSeq++
+	e.hoverText = ""
+}
+
+func (e *Editor) handleHoverTick(msg hoverTickMsg) tea.Cmd {
+	if msg.seq != e.hoverSeq || !e.hoverValid {
+		return nil
+	}
+	req := HoverRequestMsg{Path: e.FilePath, Position: e.hoverPos, Seq: e.hoverSeq}
+	return func() tea.Msg {
+		return req
+	}
+}
+
+func (e *Editor) handleHoverResult(msg HoverResultMsg) {
+	if msg.Seq == e.hoverSeq {
+		e.hoverText = strings.TrimSpace(msg.Text)
+	}
+}
+
+func (e *Editor) renderHover(view string) string {
+	if e.hoverText == "" {
+		return view
+	}
+
+	lines := strings.Split(e.hoverText, "\n")
+	if len(lines) > hoverMaxHeight {
+		lines = append(lines[:hoverMaxHeight-1], "…")
+	}
+	for i, line := range lines {
+		if r := []rune(line); len(r) > hoverMaxWidth {
+			lines[i] = string(r[:hoverMaxWidth-1]) + "…"
+		}
+	}
+
+	popup := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#6c7086")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Foreground(lipgloss.Color("#cdd6f4")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+
+	popupHeight := lipgloss.Height(popup)
+	popupWidth := lipgloss.Width(popup)
+
+	y := e.hoverY + 1
+	if y+popupHeight > e.Height && e.hoverY-popupHeight >= 0 {
+		y = e.hoverY - popupHeight
+	}
+	x := e.hoverX
+	if x+popupWidth > e.Width {
+		x = max(0, e.Width-popupWidth)
+	}
+
+	return layout.Overlay(view, popup, x, y)
+}
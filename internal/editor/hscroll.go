@@ -0,0 +1,102 @@
+package editor
+
+import (
+	"strings"
+
+	"tron/internal/termcaps"
+)
+
+const (
+	// hscrollMargin is how close the cursor may come to the left or right
+	// edge before the view scrolls sideways.
+	hscrollMargin = 4
+	// hscrollStep is how many columns a notch of the wheel scrolls.
+	hscrollStep = 6
+)
+
+// revealColumn scrolls sideways to keep cell hscrollMargin columns off the
+// edges. The view jumps by a quarter of its width at a time, so typing at
+// the right edge does not scroll one column per key.
+func (e *Editor) revealColumn(cell int) {
+	v := e.Viewport
+	if e.WrapLines || v.Width <= 0 {
+		return
+	}
+	margin := min(hscrollMargin, (v.Width-1)/2)
+	chunk := max(v.Width/4, 1)
+	switch {
+	case cell < v.X+margin && v.X > 0:
+		v.X = max(0, min(v.X-chunk, cell-margin))
+	case cell >= v.X+v.Width-margin:
+		v.X = max(v.X+chunk, cell-v.Width+margin+1)
+	}
+}
+
+// scrollColumns scrolls the view n columns sideways without moving the
+// cursor, stopping once the widest line's end is in view.
+func (e *Editor) scrollColumns(n int) {
+	if e.WrapLines {
+		return
+	}
+	e.Viewport.X = max(0, min(e.Viewport.X+n, e.widestLine()-e.Viewport.Width+1))
+}
+
+// widestLine returns the width in cells of the buffer's widest line,
+// cached until the next edit. A line is never wider than its length in
+// bytes, so only lines longer than the viewport are measured.
+func (e *Editor) widestLine() int {
+	if e.widest.buffer == e.Buffer && e.widest.version == e.Buffer.Version() {
+		return e.widest.cells
+	}
+	cells := 0
+	for i := 0; i < e.Buffer.LineCount(); i++ {
+		n := e.Buffer.LineLength(i)
+		if n > e.Viewport.Width && n > cells {
+			n = e.cell(i, n)
+		}
+		cells = max(cells, n)
+	}
+	e.widest.buffer, e.widest.version, e.widest.cells = e.Buffer, e.Buffer.Version(), cells
+	return cells
+}
+
+// showsScrollbar reports whether the bottom row holds the horizontal
+// scrollbar: lines are not wrapped and some are wider than the view.
+func (e *Editor) showsScrollbar() bool {
+	return !e.WrapLines && e.Height > 1 && e.widestLine() > e.Viewport.Width
+}
+
+// fitViewHeight leaves the bottom row to the scrollbar while it is shown.
+func (e *Editor) fitViewHeight() {
+	e.Viewport.Height = e.Height
+	if e.showsScrollbar() {
+		e.Viewport.Height--
+	}
+}
+
+// scrollbarThumb returns where the thumb starts in a track of the view's
+// width and how long it is.
+func (e *Editor) scrollbarThumb() (int, int) {
+	track, total := e.Viewport.Width, max(e.widestLine()+1, 1)
+	size := max(track*track/total, 1)
+	start := min(e.Viewport.X*track/total, track-size)
+	return start, size
+}
+
+func (e *Editor) renderScrollbar() string {
+	start, size := e.scrollbarThumb()
+	track := e.Viewport.Width
+	var sb strings.Builder
+	sb.WriteString(spaces(e.lineNumWidth()))
+	sb.WriteString(lineNumStyle.Render(strings.Repeat(termcaps.Symbol("─", "-"), start)))
+	sb.WriteString(activeLineNumStyle.Render(strings.Repeat(termcaps.Symbol("━", "="), size)))
+	sb.WriteString(lineNumStyle.Render(strings.Repeat(termcaps.Symbol("─", "-"), max(track-start-size, 0))))
+	return sb.String()
+}
+
+// scrollbarClicked centers the view on the part of the track clicked.
+func (e *Editor) scrollbarClicked(x int) {
+	track := max(e.Viewport.Width, 1)
+	x = max(0, min(x-e.lineNumWidth(), track-1))
+	e.scrollColumns(x*(e.widestLine()+1)/track - e.Viewport.Width/2 - e.Viewport.X)
+}
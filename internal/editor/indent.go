@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// IndentSettings describes how a buffer is indented. They travel with the
+// document, so each tab keeps its own.
+type IndentSettings struct {
+	UseTabs bool
+	Size    int
+	// Openers are the characters after which Enter indents one level more.
+	Openers string
+}
+
+// Unit returns the text of one indent level.
+func (s IndentSettings) Unit() string {
+	if s.UseTabs || s.Size <= 0 {
+		return "\t"
+	}
+	return strings.Repeat(" ", s.Size)
+}
+
+// languageIndents are the defaults for files whose content does not settle
+// the style.
+var languageIndents = map[string]IndentSettings{
+	".go":   {UseTabs: true, Size: 4, Openers: "{(["},
+	".py":   {Size: 4, Openers: ":{(["},
+	".pyw":  {Size: 4, Openers: ":{(["},
+	".yaml": {Size: 2, Openers: ":"},
+	".yml":  {Size: 2, Openers: ":"},
+	".js":   {Size: 2, Openers: "{(["},
+	".ts":   {Size: 2, Openers: "{(["},
+	".json": {Size: 2, Openers: "{["},
+	".rs":   {Size: 4, Openers: "{(["},
+}
+
+var defaultIndent = IndentSettings{Size: 4, Openers: "{(["}
+
+// DetectIndent picks indent settings for path, preferring what its content
+// already uses over the language default.
+func DetectIndent(path, content string) IndentSettings {
+	s, ok := languageIndents[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		s = defaultIndent
+		if filepath.Base(path) == "Makefile" {
+			s.UseTabs = true
+		}
+	}
+
+	tabs, spaced, smallest := 0, 0, 0
+	for i, line := range strings.SplitN(content, "\n", 1001) {
+		if i == 1000 {
+			break
+		}
+		if strings.HasPrefix(line, "\t") {
+			tabs++
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " "))
+		// Single spaces are usually comment continuations, not indents.
+		if n < 2 || n == len(line) {
+			continue
+		}
+		spaced++
+		if smallest == 0 || n < smallest {
+			smallest = n
+		}
+	}
+	switch {
+	case tabs > spaced:
+		s.UseTabs = true
+	case spaced > tabs:
+		s.UseTabs = false
+		s.Size = min(smallest, 8)
+	}
+	return s
+}
+
+// lineBreak returns the text Enter inserts at pos: a newline followed by
+// the indentation of pos's line, one level deeper after an opener.
+func (e *Editor) lineBreak(pos Position) string {
+	line := e.Buffer.Line(pos.Line)
+	before := line[:min(pos.Column, len(line))]
+	indent := before[:len(before)-len(strings.TrimLeft(before, " \t"))]
+	if trimmed := strings.TrimRight(before, " \t"); trimmed != "" && strings.ContainsRune(e.Indent.Openers, rune(trimmed[len(trimmed)-1])) {
+		indent += e.Indent.Unit()
+	}
+	return "\n" + indent
+}
@@ -0,0 +1,12 @@
+package editor
+
+// BuiltinKeys are the shortcuts the editor handles itself, beyond typing
+// and cursor movement. A keybinding on one of them takes it away from the
+// editor.
+var BuiltinKeys = []string{
+	"ctrl+a", "ctrl+c", "ctrl+v", "ctrl+x", "ctrl+s", "ctrl+f", "ctrl+h", "ctrl+d", "ctrl+l", "f9",
+	"ctrl+u", "ctrl+r",
+	"ctrl+w", "ctrl+left", "ctrl+right", "ctrl+shift+left", "ctrl+shift+right",
+	"alt+left", "alt+right", "alt+shift+left", "alt+shift+right", "alt+backspace", "alt+delete",
+	"alt+l", "alt+W",
+}
@@ -0,0 +1,128 @@
+package editor
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"tron/internal/termcaps"
+)
+
+const (
+	// minimapCells is the width of the minimap. Each cell draws a 2 by 4
+	// braille grid, a dot standing for minimapDotColumns columns of one
+	// line, so the minimap covers the first 80 columns.
+	minimapCells      = 10
+	minimapDotColumns = 4
+	// minimapMinWidth is the narrowest editor that still gets a minimap.
+	minimapMinWidth = 40
+)
+
+var (
+	minimapStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	minimapViewportStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#a6adc8")).Background(lipgloss.Color("#313244"))
+)
+
+// brailleDots maps a dot's column and row in a braille cell to its bit.
+var brailleDots = [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// SetMinimap shows or hides the minimap.
+func (e *Editor) SetMinimap(on bool) {
+	e.Minimap = on
+	e.SetSize(e.Width, e.Height)
+}
+
+func (e *Editor) minimapWidth() int {
+	if !e.Minimap || e.Width < minimapMinWidth {
+		return 0
+	}
+	return minimapCells
+}
+
+// minimapScale returns how many lines each row of dots stands for, so
+// the whole buffer fits the editor's height.
+func (e *Editor) minimapScale() int {
+	rows := max(e.Height*4, 1)
+	return max((e.Buffer.LineCount()+rows-1)/rows, 1)
+}
+
+// withMinimap draws the minimap to the right of the rendered rows.
+func (e *Editor) withMinimap(view string) string {
+	width := e.minimapWidth()
+	if width == 0 {
+		return view
+	}
+	textWidth := e.Width - width
+	rows := strings.Split(view, "\n")
+	if len(rows) > e.Height {
+		rows = rows[:e.Height]
+	}
+	for len(rows) < e.Height {
+		rows = append(rows, "")
+	}
+	scale := e.minimapScale()
+	for i, row := range rows {
+		if w := ansi.StringWidth(row); w > textWidth {
+			row = ansi.Truncate(row, textWidth, "")
+		} else {
+			row += spaces(textWidth - w)
+		}
+		rows[i] = row + e.minimapRow(i, scale)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (e *Editor) minimapRow(row, scale int) string {
+	first := row * 4 * scale
+	style := minimapStyle
+	if first < e.Viewport.Y+e.Viewport.Height && first+4*scale > e.Viewport.Y && first < e.Buffer.LineCount() {
+		style = minimapViewportStyle
+	}
+	var lines [4]string
+	tab := strings.Repeat(" ", e.tabWidth())
+	for dy := range lines {
+		if line := first + dy*scale; line < e.Buffer.LineCount() {
+			lines[dy] = strings.ReplaceAll(e.Buffer.Line(line), "\t", tab)
+		}
+	}
+	unicode := termcaps.Current().Unicode
+	var sb strings.Builder
+	for cell := 0; cell < minimapCells; cell++ {
+		var dots rune
+		for dx := 0; dx < 2; dx++ {
+			from := (cell*2 + dx) * minimapDotColumns
+			for dy, line := range lines {
+				if hasText(line, from, from+minimapDotColumns) {
+					dots |= brailleDots[dx][dy]
+				}
+			}
+		}
+		switch {
+		case unicode:
+			sb.WriteRune(0x2800 + dots)
+		case dots != 0:
+			sb.WriteByte(':')
+		default:
+			sb.WriteByte(' ')
+		}
+	}
+	return style.Render(sb.String())
+}
+
+// hasText reports whether line has anything but spaces between byte
+// columns from and to.
+func hasText(line string, from, to int) bool {
+	if from >= len(line) {
+		return false
+	}
+	return strings.TrimSpace(line[from:min(to, len(line))]) != ""
+}
+
+// minimapClicked scrolls so the lines under row of the minimap are in the
+// middle of the view.
+func (e *Editor) minimapClicked(row int) {
+	scale := e.minimapScale()
+	line := row*4*scale + 2*scale
+	e.Viewport.Y = max(min(line-e.Viewport.Height/2, e.Viewport.MaxY(e.Buffer.LineCount())), 0)
+}
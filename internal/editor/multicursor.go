@@ -0,0 +1,348 @@
+package editor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/text/unicode/norm"
+)
+
+// caret is a secondary cursor. The primary one stays in Editor.Cursor and
+// Editor.Selection; every caret receives the same typing, deletions and
+// pastes.
+type caret struct {
+	pos Position
+	sel Selection
+}
+
+// CapturesKey reports whether the editor handles msg itself even where the
+// app would otherwise bind it, as with esc while searching or while extra
+// carets are placed, or always in vim mode.
+func (e *Editor) CapturesKey(msg tea.KeyMsg) bool {
+	if !e.focused {
+		return false
+	}
+	return e.search.active || (len(e.carets) > 0 || e.Vim || e.completing()) && msg.Type == tea.KeyEsc
+}
+
+// Carets returns the number of cursors, counting the primary one.
+func (e *Editor) Carets() int {
+	return len(e.carets) + 1
+}
+
+// addCaret keeps the current cursor as a secondary caret and moves the
+// primary one to pos.
+func (e *Editor) addCaret(pos Position, sel Selection) {
+	e.carets = append(e.carets, caret{pos: e.Cursor, sel: e.Selection})
+	e.Cursor = pos
+	e.Selection = sel
+	e.dedupeCarets()
+}
+
+// addNextOccurrence selects the word under the cursor, or when something is
+// selected adds a caret selecting its next occurrence.
+func (e *Editor) addNextOccurrence() {
+	if !e.hasSelection() {
+		e.selectWordAtCursor()
+		return
+	}
+	norm := e.Selection.Normalized()
+	if norm.Start.Line != norm.End.Line {
+		return
+	}
+	text := e.Buffer.GetText(norm.Start, norm.End)
+	lines := e.Buffer.Lines()
+	for i := 0; i <= len(lines); i++ {
+		line := (norm.End.Line + i) % len(lines)
+		from := 0
+		if i == 0 {
+			from = norm.End.Column
+		}
+		for from <= len(lines[line]) {
+			idx := strings.Index(lines[line][from:], text)
+			if idx < 0 {
+				break
+			}
+			start := Position{Line: line, Column: from + idx}
+			end := Position{Line: line, Column: start.Column + len(text)}
+			if !e.caretSelects(start) {
+				e.addCaret(end, Selection{Start: start, End: end})
+				return
+			}
+			from = end.Column
+		}
+	}
+}
+
+func (e *Editor) caretSelects(start Position) bool {
+	if e.Selection.Normalized().Start == start {
+		return true
+	}
+	for _, c := range e.carets {
+		if c.sel.Normalized().Start == start {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Editor) selectWordAtCursor() {
+	line := e.Buffer.Line(e.Cursor.Line)
+	start, end := e.Cursor.Column, e.Cursor.Column
+	for start > 0 && e.classOf(line[start-1]) == classWord {
+		start--
+	}
+	for end < len(line) && e.classOf(line[end]) == classWord {
+		end++
+	}
+	if start == end {
+		return
+	}
+	e.Selection = Selection{Start: Position{Line: e.Cursor.Line, Column: start}, End: Position{Line: e.Cursor.Line, Column: end}}
+	e.Cursor = e.Selection.End
+}
+
+// handleCaretKey applies msg at every caret. It reports false for keys
+// that collapse back to the primary cursor.
+func (e *Editor) handleCaretKey(msg tea.KeyMsg) bool {
+	e.clampCarets()
+	switch msg.Type {
+	case tea.KeyEsc:
+		e.carets = nil
+	case tea.KeyRunes, tea.KeySpace:
+		text := string(msg.Runes)
+		if msg.Paste {
+			text = normalizeLineEndings(text)
+		} else {
+			text = norm.NFC.String(text)
+		}
+		e.editAtCarets(func(_ int, c caret) Position { return e.insertAtCaret(c, text) })
+	case tea.KeyEnter:
+		e.editAtCarets(func(_ int, c caret) Position { return e.insertAtCaret(c, e.lineBreak(c.pos)) })
+	case tea.KeyBackspace:
+		e.editAtCarets(func(_ int, c caret) Position { return e.deleteAtCaret(c, false) })
+	case tea.KeyDelete:
+		e.editAtCarets(func(_ int, c caret) Position { return e.deleteAtCaret(c, true) })
+	case tea.KeyLeft:
+		e.moveCarets(-1, 0)
+	case tea.KeyRight:
+		e.moveCarets(1, 0)
+	case tea.KeyUp:
+		e.moveCarets(0, -1)
+	case tea.KeyDown:
+		e.moveCarets(0, 1)
+	default:
+		switch msg.String() {
+		case "ctrl+v":
+			e.pasteAtCarets()
+		case "ctrl+c":
+			e.copyCarets()
+		case "ctrl+x":
+			e.copyCarets()
+			e.editAtCarets(func(_ int, c caret) Position {
+				if c.sel.IsEmpty() {
+					return c.pos
+				}
+				return e.deleteAtCaret(c, false)
+			})
+		default:
+			e.carets = nil
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Editor) insertAtCaret(c caret, text string) Position {
+	pos := c.pos
+	if !c.sel.IsEmpty() {
+		norm := c.sel.Normalized()
+		e.Buffer.Delete(norm.Start, norm.End)
+		pos = norm.Start
+	}
+	e.Buffer.Insert(pos, text)
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		return Position{Line: pos.Line, Column: pos.Column + len(text)}
+	}
+	return Position{Line: pos.Line + len(lines) - 1, Column: len(lines[len(lines)-1])}
+}
+
+func (e *Editor) deleteAtCaret(c caret, forward bool) Position {
+	if !c.sel.IsEmpty() {
+		norm := c.sel.Normalized()
+		e.Buffer.Delete(norm.Start, norm.End)
+		return norm.Start
+	}
+	pos := c.pos
+	if !forward {
+		switch {
+		case pos.Column > 0:
+			pos.Column = prevBoundary(e.Buffer.Line(pos.Line), pos.Column)
+		case pos.Line > 0:
+			pos = Position{Line: pos.Line - 1, Column: e.Buffer.LineLength(pos.Line - 1)}
+		}
+	}
+	e.Buffer.DeleteChar(c.pos, forward)
+	return pos
+}
+
+// editAtCarets runs edit once per caret in document order. Carets are
+// tracked as offsets so each edit sees positions shifted by the ones
+// before it; edit returns where its caret ends up.
+func (e *Editor) editAtCarets(edit func(i int, c caret) Position) {
+	all := append([]caret{{pos: e.Cursor, sel: e.Selection}}, e.carets...)
+	order := make([]int, len(all))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return positionBefore(caretStart(all[order[a]]), caretStart(all[order[b]]))
+	})
+
+	type offsets struct{ pos, start, end int }
+	before := make([]offsets, len(all))
+	for i, c := range all {
+		before[i] = offsets{e.positionOffset(c.pos), e.positionOffset(c.sel.Start), e.positionOffset(c.sel.End)}
+	}
+
+	delta := 0
+	for n, i := range order {
+		c := caret{pos: e.offsetPosition(before[i].pos + delta)}
+		if !all[i].sel.IsEmpty() {
+			c.sel = Selection{Start: e.offsetPosition(before[i].start + delta), End: e.offsetPosition(before[i].end + delta)}
+		}
+		length := e.bufferLength()
+		all[i] = caret{pos: edit(n, c)}
+		delta += e.bufferLength() - length
+	}
+
+	e.Cursor, e.Selection = all[0].pos, Selection{}
+	e.carets = all[1:]
+	e.dedupeCarets()
+	e.markDirty()
+}
+
+func caretStart(c caret) Position {
+	if c.sel.IsEmpty() {
+		return c.pos
+	}
+	return c.sel.Normalized().Start
+}
+
+func (e *Editor) positionOffset(p Position) int {
+	return e.Buffer.LineOffset(p.Line) + p.Column
+}
+
+func (e *Editor) offsetPosition(offset int) Position {
+	line := sort.Search(e.Buffer.LineCount(), func(i int) bool { return e.Buffer.LineOffset(i) > offset }) - 1
+	line = max(line, 0)
+	return Position{Line: line, Column: offset - e.Buffer.LineOffset(line)}
+}
+
+func (e *Editor) bufferLength() int {
+	last := e.Buffer.LineCount() - 1
+	return e.Buffer.LineOffset(last) + e.Buffer.LineLength(last)
+}
+
+func (e *Editor) moveCarets(dx, dy int) {
+	primary := e.Cursor
+	for i := range e.carets {
+		e.Cursor = e.carets[i].pos
+		e.moveCursor(dx, dy, false)
+		e.carets[i] = caret{pos: e.Cursor}
+	}
+	e.Cursor = primary
+	e.moveCursor(dx, dy, false)
+	e.dedupeCarets()
+}
+
+// pasteAtCarets gives each caret one line of the clipboard when their
+// counts match, as after copying from the same carets, and the whole text
+// otherwise.
+func (e *Editor) pasteAtCarets() {
+	text, err := clipboard.ReadAll()
+	if err != nil {
+		return
+	}
+	text = normalizeLineEndings(text)
+	parts := strings.Split(text, "\n")
+	distribute := len(parts) == e.Carets()
+	e.editAtCarets(func(i int, c caret) Position {
+		if distribute {
+			return e.insertAtCaret(c, parts[i])
+		}
+		return e.insertAtCaret(c, text)
+	})
+}
+
+func (e *Editor) copyCarets() {
+	sels := []Selection{e.Selection.Normalized()}
+	for _, c := range e.carets {
+		sels = append(sels, c.sel.Normalized())
+	}
+	sort.Slice(sels, func(a, b int) bool { return positionBefore(sels[a].Start, sels[b].Start) })
+	texts := make([]string, len(sels))
+	for i, sel := range sels {
+		texts[i] = e.Buffer.GetText(sel.Start, sel.End)
+	}
+	e.blockClip = strings.Join(texts, "\n")
+	_ = clipboard.WriteAll(e.blockClip)
+}
+
+// dedupeCarets drops carets that ended up on the primary cursor or on each
+// other.
+func (e *Editor) dedupeCarets() {
+	seen := map[Position]bool{e.Cursor: true}
+	kept := e.carets[:0]
+	for _, c := range e.carets {
+		if !seen[c.pos] {
+			seen[c.pos] = true
+			kept = append(kept, c)
+		}
+	}
+	e.carets = kept
+}
+
+// clampCarets keeps carets inside the buffer after edits made elsewhere.
+func (e *Editor) clampCarets() {
+	clamp := func(p Position) Position {
+		p.Line = max(0, min(p.Line, e.Buffer.LineCount()-1))
+		p.Column = max(0, min(p.Column, e.Buffer.LineLength(p.Line)))
+		return p
+	}
+	for i, c := range e.carets {
+		e.carets[i] = caret{pos: clamp(c.pos), sel: Selection{Start: clamp(c.sel.Start), End: clamp(c.sel.End)}}
+	}
+	e.dedupeCarets()
+}
+
+func (e *Editor) renderCarets(line string, lineNum, startCol int) string {
+	selStyle := backgroundStyle(e.SelectionColor)
+	if e.Accessible {
+		selStyle = accessibleSelectionStyle
+	}
+	for _, c := range e.carets {
+		sel := c.sel.Normalized()
+		if !sel.IsEmpty() && lineNum >= sel.Start.Line && lineNum <= sel.End.Line {
+			from, to := 0, e.Buffer.LineLength(lineNum)
+			if lineNum == sel.Start.Line {
+				from = sel.Start.Column
+			}
+			if lineNum == sel.End.Line {
+				to = sel.End.Column
+			}
+			from, to = e.visibleCells(lineNum, from, to, startCol)
+			line = styleColumns(line, from, to, selStyle)
+		}
+		if c.pos.Line != lineNum || !e.ShowCursor || !e.focused {
+			continue
+		}
+		if cell, width := e.clusterCells(lineNum, c.pos.Column); cell-startCol < e.Viewport.Width {
+			line = styleColumns(line, cell-startCol, cell-startCol+width, blockCursorStyle)
+		}
+	}
+	return line
+}
@@ -0,0 +1,114 @@
+package editor
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var DefaultElevateCommand = []string{"sudo", "tee"}
+
+type EditorSaveFailedMsg struct {
+	Path string
+	Err  error
+}
+
+type elevatedSaveDoneMsg struct {
+	path    string
+	content string
+	err     error
+}
+
+func isWritable(path string) bool {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return !errors.Is(err, fs.ErrPermission)
+	}
+	f.Close()
+	return true
+}
+
+func isEditKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace, tea.KeyEnter, tea.KeyBackspace, tea.KeyDelete, tea.KeyTab:
+		return true
+	}
+	switch msg.String() {
+	case "ctrl+v", "ctrl+x", "ctrl+w":
+		return true
+	}
+	return false
+}
+
+func (e *Editor) saveCmd() tea.Cmd {
+	err := e.Save()
+	// Save-time transforms leave an edit to announce.
+	return tea.Batch(e.scheduleFlush(), e.writeCmd(err))
+}
+
+func (e *Editor) writeCmd(err error) tea.Cmd {
+	if err == nil {
+		path := e.FilePath
+		return func() tea.Msg {
+			return EditorSavedMsg{Path: path}
+		}
+	}
+	if errors.Is(err, fs.ErrPermission) && len(e.ElevateCommand) > 0 {
+		e.confirmElevate = true
+		return nil
+	}
+	if errors.Is(err, ErrConflict) {
+		e.confirmConflict = true
+		return nil
+	}
+	path := e.FilePath
+	return func() tea.Msg {
+		return EditorSaveFailedMsg{Path: path, Err: err}
+	}
+}
+
+func (e *Editor) handleElevateConfirm(msg tea.KeyMsg) tea.Cmd {
+	e.confirmElevate = false
+	switch msg.String() {
+	case "y", "Y":
+		return e.elevatedSave()
+	}
+	return nil
+}
+
+func (e *Editor) elevatedSave() tea.Cmd {
+	e.applySaveTransforms()
+	path := e.FilePath
+	content := e.LineEnding.Apply(e.Buffer.Content())
+
+	args := append(append([]string{}, e.ElevateCommand[1:]...), path)
+	cmd := exec.Command(e.ElevateCommand[0], args...)
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = io.Discard
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return elevatedSaveDoneMsg{path: path, content: content, err: err}
+	})
+}
+
+func (e *Editor) handleElevatedSaveDone(msg elevatedSaveDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		return func() tea.Msg {
+			return EditorSaveFailedMsg{Path: msg.path, Err: msg.err}
+		}
+	}
+	if msg.path == e.FilePath {
+		e.savedHash = e.LineEnding.hash(msg.content)
+		e.savedEnding = e.LineEnding
+		e.updateDirty()
+		e.recordDiskState()
+	}
+	return func() tea.Msg {
+		return EditorSavedMsg{Path: msg.path}
+	}
+}
@@ -0,0 +1,327 @@
+package editor
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// PieceBufferThreshold is the file size from which LoadFile uses a
+// PieceBuffer rather than a SimpleBuffer.
+const PieceBufferThreshold = 1 << 20
+
+type piece struct {
+	added    bool
+	start    int
+	length   int
+	newlines int
+}
+
+// PieceBuffer is a piece table: the loaded text is never copied or
+// modified, inserted text is appended to a second buffer, and the document
+// is a list of pieces of either. Edits touch only the pieces around them,
+// so they stay cheap however large the file is. Content, Lines and Hash
+// are built on demand and cached until the next edit.
+type PieceBuffer struct {
+	changeFeed
+	original string
+	added    []byte
+	// originalNewlines and addedNewlines hold the offsets of every '\n' in
+	// each source, so lines are found without scanning text.
+	originalNewlines []int
+	addedNewlines    []int
+	pieces           []piece
+	length           int
+	newlines         int
+
+	content      string
+	contentValid bool
+	lines        []string
+	hash         uint64
+	hashValid    bool
+}
+
+func NewPieceBuffer() *PieceBuffer {
+	return &PieceBuffer{}
+}
+
+func NewPieceBufferWithContent(content string) *PieceBuffer {
+	b := &PieceBuffer{}
+	b.SetContent(content)
+	return b
+}
+
+// newBufferForSize picks the buffer implementation for a file of size bytes.
+func newBufferForSize(size int) Buffer {
+	if size >= PieceBufferThreshold {
+		return NewPieceBuffer()
+	}
+	return NewSimpleBuffer()
+}
+
+func (b *PieceBuffer) SetContent(content string) {
+	defer b.publish(b.prepare(b, Position{}, Position{Line: b.newlines, Column: b.LineLength(b.newlines)}, content))
+	b.original = content
+	b.added = nil
+	b.originalNewlines = newlineOffsets(content, 0, nil)
+	b.addedNewlines = nil
+	b.pieces = nil
+	if content != "" {
+		b.pieces = []piece{{start: 0, length: len(content), newlines: len(b.originalNewlines)}}
+	}
+	b.length = len(content)
+	b.newlines = len(b.originalNewlines)
+	b.invalidate()
+}
+
+func newlineOffsets(text string, base int, offsets []int) []int {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			offsets = append(offsets, base+i)
+		}
+	}
+	return offsets
+}
+
+func (b *PieceBuffer) invalidate() {
+	b.contentValid = false
+	b.lines = nil
+	b.hashValid = false
+}
+
+func (b *PieceBuffer) text(p piece) string {
+	if p.added {
+		return string(b.added[p.start : p.start+p.length])
+	}
+	return b.original[p.start : p.start+p.length]
+}
+
+func (b *PieceBuffer) sourceNewlines(p piece) []int {
+	if p.added {
+		return b.addedNewlines
+	}
+	return b.originalNewlines
+}
+
+// makePiece builds a piece over [start, start+length) of a source, counting
+// its newlines by binary search.
+func (b *PieceBuffer) makePiece(added bool, start, length int) piece {
+	p := piece{added: added, start: start, length: length}
+	nl := b.sourceNewlines(p)
+	p.newlines = sort.SearchInts(nl, start+length) - sort.SearchInts(nl, start)
+	return p
+}
+
+func (b *PieceBuffer) Content() string {
+	if !b.contentValid {
+		var sb strings.Builder
+		sb.Grow(b.length)
+		for _, p := range b.pieces {
+			sb.WriteString(b.text(p))
+		}
+		b.content = sb.String()
+		b.contentValid = true
+	}
+	return b.content
+}
+
+func (b *PieceBuffer) Lines() []string {
+	if b.lines == nil {
+		b.lines = strings.Split(b.Content(), "\n")
+	}
+	return b.lines
+}
+
+func (b *PieceBuffer) LineCount() int {
+	return b.newlines + 1
+}
+
+func (b *PieceBuffer) LineOffset(line int) int {
+	if line <= 0 {
+		return 0
+	}
+	if line > b.newlines {
+		return b.length + 1
+	}
+	offset, seen := 0, 0
+	for _, p := range b.pieces {
+		if seen+p.newlines >= line {
+			nl := b.sourceNewlines(p)
+			at := nl[sort.SearchInts(nl, p.start)+line-seen-1]
+			return offset + at - p.start + 1
+		}
+		seen += p.newlines
+		offset += p.length
+	}
+	return b.length + 1
+}
+
+func (b *PieceBuffer) LineLength(line int) int {
+	if line < 0 || line > b.newlines {
+		return 0
+	}
+	end := b.length
+	if line < b.newlines {
+		end = b.LineOffset(line+1) - 1
+	}
+	return end - b.LineOffset(line)
+}
+
+func (b *PieceBuffer) Line(line int) string {
+	if line < 0 || line > b.newlines {
+		return ""
+	}
+	start := b.LineOffset(line)
+	return b.slice(start, start+b.LineLength(line))
+}
+
+func (b *PieceBuffer) CharAt(line, col int) rune {
+	if col < 0 || col >= b.LineLength(line) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(b.Line(line)[col:])
+	return r
+}
+
+// slice returns the text between two offsets of the document.
+func (b *PieceBuffer) slice(from, to int) string {
+	if b.contentValid {
+		return b.content[from:to]
+	}
+	var sb strings.Builder
+	offset := 0
+	for _, p := range b.pieces {
+		if offset >= to {
+			break
+		}
+		if end := offset + p.length; end > from {
+			text := b.text(p)
+			sb.WriteString(text[max(from-offset, 0):min(to-offset, p.length)])
+		}
+		offset += p.length
+	}
+	return sb.String()
+}
+
+// clamp moves pos onto the nearest position that exists.
+func (b *PieceBuffer) clamp(pos Position) Position {
+	pos.Line = max(0, min(pos.Line, b.newlines))
+	pos.Column = max(0, min(pos.Column, b.LineLength(pos.Line)))
+	return pos
+}
+
+func (b *PieceBuffer) offset(pos Position) int {
+	pos = b.clamp(pos)
+	return b.LineOffset(pos.Line) + pos.Column
+}
+
+func (b *PieceBuffer) Insert(pos Position, text string) {
+	if text == "\r\n" {
+		text = "\n"
+	}
+	if extra := pos.Line - b.newlines; extra > 0 {
+		text = strings.Repeat("\n", extra) + text
+		pos = Position{Line: b.newlines, Column: b.LineLength(b.newlines)}
+	}
+	pos = b.clamp(pos)
+	defer b.publish(b.prepare(b, pos, pos, text))
+	b.insertAt(b.offset(pos), text)
+}
+
+func (b *PieceBuffer) insertAt(offset int, text string) {
+	if text == "" {
+		return
+	}
+	start := len(b.added)
+	b.added = append(b.added, text...)
+	b.addedNewlines = newlineOffsets(text, start, b.addedNewlines)
+	inserted := b.makePiece(true, start, len(text))
+	b.length += len(text)
+	b.newlines += inserted.newlines
+	b.invalidate()
+
+	at := 0
+	for i, p := range b.pieces {
+		switch {
+		case offset == at+p.length && p.added && p.start+p.length == start:
+			// Typing at the end of the last insertion grows its piece.
+			b.pieces[i] = b.makePiece(true, p.start, p.length+len(text))
+			return
+		case offset == at:
+			b.pieces = append(b.pieces[:i], append([]piece{inserted}, b.pieces[i:]...)...)
+			return
+		case offset < at+p.length:
+			split := offset - at
+			left := b.makePiece(p.added, p.start, split)
+			right := b.makePiece(p.added, p.start+split, p.length-split)
+			b.pieces = append(b.pieces[:i], append([]piece{left, inserted, right}, b.pieces[i+1:]...)...)
+			return
+		}
+		at += p.length
+	}
+	b.pieces = append(b.pieces, inserted)
+}
+
+func (b *PieceBuffer) deleteRange(from, to int) {
+	if from >= to {
+		return
+	}
+	kept := make([]piece, 0, len(b.pieces)+1)
+	at := 0
+	for _, p := range b.pieces {
+		end := at + p.length
+		if end <= from || at >= to {
+			kept = append(kept, p)
+			at = end
+			continue
+		}
+		if at < from {
+			kept = append(kept, b.makePiece(p.added, p.start, from-at))
+		}
+		if end > to {
+			cut := to - at
+			kept = append(kept, b.makePiece(p.added, p.start+cut, p.length-cut))
+		}
+		at = end
+	}
+	b.pieces = kept
+	b.length, b.newlines = 0, 0
+	for _, p := range b.pieces {
+		b.length += p.length
+		b.newlines += p.newlines
+	}
+	b.invalidate()
+}
+
+func (b *PieceBuffer) Delete(start, end Position) {
+	start, end = normalizeRange(start, end)
+	if start.Line != end.Line && (start.Line > b.newlines || end.Line > b.newlines) {
+		return
+	}
+	start, end = b.clamp(start), b.clamp(end)
+	defer b.publish(b.prepare(b, start, end, ""))
+	b.deleteRange(b.offset(start), b.offset(end))
+}
+
+func (b *PieceBuffer) DeleteChar(pos Position, forward bool) {
+	if start, end, ok := charRange(b, pos, forward); ok {
+		b.Delete(start, end)
+	}
+}
+
+func (b *PieceBuffer) GetText(start, end Position) string {
+	start, end = normalizeRange(start, end)
+	return b.slice(b.offset(start), b.offset(end))
+}
+
+func (b *PieceBuffer) Hash() uint64 {
+	if !b.hashValid {
+		var h uint64
+		for _, line := range b.Lines() {
+			h = h*hashMultiplier + hashLine(line)
+		}
+		b.hash = h
+		b.hashValid = true
+	}
+	return b.hash
+}
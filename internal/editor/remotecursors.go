@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type RemoteCursor struct {
+	Name      string
+	Color     string
+	Pos       Position
+	Selection Selection
+}
+
+func (e *Editor) SetRemoteCursors(cursors []RemoteCursor) {
+	e.remoteCursors = cursors
+}
+
+func (e *Editor) renderRemoteCursors(line string, lineNum, startCol int) string {
+	for _, rc := range e.remoteCursors {
+		sel := rc.Selection.Normalized()
+		if !sel.IsEmpty() && lineNum >= sel.Start.Line && lineNum <= sel.End.Line {
+			from, to := 0, e.Buffer.LineLength(lineNum)
+			if lineNum == sel.Start.Line {
+				from = sel.Start.Column
+			}
+			if lineNum == sel.End.Line {
+				to = sel.End.Column
+			}
+			from, to = e.visibleCells(lineNum, from, to, startCol)
+			style := lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color(rc.Color))
+			line = styleColumns(line, from, to, style)
+		}
+		if rc.Pos.Line == lineNum {
+			cell, width := e.clusterCells(lineNum, rc.Pos.Column)
+			style := lipgloss.NewStyle().Background(lipgloss.Color(rc.Color)).Foreground(lipgloss.Color("#1e1e2e"))
+			line = styleColumns(line, cell-startCol, cell-startCol+width, style)
+		}
+	}
+	return line
+}
+
+// styleColumns restyles the visible columns [from, to) of an already styled
+// line, padding with spaces when the range extends past its end.
+func styleColumns(line string, from, to int, style lipgloss.Style) string {
+	from = max(0, from)
+	if to <= from {
+		return line
+	}
+	width := ansi.StringWidth(line)
+	if width < to {
+		line += spaces(to - width)
+	}
+	segment := ansi.Strip(ansi.Cut(line, from, to))
+	return ansi.Truncate(line, from, "") + style.Render(segment) + ansi.TruncateLeft(line, to, "")
+}
+
+func spaces(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = ' '
+	}
+	return string(b)
+}
@@ -0,0 +1,62 @@
+package editor
+
+import "strings"
+
+// applySaveTransforms trims trailing whitespace and ends the buffer with
+// exactly one newline, as the editor's options ask, before an explicit
+// save. The changes are made to the buffer as a single edit, so they show
+// and the written file matches it.
+func (e *Editor) applySaveTransforms() {
+	if edits := e.saveEdits(); len(edits) > 0 {
+		_, _ = e.ApplyEdits(edits)
+	}
+}
+
+func (e *Editor) saveEdits() []TextEdit {
+	if !e.TrimTrailingWhitespace && !e.InsertFinalNewline {
+		return nil
+	}
+	count := e.Buffer.LineCount()
+	blank := func(line string) bool {
+		if e.TrimTrailingWhitespace {
+			return strings.TrimRight(line, " \t") == ""
+		}
+		return line == ""
+	}
+	// Lines after last are blank and dropped when a final newline is
+	// wanted, so they need no trimming.
+	last := count - 1
+	if e.InsertFinalNewline {
+		for last >= 0 && blank(e.Buffer.Line(last)) {
+			last--
+		}
+	}
+
+	var edits []TextEdit
+	if e.TrimTrailingWhitespace {
+		for i := 0; i <= last; i++ {
+			line := e.Buffer.Line(i)
+			if trimmed := strings.TrimRight(line, " \t"); len(trimmed) < len(line) {
+				edits = append(edits, TextEdit{Range: Selection{
+					Start: Position{Line: i, Column: len(trimmed)},
+					End:   Position{Line: i, Column: len(line)},
+				}})
+			}
+		}
+	}
+	if !e.InsertFinalNewline {
+		return edits
+	}
+	switch {
+	case last == count-1 && last >= 0:
+		end := Position{Line: last, Column: e.Buffer.LineLength(last)}
+		edits = append(edits, TextEdit{Range: Selection{Start: end, End: end}, NewText: "\n"})
+	case last < count-2 || e.Buffer.LineLength(count-1) > 0:
+		// Keep one empty line after the last, for the single newline.
+		edits = append(edits, TextEdit{Range: Selection{
+			Start: Position{Line: last + 1, Column: 0},
+			End:   Position{Line: count - 1, Column: e.Buffer.LineLength(count - 1)},
+		}})
+	}
+	return edits
+}
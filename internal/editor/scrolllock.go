@@ -0,0 +1,123 @@
+package editor
+
+type ScrollLink struct {
+	First   *Editor
+	Second  *Editor
+	enabled bool
+	syncing bool
+}
+
+func LinkScroll(first, second *Editor) *ScrollLink {
+	l := &ScrollLink{
+		First:   first,
+		Second:  second,
+		enabled: true,
+	}
+	first.scrollLinks = append(first.scrollLinks, l)
+	second.scrollLinks = append(second.scrollLinks, l)
+	l.sync(first)
+	return l
+}
+
+func (l *ScrollLink) Unlink() {
+	l.First.scrollLinks = removeScrollLink(l.First.scrollLinks, l)
+	l.Second.scrollLinks = removeScrollLink(l.Second.scrollLinks, l)
+	l.enabled = false
+}
+
+func (l *ScrollLink) Toggle() {
+	l.enabled = !l.enabled
+	if l.enabled {
+		l.sync(l.First)
+	}
+}
+
+func (l *ScrollLink) Enabled() bool {
+	return l.enabled
+}
+
+func (l *ScrollLink) sync(source *Editor) {
+	if !l.enabled || l.syncing {
+		return
+	}
+
+	target := l.Second
+	if source == l.Second {
+		target = l.First
+	}
+
+	l.syncing = true
+	defer func() { l.syncing = false }()
+
+	sourceRange := scrollRange(source)
+	targetRange := scrollRange(target)
+	if sourceRange == 0 {
+		target.Viewport.Y = 0
+	} else {
+		target.Viewport.Y = source.Viewport.Y * targetRange / sourceRange
+	}
+	if target.Viewport.Y > targetRange {
+		target.Viewport.Y = targetRange
+	}
+	target.Viewport.X = source.Viewport.X
+}
+
+func scrollRange(e *Editor) int {
+	r := e.Buffer.LineCount() - e.Viewport.Height
+	if r < 0 {
+		return 0
+	}
+	return r
+}
+
+func removeScrollLink(links []*ScrollLink, l *ScrollLink) []*ScrollLink {
+	for i, link := range links {
+		if link == l {
+			return append(links[:i], links[i+1:]...)
+		}
+	}
+	return links
+}
+
+func (e *Editor) ScrollLinks() []*ScrollLink {
+	return e.scrollLinks
+}
+
+// LinkedWith reports whether e's scrolling is linked to other's.
+func (e *Editor) LinkedWith(other *Editor) bool {
+	for _, l := range e.scrollLinks {
+		if l.First == other || l.Second == other {
+			return true
+		}
+	}
+	return false
+}
+
+// ScrollLocked reports whether scrolling e scrolls another editor.
+func (e *Editor) ScrollLocked() bool {
+	for _, l := range e.scrollLinks {
+		if l.enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// UnlinkScroll removes all of e's scroll links.
+func (e *Editor) UnlinkScroll() {
+	for len(e.scrollLinks) > 0 {
+		e.scrollLinks[0].Unlink()
+	}
+}
+
+func (e *Editor) ToggleScrollLock() {
+	for _, l := range e.scrollLinks {
+		l.Toggle()
+	}
+}
+
+func (e *Editor) syncScrollLinks() {
+	for _, l := range e.scrollLinks {
+		l.sync(e)
+	}
+}
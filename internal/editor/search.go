@@ -0,0 +1,353 @@
+package editor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+var (
+	searchMatchStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#45475a"))
+	searchCurrentStyle = lipgloss.NewStyle().Background(lipgloss.Color("#f9e2af")).Foreground(lipgloss.Color("#1e1e2e"))
+	searchBarStyle     = lipgloss.NewStyle().Background(lipgloss.Color("#313244")).Foreground(lipgloss.Color("#cdd6f4"))
+	searchToggleStyle  = lipgloss.NewStyle().Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	searchErrorStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#313244")).Foreground(lipgloss.Color("#f38ba8"))
+)
+
+// searchState is the find and replace bar. While it is open it takes all
+// keys; matches are recomputed whenever the query or the buffer changes,
+// and the view follows the current one as the query is typed.
+type searchState struct {
+	active        bool
+	replacing     bool
+	inReplace     bool
+	query         []rune
+	replacement   []rune
+	caseSensitive bool
+	regex         bool
+	origin        Position
+	// before is the cursor, selection and scroll from before the bar
+	// opened, put back when it is cancelled.
+	before struct {
+		cursor       Position
+		selection    Selection
+		viewX, viewY int
+	}
+
+	re      *regexp.Regexp
+	err     error
+	hash    uint64
+	matches []Selection
+	current int
+}
+
+// OpenSearch shows the find bar, or the find and replace bar, seeded with
+// the selection when it fits on one line.
+func (e *Editor) OpenSearch(replace bool) {
+	s := &e.search
+	s.active = true
+	s.replacing = replace && !e.ReadOnly
+	s.inReplace = false
+	s.origin = e.Cursor
+	s.before.cursor, s.before.selection = e.Cursor, e.Selection
+	s.before.viewX, s.before.viewY = e.Viewport.X, e.Viewport.Y
+	if norm := e.Selection.Normalized(); e.hasSelection() && norm.Start.Line == norm.End.Line {
+		s.query = []rune(e.Buffer.GetText(norm.Start, norm.End))
+		s.origin = norm.Start
+	}
+	e.updateSearch(true)
+}
+
+// closeSearch closes the bar leaving the current match selected.
+func (e *Editor) closeSearch() {
+	s := &e.search
+	s.active = false
+	if s.current < len(s.matches) {
+		match := s.matches[s.current]
+		e.Selection = match
+		e.Cursor = match.End
+	}
+	s.matches = nil
+}
+
+// cancelSearch closes the bar and puts the cursor, selection and scroll
+// back as they were before it opened.
+func (e *Editor) cancelSearch() {
+	s := &e.search
+	s.active = false
+	s.matches = nil
+	e.Cursor, e.Selection = s.before.cursor, s.before.selection
+	e.Viewport.X, e.Viewport.Y = s.before.viewX, s.before.viewY
+	e.ensureCursorValid()
+}
+
+func (e *Editor) handleSearchKey(msg tea.KeyMsg) tea.Cmd {
+	s := &e.search
+	switch msg.String() {
+	case "esc":
+		e.cancelSearch()
+		return nil
+	case "ctrl+f":
+		s.inReplace = false
+		return nil
+	case "ctrl+h":
+		if !e.ReadOnly {
+			s.replacing, s.inReplace = true, true
+		}
+		return nil
+	case "tab", "shift+tab":
+		if s.replacing {
+			s.inReplace = !s.inReplace
+		}
+		return nil
+	case "alt+c":
+		s.caseSensitive = !s.caseSensitive
+		e.updateSearch(true)
+		return nil
+	case "alt+x":
+		s.regex = !s.regex
+		e.updateSearch(true)
+		return nil
+	case "down", "f3":
+		e.stepMatch(1)
+		return nil
+	case "up", "shift+f3":
+		e.stepMatch(-1)
+		return nil
+	case "alt+a":
+		return e.replaceAll()
+	case "enter":
+		if s.inReplace {
+			return e.replaceCurrent()
+		}
+		e.closeSearch()
+		return nil
+	}
+
+	field := &s.query
+	if s.inReplace {
+		field = &s.replacement
+	}
+	switch msg.Type {
+	case tea.KeyBackspace:
+		if len(*field) > 0 {
+			*field = (*field)[:len(*field)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		*field = append(*field, msg.Runes...)
+	default:
+		return nil
+	}
+	if !s.inReplace {
+		e.updateSearch(true)
+	}
+	return nil
+}
+
+// updateSearch recompiles the query and collects every match. With jump
+// the current match becomes the first one at or after where the search
+// started, so matches are followed as the query is typed.
+func (e *Editor) updateSearch(jump bool) {
+	s := &e.search
+	s.re, s.err, s.matches = nil, nil, nil
+	s.hash = e.Buffer.Hash()
+	if len(s.query) == 0 {
+		return
+	}
+	pattern := string(s.query)
+	if !s.regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !s.caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	s.re, s.err = regexp.Compile(pattern)
+	if s.err != nil {
+		return
+	}
+	for i, line := range e.Buffer.Lines() {
+		for _, loc := range s.re.FindAllStringIndex(line, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			s.matches = append(s.matches, Selection{Start: Position{Line: i, Column: loc[0]}, End: Position{Line: i, Column: loc[1]}})
+		}
+	}
+	if !jump {
+		s.current = min(s.current, max(len(s.matches)-1, 0))
+		return
+	}
+	s.current = sort.Search(len(s.matches), func(i int) bool {
+		return !positionBefore(s.matches[i].Start, s.origin)
+	})
+	if s.current == len(s.matches) {
+		s.current = 0
+	}
+	e.revealMatch()
+}
+
+// refreshSearch picks up edits made while the bar is open.
+func (e *Editor) refreshSearch() {
+	if e.search.active && e.search.hash != e.Buffer.Hash() {
+		e.updateSearch(false)
+	}
+}
+
+func (e *Editor) stepMatch(dir int) {
+	s := &e.search
+	if len(s.matches) == 0 {
+		return
+	}
+	s.current = (s.current + dir + len(s.matches)) % len(s.matches)
+	s.origin = s.matches[s.current].Start
+	e.revealMatch()
+}
+
+func (e *Editor) revealMatch() {
+	s := &e.search
+	if s.current >= len(s.matches) {
+		return
+	}
+	e.clearSelection()
+	e.Cursor = s.matches[s.current].Start
+	line := e.Cursor.Line
+	hidden := line < e.Viewport.Y || line >= e.Viewport.Y+e.Viewport.Height-e.searchBarRows()
+	e.revealCursor()
+	// A match scrolled to sits mid-view rather than at an edge, where it
+	// could be under the bar.
+	if hidden && !e.WrapLines {
+		e.Viewport.Y = max(0, min(line-e.Viewport.Height/2, e.Viewport.MaxY(e.Buffer.LineCount())))
+	}
+}
+
+func (e *Editor) searchBarRows() int {
+	if e.search.replacing {
+		return 2
+	}
+	return 1
+}
+
+// replacementFor expands the replacement for match, resolving $1 style
+// group references in regex mode.
+func (e *Editor) replacementFor(match Selection) string {
+	s := &e.search
+	if !s.regex {
+		return string(s.replacement)
+	}
+	line := e.Buffer.Line(match.Start.Line)
+	for _, sub := range s.re.FindAllStringSubmatchIndex(line, -1) {
+		if sub[0] == match.Start.Column {
+			return string(s.re.ExpandString(nil, string(s.replacement), line, sub))
+		}
+	}
+	return string(s.replacement)
+}
+
+func (e *Editor) replaceMatch(match Selection) {
+	text := e.replacementFor(match)
+	e.Buffer.Delete(match.Start, match.End)
+	e.Buffer.Insert(match.Start, text)
+	e.Cursor = Position{Line: match.Start.Line, Column: match.Start.Column + len(text)}
+}
+
+func (e *Editor) replaceCurrent() tea.Cmd {
+	s := &e.search
+	e.refreshSearch()
+	if e.ReadOnly || s.current >= len(s.matches) {
+		return nil
+	}
+	e.replaceMatch(s.matches[s.current])
+	s.origin = e.Cursor
+	e.markDirty()
+	e.updateSearch(true)
+	return e.scheduleFlush()
+}
+
+func (e *Editor) replaceAll() tea.Cmd {
+	s := &e.search
+	e.refreshSearch()
+	if e.ReadOnly || len(s.matches) == 0 {
+		return nil
+	}
+	for i := len(s.matches) - 1; i >= 0; i-- {
+		e.replaceMatch(s.matches[i])
+	}
+	s.origin = e.Cursor
+	e.markDirty()
+	e.updateSearch(true)
+	return e.scheduleFlush()
+}
+
+func positionBefore(a, b Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+}
+
+func (e *Editor) renderSearchMatches(line string, lineNum, startCol int) string {
+	s := &e.search
+	i := sort.Search(len(s.matches), func(i int) bool { return s.matches[i].Start.Line >= lineNum })
+	for ; i < len(s.matches) && s.matches[i].Start.Line == lineNum; i++ {
+		style := searchMatchStyle
+		if i == s.current {
+			style = searchCurrentStyle
+		}
+		match := s.matches[i]
+		from, to := e.visibleCells(lineNum, match.Start.Column, match.End.Column, startCol)
+		line = styleColumns(line, from, to, style)
+	}
+	return line
+}
+
+// withSearchBar draws the find row, and the replace row under it, over the
+// bottom of the view.
+func (e *Editor) withSearchBar(view string) string {
+	s := &e.search
+	rows := []string{e.searchRow(i18n.T("editor.find"), s.query, !s.inReplace, e.searchStatus())}
+	if s.replacing {
+		rows = append(rows, e.searchRow(i18n.T("editor.replace"), s.replacement, s.inReplace, ""))
+	}
+
+	lines := strings.Split(view, "\n")
+	row := min(len(lines), e.Height) - len(rows)
+	if row < 0 {
+		return view
+	}
+	for i, r := range rows {
+		lines[row+i] = r
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *Editor) searchRow(label string, text []rune, focused bool, status string) string {
+	input := string(text)
+	if focused {
+		input += "█"
+	}
+	row := searchBarStyle.Render(fmt.Sprintf(" %-8s %s ", label, input)) + status
+	return searchBarStyle.Width(e.Width).MaxWidth(e.Width).MaxHeight(1).Render(row)
+}
+
+func (e *Editor) searchStatus() string {
+	s := &e.search
+	toggle := func(label string, on bool) string {
+		if on {
+			return searchToggleStyle.Render(label)
+		}
+		return searchBarStyle.Render(label)
+	}
+	status := toggle("Aa", s.caseSensitive) + searchBarStyle.Render(" ") + toggle(".*", s.regex) + searchBarStyle.Render("  ")
+	switch {
+	case s.err != nil:
+		return status + searchErrorStyle.Render(i18n.T("editor.searchInvalid"))
+	case len(s.query) == 0:
+		return status
+	case len(s.matches) == 0:
+		return status + searchErrorStyle.Render(i18n.T("editor.searchNoResults"))
+	}
+	return status + searchBarStyle.Render(fmt.Sprintf("%d/%d", s.current+1, len(s.matches)))
+}
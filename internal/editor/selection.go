@@ -0,0 +1,148 @@
+package editor
+
+import "time"
+
+// selectionMode is how the span from the anchor to the cursor is widened
+// into a selection. Block selections are laid out by blockState instead.
+type selectionMode int
+
+const (
+	charSelection selectionMode = iota
+	wordSelection
+	lineSelection
+)
+
+// anchorSelection fixes the end a selection grows from before the cursor
+// moves. Every selection source goes through it, so a selection started by
+// the mouse keeps growing from the same place under shift+arrows and the
+// other way round. A selection set some other way, by select all or a
+// search, is taken over from its start, and so is a word selection, which
+// keys then grow a character at a time.
+func (e *Editor) anchorSelection() {
+	switch {
+	case !e.hasSelection():
+		e.anchor, e.selectMode = e.Cursor, charSelection
+	case e.selectMode == wordSelection || e.Selection != e.selectionSpan(e.anchor, e.Cursor):
+		e.anchor, e.selectMode = e.Selection.Start, charSelection
+	}
+}
+
+// selectTo moves the cursor to p and selects from the anchor to it. Word
+// selections leave the cursor at the end of the word they grew to.
+func (e *Editor) selectTo(p Position) {
+	e.Cursor = p
+	e.Selection = e.selectionSpan(e.anchor, p)
+	if e.selectMode == wordSelection {
+		e.Cursor = e.Selection.End
+	}
+}
+
+// selectionSpan is the selection from anchor to head in the current mode.
+// Line selections cover whole lines and keep their direction, so the end
+// the cursor is on stays the end that moves.
+func (e *Editor) selectionSpan(anchor, head Position) Selection {
+	switch e.selectMode {
+	case charSelection:
+		return Selection{Start: anchor, End: head}
+	case wordSelection:
+		if positionBefore(head, anchor) {
+			_, anchorEnd := e.wordBounds(anchor, false)
+			headStart, _ := e.wordBounds(head, false)
+			return Selection{Start: Position{Line: anchor.Line, Column: anchorEnd}, End: Position{Line: head.Line, Column: headStart}}
+		}
+		anchorStart, _ := e.wordBounds(anchor, false)
+		_, headEnd := e.wordBounds(head, true)
+		return Selection{Start: Position{Line: anchor.Line, Column: anchorStart}, End: Position{Line: head.Line, Column: headEnd}}
+	}
+	if positionBefore(head, anchor) {
+		return Selection{Start: e.lineEnd(anchor.Line), End: Position{Line: head.Line}}
+	}
+	return Selection{Start: Position{Line: anchor.Line}, End: e.lineEnd(head.Line)}
+}
+
+// wordBounds returns the run of word, punctuation or space characters at
+// p. A head moving forward names the character before it, so the end of a
+// run maps back to the same run; otherwise the character at p counts.
+func (e *Editor) wordBounds(p Position, forward bool) (int, int) {
+	line := e.Buffer.Line(p.Line)
+	i := min(p.Column, len(line))
+	if i > 0 && (forward || i == len(line)) {
+		i--
+	}
+	if i >= len(line) {
+		return i, i
+	}
+	class := e.classOf(line[i])
+	start, end := i, i+1
+	for start > 0 && e.classOf(line[start-1]) == class {
+		start--
+	}
+	for end < len(line) && e.classOf(line[end]) == class {
+		end++
+	}
+	return start, end
+}
+
+// startWordDrag selects the word under p, as a double click does, and
+// lets a drag grow the selection a word at a time.
+func (e *Editor) startWordDrag(p Position) {
+	e.carets = nil
+	start, _ := e.wordBounds(p, false)
+	e.anchor, e.selectMode = Position{Line: p.Line, Column: start}, wordSelection
+	e.dragTo(p)
+	e.dragging = true
+}
+
+// dragTo extends a mouse selection to the character under the pointer at
+// p, which a forward word selection takes in whole.
+func (e *Editor) dragTo(p Position) {
+	if e.selectMode == wordSelection && !positionBefore(p, e.anchor) {
+		p.Column = nextBoundary(e.Buffer.Line(p.Line), p.Column)
+	}
+	e.selectTo(p)
+}
+
+// lineEnd is the position just past line, including its newline when it
+// has one.
+func (e *Editor) lineEnd(line int) Position {
+	if line < e.Buffer.LineCount()-1 {
+		return Position{Line: line + 1}
+	}
+	return Position{Line: line, Column: e.Buffer.LineLength(line)}
+}
+
+// selectLine selects the cursor's line, or grows a line selection by the
+// next line when one is already being made.
+func (e *Editor) selectLine() {
+	e.carets = nil
+	if e.selectMode == lineSelection && e.hasSelection() && e.Selection == e.selectionSpan(e.anchor, e.Cursor) {
+		e.selectTo(Position{Line: min(e.Cursor.Line+1, e.Buffer.LineCount()-1)})
+		return
+	}
+	e.anchor, e.selectMode = Position{Line: e.Cursor.Line}, lineSelection
+	e.selectTo(e.anchor)
+}
+
+// multiClickInterval is how soon a click must follow the last one on the
+// same cell to count towards a double or triple click.
+const multiClickInterval = 400 * time.Millisecond
+
+type clickCounter struct {
+	x, y  int
+	at    time.Time
+	count int
+}
+
+// countClick records a click at x, y and returns how many clicks in a row
+// it makes, going back to one after a triple click.
+func (e *Editor) countClick(x, y int) int {
+	c := &e.clicks
+	now := time.Now()
+	if c.count > 0 && c.count < 3 && x == c.x && y == c.y && now.Sub(c.at) <= multiClickInterval {
+		c.count++
+	} else {
+		c.count = 1
+	}
+	c.x, c.y, c.at = x, y, now
+	return c.count
+}
@@ -0,0 +1,53 @@
+package editor
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles used on every rendered line are built once rather than per frame.
+var (
+	activeLineNumStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	lineNumStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#555555"))
+	blockCursorStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#ffffff")).Foreground(lipgloss.Color("#000000"))
+	lineCursorStyle    = lipgloss.NewStyle().Background(lipgloss.Color("#ffffff"))
+	underlineStyle     = lipgloss.NewStyle().Underline(true)
+	ghostStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Italic(true)
+	foldedStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Background(lipgloss.Color("#313244"))
+	preeditStyle       = lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color("#f9e2af"))
+	promptStyle        = lipgloss.NewStyle().Background(lipgloss.Color("#f9e2af")).Foreground(lipgloss.Color("#1e1e2e"))
+
+	accessibleSelectionStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+var (
+	styleCacheMu sync.Mutex
+	fgStyles     = map[string]lipgloss.Style{}
+	bgStyles     = map[string]lipgloss.Style{}
+)
+
+// foregroundStyle returns a cached style with the given foreground color.
+func foregroundStyle(color string) lipgloss.Style {
+	return cachedStyle(fgStyles, color, func(s lipgloss.Style) lipgloss.Style {
+		return s.Foreground(lipgloss.Color(color))
+	})
+}
+
+// backgroundStyle returns a cached style with the given background color.
+func backgroundStyle(color string) lipgloss.Style {
+	return cachedStyle(bgStyles, color, func(s lipgloss.Style) lipgloss.Style {
+		return s.Background(lipgloss.Color(color))
+	})
+}
+
+func cachedStyle(cache map[string]lipgloss.Style, color string, build func(lipgloss.Style) lipgloss.Style) lipgloss.Style {
+	styleCacheMu.Lock()
+	defer styleCacheMu.Unlock()
+	if s, ok := cache[color]; ok {
+		return s
+	}
+	s := build(lipgloss.NewStyle())
+	cache[color] = s
+	return s
+}
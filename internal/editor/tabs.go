@@ -0,0 +1,65 @@
+package editor
+
+import "strings"
+
+// DefaultTabWidth is how many cells apart tab stops are unless set.
+const DefaultTabWidth = 4
+
+func (e *Editor) tabWidth() int {
+	if e.TabWidth > 0 {
+		return e.TabWidth
+	}
+	return DefaultTabWidth
+}
+
+// tabStop returns the cell a tab drawn at cell runs up to.
+func (e *Editor) tabStop(cell int) int {
+	w := e.tabWidth()
+	return (cell/w + 1) * w
+}
+
+// expandTabs replaces the tabs in text, drawn from cell, with the spaces
+// up to their tab stops.
+func (e *Editor) expandTabs(text string, cell int) string {
+	if !strings.Contains(text, "\t") {
+		return text
+	}
+	var sb strings.Builder
+	for {
+		i := strings.IndexByte(text, '\t')
+		if i < 0 {
+			sb.WriteString(text)
+			return sb.String()
+		}
+		sb.WriteString(text[:i])
+		cell += displayWidth(text[:i])
+		stop := e.tabStop(cell)
+		sb.WriteString(spaces(stop - cell))
+		cell, text = stop, text[i+1:]
+	}
+}
+
+// insertTab types a tab, or with spaced indentation the spaces up to the
+// next indent level.
+func (e *Editor) insertTab() {
+	if e.hasSelection() {
+		e.deleteSelection()
+	}
+	text := "\t"
+	if !e.Indent.UseTabs {
+		size := max(e.Indent.Size, 1)
+		text = spaces(size - e.cell(e.Cursor.Line, e.Cursor.Column)%size)
+	}
+	e.Buffer.Insert(e.Cursor, text)
+	e.Cursor.Column += len(text)
+	e.clearSelection()
+}
+
+// ToggleInsertSpaces switches the document between indenting with tabs
+// and with spaces.
+func (e *Editor) ToggleInsertSpaces() {
+	e.Indent.UseTabs = !e.Indent.UseTabs
+	if e.Indent.Size <= 0 {
+		e.Indent.Size = defaultIndent.Size
+	}
+}
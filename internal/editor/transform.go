@@ -0,0 +1,128 @@
+package editor
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CaseTransform is a change of letter case for TransformCase.
+type CaseTransform int
+
+const (
+	UpperCase CaseTransform = iota
+	LowerCase
+	TitleCase
+)
+
+// selectedLines returns the lines the selection covers, or the cursor's
+// line without one.
+func (e *Editor) selectedLines() (int, int) {
+	if !e.hasSelection() {
+		return e.Cursor.Line, e.Cursor.Line
+	}
+	sel := e.Selection.Normalized()
+	first, last := sel.Start.Line, sel.End.Line
+	// A selection of whole lines ends at the start of the next one.
+	if sel.End.Column == 0 && last > first {
+		last--
+	}
+	return first, last
+}
+
+// SortLines sorts the selected lines, or every line without a selection,
+// dropping repeats when unique is set.
+func (e *Editor) SortLines(descending, unique bool) tea.Cmd {
+	return e.transformLines(func(lines []string) []string {
+		slices.Sort(lines)
+		if unique {
+			lines = slices.Compact(lines)
+		}
+		if descending {
+			slices.Reverse(lines)
+		}
+		return lines
+	})
+}
+
+// ReverseLines reverses the order of the selected lines, or of every line
+// without a selection.
+func (e *Editor) ReverseLines() tea.Cmd {
+	return e.transformLines(func(lines []string) []string {
+		slices.Reverse(lines)
+		return lines
+	})
+}
+
+// transformLines replaces the selected lines, or the whole buffer, with
+// what fn makes of them as one change, and selects the result.
+func (e *Editor) transformLines(fn func([]string) []string) tea.Cmd {
+	first, last := 0, e.Buffer.LineCount()-1
+	if e.hasSelection() {
+		first, last = e.selectedLines()
+	} else if last > 0 && e.Buffer.LineLength(last) == 0 {
+		// The empty line after a final newline is not one to sort.
+		last--
+	}
+	lines := make([]string, 0, last-first+1)
+	for l := first; l <= last; l++ {
+		lines = append(lines, e.Buffer.Line(l))
+	}
+	lines = fn(lines)
+	end := Position{Line: last, Column: e.Buffer.LineLength(last)}
+	cmd, err := e.ApplyEdits([]TextEdit{{Range: Selection{Start: Position{Line: first}, End: end}, NewText: strings.Join(lines, "\n")}})
+	if err != nil || cmd == nil {
+		return nil
+	}
+	last = first + len(lines) - 1
+	e.carets = nil
+	e.anchor, e.selectMode = Position{Line: first}, charSelection
+	e.selectTo(Position{Line: last, Column: e.Buffer.LineLength(last)})
+	return cmd
+}
+
+// TransformCase changes the case of the selected text at every caret.
+func (e *Editor) TransformCase(c CaseTransform) tea.Cmd {
+	var edits []TextEdit
+	for _, sel := range append([]Selection{e.Selection}, e.caretSelections()...) {
+		if sel.IsEmpty() {
+			continue
+		}
+		norm := sel.Normalized()
+		text := e.Buffer.GetText(norm.Start, norm.End)
+		if changed := transformCase(text, c); changed != text {
+			edits = append(edits, TextEdit{Range: norm, NewText: changed})
+		}
+	}
+	cmd, _ := e.ApplyEdits(edits)
+	return cmd
+}
+
+func (e *Editor) caretSelections() []Selection {
+	sels := make([]Selection, len(e.carets))
+	for i, c := range e.carets {
+		sels[i] = c.sel
+	}
+	return sels
+}
+
+func transformCase(s string, c CaseTransform) string {
+	switch c {
+	case UpperCase:
+		return strings.ToUpper(s)
+	case LowerCase:
+		return strings.ToLower(s)
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		inWord := i > 0 && (unicode.IsLetter(runes[i-1]) || unicode.IsDigit(runes[i-1]) || runes[i-1] == '\'')
+		if inWord {
+			runes[i] = unicode.ToLower(r)
+		} else {
+			runes[i] = unicode.ToTitle(r)
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,166 @@
+package editor
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndoSteps bounds the history; the oldest steps are dropped past it.
+const maxUndoSteps = 1000
+
+// undoStep is what one undo takes back: the buffer's changes, oldest
+// first, and where the cursor was before and after them.
+type undoStep struct {
+	changes       []ChangeEvent
+	before, after Position
+}
+
+// typing reports whether the step only inserted text on one line, so that
+// runs of it can be undone a word at a time.
+func (s undoStep) typing() bool {
+	return len(s.changes) == 1 && s.changes[0].OldText == "" && !strings.Contains(s.changes[0].NewText, "\n")
+}
+
+// undoHistory records the buffer's changes as they are published. They
+// collect in pending until commitUndo closes the step: after each key or
+// mouse event, and around ApplyEdits, so a batch of edits is one step.
+type undoHistory struct {
+	done, undone []undoStep
+	pending      undoStep
+	replaying    bool
+}
+
+func (e *Editor) recordUndo(ev ChangeEvent) {
+	h := &e.history
+	if h.replaying {
+		return
+	}
+	if len(h.pending.changes) == 0 {
+		h.pending.before = e.Cursor
+	}
+	h.pending.changes = append(h.pending.changes, ev)
+	h.undone = nil
+}
+
+// commitUndo closes the pending step. Typing that carries on where the
+// last step left off joins it, up to the next word.
+func (e *Editor) commitUndo() {
+	h := &e.history
+	step := h.pending
+	if len(step.changes) == 0 {
+		return
+	}
+	h.pending = undoStep{}
+	step.after = e.Cursor
+	if n := len(h.done); n > 0 && step.typing() && h.done[n-1].typing() {
+		last := &h.done[n-1]
+		prev, next := last.changes[0], step.changes[0]
+		if next.Range.Start == textEnd(prev.Range.Start, prev.NewText) &&
+			!(isSpace(next.NewText) && !isSpace(prev.NewText[len(prev.NewText)-1:])) {
+			prev.NewText += next.NewText
+			last.changes[0], last.after = prev, step.after
+			return
+		}
+	}
+	h.done = append(h.done, step)
+	if len(h.done) > maxUndoSteps {
+		h.done = h.done[len(h.done)-maxUndoSteps:]
+	}
+}
+
+// withoutUndo makes changes that undo never takes back, such as output
+// appended to a log file being followed.
+func (e *Editor) withoutUndo(change func()) {
+	e.commitUndo()
+	e.history.replaying = true
+	change()
+	e.history.replaying = false
+}
+
+func (e *Editor) clearUndo() {
+	e.history = undoHistory{}
+}
+
+// CanUndo reports whether there is a change to take back.
+func (e *Editor) CanUndo() bool {
+	return len(e.history.done) > 0 || len(e.history.pending.changes) > 0
+}
+
+// Undo takes back the last step of changes and puts the cursor where it
+// was before them.
+func (e *Editor) Undo() tea.Cmd {
+	if e.ReadOnly {
+		return nil
+	}
+	e.commitUndo()
+	h := &e.history
+	n := len(h.done)
+	if n == 0 {
+		return nil
+	}
+	step := h.done[n-1]
+	h.done = h.done[:n-1]
+	h.replaying = true
+	for i := len(step.changes) - 1; i >= 0; i-- {
+		ev := step.changes[i]
+		replaceText(e.Buffer, ev.Range.Start, textEnd(ev.Range.Start, ev.NewText), ev.OldText)
+	}
+	h.replaying = false
+	h.undone = append(h.undone, step)
+	e.Cursor = step.before
+	return e.afterReplay()
+}
+
+// Redo makes the last undone step of changes again.
+func (e *Editor) Redo() tea.Cmd {
+	if e.ReadOnly {
+		return nil
+	}
+	e.commitUndo()
+	h := &e.history
+	n := len(h.undone)
+	if n == 0 {
+		return nil
+	}
+	step := h.undone[n-1]
+	h.undone = h.undone[:n-1]
+	h.replaying = true
+	for _, ev := range step.changes {
+		replaceText(e.Buffer, ev.Range.Start, ev.Range.End, ev.NewText)
+	}
+	h.replaying = false
+	h.done = append(h.done, step)
+	e.Cursor = step.after
+	return e.afterReplay()
+}
+
+func (e *Editor) afterReplay() tea.Cmd {
+	e.clearSelection()
+	e.carets = nil
+	e.block.active = false
+	e.markDirty()
+	e.updateDirty()
+	return e.afterCommand()
+}
+
+func replaceText(b Buffer, start, end Position, text string) {
+	if start != end {
+		b.Delete(start, end)
+	}
+	if text != "" {
+		b.Insert(start, text)
+	}
+}
+
+// textEnd is where text ends when inserted at start.
+func textEnd(start Position, text string) Position {
+	if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+		return Position{Line: start.Line + strings.Count(text, "\n"), Column: len(text) - i - 1}
+	}
+	return Position{Line: start.Line, Column: start.Column + len(text)}
+}
+
+func isSpace(s string) bool {
+	return s != "" && strings.TrimLeft(s, " \t") == ""
+}
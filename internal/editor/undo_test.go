@@ -0,0 +1,112 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUndoApplyEditsIsOneStep(t *testing.T) {
+	const original = "one two\nthree four\nfive\n"
+	e := NewWithContent(original)
+	e.Cursor = Position{Line: 1, Column: 6}
+
+	_, err := e.ApplyEdits([]TextEdit{
+		{Range: Selection{Start: Position{Line: 0, Column: 0}, End: Position{Line: 0, Column: 3}}, NewText: "1"},
+		{Range: Selection{Start: Position{Line: 1, Column: 0}, End: Position{Line: 1, Column: 5}}, NewText: "3\n3"},
+		{Range: Selection{Start: Position{Line: 2, Column: 4}, End: Position{Line: 2, Column: 4}}, NewText: "!"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := e.Buffer.Content()
+	if edited != "1 two\n3\n3 four\nfive!\n" {
+		t.Fatalf("after ApplyEdits: %q", edited)
+	}
+
+	e.Undo()
+	if got := e.Buffer.Content(); got != original {
+		t.Fatalf("after Undo: %q, want %q", got, original)
+	}
+	if e.Cursor != (Position{Line: 1, Column: 6}) {
+		t.Errorf("cursor after Undo = %+v", e.Cursor)
+	}
+	if e.CanUndo() {
+		t.Error("CanUndo after undoing the only step")
+	}
+
+	e.Redo()
+	if got := e.Buffer.Content(); got != edited {
+		t.Fatalf("after Redo: %q, want %q", got, edited)
+	}
+}
+
+func TestUndoTypingByWord(t *testing.T) {
+	e := NewWithContent("")
+	for _, r := range "hello world" {
+		e.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	e.Undo()
+	if got := e.Buffer.Content(); got != "hello" {
+		t.Fatalf("after one Undo: %q", got)
+	}
+	e.Undo()
+	if got := e.Buffer.Content(); got != "" {
+		t.Fatalf("after two Undos: %q", got)
+	}
+}
+
+func TestUndoPasteAndSaveTransforms(t *testing.T) {
+	e := NewWithContent("a\n")
+	e.Cursor = Position{Line: 1}
+	e.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x  \r\ny  \r\n"), Paste: true})
+	if got := e.Buffer.Content(); got != "a\nx  \ny  \n" {
+		t.Fatalf("after paste: %q", got)
+	}
+
+	e.TrimTrailingWhitespace = true
+	e.applySaveTransforms()
+	if got := e.Buffer.Content(); got != "a\nx\ny\n" {
+		t.Fatalf("after save transforms: %q", got)
+	}
+
+	e.Undo()
+	if got := e.Buffer.Content(); got != "a\nx  \ny  \n" {
+		t.Fatalf("undoing the transforms: %q", got)
+	}
+	e.Undo()
+	if got := e.Buffer.Content(); got != "a\n" {
+		t.Fatalf("undoing the paste: %q", got)
+	}
+}
+
+func TestFollowedLogOutputIsNotUndone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("start\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e := New()
+	if err := e.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"one\n", "two\n"} {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(line)
+		f.Close()
+		if !e.FollowFile() {
+			t.Fatal("FollowFile saw no change")
+		}
+	}
+	if e.CanUndo() {
+		t.Error("CanUndo after following log output")
+	}
+	e.Undo()
+	if got := e.Buffer.Content(); got != "start\none\ntwo\n" {
+		t.Fatalf("after Undo: %q", got)
+	}
+}
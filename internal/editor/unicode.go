@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// Columns in a Position are byte offsets into the line and always sit on a
+// grapheme cluster boundary. What is drawn is measured in cells, so wide
+// characters take two, combining sequences move as one and tabs run to the
+// next tab stop.
+
+// displayWidth returns how many cells text takes.
+func displayWidth(text string) int {
+	return uniseg.StringWidth(text)
+}
+
+// displayColumn returns the cell at which byte offset col of line is drawn.
+func (e *Editor) displayColumn(line string, col int) int {
+	line = line[:max(0, min(col, len(line)))]
+	cell := 0
+	for {
+		i := strings.IndexByte(line, '\t')
+		if i < 0 {
+			return cell + displayWidth(line)
+		}
+		cell = e.tabStop(cell + displayWidth(line[:i]))
+		line = line[i+1:]
+	}
+}
+
+// byteColumn returns the offset of the grapheme cluster drawn over cell,
+// or the end of line when cell is past it.
+func (e *Editor) byteColumn(line string, cell int) int {
+	offset, width, state := 0, 0, -1
+	for rest := line; rest != ""; {
+		cluster, next, w, s := uniseg.FirstGraphemeClusterInString(rest, state)
+		if cluster == "\t" {
+			w = e.tabStop(width) - width
+		}
+		if width+w > cell {
+			return offset
+		}
+		width += w
+		offset += len(cluster)
+		rest, state = next, s
+	}
+	return offset
+}
+
+// nextBoundary returns the end of the grapheme cluster starting at col.
+func nextBoundary(line string, col int) int {
+	if col >= len(line) {
+		return len(line)
+	}
+	cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(line[col:], -1)
+	return col + len(cluster)
+}
+
+// prevBoundary returns the start of the grapheme cluster ending at col.
+func prevBoundary(line string, col int) int {
+	col = min(col, len(line))
+	offset, state := 0, -1
+	for rest := line; rest != ""; {
+		cluster, next, _, s := uniseg.FirstGraphemeClusterInString(rest, state)
+		if offset+len(cluster) >= col {
+			return offset
+		}
+		offset += len(cluster)
+		rest, state = next, s
+	}
+	return offset
+}
+
+// cell returns the cell of byte column col on line lineNum.
+func (e *Editor) cell(lineNum, col int) int {
+	return e.displayColumn(e.Buffer.Line(lineNum), col)
+}
+
+// clusterCells returns the cell and width of the character at col, one
+// cell wide past the end of the line where the cursor is drawn as a space.
+func (e *Editor) clusterCells(lineNum, col int) (int, int) {
+	line := e.Buffer.Line(lineNum)
+	col = max(0, min(col, len(line)))
+	cell := e.displayColumn(line, col)
+	if col < len(line) && line[col] == '\t' {
+		return cell, e.tabStop(cell) - cell
+	}
+	return cell, max(displayWidth(line[col:nextBoundary(line, col)]), 1)
+}
+
+// visibleCells converts the byte range [from, to) of line lineNum into cells
+// of a row drawn from startCol, clipped to the viewport.
+func (e *Editor) visibleCells(lineNum, from, to, startCol int) (int, int) {
+	return max(e.cell(lineNum, from)-startCol, 0), min(e.cell(lineNum, to)-startCol, e.Viewport.Width)
+}
+
+// revealCursor scrolls the cursor's cell into view.
+func (e *Editor) revealCursor() {
+	e.revealFolds()
+	cell := Position{Line: e.Cursor.Line, Column: e.cell(e.Cursor.Line, e.Cursor.Column)}
+	e.fitViewHeight()
+	e.Viewport.EnsureCursorVisible(cell, e.Buffer.LineCount())
+	e.revealColumn(cell.Column)
+	e.fitWrappedView()
+}
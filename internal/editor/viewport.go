@@ -9,6 +9,12 @@ type Viewport struct {
 	X      int
 	Height int
 	Width  int
+	// Margin is how many lines of context EnsureCursorVisible keeps above
+	// and below the cursor, capped at half the height.
+	Margin int
+	// PastEnd lets the view scroll on until the last line is at the top
+	// rather than stopping once it is at the bottom.
+	PastEnd bool
 }
 
 func NewViewport() *Viewport {
@@ -59,11 +65,20 @@ func (v *Viewport) ScrollUp() {
 }
 
 func (v *Viewport) ScrollDown(maxLines int) {
-	if v.Y+v.Height < maxLines {
+	if v.Y < v.MaxY(maxLines) {
 		v.Y++
 	}
 }
 
+// MaxY is the furthest the view scrolls down in a buffer of lineCount
+// lines.
+func (v *Viewport) MaxY(lineCount int) int {
+	if v.PastEnd {
+		return max(lineCount-1, 0)
+	}
+	return max(lineCount-v.Height, 0)
+}
+
 func (v *Viewport) ScrollLeft() {
 	if v.X > 0 {
 		v.X--
@@ -76,9 +91,18 @@ func (v *Viewport) ScrollRight(maxCol int) {
 	}
 }
 
-func (v *Viewport) EnsureCursorVisible(cursor Position, lineLength int) {
-	v.ScrollToLine(cursor.Line)
-	v.ScrollToColumn(cursor.Column)
+// EnsureCursorVisible scrolls the cursor's line into view with Margin
+// lines of context around it, except where the buffer of lineCount lines
+// runs out of them. Columns are left to the editor, which scrolls
+// sideways in steps.
+func (v *Viewport) EnsureCursorVisible(cursor Position, lineCount int) {
+	margin := max(min(v.Margin, (v.Height-1)/2), 0)
+	switch {
+	case cursor.Line-margin < v.Y:
+		v.Y = max(cursor.Line-margin, 0)
+	case cursor.Line+margin >= v.Y+v.Height:
+		v.Y = min(cursor.Line+margin-v.Height+1, max(v.MaxY(lineCount), cursor.Line-v.Height+1))
+	}
 }
 
 func (v *Viewport) HandleMouse(msg tea.MouseMsg, buffer Buffer) {
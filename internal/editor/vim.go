@@ -0,0 +1,730 @@
+package editor
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type vimMode int
+
+const (
+	vimNormal vimMode = iota
+	vimInsert
+	vimVisual
+	vimVisualLine
+)
+
+// vimState tracks the modal layer's progress through a command such as
+// 3dw: the count typed so far, an operator waiting for its motion and a
+// prefix key like the g of gg or the i of a text object.
+type vimState struct {
+	mode        vimMode
+	count       int
+	operator    rune
+	opCount     int
+	prefix      rune
+	visualStart Position
+	// register holds the last yanked or deleted text, which is whole lines
+	// when linewise is set.
+	register string
+	linewise bool
+}
+
+// vimTarget is where a motion lands. Inclusive motions take the character
+// at to along with them; linewise ones act on whole lines.
+type vimTarget struct {
+	to        Position
+	linewise  bool
+	inclusive bool
+}
+
+// vimArrows lets the non-text keys act as their motions in normal and
+// visual mode.
+var vimArrows = map[tea.KeyType]rune{
+	tea.KeyLeft:      'h',
+	tea.KeyRight:     'l',
+	tea.KeyUp:        'k',
+	tea.KeyDown:      'j',
+	tea.KeyBackspace: 'h',
+	tea.KeyEnter:     'j',
+	tea.KeyDelete:    'x',
+}
+
+// SetVim turns the modal editing layer on or off. It starts in normal
+// mode.
+func (e *Editor) SetVim(on bool) {
+	e.Vim = on
+	e.vim = vimState{register: e.vim.register, linewise: e.vim.linewise}
+	e.setVimMode(vimNormal)
+	if !on {
+		e.CursorStyle = CursorBlock
+	}
+}
+
+// VimMode names the current mode for display, or returns "" when the modal
+// layer is off.
+func (e *Editor) VimMode() string {
+	if !e.Vim {
+		return ""
+	}
+	switch e.vim.mode {
+	case vimInsert:
+		return "insert"
+	case vimVisual:
+		return "visual"
+	case vimVisualLine:
+		return "visualLine"
+	}
+	return "normal"
+}
+
+func (e *Editor) setVimMode(mode vimMode) {
+	if e.vim.mode >= vimVisual && mode < vimVisual {
+		e.clearSelection()
+	}
+	e.vim.mode = mode
+	e.vim.count, e.vim.operator, e.vim.opCount, e.vim.prefix = 0, 0, 0, 0
+	if mode == vimInsert {
+		e.CursorStyle = CursorLine
+	} else {
+		e.CursorStyle = CursorBlock
+	}
+}
+
+// handleVimKey runs msg as a vim command. It reports false for keys the
+// editor should handle as usual, which is everything in insert mode except
+// esc.
+func (e *Editor) handleVimKey(msg tea.KeyMsg) bool {
+	v := &e.vim
+	if v.mode == vimInsert {
+		if msg.Type != tea.KeyEsc {
+			return false
+		}
+		e.setVimMode(vimNormal)
+		e.Cursor.Column = prevBoundary(e.Buffer.Line(e.Cursor.Line), e.Cursor.Column)
+		return true
+	}
+	if msg.Paste || msg.Alt {
+		return false
+	}
+	e.carets = nil
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		e.setVimMode(vimNormal)
+		return true
+	case tea.KeyRunes, tea.KeySpace:
+		for i, r := range msg.Runes {
+			e.vimRune(r)
+			if v.mode == vimInsert {
+				if rest := msg.Runes[i+1:]; len(rest) > 0 && !e.ReadOnly {
+					e.typeRunes(rest)
+					e.markDirty()
+				}
+				return true
+			}
+		}
+	default:
+		r, ok := vimArrows[msg.Type]
+		if !ok {
+			// Other edit keys would change the text behind the mode's back.
+			return isEditKey(msg)
+		}
+		e.vimRune(r)
+	}
+	if v.mode != vimInsert {
+		e.vimClamp()
+	}
+	if v.mode >= vimVisual {
+		from, to := e.visualRange()
+		e.Selection = Selection{Start: from, End: to}
+	}
+	return true
+}
+
+// vimRune feeds one key of a command, running it once complete.
+func (e *Editor) vimRune(r rune) {
+	v := &e.vim
+	if v.prefix == 0 && (r >= '1' && r <= '9' || r == '0' && v.count > 0) {
+		v.count = v.count*10 + int(r-'0')
+		return
+	}
+	key := string(r)
+	if v.prefix != 0 {
+		key = string(v.prefix) + key
+		v.prefix = 0
+	} else if r == 'g' || r == 'r' && v.operator == 0 && v.mode == vimNormal ||
+		(r == 'i' || r == 'a') && (v.operator != 0 || v.mode >= vimVisual) {
+		v.prefix = r
+		return
+	}
+	count, hasCount := max(v.count, 1)*max(v.opCount, 1), v.count > 0 || v.opCount > 0
+	v.count = 0
+	e.vimCommand(key, count, hasCount)
+}
+
+func (e *Editor) vimCommand(key string, count int, hasCount bool) {
+	v := &e.vim
+	op := v.operator
+	if op == 'c' && key == "w" {
+		// cw changes to the end of the word, leaving the space after it.
+		key = "e"
+	}
+	if t, ok := e.vimMotion(key, count, hasCount); ok {
+		if op == 0 {
+			e.Cursor = t.to
+			return
+		}
+		v.operator, v.opCount = 0, 0
+		from, to := e.Cursor, t.to
+		if key == "w" && to.Line > from.Line {
+			// dw on the last word of a line stops at its end.
+			to = Position{Line: from.Line, Column: e.Buffer.LineLength(from.Line)}
+		}
+		if to.Line < from.Line || to.Line == from.Line && to.Column < from.Column {
+			from, to = to, from
+		}
+		if t.inclusive {
+			to.Column = nextBoundary(e.Buffer.Line(to.Line), to.Column)
+		}
+		e.vimApply(op, from, to, t.linewise)
+		return
+	}
+	if len(key) == 2 && (key[0] == 'i' || key[0] == 'a') {
+		from, t, ok := e.vimTextObject(key)
+		to := t.to
+		switch {
+		case !ok:
+		case op != 0:
+			e.vimApply(op, from, to, t.linewise)
+		case t.linewise:
+			v.visualStart, e.Cursor, v.mode = from, to, vimVisualLine
+		case from != to:
+			v.visualStart = from
+			e.Cursor = Position{Line: to.Line, Column: prevBoundary(e.Buffer.Line(to.Line), to.Column)}
+		}
+		v.operator, v.opCount = 0, 0
+		return
+	}
+	if op != 0 {
+		v.operator, v.opCount = 0, 0
+		if key == string(op) {
+			// A doubled operator, like dd, acts on count lines.
+			last := min(e.Cursor.Line+count-1, e.Buffer.LineCount()-1)
+			e.vimApply(op, Position{Line: e.Cursor.Line}, Position{Line: last}, true)
+		}
+		return
+	}
+	if v.mode >= vimVisual {
+		e.vimVisualCommand(key)
+		return
+	}
+	e.vimNormalCommand(key, count, hasCount)
+}
+
+func (e *Editor) vimNormalCommand(key string, count int, hasCount bool) {
+	v := &e.vim
+	line := e.Buffer.Line(e.Cursor.Line)
+	switch key {
+	case "d", "y", "c":
+		v.operator = rune(key[0])
+		if hasCount {
+			v.opCount = count
+		}
+	case "x", "X", "D", "C":
+		motion := map[string]string{"x": "l", "X": "h", "D": "$", "C": "$"}[key]
+		op := map[string]rune{"x": 'd', "X": 'd', "D": 'd', "C": 'c'}[key]
+		t, _ := e.vimMotion(motion, count, false)
+		from, to := e.Cursor, t.to
+		if key == "X" {
+			from, to = to, from
+		}
+		e.vimApply(op, from, to, false)
+	case "Y":
+		last := min(e.Cursor.Line+count-1, e.Buffer.LineCount()-1)
+		e.vimApply('y', Position{Line: e.Cursor.Line}, Position{Line: last}, true)
+	case "p", "P":
+		e.vimPut(key == "p", count)
+	case "i":
+		e.setVimMode(vimInsert)
+	case "a":
+		e.Cursor.Column = nextBoundary(line, e.Cursor.Column)
+		e.setVimMode(vimInsert)
+	case "I":
+		e.Cursor.Column = firstNonBlank(line)
+		e.setVimMode(vimInsert)
+	case "A":
+		e.Cursor.Column = len(line)
+		e.setVimMode(vimInsert)
+	case "o", "O":
+		if e.ReadOnly {
+			return
+		}
+		if key == "o" {
+			e.Cursor.Column = len(line)
+			text := e.lineBreak(e.Cursor)
+			e.Buffer.Insert(e.Cursor, text)
+			e.moveCursorAfterInsert(text)
+		} else {
+			indent := line[:firstNonBlank(line)]
+			e.Buffer.Insert(Position{Line: e.Cursor.Line}, indent+"\n")
+			e.Cursor.Column = len(indent)
+		}
+		e.markDirty()
+		e.setVimMode(vimInsert)
+	case "J":
+		e.vimJoin(max(count-1, 1))
+	case "u":
+		for range count {
+			e.Undo()
+		}
+	case "v":
+		v.visualStart = e.Cursor
+		e.setVimMode(vimVisual)
+	case "V":
+		v.visualStart = e.Cursor
+		e.setVimMode(vimVisualLine)
+	default:
+		if len(key) > 1 && key[0] == 'r' {
+			e.vimReplace(key[1:], count)
+		}
+	}
+}
+
+func (e *Editor) vimVisualCommand(key string) {
+	v := &e.vim
+	switch key {
+	case "d", "x", "y", "c":
+		from, to := e.visualRange()
+		linewise := v.mode == vimVisualLine
+		e.setVimMode(vimNormal)
+		op := rune(key[0])
+		if op == 'x' {
+			op = 'd'
+		}
+		e.vimApply(op, from, to, linewise)
+	case "o":
+		v.visualStart, e.Cursor = e.Cursor, v.visualStart
+	case "v", "V":
+		mode := map[string]vimMode{"v": vimVisual, "V": vimVisualLine}[key]
+		if v.mode == mode {
+			e.setVimMode(vimNormal)
+		} else {
+			v.mode = mode
+		}
+	}
+}
+
+// visualRange returns the text a visual selection covers, which takes in
+// the character under the cursor or, linewise, whole lines.
+func (e *Editor) visualRange() (Position, Position) {
+	sel := Selection{Start: e.vim.visualStart, End: e.Cursor}.Normalized()
+	if e.vim.mode == vimVisualLine {
+		return Position{Line: sel.Start.Line}, Position{Line: sel.End.Line, Column: e.Buffer.LineLength(sel.End.Line)}
+	}
+	sel.End.Column = nextBoundary(e.Buffer.Line(sel.End.Line), sel.End.Column)
+	return sel.Start, sel.End
+}
+
+// vimClamp keeps the cursor on a character, as normal mode has no place
+// past the end of a line.
+func (e *Editor) vimClamp() {
+	e.ensureCursorValid()
+	if line := e.Buffer.Line(e.Cursor.Line); line != "" && e.Cursor.Column >= len(line) {
+		e.Cursor.Column = prevBoundary(line, len(line))
+	}
+}
+
+func (e *Editor) vimMotion(key string, count int, hasCount bool) (vimTarget, bool) {
+	p := e.Cursor
+	line := e.Buffer.Line(p.Line)
+	switch key {
+	case "h":
+		for i := 0; i < count && p.Column > 0; i++ {
+			p.Column = prevBoundary(line, p.Column)
+		}
+	case "l", " ":
+		for i := 0; i < count && p.Column < len(line); i++ {
+			p.Column = nextBoundary(line, p.Column)
+		}
+	case "j", "k":
+		dy := count
+		if key == "k" {
+			dy = -count
+		}
+		cell := e.cell(p.Line, p.Column)
+		p.Line = e.stepVisible(p.Line, dy)
+		p.Column = e.byteColumn(e.Buffer.Line(p.Line), cell)
+		return vimTarget{to: p, linewise: true}, true
+	case "w":
+		for i := 0; i < count; i++ {
+			p = e.vimWordStart(p)
+		}
+	case "b":
+		for i := 0; i < count; i++ {
+			p = e.vimWordBack(p)
+		}
+	case "e":
+		for i := 0; i < count; i++ {
+			p = e.vimWordEnd(p)
+		}
+		return vimTarget{to: p, inclusive: true}, true
+	case "0":
+		p.Column = 0
+	case "^":
+		p.Column = firstNonBlank(line)
+	case "$":
+		p.Line = min(p.Line+count-1, e.Buffer.LineCount()-1)
+		p.Column = e.Buffer.LineLength(p.Line)
+	case "G", "gg":
+		p.Line = 0
+		if key == "G" {
+			p.Line = e.Buffer.LineCount() - 1
+		}
+		if hasCount {
+			p.Line = max(0, min(count-1, e.Buffer.LineCount()-1))
+		}
+		p.Column = firstNonBlank(e.Buffer.Line(p.Line))
+		return vimTarget{to: p, linewise: true}, true
+	default:
+		return vimTarget{}, false
+	}
+	return vimTarget{to: p}, true
+}
+
+// vimWordStart returns the start of the next word after p, where a run of
+// punctuation is a word too and an empty line stops the motion.
+func (e *Editor) vimWordStart(p Position) Position {
+	line := e.Buffer.Line(p.Line)
+	if p.Column < len(line) {
+		class := e.classOf(line[p.Column])
+		for p.Column < len(line) && e.classOf(line[p.Column]) == class {
+			p.Column++
+		}
+	}
+	for {
+		for p.Column < len(line) && e.classOf(line[p.Column]) == classSpace {
+			p.Column++
+		}
+		if p.Column < len(line) || p.Line >= e.Buffer.LineCount()-1 {
+			return p
+		}
+		p = Position{Line: p.Line + 1}
+		if line = e.Buffer.Line(p.Line); line == "" {
+			return p
+		}
+	}
+}
+
+// vimWordBack returns the start of the word before p.
+func (e *Editor) vimWordBack(p Position) Position {
+	line := e.Buffer.Line(p.Line)
+	for {
+		for p.Column > 0 && e.classOf(line[p.Column-1]) == classSpace {
+			p.Column--
+		}
+		if p.Column > 0 || p.Line == 0 {
+			break
+		}
+		p.Line--
+		line = e.Buffer.Line(p.Line)
+		p.Column = len(line)
+		if line == "" {
+			return p
+		}
+	}
+	if p.Column > 0 {
+		class := e.classOf(line[p.Column-1])
+		for p.Column > 0 && e.classOf(line[p.Column-1]) == class {
+			p.Column--
+		}
+	}
+	return p
+}
+
+// vimWordEnd returns the last character of the word ending after p.
+func (e *Editor) vimWordEnd(p Position) Position {
+	line := e.Buffer.Line(p.Line)
+	p.Column = nextBoundary(line, p.Column)
+	for {
+		for p.Column < len(line) && e.classOf(line[p.Column]) == classSpace {
+			p.Column++
+		}
+		if p.Column < len(line) {
+			break
+		}
+		if p.Line >= e.Buffer.LineCount()-1 {
+			p.Column = prevBoundary(line, len(line))
+			return p
+		}
+		p = Position{Line: p.Line + 1}
+		line = e.Buffer.Line(p.Line)
+	}
+	class := e.classOf(line[p.Column])
+	for p.Column+1 < len(line) && e.classOf(line[p.Column+1]) == class {
+		p.Column++
+	}
+	p.Column = prevBoundary(line, p.Column+1)
+	return p
+}
+
+func firstNonBlank(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// vimTextObject returns the range of a text object around the cursor: iw
+// and aw for words, quotes and brackets with i for the inside and a for
+// the delimiters too.
+func (e *Editor) vimTextObject(key string) (Position, vimTarget, bool) {
+	around := key[0] == 'a'
+	switch key[1] {
+	case 'w':
+		from, to, ok := e.vimWordObject(around)
+		return from, vimTarget{to: to}, ok
+	case '"', '\'', '`':
+		from, to, ok := e.vimQuoteObject(key[1], around)
+		return from, vimTarget{to: to}, ok
+	case '(', ')', 'b':
+		return e.vimBracketObject('(', ')', around)
+	case '{', '}', 'B':
+		return e.vimBracketObject('{', '}', around)
+	case '[', ']':
+		return e.vimBracketObject('[', ']', around)
+	case '<', '>':
+		return e.vimBracketObject('<', '>', around)
+	}
+	return Position{}, vimTarget{}, false
+}
+
+func (e *Editor) vimWordObject(around bool) (Position, Position, bool) {
+	l := e.Cursor.Line
+	line := e.Buffer.Line(l)
+	if line == "" {
+		return Position{}, Position{}, false
+	}
+	col := min(e.Cursor.Column, len(line)-1)
+	class := e.classOf(line[col])
+	start, end := col, col
+	for start > 0 && e.classOf(line[start-1]) == class {
+		start--
+	}
+	for end < len(line) && e.classOf(line[end]) == class {
+		end++
+	}
+	if around {
+		trail := end
+		for trail < len(line) && e.classOf(line[trail]) == classSpace {
+			trail++
+		}
+		if trail > end {
+			end = trail
+		} else {
+			for start > 0 && e.classOf(line[start-1]) == classSpace {
+				start--
+			}
+		}
+	}
+	return Position{Line: l, Column: start}, Position{Line: l, Column: end}, true
+}
+
+// vimQuoteObject finds the quoted string on the cursor line that holds the
+// cursor, or else the first one after it.
+func (e *Editor) vimQuoteObject(quote byte, around bool) (Position, Position, bool) {
+	l := e.Cursor.Line
+	line := e.Buffer.Line(l)
+	var quotes []int
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+		} else if line[i] == quote {
+			quotes = append(quotes, i)
+		}
+	}
+	for i := 0; i+1 < len(quotes); i += 2 {
+		open, close := quotes[i], quotes[i+1]
+		if close < e.Cursor.Column {
+			continue
+		}
+		if around {
+			return Position{Line: l, Column: open}, Position{Line: l, Column: close + 1}, true
+		}
+		return Position{Line: l, Column: open + 1}, Position{Line: l, Column: close}, true
+	}
+	return Position{}, Position{}, false
+}
+
+func (e *Editor) vimBracketObject(open, close byte, around bool) (Position, vimTarget, bool) {
+	start, ok := e.vimEnclosing(open, close, -1)
+	if !ok {
+		return Position{}, vimTarget{}, false
+	}
+	end, ok := e.vimEnclosing(open, close, 1)
+	if !ok {
+		return Position{}, vimTarget{}, false
+	}
+	switch {
+	case around:
+		end.Column++
+	case start.Column+1 == e.Buffer.LineLength(start.Line) && end.Column == firstNonBlank(e.Buffer.Line(end.Line)) && end.Line > start.Line+1:
+		// The inside of a block spread over lines is the lines between its
+		// brackets.
+		return Position{Line: start.Line + 1}, vimTarget{to: Position{Line: end.Line - 1}, linewise: true}, true
+	default:
+		start.Column++
+	}
+	return start, vimTarget{to: end}, true
+}
+
+// vimEnclosing scans from the cursor in direction dir for the bracket
+// enclosing it. A bracket under the cursor counts as enclosing it.
+func (e *Editor) vimEnclosing(open, close byte, dir int) (Position, bool) {
+	want, other := open, close
+	if dir > 0 {
+		want, other = close, open
+	}
+	p := e.Cursor
+	line := e.Buffer.Line(p.Line)
+	if p.Column < len(line) && line[p.Column] == want {
+		return p, true
+	}
+	depth := 0
+	for {
+		p.Column += dir
+		for p.Column < 0 || p.Column >= len(line) {
+			p.Line += dir
+			if p.Line < 0 || p.Line >= e.Buffer.LineCount() {
+				return Position{}, false
+			}
+			line = e.Buffer.Line(p.Line)
+			p.Column = 0
+			if dir < 0 {
+				p.Column = len(line) - 1
+			}
+		}
+		switch line[p.Column] {
+		case other:
+			depth++
+		case want:
+			if depth == 0 {
+				return p, true
+			}
+			depth--
+		}
+	}
+}
+
+// vimApply runs an operator over from to to, or over the lines from.Line
+// to to.Line when linewise.
+func (e *Editor) vimApply(op rune, from, to Position, linewise bool) {
+	v := &e.vim
+	if linewise {
+		lines := make([]string, 0, to.Line-from.Line+1)
+		for l := from.Line; l <= to.Line; l++ {
+			lines = append(lines, e.Buffer.Line(l))
+		}
+		v.register, v.linewise = strings.Join(lines, "\n")+"\n", true
+	} else {
+		v.register, v.linewise = e.Buffer.GetText(from, to), false
+	}
+
+	if op == 'y' {
+		if linewise {
+			e.Cursor.Line = from.Line
+		} else {
+			e.Cursor = from
+		}
+		return
+	}
+	if e.ReadOnly {
+		return
+	}
+	switch {
+	case linewise && op == 'c':
+		line := e.Buffer.Line(from.Line)
+		indent := line[:firstNonBlank(line)]
+		e.replaceLines(from.Line, to.Line+1, []string{indent})
+		e.Cursor.Column = len(indent)
+	case linewise:
+		e.replaceLines(from.Line, to.Line+1, nil)
+		e.Cursor.Column = firstNonBlank(e.Buffer.Line(e.Cursor.Line))
+	default:
+		e.Buffer.Delete(from, to)
+		e.Cursor = from
+	}
+	e.markDirty()
+	if op == 'c' {
+		e.setVimMode(vimInsert)
+	}
+}
+
+// vimPut pastes the register after the cursor, or before it, count times.
+// Whole lines go below or above the cursor line.
+func (e *Editor) vimPut(after bool, count int) {
+	v := &e.vim
+	if v.register == "" || e.ReadOnly {
+		return
+	}
+	text := strings.Repeat(v.register, count)
+	if v.linewise {
+		l := e.Cursor.Line
+		if after {
+			l++
+		}
+		if l < e.Buffer.LineCount() {
+			e.Buffer.Insert(Position{Line: l}, text)
+		} else {
+			last := e.Buffer.LineCount() - 1
+			e.Buffer.Insert(Position{Line: last, Column: e.Buffer.LineLength(last)}, "\n"+strings.TrimSuffix(text, "\n"))
+		}
+		e.Cursor = Position{Line: l, Column: firstNonBlank(e.Buffer.Line(l))}
+	} else {
+		if after {
+			e.Cursor.Column = nextBoundary(e.Buffer.Line(e.Cursor.Line), e.Cursor.Column)
+		}
+		e.Buffer.Insert(e.Cursor, text)
+		e.moveCursorAfterInsert(text)
+		e.Cursor.Column = prevBoundary(e.Buffer.Line(e.Cursor.Line), e.Cursor.Column)
+	}
+	e.markDirty()
+}
+
+// vimJoin joins the next n lines onto the cursor line, separated by a
+// space in place of their indentation.
+func (e *Editor) vimJoin(n int) {
+	if e.ReadOnly {
+		return
+	}
+	for ; n > 0 && e.Cursor.Line+1 < e.Buffer.LineCount(); n-- {
+		l := e.Cursor.Line
+		end := e.Buffer.LineLength(l)
+		next := e.Buffer.Line(l + 1)
+		indent := firstNonBlank(next)
+		e.Buffer.Delete(Position{Line: l, Column: end}, Position{Line: l + 1, Column: indent})
+		if end > 0 && indent < len(next) {
+			e.Buffer.Insert(Position{Line: l, Column: end}, " ")
+		}
+		e.Cursor.Column = end
+	}
+	e.markDirty()
+}
+
+// vimReplace overwrites count characters from the cursor with with.
+func (e *Editor) vimReplace(with string, count int) {
+	line := e.Buffer.Line(e.Cursor.Line)
+	end := e.Cursor.Column
+	for i := 0; i < count; i++ {
+		if end >= len(line) {
+			return
+		}
+		end = nextBoundary(line, end)
+	}
+	if e.ReadOnly {
+		return
+	}
+	e.Buffer.Delete(e.Cursor, Position{Line: e.Cursor.Line, Column: end})
+	text := strings.Repeat(with, count)
+	e.Buffer.Insert(e.Cursor, text)
+	e.Cursor.Column += len(text) - len(with)
+	e.markDirty()
+}
@@ -0,0 +1,25 @@
+package editor
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrVirtualDocument is returned when saving a document that has no file,
+// such as command output; Save As still writes it to one.
+var ErrVirtualDocument = errors.New("virtual documents have no file to save to")
+
+// LoadVirtual shows content as the read-only document uri. name is what
+// the content would be called as a file and picks its highlighting.
+func (e *Editor) LoadVirtual(uri, name, content string) {
+	e.FilePath = uri
+	e.SetBuffer(newBufferForSize(len(content)))
+	e.SetContent(content)
+	e.Indent = DetectIndent(name, content)
+	e.savedHash = e.Buffer.Hash()
+	e.LineEnding, e.savedEnding = LF, LF
+	e.Dirty = false
+	e.diskModTime, e.diskSize = time.Time{}, 0
+	e.ReadOnly = true
+	e.SetFilePath(name)
+}
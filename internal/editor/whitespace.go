@@ -0,0 +1,80 @@
+package editor
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/termcaps"
+)
+
+const (
+	spaceMark = "·"
+	tabMark   = "→"
+	guideMark = "│"
+)
+
+var whitespaceStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#45475a"))
+
+// guideStep is how many cells apart indentation guides are drawn.
+func (e *Editor) guideStep() int {
+	if e.Indent.UseTabs || e.Indent.Size <= 0 {
+		return e.tabWidth()
+	}
+	return e.Indent.Size
+}
+
+// drawText expands the tabs in text, drawn from cell, and renders it in
+// style, if any. With ShowWhitespace its spaces and tabs are marked in a
+// dim style instead, with guides at each indentation level of the first
+// indent cells of the line.
+func (e *Editor) drawText(text string, cell, indent int, style *lipgloss.Style) (string, int) {
+	render := func(s string) string {
+		if style == nil {
+			return s
+		}
+		return style.Render(s)
+	}
+	if !e.ShowWhitespace {
+		s := e.expandTabs(text, cell)
+		return render(s), cell + displayWidth(s)
+	}
+
+	var sb strings.Builder
+	for text != "" {
+		n := strings.IndexAny(text, " \t")
+		if n < 0 {
+			n = len(text)
+		}
+		if n > 0 {
+			sb.WriteString(render(text[:n]))
+			cell += displayWidth(text[:n])
+			text = text[n:]
+			continue
+		}
+		n = len(text) - len(strings.TrimLeft(text, " \t"))
+		var marks strings.Builder
+		for _, c := range text[:n] {
+			end := cell + 1
+			if c == '\t' {
+				end = e.tabStop(cell)
+			}
+			for ; cell < end; cell++ {
+				switch {
+				case cell < indent && cell%e.guideStep() == 0:
+					marks.WriteString(termcaps.Symbol(guideMark, "|"))
+				case c == ' ':
+					marks.WriteString(termcaps.Symbol(spaceMark, "."))
+				case cell == end-1:
+					// The arrow ends the tab, so a guide can start it.
+					marks.WriteString(termcaps.Symbol(tabMark, ">"))
+				default:
+					marks.WriteByte(' ')
+				}
+			}
+		}
+		sb.WriteString(whitespaceStyle.Render(marks.String()))
+		text = text[n:]
+	}
+	return sb.String(), cell
+}
@@ -0,0 +1,252 @@
+package editor
+
+import (
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type charClass int
+
+const (
+	classSpace charClass = iota
+	classWord
+	classPunct
+)
+
+// defaultWordSeparators are the characters that end a word besides
+// whitespace. Everything else, including '_' and non-ASCII text, is part
+// of words.
+const defaultWordSeparators = "`~!@#$%^&*()-=+[{]}\\|;:'\",.<>/?"
+
+// languageWordSeparators are the defaults for languages whose names use
+// characters that separate words elsewhere.
+var languageWordSeparators = map[string]string{
+	".css":  strings.ReplaceAll(defaultWordSeparators, "-", ""),
+	".scss": strings.ReplaceAll(defaultWordSeparators, "-", ""),
+	".less": strings.ReplaceAll(defaultWordSeparators, "-", ""),
+	".html": strings.ReplaceAll(defaultWordSeparators, "-", ""),
+	".lisp": strings.ReplaceAll(defaultWordSeparators, "-", ""),
+	".clj":  strings.ReplaceAll(defaultWordSeparators, "-", ""),
+	".php":  strings.ReplaceAll(defaultWordSeparators, "$", ""),
+	".sh":   strings.ReplaceAll(defaultWordSeparators, "$", ""),
+}
+
+// wordSeparators returns the separators for the current file: those set
+// for its extension in WordSeparators, then those set for "*", then the
+// language's defaults.
+func (e *Editor) wordSeparators() string {
+	ext := strings.ToLower(filepath.Ext(e.FilePath))
+	if seps, ok := e.WordSeparators[ext]; ok {
+		return seps
+	}
+	if seps, ok := e.WordSeparators["*"]; ok {
+		return seps
+	}
+	if seps, ok := languageWordSeparators[ext]; ok {
+		return seps
+	}
+	return defaultWordSeparators
+}
+
+func (e *Editor) classOf(b byte) charClass {
+	switch {
+	case b == ' ' || b == '\t':
+		return classSpace
+	case b < 0x80 && strings.IndexByte(e.wordSeparators(), b) >= 0:
+		return classPunct
+	}
+	return classWord
+}
+
+// handleWordKey moves or deletes by word at every caret, and alt+arrows
+// move by the parts of camelCase and snake_case words. Terminals report
+// ctrl+backspace as ctrl+w or alt+backspace and ctrl+delete as
+// alt+delete, so those are taken too.
+func (e *Editor) handleWordKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "ctrl+left":
+		e.moveByWord(e.wordLeft, false)
+	case "ctrl+right":
+		e.moveByWord(e.wordRight, false)
+	case "ctrl+shift+left":
+		e.moveByWord(e.wordLeft, true)
+	case "ctrl+shift+right":
+		e.moveByWord(e.wordRight, true)
+	case "alt+left":
+		e.moveByWord(e.subwordLeft, false)
+	case "alt+right":
+		e.moveByWord(e.subwordRight, false)
+	case "alt+shift+left":
+		e.moveByWord(e.subwordLeft, true)
+	case "alt+shift+right":
+		e.moveByWord(e.subwordRight, true)
+	case "ctrl+w", "alt+backspace":
+		e.deleteWord(e.wordLeft)
+	case "alt+delete":
+		e.deleteWord(e.wordRight)
+	default:
+		return false
+	}
+	return true
+}
+
+// wordLeft returns the start of the word before p, skipping whitespace and
+// stepping to the end of the previous line from column zero.
+func (e *Editor) wordLeft(p Position) Position {
+	if p.Column == 0 {
+		if p.Line == 0 {
+			return p
+		}
+		return Position{Line: p.Line - 1, Column: e.Buffer.LineLength(p.Line - 1)}
+	}
+	line := e.Buffer.Line(p.Line)
+	col := min(p.Column, len(line))
+	for col > 0 && e.classOf(line[col-1]) == classSpace {
+		col--
+	}
+	if col > 0 {
+		class := e.classOf(line[col-1])
+		for col > 0 && e.classOf(line[col-1]) == class {
+			col--
+		}
+	}
+	return Position{Line: p.Line, Column: col}
+}
+
+// wordRight returns the end of the word after p.
+func (e *Editor) wordRight(p Position) Position {
+	line := e.Buffer.Line(p.Line)
+	if p.Column >= len(line) {
+		if p.Line >= e.Buffer.LineCount()-1 {
+			return p
+		}
+		return Position{Line: p.Line + 1, Column: 0}
+	}
+	col := p.Column
+	for col < len(line) && e.classOf(line[col]) == classSpace {
+		col++
+	}
+	if col < len(line) {
+		class := e.classOf(line[col])
+		for col < len(line) && e.classOf(line[col]) == class {
+			col++
+		}
+	}
+	return Position{Line: p.Line, Column: col}
+}
+
+func isUpper(b byte) bool { return b >= 'A' && b <= 'Z' }
+
+// isLower counts digits and non-ASCII bytes as lower case, so they stay
+// with the hump before them.
+func isLower(b byte) bool { return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b >= 0x80 }
+
+// subwordLeft is wordLeft stopping also at the start of each part of a
+// camelCase or snake_case word: fooBar, HTTPServer and foo_bar each have
+// two.
+func (e *Editor) subwordLeft(p Position) Position {
+	line := e.Buffer.Line(p.Line)
+	col := min(p.Column, len(line))
+	for col > 0 && e.classOf(line[col-1]) == classSpace {
+		col--
+	}
+	if col == 0 || e.classOf(line[col-1]) != classWord {
+		return e.wordLeft(p)
+	}
+	for col > 0 && line[col-1] == '_' {
+		col--
+	}
+	start := col
+	for col > 0 && e.classOf(line[col-1]) == classWord && isLower(line[col-1]) {
+		col--
+	}
+	switch {
+	case col < start && col > 0 && isUpper(line[col-1]):
+		col--
+	case col == start:
+		for col > 0 && isUpper(line[col-1]) {
+			col--
+		}
+	}
+	if col == start {
+		return e.wordLeft(p)
+	}
+	return Position{Line: p.Line, Column: col}
+}
+
+// subwordRight is wordRight stopping also at the end of each part of a
+// camelCase or snake_case word.
+func (e *Editor) subwordRight(p Position) Position {
+	line := e.Buffer.Line(p.Line)
+	col := p.Column
+	for col < len(line) && e.classOf(line[col]) == classSpace {
+		col++
+	}
+	if col >= len(line) || e.classOf(line[col]) != classWord {
+		return e.wordRight(p)
+	}
+	for col < len(line) && line[col] == '_' {
+		col++
+	}
+	start := col
+	for col < len(line) && isUpper(line[col]) {
+		col++
+	}
+	// A run of capitals before a lower-case letter ends with the first
+	// capital of the next part, as in HTTPServer.
+	if col-start > 1 && col < len(line) && isLower(line[col]) && line[col] < 0x80 {
+		col--
+	}
+	if col-start <= 1 {
+		for col < len(line) && e.classOf(line[col]) == classWord && isLower(line[col]) {
+			col++
+		}
+	}
+	if col == start {
+		return e.wordRight(p)
+	}
+	return Position{Line: p.Line, Column: col}
+}
+
+func (e *Editor) moveByWord(step func(Position) Position, extend bool) {
+	move := func(c caret) caret {
+		if !extend {
+			return caret{pos: step(c.pos)}
+		}
+		if c.sel.IsEmpty() {
+			c.sel.Start = c.pos
+		}
+		c.pos = step(c.pos)
+		c.sel.End = c.pos
+		return c
+	}
+	for i := range e.carets {
+		e.carets[i] = move(e.carets[i])
+	}
+	if extend {
+		e.anchorSelection()
+		e.selectTo(step(e.Cursor))
+	} else {
+		e.Cursor = step(e.Cursor)
+		e.clearSelection()
+	}
+	e.dedupeCarets()
+}
+
+// deleteWord deletes from each caret to where step leads, or the caret's
+// selection when it has one.
+func (e *Editor) deleteWord(step func(Position) Position) {
+	e.editAtCarets(func(_ int, c caret) Position {
+		if !c.sel.IsEmpty() {
+			return e.deleteAtCaret(c, false)
+		}
+		from, to := step(c.pos), c.pos
+		if positionBefore(to, from) {
+			from, to = to, from
+		}
+		e.Buffer.Delete(from, to)
+		return from
+	})
+}
@@ -0,0 +1,174 @@
+package editor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// ToggleWrap switches between soft wrapping long lines at the viewport
+// width and scrolling horizontally.
+func (e *Editor) ToggleWrap() {
+	e.WrapLines = !e.WrapLines
+	e.Viewport.X = 0
+	e.revealCursor()
+}
+
+func (e *Editor) wrapWidth() int {
+	return max(e.Viewport.Width, 1)
+}
+
+// wrapBreaks returns the byte offsets at which the visual rows of line
+// start. Rows break between grapheme clusters, so a wide character never
+// straddles two. A line filling its last row exactly gets an empty one
+// after it so the cursor has somewhere to go at its end.
+func (e *Editor) wrapBreaks(line int) []int {
+	text, width := e.Buffer.Line(line), e.wrapWidth()
+	breaks := []int{0}
+	offset, cell, rowWidth, state := 0, 0, 0, -1
+	for rest := text; rest != ""; {
+		cluster, next, w, s := uniseg.FirstGraphemeClusterInString(rest, state)
+		if cluster == "\t" {
+			w = e.tabStop(cell) - cell
+		}
+		if rowWidth+w > width && rowWidth > 0 {
+			breaks = append(breaks, offset)
+			rowWidth = 0
+		}
+		rowWidth += w
+		cell += w
+		offset += len(cluster)
+		rest, state = next, s
+	}
+	if rowWidth >= width {
+		breaks = append(breaks, len(text))
+	}
+	return breaks
+}
+
+func (e *Editor) wrapRows(line int) int {
+	return len(e.wrapBreaks(line))
+}
+
+// wrapRow returns the row of breaks that col is drawn in.
+func wrapRow(breaks []int, col int) int {
+	return sort.Search(len(breaks), func(i int) bool { return breaks[i] > col }) - 1
+}
+
+// rowColumn returns the column x cells into row of line, staying before
+// the start of the next row.
+func (e *Editor) rowColumn(line int, breaks []int, row, x int) int {
+	text := e.Buffer.Line(line)
+	col := e.byteColumn(text, e.displayColumn(text, breaks[row])+x)
+	if row+1 < len(breaks) && col >= breaks[row+1] {
+		col = prevBoundary(text, breaks[row+1])
+	}
+	return col
+}
+
+// renderWrapped writes the visual rows from the top of the viewport and
+// returns how many it wrote.
+func (e *Editor) renderWrapped(sb *strings.Builder) int {
+	rows := 0
+	e.showFoldHeader()
+	for line := e.Viewport.Y; line < e.Buffer.LineCount() && rows < e.Height; line = e.nextVisibleLine(line) {
+		for _, start := range e.wrapBreaks(line) {
+			if rows == e.Height {
+				break
+			}
+			if rows > 0 {
+				sb.WriteString("\n")
+			}
+			e.renderLine(sb, line, e.cell(line, start))
+			rows++
+		}
+	}
+	return rows
+}
+
+// cursorInRow reports whether the cursor is drawn in the row of its line
+// starting at cell startCol.
+func (e *Editor) cursorInRow(startCol int) bool {
+	if !e.WrapLines {
+		return true
+	}
+	breaks := e.wrapBreaks(e.Cursor.Line)
+	return e.cell(e.Cursor.Line, breaks[wrapRow(breaks, e.Cursor.Column)]) == startCol
+}
+
+// positionAt maps a point in the text area to a buffer line and column.
+// The line is not clamped to the buffer, and the column is negative left
+// of the text.
+func (e *Editor) positionAt(x, y int) (line, col int) {
+	x -= e.lineNumWidth()
+	if x < 0 {
+		line, _ = e.positionAt(e.lineNumWidth(), y)
+		return line, x
+	}
+	if !e.WrapLines {
+		line = e.Viewport.Y
+		for ; y > 0; y-- {
+			line = e.nextVisibleLine(line)
+		}
+		return line, e.byteColumn(e.Buffer.Line(line), x+e.Viewport.X)
+	}
+	line = e.Viewport.Y
+	for ; line < e.Buffer.LineCount(); line = e.nextVisibleLine(line) {
+		breaks := e.wrapBreaks(line)
+		if y < len(breaks) {
+			return line, e.rowColumn(line, breaks, y, x)
+		}
+		y -= len(breaks)
+	}
+	return line + y, x
+}
+
+// moveVisualRow moves the cursor up or down by dy wrapped rows, keeping its
+// cell within the row.
+func (e *Editor) moveVisualRow(dy int) {
+	for ; dy != 0; dy -= sign(dy) {
+		breaks := e.wrapBreaks(e.Cursor.Line)
+		row := wrapRow(breaks, e.Cursor.Column)
+		x := e.cell(e.Cursor.Line, e.Cursor.Column) - e.cell(e.Cursor.Line, breaks[row])
+		switch next := row + sign(dy); {
+		case next >= 0 && next < len(breaks):
+			e.Cursor.Column = e.rowColumn(e.Cursor.Line, breaks, next, x)
+		case dy < 0 && e.prevVisibleLine(e.Cursor.Line) >= 0:
+			e.Cursor.Line = e.prevVisibleLine(e.Cursor.Line)
+			breaks = e.wrapBreaks(e.Cursor.Line)
+			e.Cursor.Column = e.rowColumn(e.Cursor.Line, breaks, len(breaks)-1, x)
+		case dy > 0 && e.nextVisibleLine(e.Cursor.Line) < e.Buffer.LineCount():
+			e.Cursor.Line = e.nextVisibleLine(e.Cursor.Line)
+			e.Cursor.Column = e.rowColumn(e.Cursor.Line, e.wrapBreaks(e.Cursor.Line), 0, x)
+		}
+	}
+}
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
+
+// fitWrappedView scrolls so the cursor's row, not just its line, is on
+// screen while wrapping.
+func (e *Editor) fitWrappedView() {
+	if !e.WrapLines {
+		return
+	}
+	e.Viewport.X = 0
+	if e.Cursor.Line < e.Viewport.Y {
+		e.Viewport.Y = e.Cursor.Line
+		return
+	}
+	above := wrapRow(e.wrapBreaks(e.Cursor.Line), e.Cursor.Column)
+	for line := e.Viewport.Y; line < e.Cursor.Line; line = e.nextVisibleLine(line) {
+		above += e.wrapRows(line)
+	}
+	for e.Viewport.Y < e.Cursor.Line && above >= e.Viewport.Height {
+		above -= e.wrapRows(e.Viewport.Y)
+		e.Viewport.Y = e.nextVisibleLine(e.Viewport.Y)
+	}
+}
@@ -0,0 +1,95 @@
+package filetree
+
+import (
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DeleteMsg asks for Path to be moved to the trash.
+type DeleteMsg struct {
+	Path string
+}
+
+// RenameMsg asks for From to be renamed, or moved when To is in another
+// directory.
+type RenameMsg struct {
+	From string
+	To   string
+}
+
+// renameState is the name being typed over the selected item. The new
+// name is relative to the item's directory, so it may move the item by
+// naming another directory.
+type renameState struct {
+	active bool
+	path   string
+	input  []rune
+}
+
+// CapturesInput reports whether a name is being typed, so every key
+// belongs to the tree.
+func (ft *FileTree) CapturesInput() bool {
+	return ft.rename.active
+}
+
+func (ft *FileTree) selectedItem() *displayItem {
+	if ft.SelectedIndex < 0 || ft.SelectedIndex >= len(ft.flattened) {
+		return nil
+	}
+	return ft.flattened[ft.SelectedIndex]
+}
+
+// HandleFileKey acts on the selected item, reporting whether msg was a key
+// for doing so. Keys reach the tree alongside the editor, so only the
+// caller knows whether the tree was the panel meant.
+func (ft *FileTree) HandleFileKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	item := ft.selectedItem()
+	if item == nil {
+		return nil, false
+	}
+	switch msg.String() {
+	case "delete", "d":
+		path := item.Path
+		return func() tea.Msg { return DeleteMsg{Path: path} }, true
+	case "f2", "r":
+		ft.rename = renameState{active: true, path: item.Path, input: []rune(item.Node.Name)}
+		return nil, true
+	}
+	return nil, false
+}
+
+func (ft *FileTree) handleRenameKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		ft.rename = renameState{}
+	case tea.KeyEnter:
+		from, name := ft.rename.path, strings.TrimSpace(string(ft.rename.input))
+		ft.rename = renameState{}
+		if name == "" {
+			return nil
+		}
+		to := filepath.Join(filepath.Dir(from), name)
+		if to == from {
+			return nil
+		}
+		return func() tea.Msg { return RenameMsg{From: from, To: to} }
+	case tea.KeyBackspace:
+		if len(ft.rename.input) > 0 {
+			ft.rename.input = ft.rename.input[:len(ft.rename.input)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		ft.rename.input = append(ft.rename.input, msg.Runes...)
+	}
+	return nil
+}
+
+// itemName is the name drawn for item, or the name being typed over it.
+func (ft *FileTree) itemName(item *displayItem) string {
+	if ft.rename.active && item.Path == ft.rename.path {
+		return lipgloss.NewStyle().Underline(true).Render(string(ft.rename.input) + "▏")
+	}
+	return item.Node.Name
+}
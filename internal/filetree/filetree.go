@@ -9,6 +9,9 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+	"tron/internal/termcaps"
 )
 
 type FileTree struct {
@@ -20,10 +23,14 @@ type FileTree struct {
 	Width         int
 	Height        int
 	ShowHidden    bool
+	Accessible    bool
+	IconTheme     IconTheme
 	focused       bool
 	flattened     []*displayItem
 	lastClickTime int64
 	lastClickY    int
+	loading       bool
+	rename        renameState
 }
 
 type displayItem struct {
@@ -38,17 +45,36 @@ func New(rootPath string) *FileTree {
 		Expanded: make(map[string]bool),
 		ShowHidden: false,
 		focused:  true,
+		loading:  true,
 	}
-	ft.Refresh()
 	return ft
 }
 
+type treeLoadedMsg struct {
+	root  string
+	nodes []*Node
+}
+
+// Load reads the tree in the background so a huge root directory does not
+// hold up the first frame.
+func (ft *FileTree) Load() tea.Cmd {
+	root, showHidden := ft.RootPath, ft.ShowHidden
+	expanded := make(map[string]bool, len(ft.Expanded))
+	for path, open := range ft.Expanded {
+		expanded[path] = open
+	}
+	return func() tea.Msg {
+		return treeLoadedMsg{root: root, nodes: readDir(root, expanded, showHidden)}
+	}
+}
+
 func (ft *FileTree) Refresh() {
-	ft.Nodes = ft.readDir(ft.RootPath)
+	ft.Nodes = readDir(ft.RootPath, ft.Expanded, ft.ShowHidden)
+	ft.loading = false
 	ft.flattenNodes()
 }
 
-func (ft *FileTree) readDir(path string) []*Node {
+func readDir(path string, expanded map[string]bool, showHidden bool) []*Node {
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		return nil
@@ -57,7 +83,7 @@ func (ft *FileTree) readDir(path string) []*Node {
 	var nodes []*Node
 	for _, entry := range entries {
 		name := entry.Name()
-		if !ft.ShowHidden && strings.HasPrefix(name, ".") {
+		if !showHidden && strings.HasPrefix(name, ".") {
 			continue
 		}
 
@@ -68,11 +94,11 @@ func (ft *FileTree) readDir(path string) []*Node {
 			Name:     name,
 			Path:     fullPath,
 			IsDir:    isDir,
-			Expanded: ft.Expanded[fullPath],
+			Expanded: expanded[fullPath],
 		}
 
 		if isDir && node.Expanded {
-			node.Children = ft.readDir(fullPath)
+			node.Children = readDir(fullPath, expanded, showHidden)
 		}
 
 		nodes = append(nodes, node)
@@ -164,6 +190,13 @@ func (ft *FileTree) Update(msg tea.Msg) tea.Cmd {
 	case FileTreeRefreshMsg:
 		ft.Refresh()
 		return nil
+	case treeLoadedMsg:
+		if msg.root == ft.RootPath && ft.loading {
+			ft.Nodes = msg.nodes
+			ft.loading = false
+			ft.flattenNodes()
+		}
+		return nil
 	}
 	return nil
 }
@@ -172,6 +205,9 @@ func (ft *FileTree) handleKey(msg tea.KeyMsg) tea.Cmd {
 	if !ft.focused {
 		return nil
 	}
+	if ft.rename.active {
+		return ft.handleRenameKey(msg)
+	}
 
 	switch msg.Type {
 	case tea.KeyUp:
@@ -290,6 +326,9 @@ func (ft *FileTree) View() string {
 	}
 
 	var lines []string
+	if ft.loading {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Width(ft.Width).Render(" " + i18n.T("filetree.loading")))
+	}
 	endIdx := ft.ScrollOffset + ft.Height
 	if endIdx > len(ft.flattened) {
 		endIdx = len(ft.flattened)
@@ -322,18 +361,25 @@ func (ft *FileTree) renderItem(item *displayItem, selected bool) string {
 
 	if item.Node.IsDir {
 		if item.Node.Expanded {
-			sb.WriteString("▾ ")
+			sb.WriteString(termcaps.Symbol("▾", "v") + " ")
 		} else {
-			sb.WriteString("▸ ")
+			sb.WriteString(termcaps.Symbol("▸", ">") + " ")
 		}
 	} else {
 		sb.WriteString(ft.fileIcon(item.Node.Name))
 		sb.WriteString(" ")
 	}
 
-	sb.WriteString(item.Node.Name)
+	sb.WriteString(ft.itemName(item))
 
 	result := sb.String()
+	if ft.Accessible {
+		if selected {
+			result = "> " + result
+		} else {
+			result = "  " + result
+		}
+	}
 
 	if selected && ft.focused {
 		style := lipgloss.NewStyle().
@@ -354,54 +400,6 @@ func (ft *FileTree) renderItem(item *displayItem, selected bool) string {
 	return result
 }
 
-func (ft *FileTree) fileIcon(name string) string {
-	ext := strings.ToLower(filepath.Ext(name))
-	switch ext {
-	case ".go":
-		return ""
-	case ".js", ".jsx":
-		return ""
-	case ".ts", ".tsx":
-		return ""
-	case ".py":
-		return ""
-	case ".rs":
-		return ""
-	case ".rb":
-		return ""
-	case ".java":
-		return ""
-	case ".c", ".h":
-		return ""
-	case ".cpp", ".hpp":
-		return ""
-	case ".md":
-		return ""
-	case ".json":
-		return ""
-	case ".yaml", ".yml":
-		return ""
-	case ".toml":
-		return ""
-	case ".sh":
-		return ""
-	case ".txt":
-		return ""
-	case ".css":
-		return ""
-	case ".html":
-		return ""
-	case ".sql":
-		return ""
-	case ".png", ".jpg", ".jpeg", ".gif", ".svg":
-		return ""
-	case ".zip", ".tar", ".gz":
-		return ""
-	default:
-		return ""
-	}
-}
-
 func (ft *FileTree) ToggleHidden() {
 	ft.ShowHidden = !ft.ShowHidden
 	ft.Refresh()
@@ -0,0 +1,258 @@
+package filetree
+
+import (
+	"path/filepath"
+	"strings"
+
+	"tron/internal/termcaps"
+)
+
+// IconTheme selects the glyphs drawn before file names.
+type IconTheme string
+
+const (
+	// IconsAuto uses Nerd Font icons where a Nerd Font was detected and
+	// ASCII letters elsewhere.
+	IconsAuto     IconTheme = ""
+	IconsNerdFont IconTheme = "nerd-font"
+	IconsEmoji    IconTheme = "emoji"
+	IconsASCII    IconTheme = "ascii"
+)
+
+// IconThemes lists the themes that can be chosen.
+var IconThemes = []IconTheme{IconsNerdFont, IconsEmoji, IconsASCII}
+
+// fileIcon is one kind of file in each theme. Nerd Font glyphs are in the
+// private use area, so they are written as escapes.
+type fileIcon struct {
+	nerd  string
+	emoji string
+	ascii string
+}
+
+var (
+	textIcon     = fileIcon{"\ue612", "📄", "-"}
+	goIcon       = fileIcon{"\ue627", "🐹", "G"}
+	jsIcon       = fileIcon{"\ue60c", "📒", "J"}
+	tsIcon       = fileIcon{"\ue628", "📘", "T"}
+	reactIcon    = fileIcon{"\ue7ba", "🌀", "X"}
+	pythonIcon   = fileIcon{"\ue606", "🐍", "P"}
+	rustIcon     = fileIcon{"\ue7a8", "🦀", "R"}
+	rubyIcon     = fileIcon{"\ue791", "💎", "r"}
+	javaIcon     = fileIcon{"\ue738", "☕", "j"}
+	kotlinIcon   = fileIcon{"\ue634", "🟣", "K"}
+	cIcon        = fileIcon{"\ue61e", "🔩", "C"}
+	cppIcon      = fileIcon{"\ue61d", "🔩", "C"}
+	csharpIcon   = fileIcon{"\ue648", "🟪", "#"}
+	swiftIcon    = fileIcon{"\ue755", "🐦", "S"}
+	phpIcon      = fileIcon{"\ue73d", "🐘", "p"}
+	luaIcon      = fileIcon{"\ue620", "🌙", "L"}
+	haskellIcon  = fileIcon{"\ue61f", "🎓", "H"}
+	elixirIcon   = fileIcon{"\ue62d", "💧", "E"}
+	shellIcon    = fileIcon{"\ue795", "🐚", "$"}
+	vimIcon      = fileIcon{"\ue62b", "📗", "V"}
+	markdownIcon = fileIcon{"\ue609", "📝", "M"}
+	jsonIcon     = fileIcon{"\ue60b", "📋", "{"}
+	configIcon   = fileIcon{"\ue615", "🔧", "="}
+	xmlIcon      = fileIcon{"\ue619", "📰", "<"}
+	htmlIcon     = fileIcon{"\ue60e", "🌐", "<"}
+	cssIcon      = fileIcon{"\ue614", "🎨", "~"}
+	sassIcon     = fileIcon{"\ue603", "🎨", "~"}
+	sqlIcon      = fileIcon{"\ue706", "💾", "Q"}
+	csvIcon      = fileIcon{"\uf0ce", "📊", ","}
+	imageIcon    = fileIcon{"\ue60d", "📷", "I"}
+	audioIcon    = fileIcon{"\uf001", "🎵", "A"}
+	videoIcon    = fileIcon{"\uf03d", "🎬", "v"}
+	pdfIcon      = fileIcon{"\uf1c1", "📕", "D"}
+	fontIcon     = fileIcon{"\uf031", "🔤", "F"}
+	archiveIcon  = fileIcon{"\uf410", "📦", "Z"}
+	binaryIcon   = fileIcon{"\uf471", "🔢", "B"}
+	lockIcon     = fileIcon{"\uf023", "🔒", "!"}
+	dockerIcon   = fileIcon{"\ue7b0", "🐳", "D"}
+	makeIcon     = fileIcon{"\ue779", "🔨", "m"}
+	licenseIcon  = fileIcon{"\ue60a", "📜", "L"}
+	gitIcon      = fileIcon{"\ue702", "🔀", "g"}
+	npmIcon      = fileIcon{"\ue71e", "📦", "n"}
+	readmeIcon   = fileIcon{"\uf405", "📖", "?"}
+)
+
+// iconsByName covers files recognised by their whole name, lower-cased.
+var iconsByName = map[string]fileIcon{
+	"dockerfile":         dockerIcon,
+	"containerfile":      dockerIcon,
+	".dockerignore":      dockerIcon,
+	"docker-compose.yml": dockerIcon,
+	"compose.yaml":       dockerIcon,
+	"makefile":           makeIcon,
+	"gnumakefile":        makeIcon,
+	"license":            licenseIcon,
+	"license.md":         licenseIcon,
+	"license.txt":        licenseIcon,
+	"licence":            licenseIcon,
+	"copying":            licenseIcon,
+	"readme":             readmeIcon,
+	"readme.md":          readmeIcon,
+	".gitignore":         gitIcon,
+	".gitattributes":     gitIcon,
+	".gitmodules":        gitIcon,
+	"go.mod":             goIcon,
+	"go.sum":             goIcon,
+	"go.work":            goIcon,
+	"package.json":       npmIcon,
+	"package-lock.json":  npmIcon,
+	".npmrc":             npmIcon,
+	"cargo.toml":         rustIcon,
+	"cargo.lock":         rustIcon,
+	"gemfile":            rubyIcon,
+	"rakefile":           rubyIcon,
+	"requirements.txt":   pythonIcon,
+	"pyproject.toml":     pythonIcon,
+	".env":               configIcon,
+	".editorconfig":      configIcon,
+	".vimrc":             vimIcon,
+	".bashrc":            shellIcon,
+	".zshrc":             shellIcon,
+}
+
+var iconsByExt = map[string]fileIcon{
+	".go":         goIcon,
+	".js":         jsIcon,
+	".mjs":        jsIcon,
+	".cjs":        jsIcon,
+	".jsx":        reactIcon,
+	".ts":         tsIcon,
+	".mts":        tsIcon,
+	".tsx":        reactIcon,
+	".py":         pythonIcon,
+	".pyi":        pythonIcon,
+	".ipynb":      pythonIcon,
+	".rs":         rustIcon,
+	".rb":         rubyIcon,
+	".java":       javaIcon,
+	".kt":         kotlinIcon,
+	".kts":        kotlinIcon,
+	".c":          cIcon,
+	".h":          cIcon,
+	".cpp":        cppIcon,
+	".cc":         cppIcon,
+	".cxx":        cppIcon,
+	".hpp":        cppIcon,
+	".hh":         cppIcon,
+	".cs":         csharpIcon,
+	".swift":      swiftIcon,
+	".php":        phpIcon,
+	".lua":        luaIcon,
+	".hs":         haskellIcon,
+	".ex":         elixirIcon,
+	".exs":        elixirIcon,
+	".sh":         shellIcon,
+	".bash":       shellIcon,
+	".zsh":        shellIcon,
+	".fish":       shellIcon,
+	".vim":        vimIcon,
+	".md":         markdownIcon,
+	".markdown":   markdownIcon,
+	".txt":        textIcon,
+	".json":       jsonIcon,
+	".jsonc":      jsonIcon,
+	".yaml":       configIcon,
+	".yml":        configIcon,
+	".toml":       configIcon,
+	".ini":        configIcon,
+	".cfg":        configIcon,
+	".conf":       configIcon,
+	".xml":        xmlIcon,
+	".html":       htmlIcon,
+	".htm":        htmlIcon,
+	".css":        cssIcon,
+	".scss":       sassIcon,
+	".sass":       sassIcon,
+	".sql":        sqlIcon,
+	".csv":        csvIcon,
+	".tsv":        csvIcon,
+	".png":        imageIcon,
+	".jpg":        imageIcon,
+	".jpeg":       imageIcon,
+	".gif":        imageIcon,
+	".svg":        imageIcon,
+	".webp":       imageIcon,
+	".ico":        imageIcon,
+	".mp3":        audioIcon,
+	".wav":        audioIcon,
+	".flac":       audioIcon,
+	".ogg":        audioIcon,
+	".mp4":        videoIcon,
+	".mkv":        videoIcon,
+	".mov":        videoIcon,
+	".webm":       videoIcon,
+	".pdf":        pdfIcon,
+	".ttf":        fontIcon,
+	".otf":        fontIcon,
+	".woff":       fontIcon,
+	".woff2":      fontIcon,
+	".zip":        archiveIcon,
+	".tar":        archiveIcon,
+	".gz":         archiveIcon,
+	".tgz":        archiveIcon,
+	".bz2":        archiveIcon,
+	".xz":         archiveIcon,
+	".7z":         archiveIcon,
+	".exe":        binaryIcon,
+	".dll":        binaryIcon,
+	".so":         binaryIcon,
+	".dylib":      binaryIcon,
+	".o":          binaryIcon,
+	".wasm":       binaryIcon,
+	".lock":       lockIcon,
+	".mk":         makeIcon,
+	".dockerfile": dockerIcon,
+	".patch":      gitIcon,
+	".diff":       gitIcon,
+}
+
+// ParseIconTheme reports whether name is one of IconThemes or empty for
+// the automatic choice.
+func ParseIconTheme(name string) (IconTheme, bool) {
+	theme := IconTheme(strings.ToLower(name))
+	if theme == IconsAuto {
+		return theme, true
+	}
+	for _, t := range IconThemes {
+		if t == theme {
+			return t, true
+		}
+	}
+	return IconsAuto, false
+}
+
+func iconFor(name string) fileIcon {
+	lower := strings.ToLower(name)
+	if icon, ok := iconsByName[lower]; ok {
+		return icon
+	}
+	if strings.HasPrefix(lower, "dockerfile.") {
+		return dockerIcon
+	}
+	if icon, ok := iconsByExt[filepath.Ext(lower)]; ok {
+		return icon
+	}
+	return textIcon
+}
+
+func (ft *FileTree) fileIcon(name string) string {
+	icon := iconFor(name)
+	theme := ft.IconTheme
+	if theme == IconsAuto {
+		theme = IconsASCII
+		if termcaps.Current().NerdFont {
+			theme = IconsNerdFont
+		}
+	}
+	switch theme {
+	case IconsNerdFont:
+		return termcaps.Symbol(icon.nerd, icon.ascii)
+	case IconsEmoji:
+		return termcaps.Symbol(icon.emoji, icon.ascii)
+	}
+	return icon.ascii
+}
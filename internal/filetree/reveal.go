@@ -0,0 +1,33 @@
+package filetree
+
+import (
+	"path/filepath"
+	"strings"
+
+	"tron/pkg/pathutil"
+)
+
+// Reveal expands the directories down to path and selects it. Paths
+// outside the root are left alone.
+func (ft *FileTree) Reveal(path string) {
+	root := pathutil.Canonical(ft.RootPath)
+	rel, err := filepath.Rel(root, pathutil.Canonical(path))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return
+	}
+	dir := ft.RootPath
+	parts := strings.Split(rel, string(filepath.Separator))
+	for _, part := range parts[:len(parts)-1] {
+		dir = filepath.Join(dir, part)
+		ft.Expanded[dir] = true
+	}
+	ft.Refresh()
+	target := filepath.Join(ft.RootPath, rel)
+	for i, item := range ft.flattened {
+		if item.Path == target {
+			ft.SelectedIndex = i
+			ft.ensureSelectedVisible()
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AskpassEnv names the socket of the editor waiting for credentials. When
+// git runs the tron binary as its askpass program this variable is set and
+// main hands over to AskpassMain.
+const AskpassEnv = "TRON_ASKPASS"
+
+// Prompt is a credential question from git or ssh. Exactly one of Reply or
+// Cancel must be called.
+type Prompt struct {
+	Text   string
+	Secret bool
+	reply  chan askpassReply
+}
+
+type askpassReply struct {
+	text string
+	ok   bool
+}
+
+func (p Prompt) Reply(answer string) {
+	p.reply <- askpassReply{text: answer, ok: true}
+}
+
+func (p Prompt) Cancel() {
+	p.reply <- askpassReply{}
+}
+
+// Askpass listens on a private unix socket for the prompts of one remote
+// operation.
+type Askpass struct {
+	dir     string
+	ln      net.Listener
+	prompts chan Prompt
+	done    chan struct{}
+}
+
+func NewAskpass() (*Askpass, error) {
+	dir, err := os.MkdirTemp("", "tron-askpass-")
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", filepath.Join(dir, "socket"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	a := &Askpass{dir: dir, ln: ln, prompts: make(chan Prompt), done: make(chan struct{})}
+	go a.accept()
+	return a, nil
+}
+
+// Env points git and ssh at the running executable as their askpass
+// program.
+func (a *Askpass) Env() []string {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+	return []string{
+		"GIT_ASKPASS=" + exe,
+		"SSH_ASKPASS=" + exe,
+		"SSH_ASKPASS_REQUIRE=force",
+		AskpassEnv + "=" + a.ln.Addr().String(),
+	}
+}
+
+func (a *Askpass) Prompts() <-chan Prompt {
+	return a.prompts
+}
+
+func (a *Askpass) Close() {
+	close(a.done)
+	a.ln.Close()
+	os.RemoveAll(a.dir)
+}
+
+func (a *Askpass) accept() {
+	for {
+		conn, err := a.ln.Accept()
+		if err != nil {
+			return
+		}
+		go a.serve(conn)
+	}
+}
+
+func (a *Askpass) serve(conn net.Conn) {
+	defer conn.Close()
+	text, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	text = strings.TrimSpace(text)
+	lower := strings.ToLower(text)
+	prompt := Prompt{
+		Text:   text,
+		Secret: strings.Contains(lower, "password") || strings.Contains(lower, "passphrase"),
+		reply:  make(chan askpassReply, 1),
+	}
+	select {
+	case a.prompts <- prompt:
+	case <-a.done:
+		return
+	}
+	select {
+	case r := <-prompt.reply:
+		if r.ok {
+			io.WriteString(conn, r.text+"\n")
+		}
+	case <-a.done:
+	}
+}
+
+// AskpassMain forwards the prompt in args to the editor listening on
+// socket and prints its answer for git. It returns the exit status.
+func AskpassMain(socket string, args []string) int {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer conn.Close()
+	prompt := strings.ReplaceAll(strings.Join(args, " "), "\n", " ")
+	if _, err := io.WriteString(conn, prompt+"\n"); err != nil {
+		return 1
+	}
+	answer, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return 1
+	}
+	fmt.Print(answer)
+	return 0
+}
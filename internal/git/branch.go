@@ -0,0 +1,100 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BranchStatus is the checked out branch and how far it is from its
+// upstream.
+type BranchStatus struct {
+	Name     string
+	Detached bool
+	Upstream string
+	Ahead    int
+	Behind   int
+}
+
+func (b BranchStatus) String() string {
+	s := "⎇ " + b.Name
+	if b.Ahead > 0 {
+		s += fmt.Sprintf(" ↑%d", b.Ahead)
+	}
+	if b.Behind > 0 {
+		s += fmt.Sprintf(" ↓%d", b.Behind)
+	}
+	return s
+}
+
+type Branch struct {
+	Name     string
+	Current  bool
+	Upstream string
+}
+
+func (r *Repo) Status(ctx context.Context) (BranchStatus, error) {
+	out, err := r.run(ctx, nil, "status", "--porcelain=v2", "--branch", "--untracked-files=no")
+	if err != nil {
+		return BranchStatus{}, err
+	}
+	var status BranchStatus
+	var oid string
+	for _, line := range strings.Split(string(out), "\n") {
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		key, value, _ := strings.Cut(line[2:], " ")
+		switch key {
+		case "branch.oid":
+			oid = value
+		case "branch.head":
+			status.Name = value
+		case "branch.upstream":
+			status.Upstream = value
+		case "branch.ab":
+			ahead, behind, _ := strings.Cut(value, " ")
+			status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+			status.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+		}
+	}
+	if status.Name == "(detached)" {
+		status.Detached = true
+		status.Name = oid[:min(len(oid), 8)]
+	}
+	return status, nil
+}
+
+func (r *Repo) Branches(ctx context.Context) ([]Branch, error) {
+	out, err := r.run(ctx, nil, "for-each-ref", "--sort=-committerdate", "--format=%(HEAD)%00%(refname:short)%00%(upstream:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	var branches []Branch
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 3 {
+			continue
+		}
+		branches = append(branches, Branch{Name: fields[1], Current: fields[0] == "*", Upstream: fields[2]})
+	}
+	return branches, nil
+}
+
+func (r *Repo) Checkout(ctx context.Context, branch string) error {
+	_, err := r.run(ctx, nil, "checkout", branch)
+	return err
+}
+
+// CreateBranch creates branch at HEAD and checks it out.
+func (r *Repo) CreateBranch(ctx context.Context, branch string) error {
+	_, err := r.run(ctx, nil, "checkout", "-b", branch)
+	return err
+}
+
+// DeleteBranch refuses to delete a branch that is not merged.
+func (r *Repo) DeleteBranch(ctx context.Context, branch string) error {
+	_, err := r.run(ctx, nil, "branch", "-d", branch)
+	return err
+}
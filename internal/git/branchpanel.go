@@ -0,0 +1,158 @@
+package git
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+type BranchAction int
+
+const (
+	CheckoutBranch BranchAction = iota
+	CreateBranch
+	DeleteBranch
+)
+
+type BranchMsg struct {
+	Action BranchAction
+	Name   string
+}
+
+// BranchPanel picks a branch to check out, create or delete.
+type BranchPanel struct {
+	visible  bool
+	branches []Branch
+	selected int
+	naming   bool
+	input    []rune
+}
+
+func NewBranchPanel() *BranchPanel {
+	return &BranchPanel{}
+}
+
+func (p *BranchPanel) Visible() bool {
+	return p.visible
+}
+
+func (p *BranchPanel) Open(branches []Branch) {
+	p.visible = true
+	p.naming = false
+	p.branches = branches
+	p.selected = 0
+	for i, b := range branches {
+		if b.Current {
+			p.selected = i
+		}
+	}
+}
+
+func (p *BranchPanel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+	if p.naming {
+		return p.handleName(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "esc", "alt+o":
+		p.visible = false
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.branches)-1 {
+			p.selected++
+		}
+	case "n":
+		p.naming = true
+		p.input = nil
+	case "enter":
+		return p.branchCmd(CheckoutBranch)
+	case "d":
+		return p.branchCmd(DeleteBranch)
+	}
+	return nil
+}
+
+func (p *BranchPanel) handleName(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		p.naming = false
+	case tea.KeyEnter:
+		name := strings.TrimSpace(string(p.input))
+		p.naming = false
+		if name == "" {
+			return nil
+		}
+		p.visible = false
+		return func() tea.Msg { return BranchMsg{Action: CreateBranch, Name: name} }
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case tea.KeyRunes:
+		p.input = append(p.input, msg.Runes...)
+	}
+	return nil
+}
+
+func (p *BranchPanel) branchCmd(action BranchAction) tea.Cmd {
+	if p.selected >= len(p.branches) {
+		return nil
+	}
+	b := p.branches[p.selected]
+	if b.Current {
+		return nil
+	}
+	p.visible = false
+	return func() tea.Msg { return BranchMsg{Action: action, Name: b.Name} }
+}
+
+func (p *BranchPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+
+	lines := []string{titleStyle.Render(i18n.T("branch.title"))}
+	if p.naming {
+		lines = append(lines, textStyle.Render(i18n.T("branch.newName")), textStyle.Render("> "+string(p.input)+"█"), "", hintStyle.Render(i18n.T("git.promptHint")))
+	} else {
+		for i, b := range p.branches {
+			marker := "  "
+			if b.Current {
+				marker = "● "
+			}
+			upstream := ""
+			if b.Upstream != "" {
+				upstream = "  → " + b.Upstream
+			}
+			if i == p.selected {
+				lines = append(lines, selectedStyle.Render(marker+b.Name+upstream))
+			} else {
+				lines = append(lines, textStyle.Render(marker+b.Name)+hintStyle.Render(upstream))
+			}
+		}
+		lines = append(lines, "", hintStyle.Render(i18n.T("branch.hint")))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(60).
+		Render(strings.Join(lines, "\n"))
+}
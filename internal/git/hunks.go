@@ -0,0 +1,180 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Hunk is one change from a zero-context diff. Line numbers are 1-based;
+// a count of zero means the hunk only adds or only removes lines, in which
+// case the start is the line before the change.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []string
+}
+
+type HunkKind int
+
+const (
+	HunkAdded HunkKind = iota
+	HunkModified
+	HunkDeleted
+)
+
+func (h Hunk) Kind() HunkKind {
+	switch {
+	case h.OldLines == 0:
+		return HunkAdded
+	case h.NewLines == 0:
+		return HunkDeleted
+	}
+	return HunkModified
+}
+
+// Removed returns the lines the hunk takes out of the old version.
+func (h Hunk) Removed() []string {
+	var lines []string
+	for _, line := range h.Lines {
+		if strings.HasPrefix(line, "-") {
+			lines = append(lines, line[1:])
+		}
+	}
+	return lines
+}
+
+// Contains reports whether the 0-based line of the new version belongs to
+// the hunk. A deletion is attributed to the line it follows.
+func (h Hunk) Contains(line int) bool {
+	if h.NewLines == 0 {
+		return line == max(h.NewStart-1, 0)
+	}
+	return line >= h.NewStart-1 && line < h.NewStart-1+h.NewLines
+}
+
+func (h Hunk) header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+// Unstaged returns the differences between the index and the working tree
+// copy of path.
+func (r *Repo) Unstaged(ctx context.Context, path string) ([]Hunk, error) {
+	return r.diff(ctx, path)
+}
+
+// Staged returns the differences between HEAD and the index for path.
+func (r *Repo) Staged(ctx context.Context, path string) ([]Hunk, error) {
+	return r.diff(ctx, path, "--cached")
+}
+
+func (r *Repo) diff(ctx context.Context, path string, args ...string) ([]Hunk, error) {
+	rel, err := r.Rel(path)
+	if err != nil {
+		return nil, err
+	}
+	args = append([]string{"diff", "--no-color", "--no-ext-diff", "-U0"}, args...)
+	out, err := r.run(ctx, nil, append(args, "--", rel)...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHunks(string(out)), nil
+}
+
+// ParseHunks reads the hunks of a single-file unified diff.
+func ParseHunks(diff string) []Hunk {
+	var hunks []Hunk
+	var current *Hunk
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			h, ok := parseHeader(line)
+			if !ok {
+				current = nil
+				continue
+			}
+			hunks = append(hunks, h)
+			current = &hunks[len(hunks)-1]
+		case current == nil:
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"), strings.HasPrefix(line, `\`):
+			current.Lines = append(current.Lines, line)
+		}
+	}
+	return hunks
+}
+
+func parseHeader(line string) (Hunk, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return Hunk{}, false
+	}
+	var h Hunk
+	var ok1, ok2 bool
+	h.OldStart, h.OldLines, ok1 = parseRange(fields[1], "-")
+	h.NewStart, h.NewLines, ok2 = parseRange(fields[2], "+")
+	return h, ok1 && ok2
+}
+
+func parseRange(field, prefix string) (start, count int, ok bool) {
+	field, found := strings.CutPrefix(field, prefix)
+	if !found {
+		return 0, 0, false
+	}
+	startText, countText, hasCount := strings.Cut(field, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
+
+// StageHunk adds one unstaged hunk of path to the index.
+func (r *Repo) StageHunk(ctx context.Context, path string, h Hunk) error {
+	return r.apply(ctx, path, h, "--cached")
+}
+
+// UnstageHunk removes one staged hunk of path from the index.
+func (r *Repo) UnstageHunk(ctx context.Context, path string, h Hunk) error {
+	return r.apply(ctx, path, h, "--cached", "--reverse")
+}
+
+func (r *Repo) apply(ctx context.Context, path string, h Hunk, args ...string) error {
+	rel, err := r.Rel(path)
+	if err != nil {
+		return err
+	}
+	var patch strings.Builder
+	fmt.Fprintf(&patch, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", rel, rel, rel, rel)
+	patch.WriteString(h.header() + "\n")
+	for _, line := range h.Lines {
+		patch.WriteString(line + "\n")
+	}
+	args = append([]string{"apply", "--unidiff-zero", "--whitespace=nowarn"}, args...)
+	_, err = r.run(ctx, strings.NewReader(patch.String()), append(args, "-")...)
+	return err
+}
+
+// InWorktree returns a copy of a staged hunk with its new-side position
+// moved from index to working tree coordinates, given the unstaged hunks
+// of the same file. The copy is for display only and cannot be applied.
+func (h Hunk) InWorktree(unstaged []Hunk) Hunk {
+	shift := 0
+	for _, u := range unstaged {
+		end := u.OldStart
+		if u.OldLines > 0 {
+			end = u.OldStart + u.OldLines - 1
+		}
+		if end < h.NewStart {
+			shift += u.NewLines - u.OldLines
+		}
+	}
+	h.NewStart += shift
+	return h
+}
@@ -0,0 +1,33 @@
+package git
+
+import "tron/pkg/diff"
+
+// DiffLines compares two versions of a file line by line and returns the
+// hunks of a zero-context diff between them, numbered as git numbers them.
+// It uses the histogram algorithm like git's own diff.
+func DiffLines(old, new []string) []Hunk {
+	var hunks []Hunk
+	for _, e := range diff.Lines(old, new, diff.Histogram) {
+		hunks = append(hunks, newHunk(e.OldStart, old[e.OldStart:e.OldEnd], e.NewStart, new[e.NewStart:e.NewEnd]))
+	}
+	return hunks
+}
+
+// newHunk builds the hunk replacing removed, which starts at 0-based line
+// oldAt, with added at newAt.
+func newHunk(oldAt int, removed []string, newAt int, added []string) Hunk {
+	h := Hunk{OldStart: oldAt, OldLines: len(removed), NewStart: newAt, NewLines: len(added)}
+	if h.OldLines > 0 {
+		h.OldStart++
+	}
+	if h.NewLines > 0 {
+		h.NewStart++
+	}
+	for _, line := range removed {
+		h.Lines = append(h.Lines, "-"+line)
+	}
+	for _, line := range added {
+		h.Lines = append(h.Lines, "+"+line)
+	}
+	return h
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is one row of the log graph. Rows that only continue the graph
+// between commits have an empty Hash.
+type Commit struct {
+	Graph   string
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string
+}
+
+// Log returns up to limit commits, newest first, touching path or the
+// whole repository when path is empty.
+func (r *Repo) Log(ctx context.Context, path string, limit int) ([]Commit, error) {
+	args := []string{"log", "--graph", "--date-order", "--format=%x00%H%x00%an%x00%at%x00%s", "-n", strconv.Itoa(limit)}
+	if path != "" {
+		rel, err := r.Rel(path)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--", rel)
+	}
+	out, err := r.run(ctx, nil, args...)
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 5 {
+			commits = append(commits, Commit{Graph: line})
+			continue
+		}
+		seconds, _ := strconv.ParseInt(fields[3], 10, 64)
+		commits = append(commits, Commit{
+			Graph:   fields[0],
+			Hash:    fields[1],
+			Author:  fields[2],
+			Date:    time.Unix(seconds, 0),
+			Subject: fields[4],
+		})
+	}
+	return commits, nil
+}
+
+// ShowFile returns the content of path at rev.
+func (r *Repo) ShowFile(ctx context.Context, rev, path string) (string, error) {
+	rel, err := r.Rel(path)
+	if err != nil {
+		return "", err
+	}
+	out, err := r.run(ctx, nil, "show", rev+":"+rel)
+	return string(out), err
+}
+
+// ShowCommit returns the message and patch of rev.
+func (r *Repo) ShowCommit(ctx context.Context, rev string) (string, error) {
+	out, err := r.run(ctx, nil, "show", "--no-color", "--stat", "--patch", rev)
+	return string(out), err
+}
+
+// DiffWorktree diffs rev against the working copy of path, or of the whole
+// tree when path is empty.
+func (r *Repo) DiffWorktree(ctx context.Context, rev, path string) (string, error) {
+	args := []string{"diff", "--no-color", rev}
+	if path != "" {
+		rel, err := r.Rel(path)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, "--", rel)
+	}
+	out, err := r.run(ctx, nil, args...)
+	return string(out), err
+}
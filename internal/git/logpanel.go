@@ -0,0 +1,339 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+const logLimit = 500
+
+type LogMsg struct {
+	Commits []Commit
+	Err     error
+}
+
+type RevisionMsg struct {
+	Title string
+	Name  string
+	Text  string
+	Diff  bool
+	Err   error
+}
+
+// OpenRevisionMsg asks for the revision being viewed to be opened as a
+// document. Name is its file name, which picks the highlighting.
+type OpenRevisionMsg struct {
+	Title string
+	Name  string
+	Text  string
+}
+
+// LogPanel browses the history of the repository or of one file. Enter
+// shows the file as it was at the selected commit and d diffs that commit
+// against the working copy; t opens what is shown in a tab.
+type LogPanel struct {
+	visible  bool
+	repo     *Repo
+	file     string
+	fileOnly bool
+	loading  bool
+	commits  []Commit
+	selected int
+	scroll   int
+	err      error
+
+	viewing    bool
+	viewTitle  string
+	viewName   string
+	viewText   string
+	viewLines  []string
+	viewDiff   bool
+	viewScroll int
+
+	width  int
+	height int
+}
+
+func NewLogPanel() *LogPanel {
+	return &LogPanel{width: 80, height: 20}
+}
+
+func (p *LogPanel) Visible() bool {
+	return p.visible
+}
+
+func (p *LogPanel) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// Open shows the history of file, or of the repository when file is empty.
+func (p *LogPanel) Open(repo *Repo, file string) tea.Cmd {
+	p.visible = true
+	p.repo = repo
+	p.file = file
+	p.fileOnly = file != ""
+	return p.load()
+}
+
+func (p *LogPanel) load() tea.Cmd {
+	p.loading = true
+	p.err = nil
+	p.viewing = false
+	p.commits = nil
+	p.selected, p.scroll = 0, 0
+	repo, path := p.repo, p.scopePath()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+		defer cancel()
+		commits, err := repo.Log(ctx, path, logLimit)
+		return LogMsg{Commits: commits, Err: err}
+	}
+}
+
+func (p *LogPanel) scopePath() string {
+	if p.fileOnly {
+		return p.file
+	}
+	return ""
+}
+
+func (p *LogPanel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case LogMsg:
+		p.loading = false
+		p.commits, p.err = msg.Commits, msg.Err
+		p.selected = p.nextCommit(-1, 1)
+	case RevisionMsg:
+		p.loading = false
+		if msg.Err != nil {
+			p.err = msg.Err
+			return nil
+		}
+		p.viewing = true
+		p.viewTitle, p.viewName, p.viewText = msg.Title, msg.Name, msg.Text
+		p.viewDiff, p.viewScroll = msg.Diff, 0
+		p.viewLines = strings.Split(strings.ReplaceAll(strings.TrimRight(msg.Text, "\n"), "\t", "    "), "\n")
+	case tea.KeyMsg:
+		if !p.visible {
+			return nil
+		}
+		if p.viewing {
+			return p.handleViewKey(msg)
+		}
+		return p.handleListKey(msg)
+	}
+	return nil
+}
+
+func (p *LogPanel) handleListKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "alt+h":
+		p.visible = false
+	case "up", "k":
+		p.selected = p.nextCommit(p.selected, -1)
+	case "down", "j":
+		p.selected = p.nextCommit(p.selected, 1)
+	case "pgup":
+		for i := 0; i < p.bodyHeight(); i++ {
+			p.selected = p.nextCommit(p.selected, -1)
+		}
+	case "pgdown":
+		for i := 0; i < p.bodyHeight(); i++ {
+			p.selected = p.nextCommit(p.selected, 1)
+		}
+	case "f":
+		if p.file != "" {
+			p.fileOnly = !p.fileOnly
+			return p.load()
+		}
+	case "enter":
+		return p.show(false)
+	case "d":
+		return p.show(true)
+	}
+	return nil
+}
+
+func (p *LogPanel) handleViewKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "backspace":
+		p.viewing = false
+	case "up", "k":
+		p.viewScroll--
+	case "down", "j":
+		p.viewScroll++
+	case "pgup":
+		p.viewScroll -= p.bodyHeight()
+	case "pgdown":
+		p.viewScroll += p.bodyHeight()
+	case "d":
+		return p.show(!p.viewDiff)
+	case "t":
+		p.visible = false
+		open := OpenRevisionMsg{Title: p.viewTitle, Name: p.viewName, Text: p.viewText}
+		return func() tea.Msg { return open }
+	}
+	return nil
+}
+
+// nextCommit steps from index in dir to the next row that is a commit,
+// staying put when there is none.
+func (p *LogPanel) nextCommit(index, dir int) int {
+	for i := index + dir; i >= 0 && i < len(p.commits); i += dir {
+		if p.commits[i].Hash != "" {
+			return i
+		}
+	}
+	return max(index, 0)
+}
+
+// show loads the selected revision of the file, or the commit itself in
+// repository scope, or its diff against the working copy.
+func (p *LogPanel) show(diff bool) tea.Cmd {
+	if p.selected >= len(p.commits) || p.commits[p.selected].Hash == "" {
+		return nil
+	}
+	c := p.commits[p.selected]
+	repo, path := p.repo, p.scopePath()
+	short := c.Hash[:min(len(c.Hash), 8)]
+	title := short
+	if path != "" {
+		title = filepath.Base(path) + " @ " + short
+	}
+	if diff {
+		title = i18n.T("gitlog.diffTitle", title)
+	}
+	p.loading = true
+	p.err = nil
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+		defer cancel()
+		var text string
+		var err error
+		switch {
+		case diff:
+			text, err = repo.DiffWorktree(ctx, c.Hash, path)
+			if err == nil && text == "" {
+				text = i18n.T("gitlog.noChanges")
+			}
+		case path != "":
+			text, err = repo.ShowFile(ctx, c.Hash, path)
+		default:
+			text, err = repo.ShowCommit(ctx, c.Hash)
+		}
+		name := "commit.diff"
+		if !diff && path != "" {
+			name = filepath.Base(path)
+		}
+		return RevisionMsg{Title: title, Name: name, Text: text, Diff: diff || path == "", Err: err}
+	}
+}
+
+func (p *LogPanel) bodyHeight() int {
+	return max(p.height-6, 3)
+}
+
+func (p *LogPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	hashStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+
+	scope := i18n.T("gitlog.repository")
+	if p.fileOnly {
+		scope = filepath.Base(p.file)
+	}
+	lines := []string{titleStyle.Render(i18n.T("gitlog.title", scope))}
+	if p.err != nil {
+		lines = append(lines, errorStyle.Render(p.err.Error()))
+	}
+
+	height := p.bodyHeight()
+	switch {
+	case p.viewing:
+		lines[0] = titleStyle.Render(p.viewTitle)
+		p.viewScroll = max(min(p.viewScroll, len(p.viewLines)-height), 0)
+		end := min(p.viewScroll+height, len(p.viewLines))
+		for i, line := range p.viewLines[p.viewScroll:end] {
+			if p.viewDiff {
+				lines = append(lines, diffLineStyle(line).Render(line))
+			} else {
+				lines = append(lines, hintStyle.Render(fmt.Sprintf("%4d ", p.viewScroll+i+1))+textStyle.Render(line))
+			}
+		}
+		lines = append(lines, "", hintStyle.Render(i18n.T("gitlog.viewHint")))
+	case p.loading && len(p.commits) == 0:
+		lines = append(lines, hintStyle.Render(i18n.T("gitlog.loading")))
+	default:
+		if len(p.commits) == 0 && p.err == nil {
+			lines = append(lines, hintStyle.Render(i18n.T("gitlog.empty")))
+		}
+		if p.selected < p.scroll {
+			p.scroll = p.selected
+		}
+		if p.selected >= p.scroll+height {
+			p.scroll = p.selected - height + 1
+		}
+		end := min(p.scroll+height, len(p.commits))
+		for i := p.scroll; i < end; i++ {
+			c := p.commits[i]
+			if c.Hash == "" {
+				lines = append(lines, hintStyle.Render(c.Graph))
+				continue
+			}
+			author := []rune(c.Author)
+			if len(author) > 16 {
+				author = append(author[:15], '…')
+			}
+			meta := fmt.Sprintf(" %s  %-16s  %s  ", c.Hash[:min(len(c.Hash), 8)], string(author), c.Date.Format("2006-01-02"))
+			if i == p.selected {
+				lines = append(lines, selectedStyle.Render(c.Graph+meta+c.Subject))
+			} else {
+				lines = append(lines, hintStyle.Render(c.Graph)+hashStyle.Render(meta)+textStyle.Render(c.Subject))
+			}
+		}
+		lines = append(lines, "", hintStyle.Render(i18n.T("gitlog.hint")))
+	}
+
+	width := max(p.width-4, 20)
+	for i, line := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(width - 2).Render(line)
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
+
+func diffLineStyle(line string) lipgloss.Style {
+	color := "#cdd6f4"
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "commit "):
+		color = "#cdd6f4"
+	case strings.HasPrefix(line, "+"):
+		color = "#a6e3a1"
+	case strings.HasPrefix(line, "-"):
+		color = "#f38ba8"
+	case strings.HasPrefix(line, "@@"):
+		color = "#89b4fa"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+}
@@ -0,0 +1,205 @@
+package git
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+type StashAction int
+
+const (
+	SaveStash StashAction = iota
+	PopStash
+	ApplyStash
+	DropStash
+)
+
+type StashMsg struct {
+	Action  StashAction
+	Ref     string
+	Message string
+}
+
+type RemoteMsg struct {
+	Op RemoteOp
+}
+
+// Panel lists the stashes of the repository and shows the progress and
+// credential prompts of fetch, pull and push.
+type Panel struct {
+	visible  bool
+	stashes  []Stash
+	selected int
+	naming   bool
+	input    []rune
+	prompt   *Prompt
+	progress string
+}
+
+func NewPanel() *Panel {
+	return &Panel{}
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+func (p *Panel) Toggle() {
+	p.visible = !p.visible
+	p.naming = false
+}
+
+func (p *Panel) SetStashes(stashes []Stash) {
+	p.stashes = stashes
+	p.selected = min(p.selected, max(len(stashes)-1, 0))
+}
+
+func (p *Panel) SetProgress(text string) {
+	p.progress = text
+}
+
+// Ask shows prompt and opens the panel so the answer can be typed.
+func (p *Panel) Ask(prompt Prompt) {
+	if p.prompt != nil {
+		p.prompt.Cancel()
+	}
+	p.prompt = &prompt
+	p.input = nil
+	p.visible = true
+}
+
+// CancelPrompt drops an unanswered prompt once its operation has ended.
+func (p *Panel) CancelPrompt() {
+	if p.prompt != nil {
+		p.prompt.Cancel()
+		p.prompt = nil
+	}
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+	if p.prompt != nil || p.naming {
+		return p.handleInput(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "esc", "alt+v":
+		p.visible = false
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.stashes)-1 {
+			p.selected++
+		}
+	case "s":
+		p.naming = true
+		p.input = nil
+	case "enter", "o":
+		return p.stashCmd(PopStash)
+	case "a":
+		return p.stashCmd(ApplyStash)
+	case "d":
+		return p.stashCmd(DropStash)
+	case "f":
+		return remoteCmd(Fetch)
+	case "p":
+		return remoteCmd(Pull)
+	case "P":
+		return remoteCmd(Push)
+	}
+	return nil
+}
+
+func (p *Panel) handleInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		if p.prompt != nil {
+			p.CancelPrompt()
+		}
+		p.naming = false
+	case tea.KeyEnter:
+		text := string(p.input)
+		p.input = nil
+		if p.prompt != nil {
+			p.prompt.Reply(text)
+			p.prompt = nil
+			return nil
+		}
+		p.naming = false
+		return func() tea.Msg { return StashMsg{Action: SaveStash, Message: text} }
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		p.input = append(p.input, msg.Runes...)
+	}
+	return nil
+}
+
+func (p *Panel) stashCmd(action StashAction) tea.Cmd {
+	if p.selected >= len(p.stashes) {
+		return nil
+	}
+	ref := p.stashes[p.selected].Ref
+	return func() tea.Msg { return StashMsg{Action: action, Ref: ref} }
+}
+
+func remoteCmd(op RemoteOp) tea.Cmd {
+	return func() tea.Msg { return RemoteMsg{Op: op} }
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+
+	lines := []string{titleStyle.Render(i18n.T("git.title"))}
+	switch {
+	case p.prompt != nil:
+		answer := string(p.input)
+		if p.prompt.Secret {
+			answer = strings.Repeat("•", len(p.input))
+		}
+		lines = append(lines, textStyle.Render(p.prompt.Text), textStyle.Render("> "+answer+"█"), "", hintStyle.Render(i18n.T("git.promptHint")))
+	case p.naming:
+		lines = append(lines, textStyle.Render(i18n.T("git.stashMessage")), textStyle.Render("> "+string(p.input)+"█"), "", hintStyle.Render(i18n.T("git.promptHint")))
+	default:
+		if len(p.stashes) == 0 {
+			lines = append(lines, hintStyle.Render(i18n.T("git.noStashes")))
+		}
+		for i, s := range p.stashes {
+			if i == p.selected {
+				lines = append(lines, selectedStyle.Render(s.Ref+"  "+s.Message))
+			} else {
+				lines = append(lines, textStyle.Render(s.Ref)+hintStyle.Render("  "+s.Message))
+			}
+		}
+		if p.progress != "" {
+			lines = append(lines, "", textStyle.Render(p.progress))
+		}
+		lines = append(lines, "", hintStyle.Render(i18n.T("git.hint")))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(72).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// RemoteTimeout bounds a fetch, pull or push, which may sit waiting on a
+// credential prompt.
+const RemoteTimeout = 10 * time.Minute
+
+type RemoteOp string
+
+const (
+	Fetch RemoteOp = "fetch"
+	Pull  RemoteOp = "pull"
+	Push  RemoteOp = "push"
+)
+
+// Remote runs op against the upstream of the current branch and passes
+// every progress line git prints to progress. env is appended to the
+// environment, normally Askpass.Env so credentials can be prompted for.
+func (r *Repo) Remote(ctx context.Context, op RemoteOp, env []string, progress func(string)) error {
+	cmd := exec.CommandContext(ctx, "git", string(op), "--progress")
+	cmd.Dir = r.Root
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	var failures []string
+	scanner := bufio.NewScanner(stderr)
+	scanner.Split(scanProgress)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		progress(line)
+		if isFailure(line) {
+			failures = append(failures, line)
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		if len(failures) > 0 {
+			return errors.New(strings.Join(failures, "\n"))
+		}
+		return err
+	}
+	return nil
+}
+
+// scanProgress splits on carriage returns as well as newlines, since git
+// redraws its progress counters in place.
+func scanProgress(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func isFailure(line string) bool {
+	return strings.HasPrefix(line, "fatal:") || strings.HasPrefix(line, "error:") || strings.HasPrefix(line, "! ")
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"context"
+	"strings"
+)
+
+// Stash is one entry of the stash list, newest first.
+type Stash struct {
+	Ref     string
+	Message string
+}
+
+func (r *Repo) Stashes(ctx context.Context) ([]Stash, error) {
+	out, err := r.run(ctx, nil, "stash", "list", "--format=%gd%x00%gs")
+	if err != nil {
+		return nil, err
+	}
+	var stashes []Stash
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		ref, message, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		stashes = append(stashes, Stash{Ref: ref, Message: message})
+	}
+	return stashes, nil
+}
+
+// StashSave stashes all local changes, including untracked files. An empty
+// message lets git describe the stash from HEAD.
+func (r *Repo) StashSave(ctx context.Context, message string) error {
+	args := []string{"stash", "push", "--include-untracked"}
+	if message != "" {
+		args = append(args, "--message", message)
+	}
+	_, err := r.run(ctx, nil, args...)
+	return err
+}
+
+func (r *Repo) StashPop(ctx context.Context, ref string) error {
+	_, err := r.run(ctx, nil, "stash", "pop", ref)
+	return err
+}
+
+func (r *Repo) StashApply(ctx context.Context, ref string) error {
+	_, err := r.run(ctx, nil, "stash", "apply", ref)
+	return err
+}
+
+func (r *Repo) StashDrop(ctx context.Context, ref string) error {
+	_, err := r.run(ctx, nil, "stash", "drop", ref)
+	return err
+}
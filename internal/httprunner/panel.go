@@ -0,0 +1,207 @@
+package httprunner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+const maxHistory = 20
+
+type Panel struct {
+	visible bool
+	pending *Request
+	history []*Response
+	index   int
+	scroll  int
+	width   int
+	height  int
+}
+
+func NewPanel() *Panel {
+	return &Panel{width: 80, height: 20}
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+func (p *Panel) Toggle() {
+	p.visible = !p.visible
+}
+
+func (p *Panel) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+func (p *Panel) Send(req *Request) tea.Cmd {
+	if req == nil {
+		return nil
+	}
+	p.visible = true
+	p.pending = req
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+		defer cancel()
+		return ResponseMsg{Response: Send(ctx, req)}
+	}
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case ResponseMsg:
+		p.pending = nil
+		p.history = append(p.history, msg.Response)
+		if len(p.history) > maxHistory {
+			p.history = p.history[len(p.history)-maxHistory:]
+		}
+		p.index = len(p.history) - 1
+		p.scroll = 0
+	case tea.KeyMsg:
+		if p.visible {
+			p.handleKey(msg)
+		}
+	}
+	return nil
+}
+
+func (p *Panel) handleKey(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "esc", "q":
+		p.visible = false
+	case "up", "k":
+		if p.scroll > 0 {
+			p.scroll--
+		}
+	case "down", "j":
+		p.scroll++
+	case "pgup":
+		p.scroll -= p.bodyHeight()
+		if p.scroll < 0 {
+			p.scroll = 0
+		}
+	case "pgdown":
+		p.scroll += p.bodyHeight()
+	case "left", "[":
+		if p.index > 0 {
+			p.index--
+			p.scroll = 0
+		}
+	case "right", "]":
+		if p.index < len(p.history)-1 {
+			p.index++
+			p.scroll = 0
+		}
+	}
+}
+
+func (p *Panel) bodyHeight() int {
+	h := p.height - 8
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#89b4fa"))
+
+	title := i18n.T("http.title")
+	if len(p.history) > 0 {
+		title += fmt.Sprintf(" %d/%d", p.index+1, len(p.history))
+	}
+	lines := []string{titleStyle.Render(title)}
+
+	if p.pending != nil {
+		lines = append(lines, hintStyle.Render(i18n.T("http.sending", p.pending.Method, p.pending.URL)))
+	}
+
+	if len(p.history) == 0 {
+		if p.pending == nil {
+			lines = append(lines, hintStyle.Render(i18n.T("http.empty")))
+		}
+	} else {
+		resp := p.history[p.index]
+		lines = append(lines, textStyle.Render(resp.Request.Method+" "+resp.Request.URL))
+		if resp.Err != nil {
+			lines = append(lines, statusStyle(0).Render(i18n.T("http.error", resp.Err.Error())))
+		} else {
+			status := fmt.Sprintf("%s %s", resp.Proto, resp.Status)
+			lines = append(lines, statusStyle(resp.StatusCode).Render(status)+
+				hintStyle.Render("  "+i18n.T("http.summary", resp.Duration.Round(1e6), len(resp.Body), resp.SentAt.Format("15:04:05"))))
+		}
+
+		var content []string
+		names := make([]string, 0, len(resp.Headers))
+		for name := range resp.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			content = append(content, headerStyle.Render(name+":")+" "+strings.Join(resp.Headers[name], ", "))
+		}
+		if len(names) > 0 {
+			content = append(content, "")
+		}
+		for _, line := range strings.Split(resp.PrettyBody(), "\n") {
+			content = append(content, textStyle.Render(line))
+		}
+
+		height := p.bodyHeight()
+		if p.scroll > len(content)-height {
+			p.scroll = len(content) - height
+		}
+		if p.scroll < 0 {
+			p.scroll = 0
+		}
+		end := p.scroll + height
+		if end > len(content) {
+			end = len(content)
+		}
+		lines = append(lines, "")
+		lines = append(lines, content[p.scroll:end]...)
+	}
+
+	lines = append(lines, "", hintStyle.Render(i18n.T("http.hint")))
+
+	width := p.width - 4
+	if width < 20 {
+		width = 20
+	}
+	for i, line := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(width).Render(line)
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
+
+func statusStyle(code int) lipgloss.Style {
+	color := "#f38ba8"
+	switch {
+	case code >= 200 && code < 300:
+		color = "#a6e3a1"
+	case code >= 300 && code < 400:
+		color = "#f9e2af"
+	}
+	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+}
@@ -0,0 +1,141 @@
+package httprunner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type Header struct {
+	Name  string
+	Value string
+}
+
+type Request struct {
+	Name      string
+	Method    string
+	URL       string
+	Headers   []Header
+	Body      string
+	StartLine int
+	EndLine   int
+
+	blockStart int
+}
+
+var (
+	requestLineRegex = regexp.MustCompile(`^(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|TRACE|CONNECT)\s+(\S+)`)
+	variableRegex    = regexp.MustCompile(`^@([\w.-]+)\s*=\s*(.*)$`)
+	referenceRegex   = regexp.MustCompile(`\{\{\s*([\w.-]+)\s*\}\}`)
+)
+
+func IsHTTPFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".http", ".rest":
+		return true
+	}
+	return false
+}
+
+// Parse splits an .http file into requests. Blocks are separated by lines
+// starting with ###, and @name = value lines define {{name}} variables.
+func Parse(content string) []*Request {
+	lines := strings.Split(content, "\n")
+	vars := make(map[string]string)
+	for _, line := range lines {
+		if m := variableRegex.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			vars[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+
+	var requests []*Request
+	start := 0
+	name := ""
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && !strings.HasPrefix(lines[i], "###") {
+			continue
+		}
+		if req := parseBlock(lines, start, i, vars); req != nil {
+			req.Name = name
+			req.blockStart = start - 1
+			requests = append(requests, req)
+		}
+		if i < len(lines) {
+			name = strings.TrimSpace(strings.TrimPrefix(lines[i], "###"))
+		}
+		start = i + 1
+	}
+	return requests
+}
+
+func parseBlock(lines []string, start, end int, vars map[string]string) *Request {
+	var req *Request
+	inBody := false
+	var body []string
+
+	for i := start; i < end; i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if req == nil {
+			if trimmed == "" || isComment(trimmed) || variableRegex.MatchString(trimmed) {
+				continue
+			}
+			req = &Request{Method: "GET", StartLine: i, EndLine: end - 1}
+			if m := requestLineRegex.FindStringSubmatch(trimmed); m != nil {
+				req.Method = m[1]
+				req.URL = expand(m[2], vars)
+			} else {
+				req.URL = expand(strings.Fields(trimmed)[0], vars)
+			}
+			continue
+		}
+
+		if inBody {
+			body = append(body, line)
+			continue
+		}
+		if trimmed == "" {
+			inBody = true
+			continue
+		}
+		if isComment(trimmed) {
+			continue
+		}
+		if name, value, ok := strings.Cut(trimmed, ":"); ok {
+			req.Headers = append(req.Headers, Header{
+				Name:  strings.TrimSpace(name),
+				Value: expand(strings.TrimSpace(value), vars),
+			})
+		}
+	}
+
+	if req == nil {
+		return nil
+	}
+	req.Body = expand(strings.TrimSpace(strings.Join(body, "\n")), vars)
+	return req
+}
+
+func isComment(line string) bool {
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//")
+}
+
+func expand(s string, vars map[string]string) string {
+	return referenceRegex.ReplaceAllStringFunc(s, func(ref string) string {
+		name := referenceRegex.FindStringSubmatch(ref)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+func RequestAt(requests []*Request, line int) *Request {
+	for _, req := range requests {
+		if line >= req.blockStart && line <= req.EndLine {
+			return req
+		}
+	}
+	return nil
+}
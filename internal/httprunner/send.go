@@ -0,0 +1,73 @@
+package httprunner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const RequestTimeout = 30 * time.Second
+
+type Response struct {
+	Request    *Request
+	Status     string
+	StatusCode int
+	Proto      string
+	Headers    http.Header
+	Body       []byte
+	Duration   time.Duration
+	Err        error
+	SentAt     time.Time
+}
+
+type ResponseMsg struct {
+	Response *Response
+}
+
+func Send(ctx context.Context, req *Request) *Response {
+	resp := &Response{Request: req, SentAt: time.Now()}
+
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
+	if err != nil {
+		resp.Err = err
+		return resp
+	}
+	for _, h := range req.Headers {
+		httpReq.Header.Add(h.Name, h.Value)
+	}
+
+	start := time.Now()
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		resp.Err = err
+		resp.Duration = time.Since(start)
+		return resp
+	}
+	defer httpResp.Body.Close()
+
+	resp.Body, resp.Err = io.ReadAll(httpResp.Body)
+	resp.Duration = time.Since(start)
+	resp.Status = httpResp.Status
+	resp.StatusCode = httpResp.StatusCode
+	resp.Proto = httpResp.Proto
+	resp.Headers = httpResp.Header
+	return resp
+}
+
+func (r *Response) PrettyBody() string {
+	if strings.Contains(r.Headers.Get("Content-Type"), "json") || json.Valid(r.Body) {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, r.Body, "", "  "); err == nil {
+			return buf.String()
+		}
+	}
+	return string(r.Body)
+}
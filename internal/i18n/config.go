@@ -0,0 +1,41 @@
+package i18n
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+type config struct {
+	Locale string `json:"locale"`
+}
+
+// Detect picks the locale from .tron/locale.json, falling back to the
+// LC_ALL, LC_MESSAGES and LANG environment variables.
+func Detect(rootPath string) string {
+	if data, err := os.ReadFile(filepath.Join(rootPath, ".tron", "locale.json")); err == nil {
+		var c config
+		if json.Unmarshal(data, &c) == nil && c.Locale != "" {
+			return c.Locale
+		}
+	}
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" && v != "C" && v != "POSIX" {
+			return v
+		}
+	}
+	return DefaultLocale
+}
+
+// Save records locale in .tron/locale.json.
+func Save(rootPath, locale string) error {
+	path := filepath.Join(rootPath, ".tron", "locale.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(config{Locale: locale}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,87 @@
+// Package i18n holds the message catalogs for user-facing text.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const DefaultLocale = "en"
+
+// Catalog maps message IDs to fmt format strings.
+type Catalog map[string]string
+
+var catalogs = map[string]Catalog{
+	"en": english,
+	"de": german,
+}
+
+var (
+	locale  = DefaultLocale
+	current = english
+)
+
+// Locales returns the locales that ship a catalog.
+func Locales() []string {
+	names := make([]string, 0, len(catalogs))
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func Locale() string {
+	return locale
+}
+
+// SetLocale switches to the catalog matching name, which may be a POSIX
+// locale such as "de_DE.UTF-8". Unknown locales fall back to English and
+// report false.
+func SetLocale(name string) bool {
+	tag := normalize(name)
+	if c, ok := catalogs[tag]; ok {
+		locale, current = tag, c
+		return true
+	}
+	if i := strings.IndexByte(tag, '_'); i > 0 {
+		if c, ok := catalogs[tag[:i]]; ok {
+			locale, current = tag[:i], c
+			return true
+		}
+	}
+	locale, current = DefaultLocale, english
+	return false
+}
+
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexAny(name, ".@"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// T returns the message for id in the current locale, formatted with args.
+// Messages missing from a translation fall back to English, and unknown IDs
+// are returned as is.
+func T(id string, args ...any) string {
+	format, ok := Lookup(id)
+	if !ok {
+		format = id
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Lookup returns the message for id without formatting it.
+func Lookup(id string) (string, bool) {
+	if format, ok := current[id]; ok {
+		return format, true
+	}
+	format, ok := english[id]
+	return format, ok
+}
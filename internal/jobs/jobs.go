@@ -0,0 +1,235 @@
+// Package jobs runs background work such as searches, indexing and git
+// calls on a bounded number of workers, so it neither blocks the Update
+// loop nor piles up, and cancels it once whatever started it goes away.
+package jobs
+
+import (
+	"context"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Priority orders queued jobs; a free worker takes the highest first.
+type Priority int
+
+const (
+	// Low is for work nobody is waiting on, like indexing.
+	Low Priority = iota
+	// Normal is for refreshes like git status.
+	Normal
+	// High is for what the user is waiting to see.
+	High
+)
+
+// DefaultWorkers is how many jobs a pool from NewPool runs at once.
+const DefaultWorkers = 4
+
+// Func does a job's work, returning the message that delivers its
+// result. It should return early once ctx is done, and may report how far
+// it has got; reports are cheap and may be dropped.
+type Func func(ctx context.Context, report func(done, total int)) tea.Msg
+
+// Job is work to run in the background. Owner names what started it, a
+// view or document, so it can be cancelled when that closes.
+type Job struct {
+	Owner    string
+	Title    string
+	Priority Priority
+	Run      Func
+}
+
+// Status describes a running job.
+type Status struct {
+	Owner, Title string
+	Done, Total  int
+}
+
+// ProgressMsg is sent, through Listen, when a job reports progress or
+// finishes.
+type ProgressMsg struct {
+	Owner string
+}
+
+type task struct {
+	job    Job
+	ctx    context.Context
+	cancel context.CancelFunc
+	// ready is closed when a worker is free for the task.
+	ready       chan struct{}
+	done, total int
+	running     bool
+}
+
+// Pool runs jobs, at most a fixed number at a time.
+type Pool struct {
+	mu      sync.Mutex
+	workers int
+	active  int
+	queued  [High + 1][]*task
+	tasks   map[*task]bool
+	events  chan tea.Msg
+	closed  bool
+}
+
+func NewPool(workers int) *Pool {
+	return &Pool{
+		workers: max(workers, 1),
+		tasks:   make(map[*task]bool),
+		events:  make(chan tea.Msg, 64),
+	}
+}
+
+// Submit queues job and returns the command that waits for its result.
+// A cancelled job's result is dropped.
+func (p *Pool) Submit(job Job) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &task{job: job, ctx: ctx, cancel: cancel, ready: make(chan struct{})}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		cancel()
+		return nil
+	}
+	p.tasks[t] = true
+	p.queued[job.Priority] = append(p.queued[job.Priority], t)
+	p.dispatch()
+	p.mu.Unlock()
+
+	return func() tea.Msg {
+		select {
+		case <-t.ready:
+		case <-ctx.Done():
+			// Cancelled while queued, unless a worker got to it first.
+			p.mu.Lock()
+			running := t.running
+			p.mu.Unlock()
+			if running {
+				p.finish(t)
+			}
+			return nil
+		}
+		defer p.finish(t)
+		msg := job.Run(ctx, func(done, total int) { p.report(t, done, total) })
+		if ctx.Err() != nil {
+			return nil
+		}
+		return msg
+	}
+}
+
+// dispatch hands free workers to the highest-priority queued tasks. p.mu
+// must be held.
+func (p *Pool) dispatch() {
+	for prio := High; prio >= Low && p.active < p.workers; {
+		queue := p.queued[prio]
+		if len(queue) == 0 {
+			prio--
+			continue
+		}
+		t := queue[0]
+		p.queued[prio] = queue[1:]
+		t.running = true
+		p.active++
+		close(t.ready)
+	}
+}
+
+func (p *Pool) finish(t *task) {
+	p.mu.Lock()
+	t.cancel()
+	delete(p.tasks, t)
+	p.active--
+	p.dispatch()
+	p.mu.Unlock()
+	p.notify(t.job.Owner)
+}
+
+func (p *Pool) report(t *task, done, total int) {
+	p.mu.Lock()
+	t.done, t.total = done, total
+	p.mu.Unlock()
+	p.notify(t.job.Owner)
+}
+
+// notify wakes the listener without ever blocking a job; when the channel
+// is full a wake-up is already pending.
+func (p *Pool) notify(owner string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	select {
+	case p.events <- ProgressMsg{Owner: owner}:
+	default:
+	}
+}
+
+// Listen returns the command that waits for the next ProgressMsg. Run it
+// again after each one to keep listening; once the pool is closed it
+// returns nil.
+func (p *Pool) Listen() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-p.events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Cancel cancels the queued and running jobs of owner.
+func (p *Pool) Cancel(owner string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for t := range p.tasks {
+		if t.job.Owner == owner {
+			p.drop(t)
+		}
+	}
+}
+
+// Close cancels every job and refuses new ones.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	close(p.events)
+	for t := range p.tasks {
+		p.drop(t)
+	}
+}
+
+// drop cancels t, taking it off its queue if no worker has it yet. p.mu
+// must be held.
+func (p *Pool) drop(t *task) {
+	t.cancel()
+	if t.running {
+		return
+	}
+	delete(p.tasks, t)
+	queue := p.queued[t.job.Priority]
+	for i, q := range queue {
+		if q == t {
+			p.queued[t.job.Priority] = append(queue[:i:i], queue[i+1:]...)
+			break
+		}
+	}
+}
+
+// Running returns the jobs that have a worker, in no particular order.
+func (p *Pool) Running() []Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var running []Status
+	for t := range p.tasks {
+		if t.running {
+			running = append(running, Status{Owner: t.job.Owner, Title: t.job.Title, Done: t.done, Total: t.total})
+		}
+	}
+	return running
+}
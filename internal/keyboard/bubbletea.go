@@ -0,0 +1,58 @@
+package keyboard
+
+import (
+	"os"
+	"reflect"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// This file holds what the package relies on in bubbletea beyond its API,
+// written against v0.27. TestUnknownCSI runs a program to check it still
+// holds after an upgrade.
+
+// unknownCSI returns the sequence in the message bubbletea sends for CSI
+// sequences it cannot parse, an unexported []byte type. It reports false
+// for any other message.
+func unknownCSI(msg tea.Msg) (string, bool) {
+	v := reflect.ValueOf(msg)
+	if !v.IsValid() || v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 {
+		return "", false
+	}
+	if t := v.Type(); t.PkgPath() != "github.com/charmbracelet/bubbletea" || t.Name() != "unknownCSISequenceMsg" {
+		return "", false
+	}
+	return string(v.Bytes()), true
+}
+
+// Output is the terminal the program draws on. Pass it to tea.WithOutput:
+// bubbletea has no command to write raw sequences, so Enable and Disable
+// write here, and taking turns with the renderer's writes keeps their
+// sequences out of the middle of a frame.
+var Output = &output{f: os.Stdout}
+
+// output serializes writes to f. It keeps Fd so bubbletea still finds the
+// terminal behind it.
+type output struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.f.Write(p)
+}
+
+func (o *output) Read(p []byte) (int, error) {
+	return o.f.Read(p)
+}
+
+func (o *output) Close() error {
+	return o.f.Close()
+}
+
+func (o *output) Fd() uintptr {
+	return o.f.Fd()
+}
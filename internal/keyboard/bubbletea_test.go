@@ -0,0 +1,52 @@
+package keyboard
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type csiModel struct {
+	decoded tea.Msg
+}
+
+func (m *csiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *csiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if decoded, ok := Decode(msg); ok {
+		m.decoded = decoded
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m *csiModel) View() string {
+	return ""
+}
+
+// TestUnknownCSI feeds bubbletea a kitty key sequence and checks that
+// Decode recognizes the message it arrives in. It fails when an upgrade of
+// bubbletea changes how unknown sequences are reported.
+func TestUnknownCSI(t *testing.T) {
+	m := &csiModel{}
+	p := tea.NewProgram(m,
+		tea.WithInput(strings.NewReader("\x1b[97;6u")),
+		tea.WithOutput(io.Discard),
+		tea.WithoutRenderer(),
+		tea.WithoutSignals(),
+	)
+	timer := time.AfterFunc(5*time.Second, p.Quit)
+	defer timer.Stop()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	ext, ok := m.decoded.(ExtendedKeyMsg)
+	if !ok || ext.Key != "ctrl+shift+a" {
+		t.Fatalf("decoded %#v, want ctrl+shift+a; has bubbletea stopped sending unknownCSISequenceMsg?", m.decoded)
+	}
+}
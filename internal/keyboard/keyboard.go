@@ -0,0 +1,241 @@
+// Package keyboard turns on the kitty keyboard protocol where the terminal
+// supports it, so keys that legacy encodings cannot tell apart (tab and
+// ctrl+i, enter and ctrl+enter, ctrl+a and ctrl+shift+a) arrive distinctly.
+// Terminals without it keep sending legacy sequences and nothing changes.
+package keyboard
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Flags requested from the terminal: disambiguate escape codes (1) and
+// report alternate keys (4), which gives the shifted key for alt+shift
+// combinations.
+const Flags = 1 | 4
+
+const (
+	push  = "\x1b[>5u"
+	pop   = "\x1b[<u"
+	query = "\x1b[?u\x1b[c"
+	// Focus reports arrive as CSI I and CSI O.
+	focusOn  = "\x1b[?1004h"
+	focusOff = "\x1b[?1004l"
+)
+
+const (
+	modShift = 1 << iota
+	modAlt
+	modCtrl
+	modSuper
+)
+
+// ExtendedKeyMsg is a key only the kitty protocol can report. Key is its
+// name in keybinding syntax, such as "ctrl+shift+a". Base is what a legacy
+// terminal would have sent, used when nothing is bound to Key; HasBase is
+// false for keys with no legacy equivalent.
+type ExtendedKeyMsg struct {
+	Key     string
+	Base    tea.KeyMsg
+	HasBase bool
+}
+
+func (k ExtendedKeyMsg) String() string {
+	return k.Key
+}
+
+// FocusMsg reports the terminal window gaining or losing focus.
+type FocusMsg struct {
+	Focused bool
+}
+
+// ReportMsg answers the capability query. Supported is false when the
+// terminal answered the device attributes request but not the protocol
+// query.
+type ReportMsg struct {
+	Supported bool
+	Flags     int
+}
+
+func write(seq string) tea.Cmd {
+	return func() tea.Msg {
+		_, _ = Output.Write([]byte(seq))
+		return nil
+	}
+}
+
+// Enable pushes the protocol flags and asks whether the terminal
+// understood them. It also turns on focus reports. Call it once the
+// alternate screen is up, since the terminal keeps separate flags for each
+// screen.
+func Enable() tea.Cmd {
+	return write(push + query + focusOn)
+}
+
+// Disable restores the flags in effect before Enable.
+func Disable() tea.Cmd {
+	return write(pop + focusOff)
+}
+
+// Decode translates the CSI sequences bubbletea does not know into key,
+// report and focus messages. It reports false for anything else.
+func Decode(msg tea.Msg) (tea.Msg, bool) {
+	seq, ok := unknownCSI(msg)
+	if !ok || !strings.HasPrefix(seq, "\x1b[") || len(seq) < 3 {
+		return nil, false
+	}
+	body, final := seq[2:len(seq)-1], seq[len(seq)-1]
+	switch {
+	case body == "" && (final == 'I' || final == 'O'):
+		return FocusMsg{Focused: final == 'I'}, true
+	case strings.HasPrefix(body, "?") && final == 'u':
+		flags, _ := strconv.Atoi(body[1:])
+		return ReportMsg{Supported: true, Flags: flags}, true
+	case strings.HasPrefix(body, "?") && final == 'c':
+		return ReportMsg{}, true
+	case final == 'u':
+		return decodeKey(body)
+	}
+	return nil, false
+}
+
+// decodeKey parses the body of CSI code[:shifted[:base]] ; modifiers u.
+func decodeKey(body string) (tea.Msg, bool) {
+	fields := strings.Split(body, ";")
+	codes := strings.Split(fields[0], ":")
+	code, err := strconv.Atoi(codes[0])
+	if err != nil {
+		return nil, false
+	}
+	shifted := 0
+	if len(codes) > 1 {
+		shifted, _ = strconv.Atoi(codes[1])
+	}
+	mods := 0
+	if len(fields) > 1 {
+		m, _ := strconv.Atoi(strings.SplitN(fields[1], ":", 2)[0])
+		// Caps and num lock are not modifiers for bindings.
+		mods = max(m-1, 0) &^ (64 | 128)
+	}
+	msg := keyMsg(rune(code), rune(shifted), mods)
+	return msg, msg != nil
+}
+
+func keyMsg(code, shifted rune, mods int) tea.Msg {
+	alt := mods&modAlt != 0
+	ctrl := mods&modCtrl != 0
+	shift := mods&modShift != 0
+	name := keyName(code)
+	if name == "" {
+		// Functional keys from the private use area are not bound to
+		// anything yet.
+		return nil
+	}
+
+	if mods&modSuper != 0 {
+		ext := ExtendedKeyMsg{Key: "super+" + modPrefix(mods&^modSuper) + name}
+		if base, ok := keyMsg(code, shifted, mods&^modSuper).(tea.KeyMsg); ok {
+			ext.Base, ext.HasBase = base, true
+		}
+		return ext
+	}
+
+	switch code {
+	case 27:
+		return legacy(tea.KeyEsc, alt, ctrl || shift, mods, name)
+	case 13:
+		return legacy(tea.KeyEnter, alt, ctrl || shift, mods, name)
+	case 9:
+		if shift && !ctrl {
+			return tea.KeyMsg{Type: tea.KeyShiftTab, Alt: alt}
+		}
+		return legacy(tea.KeyTab, alt, ctrl, mods, name)
+	case 127:
+		if ctrl {
+			// Legacy terminals send ctrl+backspace as ctrl+w.
+			return ExtendedKeyMsg{Key: modPrefix(mods) + "backspace", Base: tea.KeyMsg{Type: tea.KeyCtrlW, Alt: alt}, HasBase: true}
+		}
+		return legacy(tea.KeyBackspace, alt, shift, mods, name)
+	}
+
+	if !ctrl {
+		r := code
+		if shift {
+			r = shiftedRune(code, shifted)
+		}
+		if r == ' ' {
+			return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}, Alt: alt}
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: alt}
+	}
+
+	var base tea.KeyMsg
+	hasBase := true
+	switch {
+	case code >= 'a' && code <= 'z':
+		base = tea.KeyMsg{Type: tea.KeyCtrlA + tea.KeyType(code-'a'), Alt: alt}
+	case code == ' ':
+		base = tea.KeyMsg{Type: tea.KeyCtrlAt, Alt: alt}
+	default:
+		hasBase = false
+	}
+	// ctrl+i and ctrl+m are tab and enter in legacy encodings, so they only
+	// get names of their own here.
+	if !shift && hasBase && code != 'i' && code != 'm' {
+		return base
+	}
+	return ExtendedKeyMsg{Key: modPrefix(mods) + name, Base: base, HasBase: hasBase}
+}
+
+// legacy returns key, or an ExtendedKeyMsg falling back to it when extra
+// modifiers were held.
+func legacy(key tea.KeyType, alt, extended bool, mods int, name string) tea.Msg {
+	msg := tea.KeyMsg{Type: key, Alt: alt}
+	if !extended {
+		return msg
+	}
+	return ExtendedKeyMsg{Key: modPrefix(mods) + name, Base: msg, HasBase: true}
+}
+
+func modPrefix(mods int) string {
+	var sb strings.Builder
+	if mods&modCtrl != 0 {
+		sb.WriteString("ctrl+")
+	}
+	if mods&modAlt != 0 {
+		sb.WriteString("alt+")
+	}
+	if mods&modShift != 0 {
+		sb.WriteString("shift+")
+	}
+	return sb.String()
+}
+
+func keyName(code rune) string {
+	switch code {
+	case 27:
+		return "esc"
+	case 13:
+		return "enter"
+	case 9:
+		return "tab"
+	case 127:
+		return "backspace"
+	case ' ':
+		return "space"
+	}
+	if unicode.IsPrint(code) {
+		return string(code)
+	}
+	return ""
+}
+
+func shiftedRune(code, shifted rune) rune {
+	if shifted != 0 {
+		return shifted
+	}
+	return unicode.ToUpper(code)
+}
@@ -23,6 +23,7 @@ type Client struct {
 	cmdArgs       []string
 	process       *exec.Cmd
 	stdin         io.WriteCloser
+	writeMu       sync.Mutex
 	stdout        *bufio.Reader
 	stderr        io.ReadCloser
 	requestID     atomic.Int32
@@ -35,6 +36,13 @@ type Client struct {
 	wg            sync.WaitGroup
 	initialized   bool
 	initMu        sync.RWMutex
+	completion    completionState
+	pathMapper    PathMapper
+	settings      map[string]interface{}
+	syncKind      int
+	versions      map[string]int
+	diagVersions  map[string]int
+	log           serverLog
 }
 
 func New(command string) *Client {
@@ -145,7 +153,8 @@ func (c *Client) SendRequest(method string, params interface{}) (int, error) {
 	}
 	c.pendingMu.Unlock()
 
-	if err := WriteMessage(c.stdin, req); err != nil {
+	c.log.addf("--> %s #%d", method, id)
+	if err := c.write(req); err != nil {
 		c.pendingMu.Lock()
 		delete(c.pending, id)
 		c.pendingMu.Unlock()
@@ -162,13 +171,20 @@ func (c *Client) SendNotification(method string, params interface{}) error {
 		Params:  params,
 	}
 
-	if err := WriteMessage(c.stdin, notif); err != nil {
+	c.log.addf("--> %s", method)
+	if err := c.write(notif); err != nil {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
 	return nil
 }
 
+func (c *Client) write(msg interface{}) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return WriteMessage(c.stdin, msg)
+}
+
 func (c *Client) WaitForResponse(id int) (*Response, error) {
 	c.pendingMu.RLock()
 	pending, ok := c.pending[id]
@@ -197,6 +213,37 @@ func (c *Client) WaitForResponse(id int) (*Response, error) {
 	}
 }
 
+func (c *Client) WaitForResponseContext(ctx context.Context, id int) (*Response, error) {
+	c.pendingMu.RLock()
+	pending, ok := c.pending[id]
+	c.pendingMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no pending request with id %d", id)
+	}
+
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, id)
+		c.pendingMu.Unlock()
+	}()
+
+	select {
+	case resp := <-pending.response:
+		return resp, nil
+	case err := <-pending.err:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.ctx.Done():
+		return nil, c.ctx.Err()
+	}
+}
+
+func (c *Client) CancelRequest(id int) error {
+	return c.SendNotification("$/cancelRequest", &CancelParams{ID: id})
+}
+
 func (c *Client) readLoop() {
 	defer c.wg.Done()
 
@@ -224,7 +271,20 @@ func (c *Client) readLoop() {
 				continue
 			}
 
-			if baseMsg.ID != nil {
+			switch {
+			case baseMsg.ID != nil && baseMsg.Method != "":
+				c.log.addf("<-- %s #%d", baseMsg.Method, *baseMsg.ID)
+			case baseMsg.ID != nil && baseMsg.Error != nil:
+				c.log.addf("<-- #%d error: %s", *baseMsg.ID, baseMsg.Error.Message)
+			case baseMsg.ID != nil:
+				c.log.addf("<-- #%d", *baseMsg.ID)
+			case baseMsg.Method != "window/logMessage":
+				c.log.addf("<-- %s", baseMsg.Method)
+			}
+
+			if baseMsg.ID != nil && baseMsg.Method != "" {
+				c.handleServerRequest(*baseMsg.ID, baseMsg.Method, data)
+			} else if baseMsg.ID != nil {
 				var resp Response
 				if err := json.Unmarshal(data, &resp); err != nil {
 					c.pendingMu.RLock()
@@ -247,52 +307,36 @@ func (c *Client) readLoop() {
 	}
 }
 
-func (c *Client) stderrLoop() {
-	defer c.wg.Done()
-
-	buf := make([]byte, 1024)
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		default:
-			n, err := c.stderr.Read(buf)
-			if err != nil {
-				return
-			}
-			_ = n
-		}
-	}
-}
-
 func (c *Client) handleNotification(method string, data []byte) {
 	switch method {
 	case "textDocument/publishDiagnostics":
-		var params PublishDiagnosticsParams
 		var notif struct {
 			Params PublishDiagnosticsParams `json:"params"`
 		}
 		if err := json.Unmarshal(data, &notif); err != nil {
 			return
 		}
-		params = notif.Params
-
-		c.diagnosticsMu.Lock()
-		c.diagnostics[params.URI] = params.Diagnostics
-		c.diagnosticsMu.Unlock()
+		c.storeDiagnostics(notif.Params)
+	case "window/logMessage":
+		c.logMessage(data)
 	}
 }
 
 func (c *Client) GetDiagnostics(uri string) []Diagnostic {
 	c.diagnosticsMu.RLock()
 	defer c.diagnosticsMu.RUnlock()
-	return c.diagnostics[uri]
+	return c.diagnostics[normalizeURI(uri)]
+}
+
+func (c *Client) GetDiagnosticsForPath(path string) []Diagnostic {
+	return c.GetDiagnostics(fileToURI(path))
 }
 
 func (c *Client) ClearDiagnostics(uri string) {
 	c.diagnosticsMu.Lock()
 	defer c.diagnosticsMu.Unlock()
-	delete(c.diagnostics, uri)
+	delete(c.diagnostics, normalizeURI(uri))
+	delete(c.diagVersions, normalizeURI(uri))
 }
 
 func (c *Client) IsInitialized() bool {
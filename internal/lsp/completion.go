@@ -0,0 +1,135 @@
+package lsp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const CompletionMinInterval = 50 * time.Millisecond
+
+// completionTriggerInvoked is the LSP trigger kind for completion asked
+// for as the user types an identifier, or explicitly.
+const completionTriggerInvoked = 1
+
+var ErrStaleCompletion = errors.New("completion superseded by a newer request")
+
+type completionState struct {
+	mu            sync.Mutex
+	seq           int
+	inflightID    int
+	cancel        context.CancelFunc
+	lastRequest   time.Time
+	latestVersion int
+}
+
+func (s *completionState) begin(version int) (context.Context, int, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.seq++
+	if version > s.latestVersion {
+		s.latestVersion = version
+	}
+
+	wait := CompletionMinInterval - time.Since(s.lastRequest)
+	if wait < 0 {
+		wait = 0
+	}
+	s.lastRequest = time.Now().Add(wait)
+	return ctx, s.seq, wait
+}
+
+func (s *completionState) current(seq, version int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return seq == s.seq && version >= s.latestVersion
+}
+
+func (s *completionState) swapInflight(id int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.inflightID
+	s.inflightID = id
+	return prev
+}
+
+func (s *completionState) finish(seq int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if seq == s.seq {
+		s.inflightID = 0
+		if s.cancel != nil {
+			s.cancel()
+			s.cancel = nil
+		}
+	}
+}
+
+func (c *Client) RequestCompletions(path string, line, col, version int) (*CompletionsReceivedMsg, error) {
+	if !c.IsInitialized() {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	ctx, seq, wait := c.completion.begin(version)
+	defer c.completion.finish(seq)
+
+	if wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ErrStaleCompletion
+		}
+	}
+	if !c.completion.current(seq, version) || version < c.DocumentVersion(path) {
+		return nil, ErrStaleCompletion
+	}
+
+	params := &CompletionParams{
+		TextDocumentPositionParams: TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{
+				URI: c.documentURI(path),
+			},
+			Position: Position{
+				Line:      line,
+				Character: col,
+			},
+		},
+		Context: CompletionContext{TriggerKind: completionTriggerInvoked},
+	}
+
+	id, err := c.SendRequest("textDocument/completion", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send completion request: %w", err)
+	}
+	if prev := c.completion.swapInflight(id); prev != 0 {
+		c.CancelRequest(prev)
+	}
+
+	resp, err := c.WaitForResponseContext(ctx, id)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrStaleCompletion
+		}
+		return nil, fmt.Errorf("failed to receive completion response: %w", err)
+	}
+	if !c.completion.current(seq, version) || version < c.DocumentVersion(path) {
+		return nil, ErrStaleCompletion
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("completion failed: %s", resp.Error.Message)
+	}
+
+	return &CompletionsReceivedMsg{
+		Path:    path,
+		Version: version,
+		Items:   parseCompletionResult(resp.Result),
+	}, nil
+}
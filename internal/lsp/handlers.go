@@ -7,18 +7,6 @@ import (
 	"strings"
 )
 
-func fileToURI(path string) string {
-	abs, err := filepath.Abs(path)
-	if err != nil {
-		abs = path
-	}
-	return "file://" + abs
-}
-
-func uriToPath(uri string) string {
-	return strings.TrimPrefix(uri, "file://")
-}
-
 func getLanguageID(path string) string {
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
@@ -68,12 +56,13 @@ func getLanguageID(path string) string {
 func (c *Client) Initialize(rootPath string) error {
 	params := &InitializeParams{
 		ProcessID: 0,
-		RootURI:   fileToURI(rootPath),
+		RootURI:   c.documentURI(rootPath),
 		Capabilities: ClientCapabilities{
 			TextDocument: TextDocumentClientCapabilities{
 				Completion: CompletionClientCapabilities{
+					// The editor inserts completions as plain text.
 					CompletionItem: CompletionItemCapabilities{
-						SnippetSupport: true,
+						SnippetSupport: false,
 					},
 				},
 				Definition: DefinitionClientCapabilities{
@@ -89,6 +78,9 @@ func (c *Client) Initialize(rootPath string) error {
 		},
 		Trace: "off",
 	}
+	if c.settings != nil {
+		params.InitializationOptions = c.settings
+	}
 
 	id, err := c.SendRequest("initialize", params)
 	if err != nil {
@@ -104,32 +96,52 @@ func (c *Client) Initialize(rootPath string) error {
 		return fmt.Errorf("initialize failed: %s", resp.Error.Message)
 	}
 
+	c.syncKind = parseSyncKind(resp.Result)
 	c.SetInitialized(true)
 
 	if err := c.SendNotification("initialized", struct{}{}); err != nil {
 		return fmt.Errorf("failed to send initialized notification: %w", err)
 	}
+	if err := c.sendSettings(); err != nil {
+		return fmt.Errorf("failed to send settings: %w", err)
+	}
 
 	return nil
 }
 
-func (c *Client) OpenDocument(path string, content string) error {
+func (c *Client) OpenDocument(path string, content string, version int) error {
 	if !c.IsInitialized() {
 		return fmt.Errorf("client not initialized")
 	}
 
 	params := &DidOpenTextDocumentParams{
 		TextDocument: TextDocumentItem{
-			URI:        fileToURI(path),
+			URI:        c.documentURI(path),
 			LanguageID: getLanguageID(path),
-			Version:    1,
+			Version:    version,
 			Text:       content,
 		},
 	}
 
+	c.setVersion(path, version)
 	return c.SendNotification("textDocument/didOpen", params)
 }
 
+// CloseDocument tells the server path is no longer open, as when its file
+// was renamed, and drops its version and diagnostics.
+func (c *Client) CloseDocument(path string) error {
+	if !c.IsInitialized() {
+		return fmt.Errorf("client not initialized")
+	}
+
+	params := &DidCloseTextDocumentParams{
+		TextDocument: TextDocumentIdentifier{URI: c.documentURI(path)},
+	}
+
+	c.forgetDocument(path)
+	return c.SendNotification("textDocument/didClose", params)
+}
+
 func (c *Client) DidChangeDocument(path string, content string, version int) error {
 	if !c.IsInitialized() {
 		return fmt.Errorf("client not initialized")
@@ -137,7 +149,7 @@ func (c *Client) DidChangeDocument(path string, content string, version int) err
 
 	params := &DidChangeTextDocumentParams{
 		TextDocument: VersionedTextDocumentIdentifier{
-			URI:     fileToURI(path),
+			URI:     c.documentURI(path),
 			Version: version,
 		},
 		ContentChanges: []TextDocumentContentChangeEvent{
@@ -147,6 +159,48 @@ func (c *Client) DidChangeDocument(path string, content string, version int) err
 		},
 	}
 
+	c.setVersion(path, version)
+	return c.SendNotification("textDocument/didChange", params)
+}
+
+// parseSyncKind reads how the server wants document changes sent from its
+// initialize result; textDocumentSync is either the kind or an options
+// object holding it.
+func parseSyncKind(result interface{}) int {
+	res, _ := result.(map[string]interface{})
+	caps, _ := res["capabilities"].(map[string]interface{})
+	switch sync := caps["textDocumentSync"].(type) {
+	case float64:
+		return int(sync)
+	case map[string]interface{}:
+		change, _ := sync["change"].(float64)
+		return int(change)
+	}
+	return SyncNone
+}
+
+// IncrementalSync reports whether the server takes changed ranges in
+// didChange rather than the whole document.
+func (c *Client) IncrementalSync() bool {
+	return c.syncKind == SyncIncremental
+}
+
+// DidChangeRanges sends edits made since the last version, oldest first.
+// Only use it when IncrementalSync is true.
+func (c *Client) DidChangeRanges(path string, changes []TextDocumentContentChangeEvent, version int) error {
+	if !c.IsInitialized() {
+		return fmt.Errorf("client not initialized")
+	}
+
+	params := &DidChangeTextDocumentParams{
+		TextDocument: VersionedTextDocumentIdentifier{
+			URI:     c.documentURI(path),
+			Version: version,
+		},
+		ContentChanges: changes,
+	}
+
+	c.setVersion(path, version)
 	return c.SendNotification("textDocument/didChange", params)
 }
 
@@ -158,7 +212,7 @@ func (c *Client) GetCompletions(path string, line, col int) ([]CompletionItem, e
 	params := &CompletionParams{
 		TextDocumentPositionParams: TextDocumentPositionParams{
 			TextDocument: TextDocumentIdentifier{
-				URI: fileToURI(path),
+				URI: c.documentURI(path),
 			},
 			Position: Position{
 				Line:      line,
@@ -181,11 +235,15 @@ func (c *Client) GetCompletions(path string, line, col int) ([]CompletionItem, e
 		return nil, fmt.Errorf("completion failed: %s", resp.Error.Message)
 	}
 
+	return parseCompletionResult(resp.Result), nil
+}
+
+func parseCompletionResult(raw interface{}) []CompletionItem {
 	var items []CompletionItem
 
-	switch result := resp.Result.(type) {
+	switch result := raw.(type) {
 	case nil:
-		return nil, nil
+		return nil
 	case []interface{}:
 		for _, item := range result {
 			var ci CompletionItem
@@ -216,7 +274,7 @@ func (c *Client) GetCompletions(path string, line, col int) ([]CompletionItem, e
 		}
 	}
 
-	return items, nil
+	return items
 }
 
 func (c *Client) GoToDefinition(path string, line, col int) (*Location, error) {
@@ -226,7 +284,7 @@ func (c *Client) GoToDefinition(path string, line, col int) (*Location, error) {
 
 	params := &TextDocumentPositionParams{
 		TextDocument: TextDocumentIdentifier{
-			URI: fileToURI(path),
+			URI: c.documentURI(path),
 		},
 		Position: Position{
 			Line:      line,
@@ -260,6 +318,7 @@ func (c *Client) GoToDefinition(path string, line, col int) (*Location, error) {
 		if err := json.Unmarshal(data, &loc); err != nil {
 			return nil, err
 		}
+		loc.URI = c.localURI(loc.URI)
 		return &loc, nil
 	case []interface{}:
 		if len(result) > 0 {
@@ -271,6 +330,7 @@ func (c *Client) GoToDefinition(path string, line, col int) (*Location, error) {
 			if err := json.Unmarshal(data, &loc); err != nil {
 				return nil, err
 			}
+			loc.URI = c.localURI(loc.URI)
 			return &loc, nil
 		}
 	}
@@ -278,6 +338,62 @@ func (c *Client) GoToDefinition(path string, line, col int) (*Location, error) {
 	return nil, nil
 }
 
+func (c *Client) Hover(path string, line, col int) (string, error) {
+	if !c.IsInitialized() {
+		return "", fmt.Errorf("client not initialized")
+	}
+
+	params := &TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{
+			URI: c.documentURI(path),
+		},
+		Position: Position{
+			Line:      line,
+			Character: col,
+		},
+	}
+
+	id, err := c.SendRequest("textDocument/hover", params)
+	if err != nil {
+		return "", fmt.Errorf("failed to send hover request: %w", err)
+	}
+
+	resp, err := c.WaitForResponse(id)
+	if err != nil {
+		return "", fmt.Errorf("failed to receive hover response: %w", err)
+	}
+
+	if resp.Error != nil {
+		return "", fmt.Errorf("hover failed: %s", resp.Error.Message)
+	}
+
+	result, ok := resp.Result.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	return strings.TrimSpace(hoverContents(result["contents"])), nil
+}
+
+func hoverContents(raw interface{}) string {
+	switch v := raw.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if value, ok := v["value"].(string); ok {
+			return value
+		}
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := hoverContents(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, "\n\n")
+	}
+	return ""
+}
+
 func (c *Client) Shutdown() error {
 	if !c.IsInitialized() {
 		return nil
@@ -0,0 +1,64 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLogLines bounds the trace kept for each server.
+const maxLogLines = 5000
+
+// serverLog traces the messages exchanged with a server along with what it
+// writes to stderr or sends as window/logMessage.
+type serverLog struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *serverLog) addf(format string, args ...interface{}) {
+	stamp := time.Now().Format("15:04:05.000 ")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range strings.Split(fmt.Sprintf(format, args...), "\n") {
+		l.lines = append(l.lines, stamp+line)
+	}
+	if over := len(l.lines) - maxLogLines; over > 0 {
+		l.lines = append(l.lines[:0], l.lines[over:]...)
+	}
+}
+
+// Log returns the trace of the session with the server, oldest first.
+func (c *Client) Log() string {
+	c.log.mu.Lock()
+	defer c.log.mu.Unlock()
+	return strings.Join(c.log.lines, "\n")
+}
+
+func (c *Client) stderrLoop() {
+	defer c.wg.Done()
+
+	scanner := bufio.NewScanner(c.stderr)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		c.log.addf("stderr: %s", scanner.Text())
+	}
+}
+
+var messageTypes = map[int]string{1: "error", 2: "warning", 3: "info", 4: "log"}
+
+func (c *Client) logMessage(data []byte) {
+	var notif struct {
+		Params struct {
+			Type    int    `json:"type"`
+			Message string `json:"message"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(data, &notif); err != nil {
+		return
+	}
+	c.log.addf("%s: %s", messageTypes[notif.Params.Type], notif.Params.Message)
+}
@@ -122,6 +122,10 @@ type DidOpenTextDocumentParams struct {
 	TextDocument TextDocumentItem `json:"textDocument"`
 }
 
+type DidCloseTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
 type DidChangeTextDocumentParams struct {
 	TextDocument   VersionedTextDocumentIdentifier `json:"textDocument"`
 	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
@@ -153,6 +157,10 @@ type CompletionList struct {
 	Items        []CompletionItem `json:"items"`
 }
 
+type CancelParams struct {
+	ID int `json:"id"`
+}
+
 type PublishDiagnosticsParams struct {
 	URI         string       `json:"uri"`
 	Version     int          `json:"version,omitempty"`
@@ -209,3 +217,10 @@ func ReadMessage(reader *bufio.Reader) ([]byte, error) {
 
 	return body, nil
 }
+
+// How a server asks for document changes to be sent.
+const (
+	SyncNone        = 0
+	SyncFull        = 1
+	SyncIncremental = 2
+)
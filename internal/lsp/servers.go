@@ -0,0 +1,36 @@
+package lsp
+
+import "os/exec"
+
+var DefaultServers = map[string][]string{
+	"go":              {"gopls"},
+	"python":          {"pylsp"},
+	"rust":            {"rust-analyzer"},
+	"c":               {"clangd"},
+	"cpp":             {"clangd"},
+	"javascript":      {"typescript-language-server", "--stdio"},
+	"javascriptreact": {"typescript-language-server", "--stdio"},
+	"typescript":      {"typescript-language-server", "--stdio"},
+	"typescriptreact": {"typescript-language-server", "--stdio"},
+}
+
+// FallbackServers are tried when the default server is not installed.
+var FallbackServers = map[string][]string{
+	"python": {"pyright-langserver", "--stdio"},
+}
+
+func LanguageID(path string) string {
+	return getLanguageID(path)
+}
+
+func ServerCommand(languageID string) ([]string, bool) {
+	for _, cmd := range [][]string{DefaultServers[languageID], FallbackServers[languageID]} {
+		if len(cmd) == 0 {
+			continue
+		}
+		if _, err := exec.LookPath(cmd[0]); err == nil {
+			return cmd, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,66 @@
+package lsp
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+type serverResponse struct {
+	JsonRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Result  interface{} `json:"result"`
+}
+
+// SetSettings sets the workspace settings sent to the server at startup and
+// served back for workspace/configuration requests. Call it before Initialize.
+func (c *Client) SetSettings(settings map[string]interface{}) {
+	c.settings = settings
+}
+
+func (c *Client) sendSettings() error {
+	if c.settings == nil {
+		return nil
+	}
+	return c.SendNotification("workspace/didChangeConfiguration", map[string]interface{}{
+		"settings": c.settings,
+	})
+}
+
+// handleServerRequest answers requests the server sends to the client.
+// Anything other than workspace/configuration gets a null result so the
+// server does not wait on it.
+func (c *Client) handleServerRequest(id int, method string, data []byte) {
+	var result interface{}
+	if method == "workspace/configuration" {
+		var req struct {
+			Params struct {
+				Items []struct {
+					Section string `json:"section"`
+				} `json:"items"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(data, &req); err == nil {
+			items := make([]interface{}, len(req.Params.Items))
+			for i, item := range req.Params.Items {
+				items[i] = c.settingsSection(item.Section)
+			}
+			result = items
+		}
+	}
+	_ = c.write(&serverResponse{JsonRPC: "2.0", ID: id, Result: result})
+}
+
+func (c *Client) settingsSection(section string) interface{} {
+	if section == "" {
+		return c.settings
+	}
+	var value interface{} = c.settings
+	for _, key := range strings.Split(section, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
@@ -15,6 +15,11 @@ type Location struct {
 	Range Range  `json:"range"`
 }
 
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
 type DiagnosticSeverity int
 
 const (
@@ -32,6 +37,20 @@ type Diagnostic struct {
 	Message  string             `json:"message"`
 }
 
+func (d Diagnostic) Contains(line, col int) bool {
+	start, end := d.Range.Start, d.Range.End
+	if line < start.Line || line > end.Line {
+		return false
+	}
+	if line == start.Line && col < start.Character {
+		return false
+	}
+	if line == end.Line && col > end.Character {
+		return false
+	}
+	return true
+}
+
 type CompletionItemKind int
 
 const (
@@ -66,21 +85,41 @@ type CompletionItem struct {
 	Label            string             `json:"label"`
 	Kind             CompletionItemKind `json:"kind,omitempty"`
 	Detail           string             `json:"detail,omitempty"`
-	Documentation    string             `json:"documentation,omitempty"`
+	// Documentation is a string or a MarkupContent.
+	Documentation    interface{}        `json:"documentation,omitempty"`
 	InsertText       string             `json:"insertText,omitempty"`
+	TextEdit         *TextEdit          `json:"textEdit,omitempty"`
 	SortText         string             `json:"sortText,omitempty"`
 	FilterText       string             `json:"filterText,omitempty"`
 	Preselect        bool               `json:"preselect,omitempty"`
 	Data             interface{}        `json:"data,omitempty"`
 }
 
+// Text is what accepting the item inserts in place of the word typed so
+// far.
+func (ci CompletionItem) Text() string {
+	switch {
+	case ci.TextEdit != nil:
+		return ci.TextEdit.NewText
+	case ci.InsertText != "":
+		return ci.InsertText
+	}
+	return ci.Label
+}
+
 type DiagnosticsReceivedMsg struct {
 	URI         string
 	Diagnostics []Diagnostic
 }
 
 type CompletionsReceivedMsg struct {
-	Items []CompletionItem
+	Path    string
+	Version int
+	Items   []CompletionItem
+}
+
+func (m CompletionsReceivedMsg) IsStale(currentVersion int) bool {
+	return m.Version < currentVersion
 }
 
 type DefinitionReceivedMsg struct {
@@ -0,0 +1,95 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"tron/pkg/pathutil"
+)
+
+// PathMapper translates between local paths and the paths a language server
+// sees, for servers running on another machine.
+type PathMapper interface {
+	ToRemote(path string) string
+	ToLocal(path string) string
+}
+
+func (c *Client) SetPathMapper(m PathMapper) {
+	c.pathMapper = m
+}
+
+func (c *Client) documentURI(path string) string {
+	if c.pathMapper != nil {
+		return pathToURI(c.pathMapper.ToRemote(path))
+	}
+	return fileToURI(path)
+}
+
+func (c *Client) localURI(uri string) string {
+	if c.pathMapper != nil && strings.HasPrefix(uri, "file:") {
+		return fileToURI(c.pathMapper.ToLocal(uriToPath(uri)))
+	}
+	return normalizeURI(uri)
+}
+
+func fileToURI(path string) string {
+	return pathToURI(pathutil.Canonical(path))
+}
+
+func pathToURI(path string) string {
+	p := filepath.ToSlash(path)
+	u := url.URL{Scheme: "file"}
+
+	if strings.HasPrefix(p, "//") {
+		host, rest, _ := strings.Cut(p[2:], "/")
+		u.Host = host
+		u.Path = "/" + rest
+	} else {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		u.Path = p
+	}
+
+	return u.String()
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return strings.TrimPrefix(uri, "file://")
+	}
+
+	p := u.Path
+	if u.Host != "" && u.Host != "localhost" {
+		p = "//" + u.Host + p
+	} else if hasDriveLetter(p) {
+		p = p[1:]
+	}
+
+	return filepath.FromSlash(p)
+}
+
+func normalizeURI(uri string) string {
+	if !strings.HasPrefix(uri, "file:") {
+		return uri
+	}
+	return fileToURI(uriToPath(uri))
+}
+
+func hasDriveLetter(p string) bool {
+	if len(p) < 3 || p[0] != '/' || p[2] != ':' {
+		return false
+	}
+	c := p[1]
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func FileToURI(path string) string {
+	return fileToURI(path)
+}
+
+func URIToPath(uri string) string {
+	return uriToPath(uri)
+}
@@ -0,0 +1,53 @@
+package lsp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestURIRoundTrip(t *testing.T) {
+	tests := []struct {
+		path string
+		uri  string
+	}{
+		{"/home/me/project/main.go", "file:///home/me/project/main.go"},
+		{"/home/me/my project/main file.go", "file:///home/me/my%20project/main%20file.go"},
+		{"/home/me/café/日本語.go", "file:///home/me/caf%C3%A9/%E6%97%A5%E6%9C%AC%E8%AA%9E.go"},
+		{"/tmp/100%/a#b?c.go", "file:///tmp/100%25/a%23b%3Fc.go"},
+		{"/tmp/a+b/[x]/c;d.go", "file:///tmp/a+b/%5Bx%5D/c;d.go"},
+		{"C:/Users/me/main.go", "file:///C:/Users/me/main.go"},
+		{"d:/My Documents/ü.go", "file:///d:/My%20Documents/%C3%BC.go"},
+		{"//server/share/dir/main.go", "file://server/share/dir/main.go"},
+	}
+	for _, tt := range tests {
+		path := filepath.FromSlash(tt.path)
+		uri := pathToURI(path)
+		if uri != tt.uri {
+			t.Errorf("pathToURI(%q) = %q, want %q", path, uri, tt.uri)
+		}
+		if got := uriToPath(uri); got != path {
+			t.Errorf("uriToPath(%q) = %q, want %q", uri, got, path)
+		}
+	}
+}
+
+// Servers may encode more than pathToURI does, or less.
+func TestURIToPathDecodes(t *testing.T) {
+	tests := []struct {
+		uri  string
+		path string
+	}{
+		{"file:///home/me/a%20b/%C3%A9t%C3%A9.go", "/home/me/a b/été.go"},
+		{"file:///home/me/%61%62c.go", "/home/me/abc.go"},
+		{"file:///home/me/été.go", "/home/me/été.go"},
+		{"file:///c%3A/Users/me/main.go", "c:/Users/me/main.go"},
+		{"file:///C:/Users/me/a%23b.go", "C:/Users/me/a#b.go"},
+		{"file://localhost/home/me/main.go", "/home/me/main.go"},
+		{"file://server/share/main.go", "//server/share/main.go"},
+	}
+	for _, tt := range tests {
+		if got, want := uriToPath(tt.uri), filepath.FromSlash(tt.path); got != want {
+			t.Errorf("uriToPath(%q) = %q, want %q", tt.uri, got, want)
+		}
+	}
+}
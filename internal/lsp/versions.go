@@ -0,0 +1,64 @@
+package lsp
+
+// Document versions increase with every didChange. Diagnostics carry the
+// version they were computed for, so ones that arrive after further edits
+// can be told apart from current ones.
+
+// setVersion records the version of path last sent to the server.
+func (c *Client) setVersion(path string, version int) {
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+	if c.versions == nil {
+		c.versions = make(map[string]int)
+	}
+	c.versions[fileToURI(path)] = version
+}
+
+// forgetDocument drops the version and diagnostics kept for path.
+func (c *Client) forgetDocument(path string) {
+	uri := fileToURI(path)
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+	delete(c.versions, uri)
+	delete(c.diagnostics, uri)
+	delete(c.diagVersions, uri)
+}
+
+// DocumentVersion returns the version of path last sent to the server, or
+// zero if it is not open.
+func (c *Client) DocumentVersion(path string) int {
+	c.diagnosticsMu.RLock()
+	defer c.diagnosticsMu.RUnlock()
+	return c.versions[fileToURI(path)]
+}
+
+// storeDiagnostics keeps published diagnostics unless they were computed
+// for an older version than ones already stored. Servers that leave the
+// version out are taken at their word.
+func (c *Client) storeDiagnostics(params PublishDiagnosticsParams) {
+	uri := c.localURI(params.URI)
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+	if c.diagVersions == nil {
+		c.diagVersions = make(map[string]int)
+	}
+	if params.Version != 0 && params.Version < c.diagVersions[uri] {
+		return
+	}
+	c.diagnostics[uri] = params.Diagnostics
+	c.diagVersions[uri] = params.Version
+}
+
+// CurrentDiagnostics returns path's diagnostics if they were computed for
+// the version last sent. Older ones are left out, since the edits since
+// may have moved the text they point at; the server publishes again once
+// it catches up.
+func (c *Client) CurrentDiagnostics(path string) []Diagnostic {
+	uri := fileToURI(path)
+	c.diagnosticsMu.RLock()
+	defer c.diagnosticsMu.RUnlock()
+	if v := c.diagVersions[uri]; v != 0 && v < c.versions[uri] {
+		return nil
+	}
+	return c.diagnostics[uri]
+}
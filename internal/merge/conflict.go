@@ -0,0 +1,103 @@
+// Package merge finds and resolves git-style conflict blocks.
+package merge
+
+import "strings"
+
+const (
+	oursMarker   = "<<<<<<<"
+	baseMarker   = "|||||||"
+	sepMarker    = "======="
+	theirsMarker = ">>>>>>>"
+)
+
+// Conflict is one marked block. Start is the line of the opening marker and
+// End the line of the closing one; Base is nil unless the block was written
+// in diff3 style.
+type Conflict struct {
+	Start, End  int
+	OursLabel   string
+	TheirsLabel string
+	Ours        []string
+	Base        []string
+	Theirs      []string
+}
+
+type Resolution int
+
+const (
+	AcceptOurs Resolution = iota
+	AcceptTheirs
+	AcceptBoth
+)
+
+// Parse returns the complete conflict blocks in lines, in order.
+// Unterminated blocks are ignored.
+func Parse(lines []string) []Conflict {
+	var conflicts []Conflict
+	for i := 0; i < len(lines); i++ {
+		if !isMarker(lines[i], oursMarker) {
+			continue
+		}
+		c, ok := parseBlock(lines, i)
+		if !ok {
+			continue
+		}
+		conflicts = append(conflicts, c)
+		i = c.End
+	}
+	return conflicts
+}
+
+func parseBlock(lines []string, start int) (Conflict, bool) {
+	c := Conflict{Start: start, OursLabel: label(lines[start], oursMarker)}
+	section := &c.Ours
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case isMarker(line, oursMarker):
+			return Conflict{}, false
+		case isMarker(line, baseMarker) && section == &c.Ours:
+			c.Base = []string{}
+			section = &c.Base
+		case isMarker(line, sepMarker) && section != &c.Theirs:
+			section = &c.Theirs
+		case isMarker(line, theirsMarker) && section == &c.Theirs:
+			c.End = i
+			c.TheirsLabel = label(line, theirsMarker)
+			return c, true
+		default:
+			*section = append(*section, line)
+		}
+	}
+	return Conflict{}, false
+}
+
+func isMarker(line, marker string) bool {
+	return line == marker || strings.HasPrefix(line, marker+" ")
+}
+
+func label(line, marker string) string {
+	return strings.TrimSpace(strings.TrimPrefix(line, marker))
+}
+
+// Lines returns what the block is replaced with under r.
+func (c Conflict) Lines(r Resolution) []string {
+	switch r {
+	case AcceptOurs:
+		return c.Ours
+	case AcceptTheirs:
+		return c.Theirs
+	}
+	both := make([]string, 0, len(c.Ours)+len(c.Theirs))
+	return append(append(both, c.Ours...), c.Theirs...)
+}
+
+// At returns the index of the conflict containing line, or -1.
+func At(conflicts []Conflict, line int) int {
+	for i, c := range conflicts {
+		if line >= c.Start && line <= c.End {
+			return i
+		}
+	}
+	return -1
+}
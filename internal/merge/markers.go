@@ -0,0 +1,28 @@
+package merge
+
+import (
+	"strings"
+
+	"tron/pkg/diff"
+)
+
+// Markers merges two versions of a file into one text, writing every region
+// where they differ as a conflict block labelled with oursLabel and
+// theirsLabel.
+func Markers(ours, theirs, oursLabel, theirsLabel string) string {
+	a, b, edits := diff.Text(ours, theirs, diff.Histogram)
+
+	var out []string
+	last := 0
+	for _, e := range edits {
+		out = append(out, a[last:e.OldStart]...)
+		out = append(out, oursMarker+" "+oursLabel)
+		out = append(out, a[e.OldStart:e.OldEnd]...)
+		out = append(out, sepMarker)
+		out = append(out, b[e.NewStart:e.NewEnd]...)
+		out = append(out, theirsMarker+" "+theirsLabel)
+		last = e.OldEnd
+	}
+	out = append(out, a[last:]...)
+	return strings.Join(out, "\n")
+}
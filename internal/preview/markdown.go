@@ -0,0 +1,152 @@
+package preview
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingRegex    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	listItemRegex   = regexp.MustCompile(`^\s*([-*+]|\d+\.)\s+(.*)$`)
+	inlineCodeRegex = regexp.MustCompile("`([^`]+)`")
+	boldRegex       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	italicRegex     = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+	imageRegex      = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	linkRegex       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// RenderMarkdown converts the common subset of Markdown used in READMEs
+// and notes: headings, paragraphs, lists, quotes, fenced code and inline
+// formatting.
+func RenderMarkdown(src string) string {
+	var out strings.Builder
+	var paragraph []string
+	listTag := ""
+	inCode := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + renderInline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				out.WriteString("</code></pre>\n")
+			} else {
+				flushParagraph()
+				closeList()
+				lang := strings.TrimPrefix(trimmed, "```")
+				out.WriteString(`<pre><code class="language-` + html.EscapeString(lang) + `">`)
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case headingRegex.MatchString(trimmed):
+			flushParagraph()
+			closeList()
+			m := headingRegex.FindStringSubmatch(trimmed)
+			level := string(rune('0' + len(m[1])))
+			out.WriteString("<h" + level + ">" + renderInline(m[2]) + "</h" + level + ">\n")
+		case trimmed == "---" || trimmed == "***":
+			flushParagraph()
+			closeList()
+			out.WriteString("<hr>\n")
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			closeList()
+			out.WriteString("<blockquote>" + renderInline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))) + "</blockquote>\n")
+		case listItemRegex.MatchString(line):
+			flushParagraph()
+			m := listItemRegex.FindStringSubmatch(line)
+			tag := "ul"
+			if strings.HasSuffix(m[1], ".") {
+				tag = "ol"
+			}
+			if tag != listTag {
+				closeList()
+				out.WriteString("<" + tag + ">\n")
+				listTag = tag
+			}
+			out.WriteString("<li>" + renderInline(m[2]) + "</li>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	if inCode {
+		out.WriteString("</code></pre>\n")
+	}
+	flushParagraph()
+	closeList()
+	return out.String()
+}
+
+func renderInline(text string) string {
+	// Protect code spans from the other rules.
+	var codes []string
+	text = inlineCodeRegex.ReplaceAllStringFunc(text, func(m string) string {
+		codes = append(codes, "<code>"+html.EscapeString(m[1:len(m)-1])+"</code>")
+		return "\x00" + strconv.Itoa(len(codes)-1) + "\x00"
+	})
+
+	text = html.EscapeString(text)
+	text = imageRegex.ReplaceAllStringFunc(text, func(m string) string {
+		sub := imageRegex.FindStringSubmatch(m)
+		if !safeURL(sub[2]) {
+			return sub[1]
+		}
+		return `<img alt="` + sub[1] + `" src="` + sub[2] + `">`
+	})
+	text = linkRegex.ReplaceAllStringFunc(text, func(m string) string {
+		sub := linkRegex.FindStringSubmatch(m)
+		if !safeURL(sub[2]) {
+			return sub[1]
+		}
+		return `<a href="` + sub[2] + `">` + sub[1] + `</a>`
+	})
+	text = boldRegex.ReplaceAllString(text, `<strong>$1$2</strong>`)
+	text = italicRegex.ReplaceAllString(text, `<em>$1$2</em>`)
+
+	for i, code := range codes {
+		text = strings.Replace(text, "\x00"+strconv.Itoa(i)+"\x00", code, 1)
+	}
+	return text
+}
+
+// safeURL reports whether an escaped link target is relative or uses http,
+// https or mailto, so a document cannot run script through javascript:
+// or data: links.
+func safeURL(escaped string) bool {
+	u, err := url.Parse(html.UnescapeString(escaped))
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultAddr  = "127.0.0.1:8484"
+	eventsPath   = "/__tron/events"
+	PreviewPath  = "/__tron/preview"
+	reloadScript = `<script>new EventSource("` + eventsPath + `").onmessage = () => location.reload();</script>`
+)
+
+// Server serves the workspace and a rendered preview of the current buffer,
+// reloading open pages whenever Reload is called.
+type Server struct {
+	rootPath string
+	listener net.Listener
+	server   *http.Server
+
+	mu          sync.Mutex
+	previewPath string
+	preview     string
+	clients     map[chan struct{}]bool
+}
+
+func Start(rootPath string) (*Server, error) {
+	ln, err := net.Listen("tcp", DefaultAddr)
+	if err != nil {
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Server{
+		rootPath: rootPath,
+		listener: ln,
+		clients:  make(map[chan struct{}]bool),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(eventsPath, s.handleEvents)
+	mux.HandleFunc(PreviewPath, s.handlePreview)
+	mux.HandleFunc("/", s.handleFile)
+	s.server = &http.Server{Handler: localOnly(mux)}
+
+	go s.server.Serve(ln)
+	return s, nil
+}
+
+func (s *Server) URL() string {
+	return "http://" + s.listener.Addr().String()
+}
+
+func (s *Server) Close() error {
+	s.mu.Lock()
+	for ch := range s.clients {
+		close(ch)
+	}
+	s.clients = make(map[chan struct{}]bool)
+	s.mu.Unlock()
+	return s.server.Close()
+}
+
+func (s *Server) SetPreview(path, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.previewPath = path
+	s.preview = content
+}
+
+func (s *Server) Reload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.clients[ch] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, ch)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) handlePreview(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	path, content := s.previewPath, s.preview
+	s.mu.Unlock()
+	writeHTML(w, render(path, []byte(content)))
+}
+
+// localOnly refuses requests whose Host is not this machine by name, so a
+// web page cannot reach the server through DNS rebinding.
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if host != "127.0.0.1" && host != "localhost" {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hidden reports whether a slash-separated path has a segment starting
+// with a dot, such as .git, .env or .tron, which are never served.
+func hidden(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// dotlessFS is the workspace for directory listings, leaving out hidden
+// entries.
+type dotlessFS struct {
+	http.FileSystem
+}
+
+type dotlessFile struct {
+	http.File
+}
+
+func (fsys dotlessFS) Open(name string) (http.File, error) {
+	if hidden(name) {
+		return nil, os.ErrNotExist
+	}
+	f, err := fsys.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return dotlessFile{f}, nil
+}
+
+func (f dotlessFile) Readdir(n int) ([]os.FileInfo, error) {
+	infos, err := f.File.Readdir(n)
+	visible := infos[:0]
+	for _, info := range infos {
+		if !strings.HasPrefix(info.Name(), ".") {
+			visible = append(visible, info)
+		}
+	}
+	return visible, err
+}
+
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	if hidden(r.URL.Path) {
+		http.NotFound(w, r)
+		return
+	}
+	rel := filepath.FromSlash(strings.TrimPrefix(r.URL.Path, "/"))
+	path := filepath.Join(s.rootPath, filepath.Clean(string(filepath.Separator)+rel))
+
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		if _, err := os.Stat(filepath.Join(path, "index.html")); err == nil {
+			path = filepath.Join(path, "index.html")
+		} else {
+			http.FileServer(dotlessFS{http.Dir(s.rootPath)}).ServeHTTP(w, r)
+			return
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm", ".md", ".markdown":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		writeHTML(w, render(path, data))
+	default:
+		http.ServeFile(w, r, path)
+	}
+}
+
+func render(path string, data []byte) []byte {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		return injectReload(data)
+	case ".md", ".markdown":
+		return page(filepath.Base(path), RenderMarkdown(string(data)))
+	}
+	return page(filepath.Base(path), "<pre>"+html.EscapeString(string(data))+"</pre>")
+}
+
+func page(title, body string) []byte {
+	return []byte(`<!DOCTYPE html><html><head><meta charset="utf-8"><title>` + html.EscapeString(title) + `</title>` +
+		`<style>body{font-family:system-ui,sans-serif;max-width:52em;margin:2em auto;padding:0 1em;line-height:1.5}` +
+		`pre{background:#f4f4f4;padding:1em;overflow:auto}code{font-family:ui-monospace,monospace}</style>` +
+		`</head><body>` + body + reloadScript + `</body></html>`)
+}
+
+func injectReload(data []byte) []byte {
+	if i := bytes.LastIndex(bytes.ToLower(data), []byte("</body>")); i >= 0 {
+		return append(append(append([]byte{}, data[:i]...), reloadScript...), data[i:]...)
+	}
+	return append(append([]byte{}, data...), reloadScript...)
+}
+
+func writeHTML(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(data)
+}
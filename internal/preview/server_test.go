@@ -0,0 +1,83 @@
+package preview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServerRefusesForeignHostsAndDotfiles(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"index.txt":           "hello",
+		".env":                "SECRET=1",
+		".tron/database.json": "{}",
+		"docs/.hidden":        "x",
+		"docs/guide.txt":      "guide",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := Start(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	handler := s.server.Handler
+
+	get := func(host, path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Host = host
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	for _, host := range []string{"127.0.0.1:8484", "localhost:8484", "localhost"} {
+		if w := get(host, "/index.txt"); w.Code != http.StatusOK {
+			t.Errorf("Host %s: status %d, want 200", host, w.Code)
+		}
+	}
+	for _, host := range []string{"evil.example:8484", "evil.example", "127.0.0.1.evil.example"} {
+		if w := get(host, "/index.txt"); w.Code != http.StatusForbidden {
+			t.Errorf("Host %s: status %d, want 403", host, w.Code)
+		}
+	}
+
+	for _, path := range []string{"/.env", "/.tron/database.json", "/docs/.hidden", "/.tron/"} {
+		if w := get("localhost", path); w.Code != http.StatusNotFound {
+			t.Errorf("%s: status %d, want 404", path, w.Code)
+		}
+	}
+	for _, path := range []string{"/", "/docs/"} {
+		body := get("localhost", path).Body.String()
+		if strings.Contains(body, ".env") || strings.Contains(body, ".tron") || strings.Contains(body, ".hidden") {
+			t.Errorf("listing of %s shows hidden entries:\n%s", path, body)
+		}
+	}
+}
+
+func TestRenderMarkdownDropsUnsafeLinks(t *testing.T) {
+	for src, want := range map[string]string{
+		"[x](javascript:alert(1))":     "<p>x)</p>\n",
+		"[x](JavaScript:alert%281%29)": "<p>x</p>\n",
+		"[x](data:text/html,hi)":       "<p>x</p>\n",
+		"![x](javascript:alert(1))":    "<p>x)</p>\n",
+		"[x](https://example.com)":     `<p><a href="https://example.com">x</a></p>` + "\n",
+		"[x](mailto:a@example.com)":    `<p><a href="mailto:a@example.com">x</a></p>` + "\n",
+		"[x](docs/guide.md?a=1&b=2)":   `<p><a href="docs/guide.md?a=1&amp;b=2">x</a></p>` + "\n",
+		"![logo](img/logo.png)":        `<p><img alt="logo" src="img/logo.png"></p>` + "\n",
+	} {
+		if got := RenderMarkdown(src); got != want {
+			t.Errorf("RenderMarkdown(%q) = %q, want %q", src, got, want)
+		}
+	}
+}
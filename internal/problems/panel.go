@@ -0,0 +1,146 @@
+package problems
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"tron/internal/i18n"
+	"tron/pkg/pathutil"
+)
+
+const (
+	panelRows  = 14
+	panelWidth = 80
+)
+
+// Panel lists the problems of the last check, errors first.
+type Panel struct {
+	visible  bool
+	rootPath string
+	problems []Problem
+	selected int
+}
+
+func NewPanel(rootPath string) *Panel {
+	return &Panel{rootPath: pathutil.Canonical(rootPath)}
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+func (p *Panel) Toggle() {
+	p.visible = !p.visible
+}
+
+func (p *Panel) Open() {
+	p.visible = true
+}
+
+func (p *Panel) Problems() []Problem {
+	return p.problems
+}
+
+// SetProblems replaces the listed problems.
+func (p *Panel) SetProblems(list []Problem) {
+	p.problems = sortBySeverity(list)
+	p.selected = 0
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		p.visible = false
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.problems)-1 {
+			p.selected++
+		}
+	case "enter":
+		if p.selected >= len(p.problems) {
+			return nil
+		}
+		pr := p.problems[p.selected]
+		p.visible = false
+		return func() tea.Msg { return JumpMsg{Path: pr.Path, Line: pr.Line, Column: pr.Column} }
+	}
+	return nil
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#313244"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+
+	errors, warnings := Count(p.problems)
+	lines := []string{titleStyle.Render(i18n.T("problems.title")) + hintStyle.Render("  "+i18n.T("problems.count", errors, warnings))}
+	if len(p.problems) == 0 {
+		lines = append(lines, hintStyle.Render(i18n.T("problems.empty")))
+	}
+	start := 0
+	if p.selected >= panelRows {
+		start = p.selected - panelRows + 1
+	}
+	for i := start; i < len(p.problems) && i < start+panelRows; i++ {
+		pr := p.problems[i]
+		where := fmt.Sprintf("%s:%d:%d", p.rel(pr.Path), pr.Line+1, pr.Column+1)
+		text := severitySymbol(pr.Severity) + " " + hintStyle.Render(where) + " " + firstLine(pr.Message)
+		if pr.Code != "" {
+			text += hintStyle.Render(" [" + pr.Code + "]")
+		}
+		text = ansi.Truncate(text, panelWidth, "…")
+		if i == p.selected {
+			lines = append(lines, selectedStyle.Width(panelWidth).Render(text))
+		} else {
+			lines = append(lines, text)
+		}
+	}
+	lines = append(lines, "", hintStyle.Render(i18n.T("problems.hint")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#f38ba8")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+func (p *Panel) rel(path string) string {
+	if rel, err := filepath.Rel(p.rootPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func severitySymbol(s Severity) string {
+	switch s {
+	case Error:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("✖")
+	case Warning:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Render("▲")
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#89b4fa")).Render("●")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
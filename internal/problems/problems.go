@@ -0,0 +1,70 @@
+package problems
+
+import (
+	"sort"
+
+	"tron/pkg/pathutil"
+)
+
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+	Note
+)
+
+// Problem is an error or warning a build tool reported. Lines and columns
+// count from zero, columns in bytes like the editor's.
+type Problem struct {
+	Path      string
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+	Severity  Severity
+	Message   string
+	// Code is the tool's identifier for the kind of problem, such as
+	// E0308 or clippy::needless_return, if it has one.
+	Code string
+}
+
+// sortBySeverity puts errors before warnings and notes, keeping the
+// tool's order within each.
+func sortBySeverity(list []Problem) []Problem {
+	out := append([]Problem(nil), list...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Severity < out[j].Severity })
+	return out
+}
+
+// JumpMsg asks for the editor to go to a problem.
+type JumpMsg struct {
+	Path   string
+	Line   int
+	Column int
+}
+
+// Count returns how many of list are errors and how many warnings.
+func Count(list []Problem) (errors, warnings int) {
+	for _, p := range list {
+		switch p.Severity {
+		case Error:
+			errors++
+		case Warning:
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// ForFile returns the problems in path.
+func ForFile(list []Problem, path string) []Problem {
+	var out []Problem
+	path = pathutil.Canonical(path)
+	for _, p := range list {
+		if p.Path == path {
+			out = append(out, p)
+		}
+	}
+	return out
+}
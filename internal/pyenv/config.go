@@ -0,0 +1,62 @@
+package pyenv
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+type config struct {
+	Interpreter string `json:"interpreter"`
+}
+
+func configPath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "python.json")
+}
+
+// Selected returns the interpreter chosen for the project, falling back to
+// the first detected environment when none was chosen or it has gone away.
+func Selected(rootPath string, envs []Env) (Env, bool) {
+	if data, err := os.ReadFile(configPath(rootPath)); err == nil {
+		var c config
+		if json.Unmarshal(data, &c) == nil {
+			for _, env := range envs {
+				if env.Interpreter == c.Interpreter {
+					return env, true
+				}
+			}
+		}
+	}
+	if len(envs) == 0 {
+		return Env{}, false
+	}
+	return envs[0], true
+}
+
+func Save(rootPath string, env Env) error {
+	path := configPath(rootPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(config{Interpreter: env.Interpreter}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LSPSettings configures pyright and pylsp to resolve imports from env.
+func (env Env) LSPSettings() map[string]interface{} {
+	python := map[string]interface{}{"pythonPath": env.Interpreter}
+	settings := map[string]interface{}{"python": python}
+	if env.Prefix != "" {
+		python["venvPath"] = filepath.Dir(env.Prefix)
+		python["venv"] = filepath.Base(env.Prefix)
+		settings["pylsp"] = map[string]interface{}{
+			"plugins": map[string]interface{}{
+				"jedi": map[string]interface{}{"environment": env.Prefix},
+			},
+		}
+	}
+	return settings
+}
@@ -0,0 +1,137 @@
+package pyenv
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	KindVenv   = "venv"
+	KindPoetry = "poetry"
+	KindConda  = "conda"
+	KindSystem = "system"
+)
+
+const detectTimeout = 3 * time.Second
+
+type Env struct {
+	Name string
+	Kind string
+	// Prefix is the environment root; empty for the system interpreter.
+	Prefix      string
+	Interpreter string
+}
+
+var projectVenvDirs = []string{".venv", "venv", "env", ".env"}
+
+// Detect lists the Python environments available to the project: in-tree
+// virtualenvs first, then poetry and conda environments, then the system
+// interpreter. Duplicates are dropped.
+func Detect(rootPath string) []Env {
+	var envs []Env
+	seen := make(map[string]bool)
+	add := func(env Env) {
+		if env.Interpreter == "" || seen[env.Interpreter] {
+			return
+		}
+		seen[env.Interpreter] = true
+		envs = append(envs, env)
+	}
+
+	for _, dir := range projectVenvDirs {
+		prefix := filepath.Join(rootPath, dir)
+		if python := interpreterIn(prefix); python != "" {
+			add(Env{Name: dir, Kind: KindVenv, Prefix: absPath(prefix), Interpreter: absPath(python)})
+		}
+	}
+	if prefix := os.Getenv("VIRTUAL_ENV"); prefix != "" {
+		if python := interpreterIn(prefix); python != "" {
+			add(Env{Name: filepath.Base(prefix), Kind: KindVenv, Prefix: prefix, Interpreter: python})
+		}
+	}
+	if prefix := poetryEnv(rootPath); prefix != "" {
+		if python := interpreterIn(prefix); python != "" {
+			add(Env{Name: filepath.Base(prefix), Kind: KindPoetry, Prefix: prefix, Interpreter: python})
+		}
+	}
+	for _, prefix := range condaEnvs() {
+		if python := interpreterIn(prefix); python != "" {
+			add(Env{Name: "conda:" + filepath.Base(prefix), Kind: KindConda, Prefix: prefix, Interpreter: python})
+		}
+	}
+	for _, name := range []string{"python3", "python"} {
+		if python, err := exec.LookPath(name); err == nil {
+			add(Env{Name: name, Kind: KindSystem, Interpreter: python})
+		}
+	}
+	return envs
+}
+
+func interpreterIn(prefix string) string {
+	candidates := []string{filepath.Join(prefix, "bin", "python"), filepath.Join(prefix, "bin", "python3")}
+	if runtime.GOOS == "windows" {
+		candidates = []string{filepath.Join(prefix, "Scripts", "python.exe"), filepath.Join(prefix, "python.exe")}
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+func poetryEnv(rootPath string) string {
+	data, err := os.ReadFile(filepath.Join(rootPath, "pyproject.toml"))
+	if err != nil || !strings.Contains(string(data), "[tool.poetry]") {
+		return ""
+	}
+	if _, err := exec.LookPath("poetry"); err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), detectTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "poetry", "env", "info", "--path")
+	cmd.Dir = rootPath
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// condaEnvs finds the active conda environment and the named environments
+// under the usual install locations.
+func condaEnvs() []string {
+	var prefixes []string
+	if prefix := os.Getenv("CONDA_PREFIX"); prefix != "" {
+		prefixes = append(prefixes, prefix)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return prefixes
+	}
+	for _, base := range []string{"miniconda3", "anaconda3", "miniforge3", ".conda"} {
+		entries, err := os.ReadDir(filepath.Join(home, base, "envs"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				prefixes = append(prefixes, filepath.Join(home, base, "envs", entry.Name()))
+			}
+		}
+	}
+	return prefixes
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,106 @@
+package pyenv
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+type SelectedMsg struct {
+	Env Env
+}
+
+type Panel struct {
+	visible  bool
+	envs     []Env
+	selected int
+	current  string
+}
+
+func NewPanel() *Panel {
+	return &Panel{}
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+// Open shows envs with the current interpreter highlighted.
+func (p *Panel) Open(envs []Env, current string) {
+	p.visible = true
+	p.envs = envs
+	p.current = current
+	p.selected = 0
+	for i, env := range envs {
+		if env.Interpreter == current {
+			p.selected = i
+		}
+	}
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "alt+p":
+		p.visible = false
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.envs)-1 {
+			p.selected++
+		}
+	case "enter":
+		if p.selected < len(p.envs) {
+			env := p.envs[p.selected]
+			p.visible = false
+			return func() tea.Msg { return SelectedMsg{Env: env} }
+		}
+	}
+	return nil
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+
+	lines := []string{titleStyle.Render(i18n.T("python.title"))}
+	if len(p.envs) == 0 {
+		lines = append(lines, hintStyle.Render(i18n.T("python.empty")))
+	}
+	for i, env := range p.envs {
+		marker := "  "
+		if env.Interpreter == p.current {
+			marker = "● "
+		}
+		name := marker + env.Name + " (" + env.Kind + ")"
+		if i == p.selected {
+			lines = append(lines, selectedStyle.Render(name+"  "+env.Interpreter))
+		} else {
+			lines = append(lines, textStyle.Render(name)+hintStyle.Render("  "+env.Interpreter))
+		}
+	}
+	lines = append(lines, "", hintStyle.Render(i18n.T("python.hint")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(72).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,111 @@
+// Package recent remembers the files opened in a workspace, most recent
+// first, and offers a picker to switch among them.
+package recent
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tron/pkg/pathutil"
+)
+
+// Limit is how many files the list remembers.
+const Limit = 50
+
+// List is the workspace's recently opened files, most recent first. It is
+// kept in .tron/recent.json with paths relative to the workspace.
+type List struct {
+	rootPath string
+	paths    []string
+}
+
+func NewList(rootPath string) *List {
+	l := &List{rootPath: rootPath}
+	l.Load()
+	return l
+}
+
+func (l *List) configPath() string {
+	return filepath.Join(l.rootPath, ".tron", "recent.json")
+}
+
+func (l *List) Load() error {
+	data, err := os.ReadFile(l.configPath())
+	if err != nil {
+		return err
+	}
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return err
+	}
+	l.paths = nil
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(l.rootPath, path)
+		}
+		l.paths = append(l.paths, pathutil.Canonical(path))
+	}
+	return nil
+}
+
+func (l *List) Save() error {
+	path := l.configPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	out := make([]string, 0, len(l.paths))
+	for _, p := range l.paths {
+		out = append(out, l.Rel(p))
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Rel returns path relative to the workspace, or as is when it lies
+// outside it.
+func (l *List) Rel(path string) string {
+	rel, err := filepath.Rel(pathutil.Canonical(l.rootPath), path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// Add moves path to the front of the list and saves it. Virtual documents
+// are not files and are left out.
+func (l *List) Add(path string) error {
+	if path == "" || pathutil.IsVirtual(path) {
+		return nil
+	}
+	path = pathutil.Canonical(path)
+	if len(l.paths) > 0 && l.paths[0] == path {
+		return nil
+	}
+	for i, p := range l.paths {
+		if p == path {
+			l.paths = append(l.paths[:i], l.paths[i+1:]...)
+			break
+		}
+	}
+	l.paths = append([]string{path}, l.paths...)
+	if len(l.paths) > Limit {
+		l.paths = l.paths[:Limit]
+	}
+	return l.Save()
+}
+
+// Paths returns the remembered files that still exist, most recent first.
+func (l *List) Paths() []string {
+	var out []string
+	for _, p := range l.paths {
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			out = append(out, p)
+		}
+	}
+	return out
+}
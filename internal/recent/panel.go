@@ -0,0 +1,150 @@
+package recent
+
+import (
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+	"tron/pkg/fuzzy"
+)
+
+const panelRows = 12
+
+// OpenMsg asks for a file picked from the recent files to be opened.
+type OpenMsg struct {
+	Path string
+}
+
+// Panel lists the recent files, most recent first, narrowed by a typed
+// fuzzy filter over their workspace-relative paths.
+type Panel struct {
+	visible  bool
+	paths    []string
+	names    []string
+	input    []rune
+	selected int
+}
+
+func NewPanel() *Panel {
+	return &Panel{}
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+// Open shows the files of list, leaving out current so that enter switches
+// straight back to the previous file.
+func (p *Panel) Open(list *List, current string) {
+	p.visible = true
+	p.paths, p.names = nil, nil
+	for _, path := range list.Paths() {
+		if path == current {
+			continue
+		}
+		p.paths = append(p.paths, path)
+		p.names = append(p.names, list.Rel(path))
+	}
+	p.input = nil
+	p.selected = 0
+}
+
+func (p *Panel) matches() []fuzzy.Match {
+	query := strings.TrimSpace(string(p.input))
+	if query == "" {
+		out := make([]fuzzy.Match, len(p.names))
+		for i := range p.names {
+			out[i] = fuzzy.Match{Index: i}
+		}
+		return out
+	}
+	return fuzzy.Filter(query, p.names)
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "ctrl+e":
+		p.visible = false
+	case "up", "ctrl+p":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "ctrl+n":
+		if p.selected < len(p.matches())-1 {
+			p.selected++
+		}
+	case "enter":
+		matches := p.matches()
+		if p.selected >= len(matches) {
+			return nil
+		}
+		path := p.paths[matches[p.selected].Index]
+		p.visible = false
+		return func() tea.Msg { return OpenMsg{Path: path} }
+	case "backspace":
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+		p.selected = 0
+	default:
+		if keyMsg.Type == tea.KeyRunes || keyMsg.Type == tea.KeySpace {
+			p.input = append(p.input, keyMsg.Runes...)
+			p.selected = 0
+		}
+	}
+	return nil
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	matchStyle := textStyle.Foreground(lipgloss.Color("#f9e2af")).Bold(true)
+
+	lines := []string{titleStyle.Render(i18n.T("recent.title")), textStyle.Render("> " + string(p.input) + "▏")}
+	matches := p.matches()
+	start := 0
+	if p.selected >= panelRows {
+		start = p.selected - panelRows + 1
+	}
+	for i := start; i < len(matches) && i < start+panelRows; i++ {
+		name := p.names[matches[i].Index]
+		if i == p.selected {
+			lines = append(lines, selectedStyle.Render(name))
+			continue
+		}
+		dir, base := filepath.Split(name)
+		line := fuzzy.Highlight(name, matches[i].Positions,
+			func(s string) string { return matchStyle.Render(s) },
+			func(s string) string { return textStyle.Render(s) })
+		if len(matches[i].Positions) == 0 {
+			line = hintStyle.Render(dir) + textStyle.Render(base)
+		}
+		lines = append(lines, line)
+	}
+	if len(matches) == 0 {
+		lines = append(lines, hintStyle.Render(i18n.T("recent.empty")))
+	}
+	lines = append(lines, "", hintStyle.Render(i18n.T("recent.hint")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(72).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,63 @@
+package recovery
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Journal keeps unsaved buffer contents on disk, one entry per file, so
+// they survive being dropped from memory.
+type Journal struct {
+	dir string
+}
+
+type entry struct {
+	Path    string    `json:"path"`
+	Content string    `json:"content"`
+	SavedAt time.Time `json:"savedAt"`
+}
+
+func New(rootPath string) *Journal {
+	return &Journal{dir: filepath.Join(rootPath, ".tron", "recovery")}
+}
+
+func (j *Journal) entryPath(path string) string {
+	sum := sha1.Sum([]byte(path))
+	return filepath.Join(j.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (j *Journal) Write(path, content string) error {
+	if err := os.MkdirAll(j.dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry{Path: path, Content: content, SavedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	tmp := j.entryPath(path) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.entryPath(path))
+}
+
+// Read returns the journaled content for path, if any.
+func (j *Journal) Read(path string) (string, bool) {
+	data, err := os.ReadFile(j.entryPath(path))
+	if err != nil {
+		return "", false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Path != path {
+		return "", false
+	}
+	return e.Content, true
+}
+
+func (j *Journal) Remove(path string) {
+	_ = os.Remove(j.entryPath(path))
+}
@@ -0,0 +1,44 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Config struct {
+	Host         string `json:"host"`
+	Dir          string `json:"dir"`
+	Port         int    `json:"port,omitempty"`
+	IdentityFile string `json:"identityFile,omitempty"`
+}
+
+func configPath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "remote.json")
+}
+
+// LoadConfig returns nil when the project has no remote configured.
+func LoadConfig(rootPath string) (*Config, error) {
+	data, err := os.ReadFile(configPath(rootPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.Host == "" {
+		return nil, nil
+	}
+	// ssh would read a host starting with - as an option, such as
+	// -oProxyCommand, which runs a command as soon as the project opens.
+	if strings.HasPrefix(c.Host, "-") {
+		return nil, fmt.Errorf("remote.json: invalid host %q", c.Host)
+	}
+	return &c, nil
+}
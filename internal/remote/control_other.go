@@ -0,0 +1,9 @@
+//go:build !unix
+
+package remote
+
+// private reports false: without unix ownership to check, ssh runs without
+// a shared control connection.
+func private(dir string) bool {
+	return false
+}
@@ -0,0 +1,40 @@
+//go:build unix
+
+package remote
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestControlDirIsPrivate(t *testing.T) {
+	runtime := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtime)
+	dir := filepath.Join(runtime, "tron-ssh")
+
+	if got := controlDir(); got != dir {
+		t.Fatalf("controlDir = %q, want %q", got, dir)
+	}
+
+	if err := os.Chmod(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if got := controlDir(); got != "" {
+		t.Errorf("controlDir accepted a world-writable directory: %q", got)
+	}
+
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	elsewhere := t.TempDir()
+	if err := os.Chmod(elsewhere, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(elsewhere, dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := controlDir(); got != "" {
+		t.Errorf("controlDir followed a symlink: %q", got)
+	}
+}
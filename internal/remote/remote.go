@@ -0,0 +1,183 @@
+package remote
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"tron/internal/i18n"
+)
+
+type Status int
+
+const (
+	StatusConnecting Status = iota
+	StatusConnected
+	StatusDisconnected
+)
+
+const (
+	CheckInterval = 15 * time.Second
+	CheckTimeout  = 10 * time.Second
+	maxBackoff    = 60 * time.Second
+)
+
+// Remote runs commands on a host over the system ssh client. A shared
+// ControlMaster connection keeps per-command latency low and is re-established
+// transparently by ssh after a drop.
+type Remote struct {
+	config   *Config
+	rootPath string
+	Status   Status
+	LastErr  error
+	failures int
+}
+
+func New(rootPath string, config *Config) *Remote {
+	abs, err := filepath.Abs(rootPath)
+	if err != nil {
+		abs = rootPath
+	}
+	return &Remote{config: config, rootPath: abs, Status: StatusConnecting}
+}
+
+func (r *Remote) Host() string {
+	return r.config.Host
+}
+
+// controlDir holds the ControlMaster sockets: in the user's runtime
+// directory, or failing that their cache directory, never one that other
+// users share. It returns "" unless the directory belongs to the user
+// alone, since whoever can write there can plant or take over a socket.
+func controlDir() string {
+	base := os.Getenv("XDG_RUNTIME_DIR")
+	if base == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(cache, "tron")
+		if err := os.MkdirAll(base, 0700); err != nil {
+			return ""
+		}
+	}
+	dir := filepath.Join(base, "tron-ssh")
+	_ = os.Mkdir(dir, 0700)
+	if !private(dir) {
+		return ""
+	}
+	return dir
+}
+
+func (r *Remote) sshArgs() []string {
+	args := []string{"-o", "BatchMode=yes"}
+	if dir := controlDir(); dir != "" {
+		args = append(args,
+			"-o", "ControlMaster=auto",
+			"-o", "ControlPath="+filepath.Join(dir, "%C"),
+			"-o", "ControlPersist=10m",
+		)
+	}
+	args = append(args,
+		"-o", "ServerAliveInterval=15",
+		"-o", "ServerAliveCountMax=3",
+	)
+	if r.config.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(r.config.Port))
+	}
+	if r.config.IdentityFile != "" {
+		args = append(args, "-i", r.config.IdentityFile)
+	}
+	return args
+}
+
+func (r *Remote) ToRemote(local string) string {
+	abs, err := filepath.Abs(local)
+	if err != nil {
+		return local
+	}
+	rel, err := filepath.Rel(r.rootPath, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(abs)
+	}
+	return path.Join(r.config.Dir, filepath.ToSlash(rel))
+}
+
+func (r *Remote) ToLocal(remote string) string {
+	dir := strings.TrimSuffix(r.config.Dir, "/")
+	if remote != dir && !strings.HasPrefix(remote, dir+"/") {
+		return remote
+	}
+	return filepath.Join(r.rootPath, filepath.FromSlash(strings.TrimPrefix(remote, dir)))
+}
+
+func (r *Remote) remoteScript(command, localCwd string) string {
+	return "cd " + shellQuote(r.ToRemote(localCwd)) + " && " + command
+}
+
+// ShellCommand wraps a shell command line so that sh -c runs it on the
+// remote host in the directory matching localCwd.
+func (r *Remote) ShellCommand(command, localCwd string) string {
+	parts := []string{"ssh"}
+	for _, arg := range r.sshArgs() {
+		parts = append(parts, shellQuote(arg))
+	}
+	parts = append(parts, "--", shellQuote(r.config.Host), shellQuote(r.remoteScript(command, localCwd)))
+	return strings.Join(parts, " ")
+}
+
+// ExecArgs returns an argv that runs argv on the remote host with stdio
+// attached, suitable for language servers.
+func (r *Remote) ExecArgs(argv []string, localCwd string) []string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = shellQuote(arg)
+	}
+	args := append([]string{"ssh"}, r.sshArgs()...)
+	return append(args, "-T", "--", r.config.Host, r.remoteScript("exec "+strings.Join(quoted, " "), localCwd))
+}
+
+func (r *Remote) Check(ctx context.Context) error {
+	args := append(r.sshArgs(), "--", r.config.Host, "true")
+	return exec.CommandContext(ctx, "ssh", args...).Run()
+}
+
+// SetResult records a health check and returns the delay until the next one,
+// backing off exponentially while the host is unreachable.
+func (r *Remote) SetResult(err error) time.Duration {
+	r.LastErr = err
+	if err == nil {
+		r.Status = StatusConnected
+		r.failures = 0
+		return CheckInterval
+	}
+	r.Status = StatusDisconnected
+	r.failures++
+	delay := time.Second << min(r.failures, 6)
+	return min(delay, maxBackoff)
+}
+
+func (r *Remote) StatusText() string {
+	switch r.Status {
+	case StatusConnected:
+		return "ssh " + r.config.Host
+	case StatusDisconnected:
+		return "ssh " + r.config.Host + " " + i18n.T("remote.retrying")
+	default:
+		return "ssh " + r.config.Host + " " + i18n.T("remote.connecting")
+	}
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
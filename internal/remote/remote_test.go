@@ -0,0 +1,36 @@
+package remote
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigRejectsOptionHost(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".tron"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"host": "-oProxyCommand=touch /tmp/pwned", "dir": "/srv"}`
+	if err := os.WriteFile(configPath(root), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if config, err := LoadConfig(root); err == nil || config != nil {
+		t.Fatalf("LoadConfig = %+v, %v; want an error", config, err)
+	}
+}
+
+func TestHostFollowsEndOfOptions(t *testing.T) {
+	r := New(t.TempDir(), &Config{Host: "example.com", Dir: "/srv"})
+
+	args := r.ExecArgs([]string{"gopls"}, ".")
+	i := slices.Index(args, "--")
+	if i < 0 || args[i+1] != "example.com" {
+		t.Errorf("ExecArgs = %q, want -- before the host", args)
+	}
+	if cmd := r.ShellCommand("make", "."); !strings.Contains(cmd, " -- example.com ") {
+		t.Errorf("ShellCommand = %q, want -- before the host", cmd)
+	}
+}
@@ -0,0 +1,93 @@
+package repl
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+type Interpreter struct {
+	Name    string
+	Command func(ctx context.Context, code string) (*exec.Cmd, func(), error)
+}
+
+var Interpreters = []Interpreter{
+	{Name: "python", Command: pythonCommand},
+	{Name: "node", Command: nodeCommand},
+	{Name: "go", Command: goCommand},
+}
+
+// pythonEval prints the value of an expression like the interactive
+// interpreter would, falling back to exec for statements.
+const pythonEval = `import sys
+src = sys.argv[1]
+try:
+    code = compile(src, "<repl>", "eval")
+except SyntaxError:
+    exec(compile(src, "<repl>", "exec"))
+else:
+    result = eval(code)
+    if result is not None:
+        print(repr(result))
+`
+
+func pythonCommand(ctx context.Context, code string) (*exec.Cmd, func(), error) {
+	python := "python3"
+	if _, err := exec.LookPath(python); err != nil {
+		python = "python"
+	}
+	return pythonCommandFor(python)(ctx, code)
+}
+
+func pythonCommandFor(python string) func(ctx context.Context, code string) (*exec.Cmd, func(), error) {
+	return func(ctx context.Context, code string) (*exec.Cmd, func(), error) {
+		return exec.CommandContext(ctx, python, "-c", pythonEval, code), func() {}, nil
+	}
+}
+
+func nodeCommand(ctx context.Context, code string) (*exec.Cmd, func(), error) {
+	return exec.CommandContext(ctx, "node", "-p", code), func() {}, nil
+}
+
+func goCommand(ctx context.Context, code string) (*exec.Cmd, func(), error) {
+	dir, err := os.MkdirTemp("", "tron-repl-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(goSource(code)), 0644); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return exec.CommandContext(ctx, "go", "run", filepath.Join(dir, "main.go")), cleanup, nil
+}
+
+func goSource(code string) string {
+	if strings.Contains(code, "package main") {
+		return code
+	}
+	if strings.Contains(code, "func main()") {
+		return "package main\n\n" + code + "\n"
+	}
+
+	body := "\tfmt.Println(" + code + ")"
+	if isGoStatement(code) {
+		body = "\t" + strings.ReplaceAll(code, "\n", "\n\t") + "\n\t_ = fmt.Sprint()"
+	}
+	return "package main\n\nimport \"fmt\"\n\nfunc main() {\n" + body + "\n}\n"
+}
+
+func isGoStatement(code string) bool {
+	if strings.ContainsAny(code, "\n;") || strings.Contains(code, ":=") {
+		return true
+	}
+	for _, prefix := range []string{"fmt.Print", "for ", "if ", "var ", "switch ", "go ", "defer "} {
+		if strings.HasPrefix(strings.TrimSpace(code), prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package repl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+const (
+	EvalTimeout    = 10 * time.Second
+	maxEntries     = 50
+	visibleEntries = 5
+	maxOutputLines = 8
+)
+
+type Panel struct {
+	rootPath    string
+	visible     bool
+	interpreter int
+	input       []rune
+	entries     []*Entry
+	historyPos  int
+	seq         int
+	width       int
+	// Python overrides the interpreter used for python entries.
+	Python string
+}
+
+func NewPanel(rootPath string) *Panel {
+	return &Panel{
+		rootPath: rootPath,
+		width:    70,
+	}
+}
+
+func (p *Panel) Toggle() {
+	p.visible = !p.visible
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case EvalResultMsg:
+		p.handleResult(msg)
+	case tea.KeyMsg:
+		if p.visible {
+			return p.handleKey(msg)
+		}
+	}
+	return nil
+}
+
+func (p *Panel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "alt+e":
+		p.visible = false
+		return nil
+	case "tab":
+		p.interpreter = (p.interpreter + 1) % len(Interpreters)
+		return nil
+	case "ctrl+l":
+		p.entries = nil
+		return nil
+	case "up":
+		p.recall(-1)
+		return nil
+	case "down":
+		p.recall(1)
+		return nil
+	case "enter":
+		return p.evaluate()
+	}
+
+	switch msg.Type {
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case tea.KeySpace:
+		p.input = append(p.input, ' ')
+	case tea.KeyRunes:
+		p.input = append(p.input, msg.Runes...)
+	}
+	return nil
+}
+
+func (p *Panel) recall(delta int) {
+	pos := p.historyPos + delta
+	if pos < 0 || pos > len(p.entries) {
+		return
+	}
+	p.historyPos = pos
+	if pos == len(p.entries) {
+		p.input = nil
+		return
+	}
+	p.input = []rune(p.entries[pos].Code)
+}
+
+func (p *Panel) evaluate() tea.Cmd {
+	code := strings.TrimSpace(string(p.input))
+	if code == "" {
+		return nil
+	}
+	interp := Interpreters[p.interpreter]
+	command := interp.Command
+	if interp.Name == "python" && p.Python != "" {
+		command = pythonCommandFor(p.Python)
+	}
+
+	p.seq++
+	seq := p.seq
+	p.entries = append(p.entries, &Entry{Interpreter: interp.Name, Code: code, Pending: true, seq: seq})
+	if len(p.entries) > maxEntries {
+		p.entries = p.entries[len(p.entries)-maxEntries:]
+	}
+	p.input = nil
+	p.historyPos = len(p.entries)
+	rootPath := p.rootPath
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), EvalTimeout)
+		defer cancel()
+
+		start := time.Now()
+		cmd, cleanup, err := command(ctx, code)
+		if err != nil {
+			return EvalResultMsg{Seq: seq, Err: err}
+		}
+		defer cleanup()
+		cmd.Dir = rootPath
+
+		out, err := cmd.CombinedOutput()
+		if ctx.Err() == context.DeadlineExceeded {
+			err = errors.New(i18n.T("repl.timedOut"))
+		}
+		return EvalResultMsg{Seq: seq, Output: strings.TrimRight(string(out), "\n"), Err: err, Duration: time.Since(start)}
+	}
+}
+
+func (p *Panel) handleResult(msg EvalResultMsg) {
+	for _, entry := range p.entries {
+		if entry.seq == msg.Seq {
+			entry.Pending = false
+			entry.Output = msg.Output
+			entry.Err = msg.Err
+			entry.Duration = msg.Duration
+			return
+		}
+	}
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4")).Width(p.width)
+	promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#89b4fa"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	activeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#1e1e2e")).Background(lipgloss.Color("#89b4fa"))
+
+	names := make([]string, len(Interpreters))
+	for i, interp := range Interpreters {
+		if i == p.interpreter {
+			names[i] = activeStyle.Render(" " + interp.Name + " ")
+		} else {
+			names[i] = hintStyle.Render(" " + interp.Name + " ")
+		}
+	}
+	lines := []string{titleStyle.Render(i18n.T("repl.title")+" ") + strings.Join(names, "")}
+
+	start := len(p.entries) - visibleEntries
+	if start < 0 {
+		start = 0
+	}
+	for _, entry := range p.entries[start:] {
+		lines = append(lines, textStyle.Render(promptStyle.Render(entry.Interpreter+"› ")+entry.Code))
+		switch {
+		case entry.Pending:
+			lines = append(lines, hintStyle.Render("  …"))
+		default:
+			for _, out := range limitLines(entry.Output, maxOutputLines) {
+				if entry.Err != nil {
+					lines = append(lines, errorStyle.Render("  "+out))
+				} else {
+					lines = append(lines, textStyle.Render("  "+out))
+				}
+			}
+			status := fmt.Sprintf("  %s", entry.Duration.Round(time.Millisecond))
+			if entry.Err != nil {
+				status += " · " + entry.Err.Error()
+				lines = append(lines, errorStyle.Render(status))
+			} else {
+				lines = append(lines, hintStyle.Render(status))
+			}
+		}
+	}
+
+	lines = append(lines, "", textStyle.Render(promptStyle.Render("› ")+string(p.input)+"▏"))
+	lines = append(lines, hintStyle.Render(i18n.T("repl.hint")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+func limitLines(s string, max int) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(s, "\n")
+	if len(lines) > max {
+		more := len(lines) - max
+		lines = append(lines[:max], i18n.T("repl.moreLines", more))
+	}
+	return lines
+}
@@ -0,0 +1,21 @@
+package repl
+
+import "time"
+
+type Entry struct {
+	Interpreter string
+	Code        string
+	Output      string
+	Err         error
+	Duration    time.Duration
+	Pending     bool
+
+	seq int
+}
+
+type EvalResultMsg struct {
+	Seq      int
+	Output   string
+	Err      error
+	Duration time.Duration
+}
@@ -3,6 +3,10 @@ package runconfig
 import (
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 type RunConfig struct {
@@ -13,11 +17,31 @@ type RunConfig struct {
 	Environment map[string]string
 }
 
+// CommandLine renders the config as a shell command, with its environment
+// as leading assignments.
+func (c *RunConfig) CommandLine() string {
+	keys := make([]string, 0, len(c.Environment))
+	for key := range c.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys)+1+len(c.Args))
+	for _, key := range keys {
+		parts = append(parts, key+"="+shellQuote(c.Environment[key]))
+	}
+	parts = append(parts, c.Command)
+	parts = append(parts, c.Args...)
+	return strings.Join(parts, " ")
+}
+
 type ConfigManager struct {
 	Configs       []*RunConfig
 	SelectedIndex int
 	ProjectRoot   string
 	ProjectType   ProjectType
+	python        string
+	goEnv         GoEnv
 }
 
 func NewConfigManager(rootPath string) *ConfigManager {
@@ -27,12 +51,39 @@ func NewConfigManager(rootPath string) *ConfigManager {
 		ProjectRoot:   rootPath,
 	}
 
-	cm.ProjectType = DetectProjectType(rootPath)
-	cm.LoadConfigs(rootPath)
+	cm.goEnv = LoadGoEnv(rootPath)
 
 	return cm
 }
 
+// ConfigsDetectedMsg carries the result of Detect.
+type ConfigsDetectedMsg struct {
+	ProjectType ProjectType
+	Defaults    []DefaultConfig
+}
+
+// Detect works out the project type and default configurations off the UI
+// thread, since it may walk the whole tree.
+func (cm *ConfigManager) Detect() tea.Cmd {
+	rootPath := cm.ProjectRoot
+	return func() tea.Msg {
+		projectType := DetectProjectType(rootPath)
+		return ConfigsDetectedMsg{ProjectType: projectType, Defaults: detectDefaults(rootPath, projectType)}
+	}
+}
+
+func (cm *ConfigManager) applyDetected(msg ConfigsDetectedMsg) {
+	cm.ProjectType = msg.ProjectType
+	if configs := cm.loadFromConfigFile(cm.ProjectRoot); len(configs) > 0 {
+		cm.Configs = configs
+	} else {
+		cm.Configs = cm.configsFromDefaults(msg.Defaults)
+	}
+	if cm.SelectedIndex >= len(cm.Configs) {
+		cm.SelectedIndex = 0
+	}
+}
+
 func (cm *ConfigManager) LoadConfigs(rootPath string) []*RunConfig {
 	configs := cm.loadFromConfigFile(rootPath)
 	if len(configs) > 0 {
@@ -55,20 +106,37 @@ func (cm *ConfigManager) loadFromConfigFile(rootPath string) []*RunConfig {
 }
 
 func (cm *ConfigManager) generateDefaults() []*RunConfig {
-	defaults, ok := DefaultConfigsByType[cm.ProjectType]
-	if !ok {
-		return []*RunConfig{}
+	return cm.configsFromDefaults(detectDefaults(cm.ProjectRoot, cm.ProjectType))
+}
+
+func detectDefaults(rootPath string, projectType ProjectType) []DefaultConfig {
+	defaults := append([]DefaultConfig{}, DefaultConfigsByType[projectType]...)
+	switch projectType {
+	case ProjectTypeGo:
+		defaults = append(defaults, goDefaults(rootPath)...)
+	case ProjectTypeCMake:
+		defaults = append(defaults, cmakeDefaults(rootPath)...)
+	case ProjectTypeC:
+		defaults = append(defaults, makeDefaults(rootPath)...)
 	}
+	return append(defaults, dockerDefaults(rootPath)...)
+}
 
+func (cm *ConfigManager) configsFromDefaults(defaults []DefaultConfig) []*RunConfig {
 	configs := make([]*RunConfig, 0, len(defaults))
 	for _, d := range defaults {
+		command := d.Command
+		if command == "python" && cm.python != "" {
+			command = cm.python
+		}
 		config := &RunConfig{
 			Name:        d.Name,
-			Command:     d.Command,
+			Command:     command,
 			Args:        d.Args,
 			WorkingDir:  cm.ProjectRoot,
 			Environment: make(map[string]string),
 		}
+		cm.applyGoEnv(config)
 		configs = append(configs, config)
 	}
 
@@ -128,3 +196,17 @@ func (cm *ConfigManager) Update(index int, name, command string, args ...string)
 	cm.Configs[index].Command = command
 	cm.Configs[index].Args = args
 }
+
+// SetPythonInterpreter points Python run configurations at interpreter.
+func (cm *ConfigManager) SetPythonInterpreter(interpreter string) {
+	previous := cm.python
+	if previous == "" {
+		previous = "python"
+	}
+	for _, config := range cm.Configs {
+		if config.Command == previous {
+			config.Command = interpreter
+		}
+	}
+	cm.python = interpreter
+}
@@ -0,0 +1,119 @@
+package runconfig
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CMakeBuildDir is where the CMake configurations build, relative to the
+// project root.
+const CMakeBuildDir = "build"
+
+const compileCommandsFile = "compile_commands.json"
+
+var cSourceExts = []string{".c", ".cc", ".cpp", ".cxx", ".h", ".hh", ".hpp"}
+
+func hasCMake(rootPath string) bool {
+	_, err := os.Stat(filepath.Join(rootPath, "CMakeLists.txt"))
+	return err == nil
+}
+
+func hasMakefile(rootPath string) bool {
+	_, err := os.Stat(filepath.Join(rootPath, "Makefile"))
+	return err == nil
+}
+
+// hasCSources reports whether the top level holds a compilation database
+// or C/C++ sources.
+func hasCSources(rootPath string) bool {
+	entries, err := os.ReadDir(rootPath)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if entry.Name() == compileCommandsFile {
+			return true
+		}
+		for _, ext := range cSourceExts {
+			if strings.HasSuffix(entry.Name(), ext) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CompileCommandsDir returns the directory holding the project's
+// compile_commands.json, looking in the root and the usual build
+// directories, or "" when there is none.
+func CompileCommandsDir(rootPath string) string {
+	dirs := []string{rootPath, filepath.Join(rootPath, CMakeBuildDir)}
+	if matches, err := filepath.Glob(filepath.Join(rootPath, "cmake-build-*")); err == nil {
+		dirs = append(dirs, matches...)
+	}
+	for _, dir := range dirs {
+		if _, err := os.Stat(filepath.Join(dir, compileCommandsFile)); err == nil {
+			if abs, err := filepath.Abs(dir); err == nil {
+				return abs
+			}
+			return dir
+		}
+	}
+	return ""
+}
+
+// CanGenerateCompileCommands reports whether CMake can write a compilation
+// database for a project that lacks one.
+func CanGenerateCompileCommands(rootPath string) bool {
+	return hasCMake(rootPath) && CompileCommandsDir(rootPath) == ""
+}
+
+// CMakeConfigureArgs configures the project into CMakeBuildDir, writing
+// compile_commands.json for clangd as it goes.
+func CMakeConfigureArgs() []string {
+	return []string{"cmake", "-S", ".", "-B", CMakeBuildDir, "-DCMAKE_EXPORT_COMPILE_COMMANDS=ON"}
+}
+
+var cmakeExecutable = regexp.MustCompile(`(?mi)^\s*add_executable\s*\(\s*([A-Za-z0-9_.+-]+)`)
+
+// cmakeExecutables returns the targets CMakeLists.txt adds with
+// add_executable, leaving out names set through variables.
+func cmakeExecutables(rootPath string) []string {
+	content, err := os.ReadFile(filepath.Join(rootPath, "CMakeLists.txt"))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, m := range cmakeExecutable.FindAllStringSubmatch(string(content), -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+func cmakeDefaults(rootPath string) []DefaultConfig {
+	configure := CMakeConfigureArgs()
+	configs := []DefaultConfig{
+		{Name: "CMake Configure", Command: configure[0], Args: configure[1:]},
+		{Name: "CMake Build", Command: "cmake", Args: []string{"--build", CMakeBuildDir}},
+		{Name: "CTest", Command: "ctest", Args: []string{"--test-dir", CMakeBuildDir, "--output-on-failure"}},
+	}
+	for _, name := range cmakeExecutables(rootPath) {
+		configs = append(configs, DefaultConfig{Name: "Run " + name, Command: "./" + CMakeBuildDir + "/" + name})
+	}
+	return configs
+}
+
+func makeDefaults(rootPath string) []DefaultConfig {
+	if !hasMakefile(rootPath) {
+		return nil
+	}
+	return []DefaultConfig{
+		{Name: "Make", Command: "make"},
+		{Name: "Make Clean", Command: "make", Args: []string{"clean"}},
+	}
+}
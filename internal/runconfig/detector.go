@@ -8,6 +8,15 @@ import (
 )
 
 func DetectProjectType(rootPath string) ProjectType {
+	if hasGoModule(rootPath) {
+		return ProjectTypeGo
+	}
+	if hasCargo(rootPath) {
+		return ProjectTypeRust
+	}
+	if hasCMake(rootPath) {
+		return ProjectTypeCMake
+	}
 	if hasDjango(rootPath) {
 		return ProjectTypeDjango
 	}
@@ -20,9 +29,17 @@ func DetectProjectType(rootPath string) ProjectType {
 	if hasPythonFiles(rootPath) {
 		return ProjectTypePython
 	}
+	if hasCSources(rootPath) {
+		return ProjectTypeC
+	}
 	return ProjectTypeNone
 }
 
+func hasCargo(rootPath string) bool {
+	_, err := os.Stat(filepath.Join(rootPath, "Cargo.toml"))
+	return err == nil
+}
+
 func hasDjango(rootPath string) bool {
 	managePath := filepath.Join(rootPath, "manage.py")
 	if _, err := os.Stat(managePath); err == nil {
@@ -0,0 +1,72 @@
+package runconfig
+
+import (
+	"os"
+	"path/filepath"
+)
+
+var composeFiles = []string{
+	"compose.yaml",
+	"compose.yml",
+	"docker-compose.yml",
+	"docker-compose.yaml",
+}
+
+func DetectComposeFile(rootPath string) string {
+	for _, name := range composeFiles {
+		if _, err := os.Stat(filepath.Join(rootPath, name)); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+func hasDockerfile(rootPath string) bool {
+	_, err := os.Stat(filepath.Join(rootPath, "Dockerfile"))
+	return err == nil
+}
+
+func dockerDefaults(rootPath string) []DefaultConfig {
+	var configs []DefaultConfig
+	if compose := DetectComposeFile(rootPath); compose != "" {
+		configs = append(configs,
+			DefaultConfig{Name: "Compose Up", Command: "docker", Args: []string{"compose", "-f", compose, "up", "-d", "--build"}},
+			DefaultConfig{Name: "Compose Down", Command: "docker", Args: []string{"compose", "-f", compose, "down"}},
+			DefaultConfig{Name: "Compose Logs", Command: "docker", Args: []string{"compose", "-f", compose, "logs", "-f", "--tail", "200"}},
+		)
+	}
+	if hasDockerfile(rootPath) {
+		tag := DockerImageName(rootPath)
+		configs = append(configs,
+			DefaultConfig{Name: "Docker Build", Command: "docker", Args: []string{"build", "-t", tag, "."}},
+			DefaultConfig{Name: "Docker Run", Command: "docker", Args: []string{"run", "--rm", tag}},
+		)
+	}
+	return configs
+}
+
+func DockerImageName(rootPath string) string {
+	return ComposeProjectName(rootPath)
+}
+
+// ComposeProjectName mirrors how docker compose derives a project name from
+// the directory: lowercase, keeping only letters, digits, dashes and underscores.
+func ComposeProjectName(rootPath string) string {
+	abs, err := filepath.Abs(rootPath)
+	if err != nil {
+		abs = rootPath
+	}
+	var name []rune
+	for _, r := range filepath.Base(abs) {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			name = append(name, r+'a'-'A')
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			name = append(name, r)
+		}
+	}
+	if len(name) == 0 {
+		return "project"
+	}
+	return string(name)
+}
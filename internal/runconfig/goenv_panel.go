@@ -0,0 +1,148 @@
+package runconfig
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+type GoEnvMsg struct {
+	Env GoEnv
+}
+
+var (
+	goosChoices   = []string{"", "linux", "darwin", "windows", "freebsd", "js", "wasip1"}
+	goarchChoices = []string{"", "amd64", "arm64", "386", "arm", "wasm", "riscv64"}
+)
+
+const (
+	goEnvRowOS = iota
+	goEnvRowArch
+	goEnvRowFlags
+	goEnvRows
+)
+
+// GoEnvPanel edits the GOOS/GOARCH/GOFLAGS applied to go run configurations.
+type GoEnvPanel struct {
+	visible bool
+	env     GoEnv
+	row     int
+}
+
+func NewGoEnvPanel() *GoEnvPanel {
+	return &GoEnvPanel{}
+}
+
+func (p *GoEnvPanel) Visible() bool {
+	return p.visible
+}
+
+func (p *GoEnvPanel) Open(env GoEnv) {
+	p.visible = true
+	p.env = env
+	p.row = goEnvRowOS
+}
+
+func (p *GoEnvPanel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		p.visible = false
+	case tea.KeyEnter:
+		p.visible = false
+		env := p.env
+		return func() tea.Msg { return GoEnvMsg{Env: env} }
+	case tea.KeyUp, tea.KeyShiftTab:
+		p.row = (p.row + goEnvRows - 1) % goEnvRows
+	case tea.KeyDown, tea.KeyTab:
+		p.row = (p.row + 1) % goEnvRows
+	case tea.KeyLeft:
+		p.cycle(-1)
+	case tea.KeyRight:
+		p.cycle(1)
+	case tea.KeyBackspace:
+		if p.row == goEnvRowFlags && p.env.GOFLAGS != "" {
+			runes := []rune(p.env.GOFLAGS)
+			p.env.GOFLAGS = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		if p.row == goEnvRowFlags {
+			p.env.GOFLAGS += " "
+		}
+	case tea.KeyRunes:
+		if p.row == goEnvRowFlags {
+			p.env.GOFLAGS += string(keyMsg.Runes)
+		}
+	}
+	return nil
+}
+
+func (p *GoEnvPanel) cycle(delta int) {
+	switch p.row {
+	case goEnvRowOS:
+		p.env.GOOS = cycleChoice(goosChoices, p.env.GOOS, delta)
+	case goEnvRowArch:
+		p.env.GOARCH = cycleChoice(goarchChoices, p.env.GOARCH, delta)
+	}
+}
+
+func cycleChoice(choices []string, current string, delta int) string {
+	index := 0
+	for i, choice := range choices {
+		if choice == current {
+			index = i
+		}
+	}
+	return choices[(index+delta+len(choices))%len(choices)]
+}
+
+func (p *GoEnvPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+
+	display := func(value string) string {
+		if value == "" {
+			return i18n.T("goenv.host")
+		}
+		return value
+	}
+	rows := []string{
+		"GOOS     ◂ " + display(p.env.GOOS) + " ▸",
+		"GOARCH   ◂ " + display(p.env.GOARCH) + " ▸",
+		"GOFLAGS  " + p.env.GOFLAGS,
+	}
+	if p.row == goEnvRowFlags {
+		rows[goEnvRowFlags] += "▏"
+	}
+
+	lines := []string{titleStyle.Render(i18n.T("goenv.title"))}
+	for i, row := range rows {
+		if i == p.row {
+			lines = append(lines, selectedStyle.Render(row))
+		} else {
+			lines = append(lines, textStyle.Render(row))
+		}
+	}
+	lines = append(lines, "", hintStyle.Render(i18n.T("goenv.hint")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(52).
+		Render(strings.Join(lines, "\n"))
+}
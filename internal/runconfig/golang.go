@@ -0,0 +1,231 @@
+package runconfig
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// GoModulePath returns the module path declared in rootPath/go.mod, or ""
+// when the project is not a Go module.
+func GoModulePath(rootPath string) string {
+	data, err := os.ReadFile(filepath.Join(rootPath, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module")), `"`)
+		}
+	}
+	return ""
+}
+
+func hasGoModule(rootPath string) bool {
+	_, err := os.Stat(filepath.Join(rootPath, "go.mod"))
+	return err == nil
+}
+
+type goPackage struct {
+	dir  string
+	main bool
+}
+
+// goPackages lists the directories under rootPath holding Go files, as
+// ./-relative patterns, skipping vendor, testdata and hidden directories.
+func goPackages(rootPath string) []goPackage {
+	var pkgs []goPackage
+	_ = filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != rootPath && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		if path != rootPath {
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+
+		hasGo, isMain := scanGoDir(path)
+		if !hasGo {
+			return nil
+		}
+		rel, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return nil
+		}
+		dir := "."
+		if rel != "." {
+			dir = "./" + filepath.ToSlash(rel)
+		}
+		pkgs = append(pkgs, goPackage{dir: dir, main: isMain})
+		return nil
+	})
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].dir < pkgs[j].dir })
+	return pkgs
+}
+
+func scanGoDir(dir string) (hasGo, isMain bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, false
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		hasGo = true
+		if !strings.HasSuffix(name, "_test.go") && goPackageName(filepath.Join(dir, name)) == "main" {
+			isMain = true
+		}
+	}
+	return hasGo, isMain
+}
+
+func goPackageName(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "package ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "package "))
+		}
+	}
+	return ""
+}
+
+// goDefaults builds, tests and vets the whole module, runs each main
+// package, and tests each top-level directory on its own, so the list
+// stays short however many packages there are.
+func goDefaults(rootPath string) []DefaultConfig {
+	configs := []DefaultConfig{
+		{Name: "Go Build ./...", Command: "go", Args: []string{"build", "./..."}},
+		{Name: "Go Test ./...", Command: "go", Args: []string{"test", "./..."}},
+		{Name: "Go Vet ./...", Command: "go", Args: []string{"vet", "./..."}},
+		{Name: "Go Generate", Command: "go", Args: []string{"generate", "./..."}},
+	}
+	pkgs := goPackages(rootPath)
+	var groups []string
+	for _, pkg := range pkgs {
+		if pkg.main {
+			configs = append(configs, DefaultConfig{Name: "Go Run " + pkg.dir, Command: "go", Args: []string{"run", pkg.dir}})
+		}
+		if group := goPackageGroup(pkg.dir); !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	if len(groups) > 1 {
+		for _, group := range groups {
+			configs = append(configs, DefaultConfig{Name: "Go Test " + group, Command: "go", Args: []string{"test", group}})
+		}
+	}
+	return configs
+}
+
+// goPackageGroup is the pattern for the top-level directory holding the
+// package in dir, such as ./internal/... for ./internal/app.
+func goPackageGroup(dir string) string {
+	if dir == "." {
+		return dir
+	}
+	top, _, _ := strings.Cut(strings.TrimPrefix(dir, "./"), "/")
+	return "./" + top + "/..."
+}
+
+// GoEnv is the build environment applied to every go run configuration.
+type GoEnv struct {
+	GOOS    string `json:"goos,omitempty"`
+	GOARCH  string `json:"goarch,omitempty"`
+	GOFLAGS string `json:"goflags,omitempty"`
+}
+
+func goEnvPath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "goenv.json")
+}
+
+func LoadGoEnv(rootPath string) GoEnv {
+	var env GoEnv
+	if data, err := os.ReadFile(goEnvPath(rootPath)); err == nil {
+		_ = json.Unmarshal(data, &env)
+	}
+	return env
+}
+
+func SaveGoEnv(rootPath string, env GoEnv) error {
+	path := goEnvPath(rootPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// vars returns the variables for a go subcommand. Only builds cross-compile;
+// running or testing a foreign binary would fail, so those get GOFLAGS alone.
+func (env GoEnv) vars(subcommand string) map[string]string {
+	vars := map[string]string{"GOOS": "", "GOARCH": "", "GOFLAGS": env.GOFLAGS}
+	if subcommand == "build" {
+		vars["GOOS"], vars["GOARCH"] = env.GOOS, env.GOARCH
+	}
+	return vars
+}
+
+// Platform returns "goos/goarch" when cross-compiling, or "".
+func (env GoEnv) Platform() string {
+	if env.GOOS == "" && env.GOARCH == "" {
+		return ""
+	}
+	goos, goarch := env.GOOS, env.GOARCH
+	if goos == "" {
+		goos = "host"
+	}
+	if goarch == "" {
+		goarch = "host"
+	}
+	return goos + "/" + goarch
+}
+
+// SetGoEnv applies env to the environment of the go configurations.
+func (cm *ConfigManager) SetGoEnv(env GoEnv) {
+	cm.goEnv = env
+	for _, config := range cm.Configs {
+		cm.applyGoEnv(config)
+	}
+}
+
+func (cm *ConfigManager) GoEnv() GoEnv {
+	return cm.goEnv
+}
+
+func (cm *ConfigManager) applyGoEnv(config *RunConfig) {
+	if config.Command != "go" || len(config.Args) == 0 {
+		return
+	}
+	if config.Environment == nil {
+		config.Environment = make(map[string]string)
+	}
+	for key, value := range cm.goEnv.vars(config.Args[0]) {
+		if value == "" {
+			delete(config.Environment, key)
+		} else {
+			config.Environment[key] = value
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package runconfig
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type TestTarget struct {
+	Language string
+	Name     string
+	FilePath string
+	NodeID   string
+	// Python is the interpreter for Python tests; "python" when empty.
+	Python string
+}
+
+var (
+	goTestFuncRegex = regexp.MustCompile(`^func\s+((?:Test|Benchmark|Example|Fuzz)\w*)\s*\(`)
+	pyTestFuncRegex = regexp.MustCompile(`^(\s*)(?:async\s+)?def\s+(test\w*)\s*\(`)
+	pyClassRegex    = regexp.MustCompile(`^(\s*)class\s+(Test\w*)\s*[:(]`)
+)
+
+func TestAtCursor(rootPath, path string, lines []string, line int) (*TestTarget, bool) {
+	if line < 0 || line >= len(lines) {
+		return nil, false
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		if !strings.HasSuffix(path, "_test.go") {
+			return nil, false
+		}
+		return goTestAt(path, lines, line)
+	case ".py":
+		return pyTestAt(rootPath, path, lines, line)
+	}
+	return nil, false
+}
+
+func goTestAt(path string, lines []string, line int) (*TestTarget, bool) {
+	for i := line; i >= 0; i-- {
+		if m := goTestFuncRegex.FindStringSubmatch(lines[i]); m != nil {
+			return &TestTarget{Language: "go", Name: m[1], FilePath: path}, true
+		}
+		if i < line && strings.HasPrefix(lines[i], "}") {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func pyTestAt(rootPath, path string, lines []string, line int) (*TestTarget, bool) {
+	funcIndent := -1
+	funcName := ""
+	for i := line; i >= 0; i-- {
+		if funcName == "" {
+			if m := pyTestFuncRegex.FindStringSubmatch(lines[i]); m != nil {
+				funcIndent = len(m[1])
+				funcName = m[2]
+			}
+			continue
+		}
+		if m := pyClassRegex.FindStringSubmatch(lines[i]); m != nil && len(m[1]) < funcIndent {
+			return newPyTarget(rootPath, path, m[2]+"::"+funcName, funcName), true
+		}
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed != "" && indentOf(lines[i]) < funcIndent && !strings.HasPrefix(trimmed, "@") && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+	}
+	if funcName == "" {
+		return nil, false
+	}
+	return newPyTarget(rootPath, path, funcName, funcName), true
+}
+
+func newPyTarget(rootPath, path, selector, name string) *TestTarget {
+	rel, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		rel = path
+	}
+	return &TestTarget{
+		Language: "python",
+		Name:     name,
+		FilePath: path,
+		NodeID:   filepath.ToSlash(rel) + "::" + selector,
+	}
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func (t *TestTarget) RunConfig(rootPath string, debug bool) *RunConfig {
+	config := &RunConfig{
+		Name:        "Test: " + t.Name,
+		WorkingDir:  rootPath,
+		Environment: make(map[string]string),
+	}
+
+	switch t.Language {
+	case "go":
+		pattern := shellQuote("^" + t.Name + "$")
+		config.WorkingDir = filepath.Dir(t.FilePath)
+		// Benchmarks and fuzz targets run under their own flags, with -run
+		// matching no tests. Under the debugger a fuzz target runs its seed
+		// corpus, since the test binary is built without fuzzing.
+		flags := []string{"-run", pattern}
+		switch {
+		case strings.HasPrefix(t.Name, "Benchmark"):
+			flags = []string{"-run", "'^$'", "-bench", pattern}
+		case strings.HasPrefix(t.Name, "Fuzz") && !debug:
+			flags = []string{"-run", "'^$'", "-fuzz", pattern}
+		}
+		if debug {
+			config.Name = "Debug " + config.Name
+			config.Command = "dlv"
+			config.Args = []string{"test", ".", "--"}
+			for i := 0; i < len(flags); i += 2 {
+				config.Args = append(config.Args, "-test."+flags[i][1:], flags[i+1])
+			}
+		} else {
+			config.Command = "go"
+			config.Args = append(append([]string{"test"}, flags...), ".")
+		}
+	case "python":
+		config.Command = t.Python
+		if config.Command == "" {
+			config.Command = "python"
+		}
+		if debug {
+			config.Name = "Debug " + config.Name
+			config.Args = []string{"-m", "pdb", "-m", "pytest", shellQuote(t.NodeID)}
+		} else {
+			config.Args = []string{"-m", "pytest", shellQuote(t.NodeID)}
+		}
+	}
+
+	return config
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
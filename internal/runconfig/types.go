@@ -8,6 +8,10 @@ const (
 	ProjectTypeFlask   ProjectType = "flask"
 	ProjectTypeFastAPI ProjectType = "fastapi"
 	ProjectTypePython  ProjectType = "python"
+	ProjectTypeGo      ProjectType = "go"
+	ProjectTypeRust    ProjectType = "rust"
+	ProjectTypeCMake   ProjectType = "cmake"
+	ProjectTypeC       ProjectType = "c"
 )
 
 type RunCommandMsg struct {
@@ -50,4 +54,10 @@ var DefaultConfigsByType = map[ProjectType][]DefaultConfig{
 	ProjectTypePython: {
 		{Name: "Run File", Command: "python", Args: []string{}},
 	},
+	ProjectTypeRust: {
+		{Name: "Cargo Build", Command: "cargo", Args: []string{"build"}},
+		{Name: "Cargo Run", Command: "cargo", Args: []string{"run"}},
+		{Name: "Cargo Test", Command: "cargo", Args: []string{"test"}},
+		{Name: "Cargo Clippy", Command: "cargo", Args: []string{"clippy", "--all-targets"}},
+	},
 }
@@ -3,6 +3,8 @@ package runconfig
 import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
 )
 
 type RunBar struct {
@@ -32,6 +34,9 @@ func (r *RunBar) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return r.handleKey(msg)
 	case tea.MouseMsg:
 		return r.handleMouse(msg)
+	case ConfigsDetectedMsg:
+		r.manager.applyDetected(msg)
+		return r, nil
 	case ConfigSelectedMsg:
 		r.manager.Select(msg.Index)
 		r.dropdownOpen = false
@@ -156,12 +161,12 @@ func (r *RunBar) renderRunButton() string {
 		Padding(0, 1).
 		Bold(true)
 
-	return style.Render(" ▶ Run ")
+	return style.Render(" ▶ " + i18n.T("runbar.run") + " ")
 }
 
 func (r *RunBar) renderDropdownButton() string {
 	config := r.manager.GetSelected()
-	name := "No Config"
+	name := i18n.T("runbar.noConfig")
 	if config != nil {
 		name = config.Name
 	}
@@ -194,7 +199,7 @@ func (r *RunBar) renderDropdown() string {
 			Background(lipgloss.Color("#313244")).
 			Foreground(lipgloss.Color("#6c7086")).
 			Padding(0, 1)
-		return style.Render(" No configs available ")
+		return style.Render(" " + i18n.T("runbar.noConfigs") + " ")
 	}
 
 	items := make([]string, 0, len(r.manager.Configs))
@@ -0,0 +1,58 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Create writes the template's files into dir, which must not exist or be
+// empty. {{name}} expands to the directory name and {{package}} to the same
+// name as a valid identifier, both in file contents and paths.
+func Create(t *Template, dir string) error {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s already exists and is not empty", dir)
+	}
+
+	name := filepath.Base(dir)
+	replacer := strings.NewReplacer(
+		"{{name}}", name,
+		"{{package}}", packageName(name),
+	)
+
+	for rel, content := range t.Files {
+		path := filepath.Join(dir, filepath.FromSlash(replacer.Replace(rel)))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(replacer.Replace(content)), 0644); err != nil {
+			return err
+		}
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// PostCreateCommand joins the template's post-create steps into one shell
+// command, or returns "" when there are none.
+func (t *Template) PostCreateCommand() string {
+	return strings.Join(t.PostCreate, " && ")
+}
+
+func packageName(name string) string {
+	var sb strings.Builder
+	for i, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r == '_':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,214 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+)
+
+// CreateMsg asks for a project from Template in Dir. RunPostCreate is set
+// only when the user agreed to run the template's post-create commands.
+type CreateMsg struct {
+	Template      *Template
+	Dir           string
+	RunPostCreate bool
+}
+
+type Panel struct {
+	visible   bool
+	templates []*Template
+	selected  int
+	naming    bool
+	input     []rune
+	// confirming asks whether to run the post-create commands for dir.
+	confirming bool
+	dir        string
+	Err        error
+}
+
+func NewPanel() *Panel {
+	return &Panel{}
+}
+
+func (p *Panel) Visible() bool {
+	return p.visible
+}
+
+func (p *Panel) Toggle() {
+	p.visible = !p.visible
+	if p.visible {
+		p.templates = Templates()
+		p.selected = 0
+		p.naming = false
+		p.confirming = false
+		p.Err = nil
+	}
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !p.visible {
+		return nil
+	}
+	if p.confirming {
+		return p.handleConfirmKey(keyMsg)
+	}
+	if p.naming {
+		return p.handleInputKey(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "esc", "alt+n":
+		p.visible = false
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.templates)-1 {
+			p.selected++
+		}
+	case "enter":
+		if p.selected < len(p.templates) {
+			p.naming = true
+			p.Err = nil
+			p.input = []rune(defaultDir(p.templates[p.selected].Name))
+		}
+	}
+	return nil
+}
+
+func (p *Panel) handleInputKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		dir := expandHome(strings.TrimSpace(string(p.input)))
+		if dir == "" {
+			return nil
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		if len(p.templates[p.selected].PostCreate) > 0 {
+			p.confirming, p.dir = true, dir
+			return nil
+		}
+		return p.create(dir, false)
+	case tea.KeyEsc:
+		p.naming = false
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case tea.KeyRunes:
+		p.input = append(p.input, msg.Runes...)
+	}
+	return nil
+}
+
+// handleConfirmKey asks before running the template's commands, which
+// may come from a user template and run with the user's rights.
+func (p *Panel) handleConfirmKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "y":
+		p.confirming = false
+		return p.create(p.dir, true)
+	case "n":
+		p.confirming = false
+		return p.create(p.dir, false)
+	case "esc":
+		p.confirming = false
+	}
+	return nil
+}
+
+func (p *Panel) create(dir string, runPostCreate bool) tea.Cmd {
+	t := p.templates[p.selected]
+	return func() tea.Msg { return CreateMsg{Template: t, Dir: dir, RunPostCreate: runPostCreate} }
+}
+
+// Done closes the panel after a successful create, or shows err.
+func (p *Panel) Done(err error) {
+	p.Err = err
+	if err == nil {
+		p.visible = false
+		p.naming = false
+		p.confirming = false
+	}
+}
+
+func (p *Panel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cdd6f4"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	selectedStyle := textStyle.Background(lipgloss.Color("#89b4fa")).Foreground(lipgloss.Color("#1e1e2e"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+
+	lines := []string{titleStyle.Render(i18n.T("scaffold.title"))}
+	for i, t := range p.templates {
+		name := t.Name
+		if t.User {
+			name += " " + i18n.T("scaffold.user")
+		}
+		row := textStyle.Render(name) + hintStyle.Render("  "+t.Description)
+		if i == p.selected {
+			row = selectedStyle.Render(name + "  " + t.Description)
+		}
+		lines = append(lines, row)
+	}
+
+	if p.naming {
+		lines = append(lines, "", textStyle.Render(i18n.T("scaffold.directory", string(p.input)+"▏")))
+	}
+	if p.confirming {
+		lines = append(lines, textStyle.Render(i18n.T("scaffold.confirmPostCreate", p.templates[p.selected].PostCreateCommand())))
+	}
+	if p.Err != nil {
+		lines = append(lines, errorStyle.Render(p.Err.Error()))
+	}
+
+	hint := i18n.T("scaffold.hint")
+	switch {
+	case p.confirming:
+		hint = i18n.T("scaffold.hintConfirm")
+	case p.naming:
+		hint = i18n.T("scaffold.hintCreate")
+	}
+	lines = append(lines, "", hintStyle.Render(hint))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Padding(0, 1).
+		Width(64).
+		Render(strings.Join(lines, "\n"))
+}
+
+// defaultDir suggests a sibling of the current workspace.
+func defaultDir(name string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(filepath.Dir(cwd), name)
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
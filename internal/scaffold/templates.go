@@ -0,0 +1,160 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type Template struct {
+	Name        string
+	Description string
+	Files       map[string]string
+	PostCreate  []string
+	User        bool
+}
+
+const userManifest = "template.json"
+
+var builtins = []*Template{
+	{
+		Name:        "go-module",
+		Description: "Go module with a main package",
+		Files: map[string]string{
+			"go.mod":     "module {{name}}\n\ngo 1.22\n",
+			"main.go":    "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"Hello from {{name}}\")\n}\n",
+			".gitignore": "/{{name}}\n*.test\ncoverage.out\n",
+		},
+		PostCreate: []string{"git init -q"},
+	},
+	{
+		Name:        "python-package",
+		Description: "Python package with pyproject.toml and a venv",
+		Files: map[string]string{
+			"pyproject.toml":              "[project]\nname = \"{{name}}\"\nversion = \"0.1.0\"\nrequires-python = \">=3.9\"\n\n[build-system]\nrequires = [\"setuptools\"]\nbuild-backend = \"setuptools.build_meta\"\n",
+			"src/{{package}}/__init__.py": "",
+			"src/{{package}}/__main__.py": "def main():\n    print(\"Hello from {{name}}\")\n\n\nif __name__ == \"__main__\":\n    main()\n",
+			"tests/test_{{package}}.py":   "import {{package}}\n\n\ndef test_import():\n    assert {{package}}\n",
+			".gitignore":                  ".venv/\n__pycache__/\n*.egg-info/\n",
+		},
+		PostCreate: []string{"git init -q", "python3 -m venv .venv", ".venv/bin/pip install -q -e ."},
+	},
+	{
+		Name:        "flask-app",
+		Description: "Flask web application with a venv",
+		Files: map[string]string{
+			"app.py":           "from flask import Flask\n\napp = Flask(__name__)\n\n\n@app.route(\"/\")\ndef index():\n    return \"Hello from {{name}}\"\n\n\nif __name__ == \"__main__\":\n    app.run(debug=True)\n",
+			"requirements.txt": "flask\n",
+			".gitignore":       ".venv/\n__pycache__/\n",
+		},
+		PostCreate: []string{"git init -q", "python3 -m venv .venv", ".venv/bin/pip install -q -r requirements.txt"},
+	},
+	{
+		Name:        "node-app",
+		Description: "Node.js application with package.json",
+		Files: map[string]string{
+			"package.json": "{\n  \"name\": \"{{name}}\",\n  \"version\": \"0.1.0\",\n  \"main\": \"index.js\",\n  \"scripts\": {\n    \"start\": \"node index.js\",\n    \"test\": \"node --test\"\n  }\n}\n",
+			"index.js":     "console.log('Hello from {{name}}');\n",
+			".gitignore":   "node_modules/\n",
+		},
+		PostCreate: []string{"git init -q", "npm install --silent"},
+	},
+}
+
+// UserTemplatesDir holds one directory per user template. Every file in it
+// is copied into the new project except template.json, which may set
+// "description" and "postCreate".
+func UserTemplatesDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tron", "templates")
+}
+
+// Templates returns the built-in templates followed by the user's, sorted
+// by name. A user template replaces a built-in one with the same name.
+func Templates() []*Template {
+	byName := make(map[string]*Template)
+	for _, t := range builtins {
+		byName[t.Name] = t
+	}
+	for _, t := range loadUserTemplates(UserTemplatesDir()) {
+		byName[t.Name] = t
+	}
+
+	templates := make([]*Template, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates
+}
+
+func loadUserTemplates(dir string) []*Template {
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var templates []*Template
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if t, err := loadUserTemplate(filepath.Join(dir, entry.Name())); err == nil {
+			templates = append(templates, t)
+		}
+	}
+	return templates
+}
+
+func loadUserTemplate(dir string) (*Template, error) {
+	t := &Template{
+		Name:        filepath.Base(dir),
+		Description: "User template",
+		Files:       make(map[string]string),
+		User:        true,
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, userManifest)); err == nil {
+		var manifest struct {
+			Description string   `json:"description"`
+			PostCreate  []string `json:"postCreate"`
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, err
+		}
+		if manifest.Description != "" {
+			t.Description = manifest.Description
+		}
+		t.PostCreate = manifest.PostCreate
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == userManifest {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		t.Files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,149 @@
+package suggest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const Timeout = 5 * time.Second
+
+// KillSwitchEnv disables inline suggestions regardless of the user's config.
+const KillSwitchEnv = "TRON_DISABLE_SUGGESTIONS"
+
+type Request struct {
+	Path     string `json:"path"`
+	Language string `json:"language"`
+	Prefix   string `json:"prefix"`
+	Suffix   string `json:"suffix"`
+}
+
+// Provider returns text to insert at the cursor, or "" for no suggestion.
+type Provider interface {
+	Suggest(ctx context.Context, req Request) (string, error)
+}
+
+// CommandProvider runs a local command with the request as JSON on stdin
+// and uses its stdout as the suggestion.
+type CommandProvider struct {
+	Command []string
+}
+
+func (p *CommandProvider) Suggest(ctx context.Context, req Request) (string, error) {
+	if len(p.Command) == 0 {
+		return "", errors.New("no suggestion command configured")
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, p.Command[0], p.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// HTTPProvider posts the request as JSON and accepts either a JSON object
+// with a "suggestion" field or a plain-text body.
+type HTTPProvider struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+func (p *HTTPProvider) Suggest(ctx context.Context, req Request) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("suggestion endpoint returned %s", resp.Status)
+	}
+
+	var parsed struct {
+		Suggestion string `json:"suggestion"`
+	}
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			return "", err
+		}
+		return parsed.Suggestion, nil
+	}
+	return strings.TrimRight(string(body), "\n"), nil
+}
+
+type Config struct {
+	Enabled  bool     `json:"enabled"`
+	Command  []string `json:"command,omitempty"`
+	Endpoint string   `json:"endpoint,omitempty"`
+}
+
+// ConfigPath is the user's suggestions.json. Suggestions run commands and
+// send buffer text away, so they are never configured from a workspace:
+// a cloned repository must not be able to turn them on.
+func ConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tron", "suggestions.json")
+}
+
+// Load reads the user's suggestions.json and builds the configured
+// provider. It returns nil, so suggestions are off, unless that file sets
+// "enabled" and the kill switch is unset.
+func Load() Provider {
+	if v := os.Getenv(KillSwitchEnv); v != "" && v != "0" {
+		return nil
+	}
+	path := ConfigPath()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil || !c.Enabled {
+		return nil
+	}
+	switch {
+	case c.Endpoint != "":
+		return &HTTPProvider{Endpoint: c.Endpoint}
+	case len(c.Command) > 0:
+		return &CommandProvider{Command: c.Command}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package syntax
+
+// Language is what the editor knows about a language besides how to
+// highlight it.
+type Language struct {
+	// LineComment starts a comment that runs to the end of the line.
+	LineComment string
+	// BlockStart and BlockEnd surround a comment, for languages without
+	// line comments.
+	BlockStart, BlockEnd string
+}
+
+var (
+	slashes   = Language{LineComment: "//"}
+	hash      = Language{LineComment: "#"}
+	dashes    = Language{LineComment: "--"}
+	semicolon = Language{LineComment: ";"}
+	percent   = Language{LineComment: "%"}
+	markup    = Language{BlockStart: "<!--", BlockEnd: "-->"}
+)
+
+// languageInfo is keyed by file extension like the highlighters.
+var languageInfo = map[string]Language{
+	".go":         slashes,
+	".c":          slashes,
+	".h":          slashes,
+	".cc":         slashes,
+	".cpp":        slashes,
+	".cxx":        slashes,
+	".hpp":        slashes,
+	".cs":         slashes,
+	".java":       slashes,
+	".kt":         slashes,
+	".kts":        slashes,
+	".scala":      slashes,
+	".swift":      slashes,
+	".dart":       slashes,
+	".rs":         slashes,
+	".js":         slashes,
+	".mjs":        slashes,
+	".cjs":        slashes,
+	".jsx":        slashes,
+	".ts":         slashes,
+	".tsx":        slashes,
+	".php":        slashes,
+	".scss":       slashes,
+	".less":       slashes,
+	".jsonc":      slashes,
+	".proto":      slashes,
+	".py":         hash,
+	".pyw":        hash,
+	".rb":         hash,
+	".sh":         hash,
+	".bash":       hash,
+	".zsh":        hash,
+	".fish":       hash,
+	".pl":         hash,
+	".r":          hash,
+	".ex":         hash,
+	".exs":        hash,
+	".jl":         hash,
+	".nix":        hash,
+	".tf":         hash,
+	".ps1":        hash,
+	".yaml":       hash,
+	".yml":        hash,
+	".toml":       hash,
+	".conf":       hash,
+	".cfg":        hash,
+	".mk":         hash,
+	".cmake":      hash,
+	".dockerfile": hash,
+	".http":       hash,
+	".rest":       hash,
+	".sql":        dashes,
+	".lua":        dashes,
+	".hs":         dashes,
+	".elm":        dashes,
+	".ada":        dashes,
+	".vim":        {LineComment: `"`},
+	".el":         semicolon,
+	".lisp":       semicolon,
+	".clj":        {LineComment: ";;"},
+	".ini":        semicolon,
+	".tex":        percent,
+	".erl":        percent,
+	".html":       markup,
+	".htm":        markup,
+	".xml":        markup,
+	".svg":        markup,
+	".vue":        markup,
+	".md":         markup,
+	".css":        {BlockStart: "/*", BlockEnd: "*/"},
+}
+
+// LanguageFor returns what is known about the language of files with ext.
+func LanguageFor(ext string) (Language, bool) {
+	lang, ok := languageInfo[ext]
+	return lang, ok
+}
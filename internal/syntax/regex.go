@@ -134,9 +134,56 @@ func NewJSHighlighter() *RegexHighlighter {
 	return NewRegexHighlighter(patterns)
 }
 
+func NewJSONHighlighter() *RegexHighlighter {
+	patterns := []pattern{
+		{regexp.MustCompile(`"(?:[^"\\]|\\.)*"\s*:`), TokenVariable},
+		{regexp.MustCompile(`"(?:[^"\\]|\\.)*"`), TokenString},
+		{regexp.MustCompile(`-?\b[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?\b`), TokenNumber},
+		{regexp.MustCompile(`\b(true|false|null)\b`), TokenConstant},
+		{regexp.MustCompile(`[\[\]\{\},:]`), TokenPunctuation},
+	}
+
+	return NewRegexHighlighter(patterns)
+}
+
+// NewDiffHighlighter colors unified diffs and the commit headers git show
+// puts before them.
+func NewDiffHighlighter() *RegexHighlighter {
+	patterns := []pattern{
+		{regexp.MustCompile(`(?m)^(diff|index|commit|Author:|Date:|Merge:) .*$`), TokenKeyword},
+		{regexp.MustCompile(`(?m)^@@.*$`), TokenFunction},
+		{regexp.MustCompile(`(?m)^\+.*$`), TokenString},
+		{regexp.MustCompile(`(?m)^-.*$`), TokenConstant},
+	}
+
+	return NewRegexHighlighter(patterns)
+}
+
 func init() {
 	RegisterLanguage(".go", NewGoHighlighter())
 	RegisterLanguage(".js", NewJSHighlighter())
 	RegisterLanguage(".mjs", NewJSHighlighter())
 	RegisterLanguage(".cjs", NewJSHighlighter())
+	RegisterLanguage(".http", NewHTTPHighlighter())
+	RegisterLanguage(".rest", NewHTTPHighlighter())
+	RegisterLanguage(".json", NewJSONHighlighter())
+	RegisterLanguage(".diff", NewDiffHighlighter())
+	RegisterLanguage(".patch", NewDiffHighlighter())
+}
+
+func NewHTTPHighlighter() *RegexHighlighter {
+	patterns := []pattern{
+		{regexp.MustCompile(`(?m)^\s*(#|//).*$`), TokenComment},
+		{regexp.MustCompile(`(?m)^(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|TRACE|CONNECT)\b`), TokenKeyword},
+		{regexp.MustCompile(`\bHTTP/[0-9.]+\b`), TokenConstant},
+		{regexp.MustCompile(`https?://[^\s{]+`), TokenString},
+		{regexp.MustCompile(`\{\{[^}]*\}\}`), TokenVariable},
+		{regexp.MustCompile(`(?m)^@[\w.-]+`), TokenVariable},
+		{regexp.MustCompile(`(?m)^[A-Za-z0-9-]+:`), TokenTypeName},
+		{regexp.MustCompile(`"(?:[^"\\]|\\.)*"`), TokenString},
+		{regexp.MustCompile(`\b[0-9]+(\.[0-9]+)?\b`), TokenNumber},
+		{regexp.MustCompile(`\b(true|false|null)\b`), TokenConstant},
+	}
+
+	return NewRegexHighlighter(patterns)
 }
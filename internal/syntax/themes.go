@@ -34,6 +34,25 @@ func DefaultTheme() *Theme {
 	}
 }
 
+// HighContrastTheme uses bright, saturated colors and adds bold or
+// underline to token classes so they stay distinguishable without color.
+func HighContrastTheme() *Theme {
+	return &Theme{
+		Keyword:     lipgloss.NewStyle().Foreground(lipgloss.Color("#ffff00")).Bold(true),
+		String:      lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff00")),
+		Comment:     lipgloss.NewStyle().Foreground(lipgloss.Color("#00ffff")).Italic(true),
+		Number:      lipgloss.NewStyle().Foreground(lipgloss.Color("#ff80ff")),
+		Function:    lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Underline(true),
+		Operator:    lipgloss.NewStyle().Foreground(lipgloss.Color("#ffff00")),
+		Identifier:  lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")),
+		Type:        lipgloss.NewStyle().Foreground(lipgloss.Color("#80c0ff")).Bold(true),
+		Builtin:     lipgloss.NewStyle().Foreground(lipgloss.Color("#ffa040")).Bold(true),
+		Constant:    lipgloss.NewStyle().Foreground(lipgloss.Color("#ff80ff")).Bold(true),
+		Variable:    lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")),
+		Punctuation: lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")),
+	}
+}
+
 func (t *Theme) StyleForToken(tt TokenType) lipgloss.Style {
 	switch tt {
 	case TokenKeyword:
@@ -0,0 +1,145 @@
+package tabs
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	quickSwitchWindow = 400 * time.Millisecond
+	switcherIdleDelay = 1200 * time.Millisecond
+)
+
+type quickSwitchCommitMsg struct {
+	seq int
+}
+
+func (t *TabBar) touchMRU(tab *Tab) {
+	t.removeMRU(tab)
+	t.mru = append([]*Tab{tab}, t.mru...)
+}
+
+func (t *TabBar) removeMRU(tab *Tab) {
+	for i, m := range t.mru {
+		if m == tab {
+			t.mru = append(t.mru[:i], t.mru[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *TabBar) MRU() []*Tab {
+	return t.mru
+}
+
+func (t *TabBar) SwitcherActive() bool {
+	return t.switcherPending || t.switcherOpen
+}
+
+// QuickSwitch switches to the previously used tab, or with the switcher
+// already pending moves it on to the next one.
+func (t *TabBar) QuickSwitch() tea.Cmd {
+	return t.quickSwitch()
+}
+
+func (t *TabBar) quickSwitch() tea.Cmd {
+	if len(t.mru) < 2 {
+		return nil
+	}
+
+	if !t.SwitcherActive() {
+		t.switcherPending = true
+		t.switcherIndex = 1
+		return t.scheduleSwitchCommit(quickSwitchWindow)
+	}
+
+	t.switcherOpen = true
+	t.switcherIndex = (t.switcherIndex + 1) % len(t.mru)
+	return t.scheduleSwitchCommit(switcherIdleDelay)
+}
+
+func (t *TabBar) scheduleSwitchCommit(delay time.Duration) tea.Cmd {
+	t.switcherSeq++
+	seq := t.switcherSeq
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return quickSwitchCommitMsg{seq: seq}
+	})
+}
+
+func (t *TabBar) handleSwitcherKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+tab":
+		return t, t.quickSwitch()
+	case "esc":
+		t.closeSwitcher()
+		return t, nil
+	}
+	// Any other key commits the switch and then does what it would have,
+	// in the tab switched to.
+	return t, tea.Sequence(t.commitSwitch(), func() tea.Msg { return msg })
+}
+
+func (t *TabBar) commitSwitch() tea.Cmd {
+	if !t.SwitcherActive() {
+		return nil
+	}
+	index := t.switcherIndex
+	t.closeSwitcher()
+	if index < 0 || index >= len(t.mru) {
+		return nil
+	}
+	tab := t.mru[index]
+	t.SetActive(tab.Index)
+	return t.switchTabCmd(tab.Index, tab.Path)
+}
+
+func (t *TabBar) closeSwitcher() {
+	t.switcherPending = false
+	t.switcherOpen = false
+	t.switcherIndex = 0
+	t.switcherSeq++
+}
+
+func (t *TabBar) SwitcherView() string {
+	if !t.switcherOpen {
+		return ""
+	}
+
+	width := 0
+	for _, tab := range t.mru {
+		if w := lipgloss.Width(tab.DisplayName); w > width {
+			width = w
+		}
+	}
+	width += 4
+
+	itemStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#cdd6f4")).
+		Padding(0, 1).
+		Width(width)
+	selectedStyle := itemStyle.
+		Background(lipgloss.Color("#89b4fa")).
+		Foreground(lipgloss.Color("#1e1e2e"))
+
+	items := make([]string, 0, len(t.mru))
+	for i, tab := range t.mru {
+		name := tab.DisplayName
+		if tab.Dirty {
+			name = "● " + name
+		}
+		if i == t.switcherIndex {
+			items = append(items, selectedStyle.Render(name))
+		} else {
+			items = append(items, itemStyle.Render(name))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Background(lipgloss.Color("#1e1e2e")).
+		Render(strings.Join(items, "\n"))
+}
@@ -6,11 +6,14 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"tron/pkg/pathutil"
 )
 
 type Tab struct {
 	Path        string
 	DisplayName string
+	Title       string
 	Dirty       bool
 	Index       int
 }
@@ -22,6 +25,19 @@ type TabBar struct {
 	maxTabWidth  int
 	scrollOffset int
 	height       int
+	renaming     bool
+	renameInput  []rune
+	hoverIndex   int
+
+	// Accessible marks the active and dirty tabs with plain characters
+	// instead of relying on color alone.
+	Accessible bool
+
+	mru             []*Tab
+	switcherPending bool
+	switcherOpen    bool
+	switcherIndex   int
+	switcherSeq     int
 }
 
 func New() *TabBar {
@@ -32,11 +48,16 @@ func New() *TabBar {
 		maxTabWidth:  30,
 		scrollOffset: 0,
 		height:       1,
+		hoverIndex:   -1,
 	}
 }
 
 func (t *TabBar) AddTab(path string) int {
+	path = pathutil.Canonical(path)
 	displayName := filepath.Base(path)
+	if pathutil.IsVirtual(path) {
+		displayName = path
+	}
 	tab := &Tab{
 		Path:        path,
 		DisplayName: displayName,
@@ -44,6 +65,7 @@ func (t *TabBar) AddTab(path string) int {
 		Index:       len(t.tabs),
 	}
 	t.tabs = append(t.tabs, tab)
+	t.mru = append(t.mru, tab)
 	if t.activeIndex < 0 {
 		t.activeIndex = 0
 	}
@@ -54,6 +76,7 @@ func (t *TabBar) CloseTab(index int) {
 	if index < 0 || index >= len(t.tabs) {
 		return
 	}
+	t.removeMRU(t.tabs[index])
 	t.tabs = append(t.tabs[:index], t.tabs[index+1:]...)
 	for i := range t.tabs {
 		t.tabs[i].Index = i
@@ -70,6 +93,7 @@ func (t *TabBar) CloseTab(index int) {
 func (t *TabBar) SetActive(index int) {
 	if index >= 0 && index < len(t.tabs) {
 		t.activeIndex = index
+		t.touchMRU(t.tabs[index])
 		t.ensureActiveVisible()
 	}
 }
@@ -88,6 +112,7 @@ func (t *TabBar) MarkDirty(index int, dirty bool) {
 }
 
 func (t *TabBar) FindTab(path string) int {
+	path = pathutil.Canonical(path)
 	for i, tab := range t.tabs {
 		if tab.Path == path {
 			return i
@@ -112,89 +137,92 @@ func (t *TabBar) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return t.handleMouse(msg)
 	case tea.KeyMsg:
 		return t.handleKey(msg)
+	case quickSwitchCommitMsg:
+		if msg.seq == t.switcherSeq {
+			return t, t.commitSwitch()
+		}
 	}
 	return t, nil
 }
 
 func (t *TabBar) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	if msg.Type != tea.MouseLeft {
+	if msg.Type == tea.MouseMotion {
+		t.hoverIndex = -1
+		if msg.Y == 0 {
+			t.hoverIndex = t.tabAt(msg.X)
+		}
 		return t, nil
 	}
-	if msg.Y != 0 {
+	if msg.Y != 0 || msg.Type != tea.MouseLeft && msg.Type != tea.MouseMiddle {
 		return t, nil
 	}
-	x := msg.X
-
-	newBtnWidth := 3
-	if x >= t.width-newBtnWidth {
-		return t, t.newTabCmd()
-	}
 
-	for i := t.scrollOffset; i < len(t.tabs); i++ {
-		tabStart, tabEnd := t.getTabBounds(i)
-
-		if x >= tabStart && x < tabEnd {
-			closeBtnStart := tabEnd - 3
-			if x >= closeBtnStart && x < tabEnd {
-				return t, t.closeTabCmd(i, t.tabs[i].Path)
-			}
-			return t, t.switchTabCmd(i, t.tabs[i].Path)
+	index, part := t.hitTest(msg.X)
+	if part == partNew {
+		if msg.Type == tea.MouseLeft {
+			return t, t.newTabCmd()
 		}
+		return t, nil
+	}
+	if index < 0 {
+		return t, nil
 	}
+	path := t.tabs[index].Path
+	switch {
+	case msg.Type == tea.MouseMiddle || part == partClose:
+		return t, t.closeTabCmd(index, path)
+	case msg.Ctrl:
+		return t, func() tea.Msg { return TabRevealMsg{Index: index, FilePath: path} }
+	case msg.Shift:
+		return t, func() tea.Msg { return TabSplitMsg{Index: index, FilePath: path} }
+	}
+	return t, t.switchTabCmd(index, path)
+}
 
-	return t, nil
+func (t *TabBar) tabAt(x int) int {
+	if index, part := t.hitTest(x); part != partNew {
+		return index
+	}
+	return -1
 }
 
 func (t *TabBar) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if t.renaming {
+		return t.handleRenameKey(msg)
+	}
+	if t.SwitcherActive() {
+		return t.handleSwitcherKey(msg)
+	}
+
 	switch msg.String() {
+	case "f2":
+		t.StartRename()
 	case "ctrl+tab":
-		if len(t.tabs) == 0 {
-			return t, nil
-		}
-		nextIndex := (t.activeIndex + 1) % len(t.tabs)
-		t.SetActive(nextIndex)
-		if tab := t.GetActive(); tab != nil {
-			return t, t.switchTabCmd(nextIndex, tab.Path)
-		}
-	case "ctrl+w":
-		if t.activeIndex >= 0 && t.activeIndex < len(t.tabs) {
-			tab := t.tabs[t.activeIndex]
-			return t, t.closeTabCmd(t.activeIndex, tab.Path)
-		}
+		return t, t.quickSwitch()
 	}
 	return t, nil
 }
 
+// CloseActive closes the active tab. It is a keymap command rather than a
+// key of the tab bar, since the editor takes ctrl+w to delete a word.
+func (t *TabBar) CloseActive() tea.Cmd {
+	if t.activeIndex < 0 || t.activeIndex >= len(t.tabs) {
+		return nil
+	}
+	return t.closeTabCmd(t.activeIndex, t.tabs[t.activeIndex].Path)
+}
+
 func (t *TabBar) View() string {
 	if t.width == 0 {
 		return ""
 	}
 
 	var tabStrs []string
-	for i := t.scrollOffset; i < len(t.tabs); i++ {
-		tab := t.tabs[i]
-		tabStr := t.renderTab(tab, i == t.activeIndex)
-		tabStrs = append(tabStrs, tabStr)
-
-		totalWidth := 0
-		for _, s := range tabStrs {
-			totalWidth += lipgloss.Width(s)
-		}
-		if totalWidth > t.width-3 {
-			tabStrs = tabStrs[:len(tabStrs)-1]
-			break
-		}
+	for _, s := range t.layout() {
+		tabStrs = append(tabStrs, t.renderTab(t.tabs[s.index], s.index == t.activeIndex))
 	}
 
 	newBtn := t.renderNewButton()
-	remainingWidth := t.width
-	for _, s := range tabStrs {
-		remainingWidth -= lipgloss.Width(s)
-	}
-	if remainingWidth < 3 {
-		remainingWidth = 3
-	}
-
 	tabBarStyle := lipgloss.NewStyle().Background(lipgloss.Color("#1e1e2e"))
 	var result string
 	if len(tabStrs) > 0 {
@@ -227,8 +255,11 @@ func (t *TabBar) renderTab(tab *Tab, active bool) string {
 	dirtyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
 
 	displayName := tab.DisplayName
+	if active && t.renaming {
+		displayName = string(t.renameInput) + "▏"
+	}
 	if tab.Dirty {
-		displayName = dirtyStyle.Render("●") + " " + displayName
+		displayName = dirtyStyle.Render(t.dirtyMarker()) + " " + displayName
 	}
 
 	maxWidth := t.maxTabWidth - 4
@@ -238,6 +269,13 @@ func (t *TabBar) renderTab(tab *Tab, active bool) string {
 	if len(displayName) > maxWidth {
 		displayName = displayName[:maxWidth-1] + "…"
 	}
+	if t.Accessible {
+		marker := " "
+		if active {
+			marker = ">"
+		}
+		displayName = marker + displayName
+	}
 
 	closeStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#f38ba8"))
@@ -256,32 +294,75 @@ func (t *TabBar) renderNewButton() string {
 	return style.Render(" + ")
 }
 
-func (t *TabBar) calculateTabWidth(tab *Tab) int {
-	dirtyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+// tabWidth is the width of tab index as drawn, so hit-testing lines up
+// with what is on screen.
+func (t *TabBar) tabWidth(index int) int {
+	return lipgloss.Width(t.renderTab(t.tabs[index], index == t.activeIndex))
+}
 
-	displayName := tab.DisplayName
-	if tab.Dirty {
-		displayName = dirtyStyle.Render("●") + " " + displayName
-	}
+func (t *TabBar) newButtonWidth() int {
+	return lipgloss.Width(t.renderNewButton())
+}
 
-	maxWidth := t.maxTabWidth - 4
-	if maxWidth < 5 {
-		maxWidth = 5
-	}
-	if len(displayName) > maxWidth {
-		displayName = displayName[:maxWidth-1] + "…"
+func (t *TabBar) dirtyMarker() string {
+	if t.Accessible {
+		return "*"
 	}
+	return "●"
+}
 
-	return lipgloss.Width(displayName) + 6
+// tabSpan is the columns a drawn tab covers, end exclusive.
+type tabSpan struct {
+	index, start, end int
+}
+
+// layout returns the tabs drawn from the scroll offset on: as many as fit
+// before the new-tab button.
+func (t *TabBar) layout() []tabSpan {
+	var spans []tabSpan
+	x, limit := 0, t.width-t.newButtonWidth()
+	for i := t.scrollOffset; i < len(t.tabs); i++ {
+		w := t.tabWidth(i)
+		if x+w > limit {
+			break
+		}
+		spans = append(spans, tabSpan{index: i, start: x, end: x + w})
+		x += w
+	}
+	return spans
 }
 
-func (t *TabBar) getTabBounds(index int) (int, int) {
-	start := 0
-	for i := t.scrollOffset; i < index; i++ {
-		start += t.calculateTabWidth(t.tabs[i])
+// tabPart is what a click in the tab bar landed on.
+type tabPart int
+
+const (
+	partNone tabPart = iota
+	partTab
+	partClose
+	partNew
+)
+
+// closeButtonWidth is the close mark at the end of each tab with the space
+// before it and the padding after.
+const closeButtonWidth = 3
+
+// hitTest returns the tab and part of it under column x. Only drawn tabs
+// are hit, so tabs scrolled off or pushed past the new-tab button cannot
+// be.
+func (t *TabBar) hitTest(x int) (int, tabPart) {
+	if x >= t.width-t.newButtonWidth() {
+		return -1, partNew
+	}
+	for _, s := range t.layout() {
+		if x < s.start || x >= s.end {
+			continue
+		}
+		if x >= s.end-closeButtonWidth {
+			return s.index, partClose
+		}
+		return s.index, partTab
 	}
-	end := start + t.calculateTabWidth(t.tabs[index])
-	return start, end
+	return -1, partNone
 }
 
 func (t *TabBar) adjustScrollOffset() {
@@ -301,14 +382,17 @@ func (t *TabBar) ensureActiveVisible() {
 		return
 	}
 
+	if t.activeIndex < t.scrollOffset {
+		t.scrollOffset = t.activeIndex
+	}
 	totalWidth := 0
 	for i := t.scrollOffset; i <= t.activeIndex; i++ {
-		totalWidth += t.calculateTabWidth(t.tabs[i])
+		totalWidth += t.tabWidth(i)
 	}
 
-	availableWidth := t.width - 3
+	availableWidth := t.width - t.newButtonWidth()
 	for totalWidth > availableWidth && t.scrollOffset < t.activeIndex {
-		totalWidth -= t.calculateTabWidth(t.tabs[t.scrollOffset])
+		totalWidth -= t.tabWidth(t.scrollOffset)
 		t.scrollOffset++
 	}
 }
@@ -350,24 +434,91 @@ func (t *TabBar) NextTab() {
 	if len(t.tabs) == 0 {
 		return
 	}
-	t.activeIndex = (t.activeIndex + 1) % len(t.tabs)
-	t.ensureActiveVisible()
+	t.SetActive((t.activeIndex + 1) % len(t.tabs))
 }
 
 func (t *TabBar) PrevTab() {
 	if len(t.tabs) == 0 {
 		return
 	}
-	t.activeIndex--
-	if t.activeIndex < 0 {
-		t.activeIndex = len(t.tabs) - 1
+	prev := t.activeIndex - 1
+	if prev < 0 {
+		prev = len(t.tabs) - 1
 	}
-	t.ensureActiveVisible()
+	t.SetActive(prev)
 }
 
 func (t *TabBar) UpdateTabPath(index int, newPath string) {
 	if index >= 0 && index < len(t.tabs) {
+		newPath = pathutil.Canonical(newPath)
 		t.tabs[index].Path = newPath
-		t.tabs[index].DisplayName = filepath.Base(newPath)
+		if t.tabs[index].Title == "" {
+			t.tabs[index].DisplayName = filepath.Base(newPath)
+		}
+	}
+}
+
+func (t *TabBar) SetTitle(index int, title string) {
+	if index < 0 || index >= len(t.tabs) {
+		return
+	}
+	tab := t.tabs[index]
+	tab.Title = strings.TrimSpace(title)
+	if tab.Title != "" {
+		tab.DisplayName = tab.Title
+	} else {
+		tab.DisplayName = filepath.Base(tab.Path)
+	}
+}
+
+func (t *TabBar) StartRename() {
+	tab := t.GetActive()
+	if tab == nil {
+		return
+	}
+	t.renaming = true
+	t.renameInput = []rune(tab.DisplayName)
+}
+
+func (t *TabBar) IsRenaming() bool {
+	return t.renaming
+}
+
+func (t *TabBar) CapturesInput() bool {
+	return t.renaming || t.SwitcherActive()
+}
+
+func (t *TabBar) handleRenameKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		t.renaming = false
+		t.SetTitle(t.activeIndex, string(t.renameInput))
+		t.renameInput = nil
+		if tab := t.GetActive(); tab != nil {
+			index, title := tab.Index, tab.Title
+			return t, func() tea.Msg {
+				return TabRenamedMsg{Index: index, Title: title}
+			}
+		}
+	case tea.KeyEsc:
+		t.renaming = false
+		t.renameInput = nil
+	case tea.KeyBackspace:
+		if len(t.renameInput) > 0 {
+			t.renameInput = t.renameInput[:len(t.renameInput)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		t.renameInput = append(t.renameInput, msg.Runes...)
+	}
+	return t, nil
+}
+
+func (t *TabBar) StatusText() string {
+	if tab := t.GetTab(t.hoverIndex); tab != nil {
+		return tab.Path
+	}
+	if tab := t.GetActive(); tab != nil && tab.Title != "" {
+		return tab.Path
 	}
+	return ""
 }
@@ -16,3 +16,20 @@ type TabAddedMsg struct {
 	Index    int
 	FilePath string
 }
+
+type TabRenamedMsg struct {
+	Index int
+	Title string
+}
+
+// TabRevealMsg asks for the tab's file to be shown in the file tree.
+type TabRevealMsg struct {
+	Index    int
+	FilePath string
+}
+
+// TabSplitMsg asks for the tab's file to be opened in a new split.
+type TabSplitMsg struct {
+	Index    int
+	FilePath string
+}
@@ -0,0 +1,183 @@
+// Package termcaps works out at startup what the terminal can draw: how
+// many colors, whether a Nerd Font supplies the file icons and whether
+// Unicode symbols render at all. Rendering code asks Symbol for a glyph
+// and gets an ASCII stand-in where the real one would come out as a box.
+package termcaps
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/rivo/uniseg"
+)
+
+// Environment variables that override detection.
+const (
+	// ColorsEnv forces the color depth: "truecolor", "256", "16" or "none".
+	ColorsEnv = "TRON_COLORS"
+	// NerdFontEnv forces Nerd Font icons on ("1") or off ("0").
+	NerdFontEnv = "TRON_NERD_FONT"
+)
+
+type ColorDepth int
+
+const (
+	NoColor ColorDepth = iota
+	Colors16
+	Colors256
+	TrueColor
+)
+
+func (d ColorDepth) String() string {
+	switch d {
+	case TrueColor:
+		return "truecolor"
+	case Colors256:
+		return "256"
+	case Colors16:
+		return "16"
+	}
+	return "none"
+}
+
+type Caps struct {
+	Colors   ColorDepth
+	NerdFont bool
+	Unicode  bool
+	// AmbiguousWide is set where East Asian characters of ambiguous width
+	// take two cells, as in CJK locales.
+	AmbiguousWide bool
+}
+
+var current = Caps{Colors: TrueColor, NerdFont: true, Unicode: true}
+
+func Current() Caps {
+	return current
+}
+
+// Detect reads the terminal's capabilities from the environment.
+func Detect() Caps {
+	term := os.Getenv("TERM")
+	c := Caps{
+		Colors:  detectColors(term),
+		Unicode: detectUnicode(term),
+	}
+	c.NerdFont = c.Unicode && detectNerdFont()
+	// RUNEWIDTH_EASTASIAN is the variable other terminal programs honor
+	// for this; without it CJK locales get wide ambiguous characters.
+	lang := localeName()
+	cjk := strings.HasPrefix(lang, "zh") || strings.HasPrefix(lang, "ja") || strings.HasPrefix(lang, "ko")
+	switch wide := os.Getenv("RUNEWIDTH_EASTASIAN"); wide {
+	case "":
+		c.AmbiguousWide = cjk
+	default:
+		c.AmbiguousWide = wide == "1"
+	}
+	return c
+}
+
+func detectColors(term string) ColorDepth {
+	switch os.Getenv(ColorsEnv) {
+	case "truecolor", "24bit":
+		return TrueColor
+	case "256":
+		return Colors256
+	case "16":
+		return Colors16
+	case "none":
+		return NoColor
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return NoColor
+	}
+	switch colorterm := os.Getenv("COLORTERM"); {
+	case colorterm == "truecolor" || colorterm == "24bit" || strings.HasSuffix(term, "-direct"):
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return Colors256
+	case term == "dumb":
+		return NoColor
+	}
+	return Colors16
+}
+
+func detectUnicode(term string) bool {
+	// The Linux console and hardware terminal types only have a small
+	// font; a locale naming a charset other than UTF-8 rules it out too.
+	if term == "linux" || term == "dumb" || strings.HasPrefix(term, "vt") {
+		return false
+	}
+	lang := localeName()
+	if i := strings.IndexByte(lang, '.'); i >= 0 {
+		charset := strings.ToLower(strings.ReplaceAll(lang[i+1:], "-", ""))
+		return strings.HasPrefix(charset, "utf8")
+	}
+	return true
+}
+
+// detectNerdFont looks for a Nerd Font among the installed fonts. Over SSH
+// the fonts that matter are on the other end, so it assumes there is none.
+func detectNerdFont() bool {
+	switch os.Getenv(NerdFontEnv) {
+	case "1":
+		return true
+	case "0":
+		return false
+	}
+	if os.Getenv("SSH_CONNECTION") != "" {
+		return false
+	}
+	out, err := exec.Command("fc-list", ":", "family").Output()
+	return err == nil && strings.Contains(string(out), "Nerd Font")
+}
+
+func localeName() string {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Apply makes c the capabilities rendering uses: colors are downgraded to
+// the depth the terminal has and widths follow its ambiguous-width rule.
+func Apply(c Caps) {
+	current = c
+	profile := map[ColorDepth]termenv.Profile{
+		TrueColor: termenv.TrueColor,
+		Colors256: termenv.ANSI256,
+		Colors16:  termenv.ANSI,
+		NoColor:   termenv.Ascii,
+	}[c.Colors]
+	lipgloss.SetColorProfile(profile)
+	uniseg.EastAsianAmbiguousWidth = 1
+	if c.AmbiguousWide {
+		uniseg.EastAsianAmbiguousWidth = 2
+	}
+}
+
+// Symbol returns glyph where the terminal can draw it and ascii where it
+// cannot.
+func Symbol(glyph, ascii string) string {
+	if current.Unicode {
+		return glyph
+	}
+	return ascii
+}
+
+// Degraded lists what falls short of a full-featured terminal, for a
+// one-time warning; it is empty when nothing does.
+func (c Caps) Degraded() []string {
+	var out []string
+	if c.Colors < Colors256 {
+		out = append(out, "colors")
+	}
+	if !c.Unicode {
+		out = append(out, "unicode")
+	}
+	return out
+}
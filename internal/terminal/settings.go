@@ -0,0 +1,81 @@
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Settings are a project's terminal setup, read from .tron/terminal.json:
+//
+//	{
+//	  "shell": "bash",
+//	  "env": {"PYTHONPATH": "src"},
+//	  "startup": ["source .venv/bin/activate"]
+//	}
+//
+// Every command runs in a session of its own, so the environment is set
+// and the startup commands run before each one.
+type Settings struct {
+	Shell   string            `json:"shell,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Startup []string          `json:"startup,omitempty"`
+}
+
+func settingsPath(rootPath string) string {
+	return filepath.Join(rootPath, ".tron", "terminal.json")
+}
+
+// LoadSettings reads the project's terminal settings. A project without a
+// config file gets the defaults.
+func LoadSettings(rootPath string) (Settings, error) {
+	data, err := os.ReadFile(settingsPath(rootPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Settings{}, nil
+		}
+		return Settings{}, err
+	}
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Settings{}, fmt.Errorf("terminal.json: %w", err)
+	}
+	for name := range s.Env {
+		if !validEnvName(name) {
+			return Settings{}, fmt.Errorf("terminal.json: %q is not a valid variable name", name)
+		}
+	}
+	return s, nil
+}
+
+func validEnvName(name string) bool {
+	for i, c := range name {
+		if !(c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// Script prefixes command with the environment and startup commands. The
+// shell expands the values, so "PATH": ".venv/bin:$PATH" extends the path.
+func (s Settings) Script(command string) string {
+	if len(s.Env) == 0 && len(s.Startup) == 0 {
+		return command
+	}
+	names := make([]string, 0, len(s.Env))
+	for name := range s.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var lines []string
+	for _, name := range names {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s.Env[name])
+		lines = append(lines, fmt.Sprintf(`export %s="%s"`, name, value))
+	}
+	lines = append(lines, s.Startup...)
+	return strings.Join(append(lines, command), "\n")
+}
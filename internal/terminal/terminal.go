@@ -10,12 +10,16 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"tron/internal/i18n"
+	"tron/internal/termcaps"
 )
 
 type Terminal struct {
 	Lines       []string
 	Command     string
 	Cwd         string
+	Shell       string
 	Cmd         *exec.Cmd
 	Width       int
 	Height      int
@@ -28,6 +32,9 @@ type Terminal struct {
 	outputQueue []string
 }
 
+// BuiltinKeys are the shortcuts the terminal panel handles itself.
+var BuiltinKeys = []string{"up", "down", "pgup", "pgdown", "ctrl+c", "ctrl+l"}
+
 func New() *Terminal {
 	return &Terminal{
 		Lines:      make([]string, 0),
@@ -37,6 +44,12 @@ func New() *Terminal {
 }
 
 func (t *Terminal) RunCommand(cmdStr string, cwd string) error {
+	return t.RunScript(cmdStr, cmdStr, cwd)
+}
+
+// RunScript runs script in the shell, showing it as cmdStr, for scripts
+// that set the session up before running the command.
+func (t *Terminal) RunScript(cmdStr, script, cwd string) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -52,7 +65,11 @@ func (t *Terminal) RunCommand(cmdStr string, cwd string) error {
 	t.Lines = append(t.Lines, "")
 	t.Lines = append(t.Lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#89b4fa")).Render("$ "+cmdStr))
 
-	t.Cmd = exec.Command("sh", "-c", cmdStr)
+	shell := t.Shell
+	if shell == "" {
+		shell = "sh"
+	}
+	t.Cmd = exec.Command(shell, "-c", script)
 	t.Cmd.Dir = cwd
 
 	stdout, err := t.Cmd.StdoutPipe()
@@ -96,6 +113,22 @@ func (t *Terminal) readOutput(r io.Reader, isStderr bool) {
 	}
 }
 
+func (t *Terminal) Println(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Lines = append(t.Lines, line)
+	if t.AutoScroll {
+		t.ScrollPos = len(t.Lines) - 1
+	}
+}
+
+// LinesSince returns a copy of the lines after the first n.
+func (t *Terminal) LinesSince(n int) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.Lines[min(n, len(t.Lines)):]...)
+}
+
 func (t *Terminal) waitProcess() {
 	err := t.Cmd.Wait()
 	t.mu.Lock()
@@ -117,6 +150,12 @@ func (t *Terminal) waitProcess() {
 	}
 }
 
+func (t *Terminal) IsRunning() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Running
+}
+
 func (t *Terminal) Stop() {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -312,24 +351,24 @@ func (t *Terminal) renderStatusBar() string {
 
 	var status string
 	if t.Running {
-		spinner := "⠋"
+		spinner := termcaps.Symbol("⠋", "*")
 		status = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#f9e2af")).
-			Render(spinner+" Running: "+t.Command)
+			Render(spinner + " " + i18n.T("terminal.running", t.Command))
 	} else if t.ExitCode >= 0 {
 		if t.ExitCode == 0 {
 			status = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#a6e3a1")).
-				Render("✓ Exit code: 0")
+				Render(termcaps.Symbol("✓", "ok") + " " + i18n.T("terminal.exitCode", 0))
 		} else {
 			status = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#f38ba8")).
-				Render("✗ Exit code: "+string(rune('0'+t.ExitCode)))
+				Render(termcaps.Symbol("✗", "!!") + " " + i18n.T("terminal.exitCode", t.ExitCode))
 		}
 	} else {
 		status = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#6c7086")).
-			Render("Ready")
+			Render(i18n.T("terminal.ready"))
 	}
 
 	style := lipgloss.NewStyle().
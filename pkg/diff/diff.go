@@ -0,0 +1,136 @@
+// Package diff finds the differences between two versions of a text, line
+// by line or within a line.
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Edit replaces a[OldStart:OldEnd] with b[NewStart:NewEnd]. The edits of a
+// diff are in order and never touch, so everything between them is equal.
+type Edit struct {
+	OldStart, OldEnd int
+	NewStart, NewEnd int
+}
+
+// Algorithm is a way of finding the edits between two sequences. Both find
+// a short diff; they differ in which one when there is a choice.
+type Algorithm int
+
+const (
+	// Myers finds a diff with as few inserted and deleted elements as
+	// possible.
+	Myers Algorithm = iota
+	// Histogram lines up rare elements first, as git's histogram diff
+	// does. This keeps a changed function together instead of matching
+	// its braces and blank lines with those of its neighbours.
+	Histogram
+)
+
+// Algorithms lists the algorithms by name.
+var Algorithms = map[string]Algorithm{"myers": Myers, "histogram": Histogram}
+
+func (alg Algorithm) String() string {
+	for name, a := range Algorithms {
+		if a == alg {
+			return name
+		}
+	}
+	return fmt.Sprintf("Algorithm(%d)", int(alg))
+}
+
+// maxEdits bounds the work spent on one diff. Past it the differing middle
+// of the inputs is reported as a single edit.
+const maxEdits = 1000
+
+// Slices returns the edits turning a into b.
+func Slices[T comparable](a, b []T, alg Algorithm) []Edit {
+	prefix, suffix := commonEnds(a, b)
+	var edits []Edit
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	switch alg {
+	case Histogram:
+		edits = histogram(midA, midB, 0, 0, edits)
+	default:
+		edits = myers(midA, midB, 0, 0, edits)
+	}
+	for i := range edits {
+		edits[i].OldStart += prefix
+		edits[i].OldEnd += prefix
+		edits[i].NewStart += prefix
+		edits[i].NewEnd += prefix
+	}
+	return edits
+}
+
+// Lines returns the edits turning the lines of a into those of b, as
+// indexes into the two slices.
+func Lines(a, b []string, alg Algorithm) []Edit {
+	return Slices(a, b, alg)
+}
+
+// Text splits a and b into lines and returns them with the edits between
+// them. A trailing newline leaves an empty last line, as in the editor.
+func Text(a, b string, alg Algorithm) (linesA, linesB []string, edits []Edit) {
+	linesA, linesB = strings.Split(a, "\n"), strings.Split(b, "\n")
+	return linesA, linesB, Lines(linesA, linesB, alg)
+}
+
+// Inline returns the edits turning a into b character by character, as
+// byte offsets, for showing what changed within a line.
+func Inline(a, b string) []Edit {
+	ra, rb := []rune(a), []rune(b)
+	edits := Slices(ra, rb, Myers)
+	offA, offB := runeOffsets(a), runeOffsets(b)
+	for i, e := range edits {
+		edits[i] = Edit{OldStart: offA[e.OldStart], OldEnd: offA[e.OldEnd], NewStart: offB[e.NewStart], NewEnd: offB[e.NewEnd]}
+	}
+	return edits
+}
+
+// runeOffsets returns the byte offset of each rune of s and, last, len(s).
+func runeOffsets(s string) []int {
+	offsets := make([]int, 0, utf8.RuneCountInString(s)+1)
+	for i := range s {
+		offsets = append(offsets, i)
+	}
+	return append(offsets, len(s))
+}
+
+// Apply rebuilds b from a and the edits between them, taking the inserted
+// elements from b.
+func Apply[T any](a, b []T, edits []Edit) []T {
+	out := make([]T, 0, len(b))
+	last := 0
+	for _, e := range edits {
+		out = append(out, a[last:e.OldStart]...)
+		out = append(out, b[e.NewStart:e.NewEnd]...)
+		last = e.OldEnd
+	}
+	return append(out, a[last:]...)
+}
+
+func commonEnds[T comparable](a, b []T) (prefix, suffix int) {
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return prefix, suffix
+}
+
+// appendEdit adds the edit replacing a[i0:i1] with b[j0:j1], merging it
+// with the previous edit when they touch. Empty edits are dropped.
+func appendEdit(edits []Edit, i0, i1, j0, j1 int) []Edit {
+	if i0 == i1 && j0 == j1 {
+		return edits
+	}
+	if n := len(edits); n > 0 && edits[n-1].OldEnd == i0 && edits[n-1].NewEnd == j0 {
+		edits[n-1].OldEnd, edits[n-1].NewEnd = i1, j1
+		return edits
+	}
+	return append(edits, Edit{OldStart: i0, OldEnd: i1, NewStart: j0, NewEnd: j1})
+}
@@ -0,0 +1,57 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func FuzzApply(f *testing.F) {
+	f.Add("a\nb\nc\n", "a\nc\nd\n")
+	f.Add("", "x\n")
+	f.Add("same\n", "same\n")
+	f.Add("func f() {\n}\n\nfunc g() {\n}\n", "func f() {\n}\n\nfunc h() {\n}\n\nfunc g() {\n}\n")
+	f.Add("{\n}\n{\n}\n{\n}\n", "}\n{\n}\n")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		for _, alg := range []Algorithm{Myers, Histogram} {
+			linesA, linesB, edits := Text(a, b, alg)
+			checkEdits(t, alg, linesA, linesB, edits)
+			if got := strings.Join(Apply(linesA, linesB, edits), "\n"); got != b {
+				t.Fatalf("%v: Apply gave %q, want %q", alg, got, b)
+			}
+
+			ba, bb := []byte(a), []byte(b)
+			edits = Slices(ba, bb, alg)
+			checkEdits(t, alg, ba, bb, edits)
+			if got := string(Apply(ba, bb, edits)); got != b {
+				t.Fatalf("%v: Apply of bytes gave %q, want %q", alg, got, b)
+			}
+		}
+	})
+}
+
+// checkEdits fails unless the edits are in order, never touch and leave
+// equal elements between them, so that Apply keeps only what a and b
+// share.
+func checkEdits[T comparable](t *testing.T, alg Algorithm, a, b []T, edits []Edit) {
+	t.Helper()
+	lastA, lastB := 0, 0
+	for i, e := range edits {
+		if e.OldStart > e.OldEnd || e.NewStart > e.NewEnd || e.OldStart == e.OldEnd && e.NewStart == e.NewEnd {
+			t.Fatalf("%v: edit %d is empty or reversed: %+v", alg, i, e)
+		}
+		if i > 0 && e.OldStart <= lastA && e.NewStart <= lastB {
+			t.Fatalf("%v: edit %d touches the one before: %+v", alg, i, edits)
+		}
+		if e.OldStart < lastA || e.NewStart < lastB || e.OldEnd > len(a) || e.NewEnd > len(b) {
+			t.Fatalf("%v: edit %d is out of order or range: %+v", alg, i, edits)
+		}
+		if !slices.Equal(a[lastA:e.OldStart], b[lastB:e.NewStart]) {
+			t.Fatalf("%v: the elements before edit %d differ: %+v", alg, i, edits)
+		}
+		lastA, lastB = e.OldEnd, e.NewEnd
+	}
+	if !slices.Equal(a[lastA:], b[lastB:]) {
+		t.Fatalf("%v: the elements after the last edit differ: %+v", alg, edits)
+	}
+}
@@ -0,0 +1,53 @@
+package diff
+
+// maxChain is the most times an element may occur in a to be used as an
+// anchor; regions with only commoner elements fall back to Myers.
+const maxChain = 64
+
+// histogram appends the edits turning a into b, which start at offA and
+// offB of the whole inputs. It anchors on the element of a occurring the
+// fewest times that b also has, grows the match around it and diffs the
+// regions on either side the same way.
+func histogram[T comparable](a, b []T, offA, offB int, edits []Edit) []Edit {
+	prefix, suffix := commonEnds(a, b)
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	offA, offB = offA+prefix, offB+prefix
+	if len(a) == 0 || len(b) == 0 {
+		return appendEdit(edits, offA, offA+len(a), offB, offB+len(b))
+	}
+
+	counts := make(map[T]int, len(a))
+	first := make(map[T]int, len(a))
+	for i, x := range a {
+		if counts[x] == 0 {
+			first[x] = i
+		}
+		counts[x]++
+	}
+	bestA, bestB, bestCount := -1, -1, maxChain+1
+	common := false
+	for j, x := range b {
+		c := counts[x]
+		common = common || c > 0
+		if c > 0 && c < bestCount {
+			bestA, bestB, bestCount = first[x], j, c
+		}
+	}
+	switch {
+	case !common:
+		return appendEdit(edits, offA, offA+len(a), offB, offB+len(b))
+	case bestA < 0:
+		return myers(a, b, offA, offB, edits)
+	}
+
+	startA, startB := bestA, bestB
+	for startA > 0 && startB > 0 && a[startA-1] == b[startB-1] {
+		startA, startB = startA-1, startB-1
+	}
+	endA, endB := bestA+1, bestB+1
+	for endA < len(a) && endB < len(b) && a[endA] == b[endB] {
+		endA, endB = endA+1, endB+1
+	}
+	edits = histogram(a[:startA], b[:startB], offA, offB, edits)
+	return histogram(a[endA:], b[endB:], offA+endA, offB+endB, edits)
+}
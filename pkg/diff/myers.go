@@ -0,0 +1,66 @@
+package diff
+
+// myers appends the edits turning a into b, which start at offA and offB
+// of the whole inputs, using Myers' O(ND) algorithm.
+func myers[T comparable](a, b []T, offA, offB int, edits []Edit) []Edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return appendEdit(edits, offA, offA+n, offB, offB+m)
+	}
+
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] keeps v for diagonals -d-1 to d+1 as it was before step d.
+	var trace [][]int
+	found := false
+	for d := 0; d <= min(n+m, maxEdits) && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			x := v[offset+k-1] + 1
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return appendEdit(edits, offA, offA+n, offB, offB+m)
+	}
+
+	// Walk the trace back from the end, marking the matched elements.
+	keep := make([]bool, n)
+	match := make([]int, n)
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		at := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		prev := k - 1
+		if k == -d || k != d && at(k-1) < at(k+1) {
+			prev = k + 1
+		}
+		px := at(prev)
+		py := px - prev
+		for x > px && y > py {
+			x, y = x-1, y-1
+			keep[x], match[x] = true, y
+		}
+		x, y = px, py
+	}
+
+	i, j := 0, 0
+	for x := range a {
+		if keep[x] {
+			edits = appendEdit(edits, offA+i, offA+x, offB+j, offB+match[x])
+			i, j = x+1, match[x]+1
+		}
+	}
+	return appendEdit(edits, offA+i, offA+n, offB+j, offB+m)
+}
@@ -0,0 +1,210 @@
+// Package fuzzy matches typed patterns against names and paths the way
+// pickers do: the pattern's characters must appear in order, and matches
+// score higher the more of them are consecutive or start a word.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	scoreMatch       = 16
+	penaltyGapStart  = -3
+	penaltyGapExtend = -1
+	// bonusConsecutive rewards a character right after the previous one.
+	bonusConsecutive = 6
+	// A character starting a path segment, a word or a camelCase hump
+	// earns these; the pattern's first character earns twice as much.
+	bonusSeparator = 10
+	bonusBoundary  = 8
+	bonusCamel     = 7
+	firstCharTimes = 2
+)
+
+// Match is a candidate a pattern matched.
+type Match struct {
+	// Index is the candidate's position in the list given to Filter.
+	Index int
+	Score int
+	// Positions are the byte offsets of the matched characters in the
+	// candidate, ascending, for highlighting them.
+	Positions []int
+}
+
+// Score reports whether pattern matches s and how well. Matching ignores
+// case unless pattern has an upper-case letter.
+func Score(pattern, s string) (score int, positions []int, ok bool) {
+	p := []rune(pattern)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	fold := !hasUpper(pattern)
+	text, offsets := runesOf(s)
+	if !subsequence(p, text, fold) {
+		return 0, nil, false
+	}
+
+	n, m := len(p), len(text)
+	bonus := make([]int, m)
+	for j := range text {
+		bonus[j] = bonusAt(text, j)
+	}
+	// best[i][j] is the best score of the first i+1 characters of the
+	// pattern with the last at text[j]; from[i][j] is where the previous
+	// character went.
+	const none = -1 << 30
+	best := make([][]int, n)
+	from := make([][]int, n)
+	for i := range best {
+		best[i] = make([]int, m)
+		from[i] = make([]int, m)
+		for j := range best[i] {
+			best[i][j] = none
+		}
+	}
+	for j := range text {
+		if equal(p[0], text[j], fold) {
+			best[0][j] = scoreMatch + bonus[j]*firstCharTimes
+		}
+	}
+	for i := 1; i < n; i++ {
+		gap, gapFrom := none, -1
+		for j := i; j < m; j++ {
+			// gap is the best score of a previous character two or more
+			// places back, less the gap between it and j.
+			if gap != none {
+				gap += penaltyGapExtend
+			}
+			if j >= 2 && best[i-1][j-2] != none && best[i-1][j-2]+penaltyGapStart > gap {
+				gap, gapFrom = best[i-1][j-2]+penaltyGapStart, j-2
+			}
+			if !equal(p[i], text[j], fold) {
+				continue
+			}
+			if prev := best[i-1][j-1]; prev != none && prev+bonusConsecutive >= gap {
+				best[i][j], from[i][j] = prev+bonusConsecutive+scoreMatch+bonus[j], j-1
+			} else if gap != none {
+				best[i][j], from[i][j] = gap+scoreMatch+bonus[j], gapFrom
+			}
+		}
+	}
+
+	end := -1
+	for j := range text {
+		if best[n-1][j] != none && (end < 0 || best[n-1][j] > best[n-1][end]) {
+			end = j
+		}
+	}
+	positions = make([]int, n)
+	for i, j := n-1, end; i >= 0; i-- {
+		positions[i] = offsets[j]
+		j = from[i][j]
+	}
+	return best[n-1][end], positions, true
+}
+
+// Filter returns the candidates pattern matches, best first. Equal scores
+// favour shorter candidates, then earlier ones. An empty pattern matches
+// everything in order.
+func Filter(pattern string, candidates []string) []Match {
+	var matches []Match
+	for i, c := range candidates {
+		if score, positions, ok := Score(pattern, c); ok {
+			matches = append(matches, Match{Index: i, Score: score, Positions: positions})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		return len(candidates[matches[a].Index]) < len(candidates[matches[b].Index])
+	})
+	return matches
+}
+
+// Highlight renders the runs of s at positions with matched and the runs
+// between them with other.
+func Highlight(s string, positions []int, matched, other func(string) string) string {
+	var sb strings.Builder
+	last := 0
+	for i := 0; i < len(positions); {
+		start := positions[i]
+		end := start + runeLen(s, start)
+		for i++; i < len(positions) && positions[i] == end; i++ {
+			end += runeLen(s, end)
+		}
+		if start > last {
+			sb.WriteString(other(s[last:start]))
+		}
+		sb.WriteString(matched(s[start:end]))
+		last = end
+	}
+	if last < len(s) {
+		sb.WriteString(other(s[last:]))
+	}
+	return sb.String()
+}
+
+func runeLen(s string, at int) int {
+	_, size := utf8.DecodeRuneInString(s[at:])
+	return size
+}
+
+func runesOf(s string) ([]rune, []int) {
+	text := make([]rune, 0, len(s))
+	offsets := make([]int, 0, len(s))
+	for i, r := range s {
+		text = append(text, r)
+		offsets = append(offsets, i)
+	}
+	return text, offsets
+}
+
+func hasUpper(s string) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(p, t rune, fold bool) bool {
+	if fold {
+		return p == unicode.ToLower(t)
+	}
+	return p == t
+}
+
+func subsequence(p, text []rune, fold bool) bool {
+	i := 0
+	for _, r := range text {
+		if i < len(p) && equal(p[i], r, fold) {
+			i++
+		}
+	}
+	return i == len(p)
+}
+
+// bonusAt returns the bonus for matching text[j], which depends on what
+// comes before it.
+func bonusAt(text []rune, j int) int {
+	if j == 0 {
+		return bonusBoundary
+	}
+	prev, r := text[j-1], text[j]
+	switch {
+	case prev == '/' || prev == '\\':
+		return bonusSeparator
+	case strings.ContainsRune(" _-.:", prev):
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return bonusBoundary
+		}
+	case unicode.IsLower(prev) && unicode.IsUpper(r), !unicode.IsDigit(prev) && unicode.IsDigit(r):
+		return bonusCamel
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+package fuzzy
+
+import (
+	"fmt"
+	"testing"
+)
+
+// paths is a workspace-sized list of file paths, like the file picker
+// filters: 10,000 of them, a few directories deep, in mixed naming styles.
+var paths = func() []string {
+	dirs := []string{"cmd/tron", "internal/app", "internal/editor", "internal/filetree", "internal/lsp",
+		"pkg/diff", "pkg/fuzzy", "web/src/components", "web/src/hooks", "docs/guide"}
+	names := []string{"main", "buffer", "pieceBuffer", "file_tree", "workspaceSymbols", "README",
+		"config", "keymap_test", "useDebounce", "SearchPanel"}
+	exts := []string{".go", ".ts", ".tsx", ".md", ".json"}
+	var out []string
+	for i := 0; len(out) < 10000; i++ {
+		dir := dirs[i%len(dirs)]
+		name := names[i/len(dirs)%len(names)]
+		ext := exts[i/(len(dirs)*len(names))%len(exts)]
+		out = append(out, fmt.Sprintf("%s/v%d/%s%s", dir, i/(len(dirs)*len(names)*len(exts)), name, ext))
+	}
+	return out
+}()
+
+func BenchmarkScore(b *testing.B) {
+	for _, pattern := range []string{"buf", "edpb", "intlspws", "SearchPanel.tsx"} {
+		b.Run(pattern, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Score(pattern, paths[i%len(paths)])
+			}
+		})
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	for _, pattern := range []string{"", "m", "buf", "edpb", "intlspws", "xyzzy"} {
+		b.Run(fmt.Sprintf("%q", pattern), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Filter(pattern, paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package layout
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	floatMinWidth  = 16
+	floatMinHeight = 4
+)
+
+var floatHandleStyle = lipgloss.NewStyle().Foreground(frameFocusColor)
+
+// Float is a bordered panel drawn over the layout. It is moved by dragging
+// its top edge, resized by dragging its bottom right corner and hidden with
+// the × in its top right corner.
+type Float struct {
+	*Frame
+	X, Y   int
+	Hidden bool
+}
+
+func (f *Float) contains(x, y int) bool {
+	return x >= f.X && x < f.X+f.width && y >= f.Y && y < f.Y+f.height
+}
+
+func (f *Float) closeX() int {
+	return f.X + f.width - 2
+}
+
+type floatDrag struct {
+	float  *Float
+	resize bool
+	// dx and dy are where the drag started relative to the float's corner.
+	dx, dy int
+}
+
+// FloatStack keeps floating panels in z-order, lowest first, and gives them
+// input before the layout underneath. Clicking a float raises and focuses
+// it; clicking anywhere else hands focus back to the layout.
+type FloatStack struct {
+	floats []*Float
+	focus  FrameGroup
+	drag   *floatDrag
+	width  int
+	height int
+}
+
+func NewFloatStack() *FloatStack {
+	return &FloatStack{}
+}
+
+// NewFloat creates a hidden float for p at the given position and size.
+func (s *FloatStack) NewFloat(p Panel, title string, x, y, w, h int) *Float {
+	f := &Float{Frame: NewFrame(p, title, &s.focus), X: x, Y: y, Hidden: true}
+	f.Border = true
+	f.SetSize(w, h)
+	s.floats = append(s.floats, f)
+	s.clamp(f)
+	return f
+}
+
+// Show makes f visible, on top and focused.
+func (s *FloatStack) Show(f *Float) {
+	f.Hidden = false
+	s.Raise(f)
+}
+
+func (s *FloatStack) Hide(f *Float) {
+	f.Hidden = true
+	if s.focus.focused == f.Frame {
+		s.focus.Focus(nil)
+	}
+	if s.drag != nil && s.drag.float == f {
+		s.drag = nil
+	}
+}
+
+// Toggle shows f if it is hidden or not focused, and hides it otherwise.
+func (s *FloatStack) Toggle(f *Float) {
+	if f.Hidden || s.Focused() != f {
+		s.Show(f)
+		return
+	}
+	s.Hide(f)
+}
+
+func (s *FloatStack) Raise(f *Float) {
+	for i, g := range s.floats {
+		if g == f {
+			s.floats = append(append(s.floats[:i:i], s.floats[i+1:]...), f)
+			break
+		}
+	}
+	s.focus.Focus(f.Frame)
+}
+
+// Focused returns the float receiving keys, or nil when the layout has them.
+func (s *FloatStack) Focused() *Float {
+	for _, f := range s.floats {
+		if !f.Hidden && f.Frame == s.focus.focused {
+			return f
+		}
+	}
+	return nil
+}
+
+func (s *FloatStack) Blur() {
+	s.focus.Focus(nil)
+}
+
+// SetSize sets the area floats are kept inside.
+func (s *FloatStack) SetSize(w, h int) {
+	s.width, s.height = w, h
+	for _, f := range s.floats {
+		s.clamp(f)
+	}
+}
+
+func (s *FloatStack) clamp(f *Float) {
+	if s.width == 0 || s.height == 0 {
+		return
+	}
+	w := max(min(f.width, s.width), min(floatMinWidth, s.width))
+	h := max(min(f.height, s.height), min(floatMinHeight, s.height))
+	if w != f.width || h != f.height {
+		f.SetSize(w, h)
+	}
+	f.X = max(0, min(f.X, s.width-w))
+	f.Y = max(0, min(f.Y, s.height-h))
+}
+
+// at returns the topmost visible float under a point.
+func (s *FloatStack) at(x, y int) *Float {
+	for i := len(s.floats) - 1; i >= 0; i-- {
+		if f := s.floats[i]; !f.Hidden && f.contains(x, y) {
+			return f
+		}
+	}
+	return nil
+}
+
+// HandleMouse routes a mouse event to the float under it and reports
+// whether one took it.
+func (s *FloatStack) HandleMouse(msg tea.MouseMsg) (tea.Cmd, bool) {
+	if d := s.drag; d != nil {
+		switch msg.Type {
+		case tea.MouseMotion:
+			if d.resize {
+				d.float.SetSize(max(msg.X-d.float.X+d.dx, floatMinWidth), max(msg.Y-d.float.Y+d.dy, floatMinHeight))
+			} else {
+				d.float.X, d.float.Y = msg.X-d.dx, msg.Y-d.dy
+			}
+			s.clamp(d.float)
+		case tea.MouseRelease:
+			s.drag = nil
+		}
+		return nil, true
+	}
+
+	f := s.at(msg.X, msg.Y)
+	if f == nil {
+		if msg.Type == tea.MouseLeft {
+			s.Blur()
+		}
+		return nil, false
+	}
+	if msg.Type == tea.MouseLeft {
+		s.Raise(f)
+		x, y := msg.X-f.X, msg.Y-f.Y
+		switch {
+		case y == 0 && msg.X == f.closeX():
+			s.Hide(f)
+			return nil, true
+		case y == 0:
+			s.drag = &floatDrag{float: f, dx: x, dy: y}
+			return nil, true
+		case y == f.height-1 && x == f.width-1:
+			s.drag = &floatDrag{float: f, resize: true, dx: f.width - x, dy: f.height - y}
+			return nil, true
+		}
+	}
+	msg.X -= f.X
+	msg.Y -= f.Y
+	return f.Update(msg), true
+}
+
+// Update sends keys to the focused float and every message other than
+// input to all of them. Mouse events go through HandleMouse.
+func (s *FloatStack) Update(msg tea.Msg) tea.Cmd {
+	switch msg.(type) {
+	case tea.MouseMsg:
+		return nil
+	case tea.KeyMsg:
+		if f := s.Focused(); f != nil {
+			return f.Panel.Update(msg)
+		}
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, f := range s.floats {
+		cmds = append(cmds, f.Panel.Update(msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+// View draws the visible floats over base, lowest first.
+func (s *FloatStack) View(base string) string {
+	for _, f := range s.floats {
+		if f.Hidden {
+			continue
+		}
+		v := f.Frame.View()
+		if v == "" {
+			continue
+		}
+		v = Overlay(v, floatHandleStyle.Render("×"), f.width-2, 0)
+		v = Overlay(v, floatHandleStyle.Render("◢"), f.width-1, f.height-1)
+		base = Overlay(base, v, f.X, f.Y)
+	}
+	return base
+}
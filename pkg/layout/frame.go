@@ -0,0 +1,128 @@
+package layout
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	frameBorderColor  = lipgloss.Color("#45475a")
+	frameFocusColor   = lipgloss.Color("#89b4fa")
+	frameTitleStyle   = lipgloss.NewStyle().Bold(true)
+	frameBorderGlyphs = lipgloss.RoundedBorder()
+)
+
+// FrameGroup tracks which of a set of frames was last clicked, so only that
+// one draws its border in the focus color.
+type FrameGroup struct {
+	focused *Frame
+}
+
+func (g *FrameGroup) Focus(f *Frame) {
+	g.focused = f
+}
+
+func (g *FrameGroup) Focused() *Frame {
+	return g.focused
+}
+
+// Frame draws an optional border around a panel with a title set into its
+// top edge. With Border off it passes everything straight through.
+type Frame struct {
+	Panel  Panel
+	Title  string
+	Border bool
+
+	group  *FrameGroup
+	width  int
+	height int
+}
+
+func NewFrame(p Panel, title string, group *FrameGroup) *Frame {
+	return &Frame{Panel: p, Title: title, group: group}
+}
+
+func (f *Frame) SetBorder(on bool) {
+	f.Border = on
+	f.SetSize(f.width, f.height)
+}
+
+func (f *Frame) SetSize(w, h int) {
+	f.width, f.height = w, h
+	if f.Border {
+		f.Panel.SetSize(max(w-2, 0), max(h-2, 0))
+		return
+	}
+	f.Panel.SetSize(w, h)
+}
+
+func (f *Frame) Update(msg tea.Msg) tea.Cmd {
+	mouse, ok := msg.(tea.MouseMsg)
+	if !ok {
+		return f.Panel.Update(msg)
+	}
+	if mouse.Type == tea.MouseLeft && f.group != nil {
+		f.group.Focus(f)
+	}
+	if !f.Border {
+		return f.Panel.Update(msg)
+	}
+	onBorder := mouse.X <= 0 || mouse.Y <= 0 || mouse.X >= f.width-1 || mouse.Y >= f.height-1
+	if onBorder && mouse.Type == tea.MouseLeft {
+		return nil
+	}
+	mouse.X--
+	mouse.Y--
+	return f.Panel.Update(mouse)
+}
+
+// Focused reports whether f is the frame of its group last clicked.
+func (f *Frame) Focused() bool {
+	return f.group != nil && f.group.focused == f
+}
+
+func (f *Frame) View() string {
+	if !f.Border {
+		return f.Panel.View()
+	}
+	if f.width < 2 || f.height < 2 {
+		return ""
+	}
+	color := frameBorderColor
+	if f.Focused() {
+		color = frameFocusColor
+	}
+	edge := lipgloss.NewStyle().Foreground(color)
+	b := frameBorderGlyphs
+	inner := f.width - 2
+
+	title := ""
+	if f.Title != "" && inner > 4 {
+		title = ansi.Truncate(" "+f.Title+" ", inner-2, "…")
+	}
+	fill := max(inner-1-ansi.StringWidth(title), 0)
+	top := edge.Render(b.TopLeft+b.Top) + frameTitleStyle.Foreground(color).Render(title) +
+		edge.Render(strings.Repeat(b.Top, fill)+b.TopRight)
+	if title == "" {
+		top = edge.Render(b.TopLeft + strings.Repeat(b.Top, inner) + b.TopRight)
+	}
+
+	lines := strings.Split(f.Panel.View(), "\n")
+	rows := make([]string, 0, f.height)
+	rows = append(rows, top)
+	for i := 0; i < f.height-2; i++ {
+		line := ""
+		if i < len(lines) {
+			line = ansi.Truncate(lines[i], inner, "")
+		}
+		if w := ansi.StringWidth(line); w < inner {
+			line += strings.Repeat(" ", inner-w)
+		}
+		rows = append(rows, edge.Render(b.Left)+line+edge.Render(b.Right))
+	}
+	rows = append(rows, edge.Render(b.BottomLeft+strings.Repeat(b.Bottom, inner)+b.BottomRight))
+	return strings.Join(rows, "\n")
+}
@@ -1,6 +1,7 @@
 package layout
 
 import (
+	"math"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,10 +21,26 @@ const (
 	Vertical
 )
 
+// DefaultSnapRatios are the divider positions a drag snaps to.
+var DefaultSnapRatios = []float64{0.25, 0.5, 0.75}
+
+// snapDistance is how many cells from a snap point the divider snaps.
+const snapDistance = 2
+
+// RatioChangedMsg is sent when a divider drag ends with the split at a new
+// ratio.
+type RatioChangedMsg struct {
+	Split *Split
+	Ratio float64
+}
+
 type Split struct {
 	Direction Direction
 	First     Panel
 	Second    Panel
+	// SnapRatios are where the divider snaps while dragged; nil disables
+	// snapping.
+	SnapRatios []float64
 
 	position    int
 	ratio       float64
@@ -33,7 +50,9 @@ type Split struct {
 	minSecond   int
 	dragging    bool
 	dragOffset  int
+	dragRatio   float64
 	dividerSize int
+	captured    Panel
 }
 
 func NewHorizontalSplit(left, right Panel, initialRatio float64) *Split {
@@ -41,6 +60,7 @@ func NewHorizontalSplit(left, right Panel, initialRatio float64) *Split {
 		Direction:   Horizontal,
 		First:       left,
 		Second:      right,
+		SnapRatios:  DefaultSnapRatios,
 		ratio:       initialRatio,
 		minFirst:    10,
 		minSecond:   10,
@@ -53,6 +73,7 @@ func NewVerticalSplit(top, bottom Panel, initialRatio float64) *Split {
 		Direction:   Vertical,
 		First:       top,
 		Second:      bottom,
+		SnapRatios:  DefaultSnapRatios,
 		ratio:       initialRatio,
 		minFirst:    3,
 		minSecond:   3,
@@ -72,25 +93,13 @@ func (s *Split) recalculateSizes() {
 	}
 
 	if s.Direction == Horizontal {
-		s.position = int(float64(s.width) * s.ratio)
-		if s.position < s.minFirst {
-			s.position = s.minFirst
-		}
-		if s.position > s.width-s.minSecond-s.dividerSize {
-			s.position = s.width - s.minSecond - s.dividerSize
-		}
+		s.position = s.clampPosition(int(float64(s.width)*s.ratio), s.width)
 		firstWidth := s.position
 		secondWidth := s.width - s.position - s.dividerSize
 		s.First.SetSize(firstWidth, s.height)
 		s.Second.SetSize(secondWidth, s.height)
 	} else {
-		s.position = int(float64(s.height) * s.ratio)
-		if s.position < s.minFirst {
-			s.position = s.minFirst
-		}
-		if s.position > s.height-s.minSecond-s.dividerSize {
-			s.position = s.height - s.minSecond - s.dividerSize
-		}
+		s.position = s.clampPosition(int(float64(s.height)*s.ratio), s.height)
 		firstHeight := s.position
 		secondHeight := s.height - s.position - s.dividerSize
 		s.First.SetSize(s.width, firstHeight)
@@ -142,6 +151,7 @@ func (s *Split) handleMouse(msg tea.MouseMsg) tea.Cmd {
 	case tea.MouseLeft:
 		if isOverDivider {
 			s.dragging = true
+			s.dragRatio = s.ratio
 			if s.Direction == Horizontal {
 				s.dragOffset = msg.X - s.position
 			} else {
@@ -150,9 +160,15 @@ func (s *Split) handleMouse(msg tea.MouseMsg) tea.Cmd {
 			return nil
 		}
 	case tea.MouseRelease:
-		s.dragging = false
-		s.dragOffset = 0
-		return nil
+		if s.dragging {
+			s.dragging = false
+			s.dragOffset = 0
+			if s.ratio == s.dragRatio {
+				return nil
+			}
+			split, ratio := s, s.ratio
+			return func() tea.Msg { return RatioChangedMsg{Split: split, Ratio: ratio} }
+		}
 	case tea.MouseMotion:
 		if s.dragging {
 			var newPos int
@@ -161,34 +177,104 @@ func (s *Split) handleMouse(msg tea.MouseMsg) tea.Cmd {
 			} else {
 				newPos = msg.Y - s.dragOffset
 			}
-			s.setPosition(newPos)
+			s.setPosition(s.snap(newPos))
 			return nil
 		}
 	}
 
-	var cmds []tea.Cmd
-	if cmd := s.First.Update(msg); cmd != nil {
-		cmds = append(cmds, cmd)
+	target := s.childAt(msg.X, msg.Y)
+	if msg.Type == tea.MouseLeft {
+		s.captured = target
 	}
-	if cmd := s.Second.Update(msg); cmd != nil {
-		cmds = append(cmds, cmd)
+	held := msg.Type == tea.MouseMotion && msg.Button != tea.MouseButtonNone
+	if s.captured != nil && (msg.Type == tea.MouseRelease || held) {
+		target = s.captured
 	}
-	return tea.Batch(cmds...)
+	if msg.Type == tea.MouseRelease {
+		s.captured = nil
+	}
+
+	switch target {
+	case s.First:
+		return s.First.Update(msg)
+	case s.Second:
+		return s.Second.Update(s.toSecond(msg))
+	}
+	return nil
 }
 
-func (s *Split) setPosition(pos int) {
-	maxPos := 0
+func (s *Split) childAt(x, y int) Panel {
+	pos := x
+	if s.Direction == Vertical {
+		pos = y
+	}
+	if pos < s.position {
+		return s.First
+	}
+	if pos >= s.position+s.dividerSize {
+		return s.Second
+	}
+	return nil
+}
+
+func (s *Split) toSecond(msg tea.MouseMsg) tea.MouseMsg {
+	offset := s.position + s.dividerSize
 	if s.Direction == Horizontal {
-		maxPos = s.width - s.minSecond - s.dividerSize
+		msg.X -= offset
 	} else {
-		maxPos = s.height - s.minSecond - s.dividerSize
+		msg.Y -= offset
+	}
+	return msg
+}
+
+func (s *Split) clampPosition(pos, size int) int {
+	available := size - s.dividerSize
+	if available <= 0 {
+		return 0
+	}
+
+	minFirst, minSecond := s.minFirst, s.minSecond
+	if minFirst+minSecond > available {
+		minFirst = int(float64(available) * s.ratio)
+		minSecond = available - minFirst
+	}
+
+	if pos < minFirst {
+		pos = minFirst
 	}
+	if pos > available-minSecond {
+		pos = available - minSecond
+	}
+	return pos
+}
+
+// snap moves pos onto the nearest snap point within snapDistance.
+func (s *Split) snap(pos int) int {
+	size := s.width
+	if s.Direction == Vertical {
+		size = s.height
+	}
+	for _, ratio := range s.SnapRatios {
+		point := int(math.Round(float64(size) * ratio))
+		if abs(pos-point) <= snapDistance {
+			return point
+		}
+	}
+	return pos
+}
 
-	if pos < s.minFirst {
-		pos = s.minFirst
+func abs(n int) int {
+	if n < 0 {
+		return -n
 	}
-	if pos > maxPos {
-		pos = maxPos
+	return n
+}
+
+func (s *Split) setPosition(pos int) {
+	if s.Direction == Horizontal {
+		pos = s.clampPosition(pos, s.width)
+	} else {
+		pos = s.clampPosition(pos, s.height)
 	}
 
 	s.position = pos
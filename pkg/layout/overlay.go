@@ -0,0 +1,48 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const resetStyle = "\x1b[0m"
+
+func Overlay(base, overlay string, x, y int) string {
+	if x < 0 {
+		x = 0
+	}
+	baseLines := strings.Split(base, "\n")
+	for i, line := range strings.Split(overlay, "\n") {
+		row := y + i
+		if row < 0 || row >= len(baseLines) {
+			continue
+		}
+
+		under := baseLines[row]
+		left := ansi.Truncate(under, x, "")
+		if w := ansi.StringWidth(left); w < x {
+			left += strings.Repeat(" ", x-w)
+		}
+		right := ansi.TruncateLeft(under, x+ansi.StringWidth(line), "")
+
+		baseLines[row] = left + resetStyle + line + resetStyle + right
+	}
+	return strings.Join(baseLines, "\n")
+}
+
+func OverlayCenter(base, overlay string, width, height int) string {
+	x := (width - ansi.StringWidth(firstLine(overlay))) / 2
+	y := (height - strings.Count(overlay, "\n") - 1) / 2
+	if y < 0 {
+		y = 0
+	}
+	return Overlay(base, overlay, x, y)
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package layout
+
+import (
+	"math"
+)
+
+type SplitState struct {
+	Ratio     float64 `json:"ratio"`
+	MinFirst  int     `json:"minFirst"`
+	MinSecond int     `json:"minSecond"`
+}
+
+func (s *Split) State() SplitState {
+	return SplitState{
+		Ratio:     s.ratio,
+		MinFirst:  s.minFirst,
+		MinSecond: s.minSecond,
+	}
+}
+
+func (s *Split) Restore(state SplitState) {
+	if math.IsNaN(state.Ratio) || state.Ratio <= 0 || state.Ratio >= 1 {
+		return
+	}
+	s.ratio = state.Ratio
+	if state.MinFirst >= 0 {
+		s.minFirst = state.MinFirst
+	}
+	if state.MinSecond >= 0 {
+		s.minSecond = state.MinSecond
+	}
+	s.recalculateSizes()
+}
+
+func CollectStates(p Panel) []SplitState {
+	s, ok := p.(*Split)
+	if !ok {
+		return nil
+	}
+	states := []SplitState{s.State()}
+	states = append(states, CollectStates(s.First)...)
+	states = append(states, CollectStates(s.Second)...)
+	return states
+}
+
+func RestoreStates(p Panel, states []SplitState) int {
+	s, ok := p.(*Split)
+	if !ok || len(states) == 0 {
+		return 0
+	}
+	s.Restore(states[0])
+	n := 1
+	n += RestoreStates(s.First, states[n:])
+	n += RestoreStates(s.Second, states[n:])
+	return n
+}
@@ -0,0 +1,51 @@
+package pathutil
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Location is a place in a file as written in compiler output, stack
+// traces and the like. Line and Column are 1-based, 0 when not given.
+type Location struct {
+	Path   string
+	Line   int
+	Column int
+}
+
+var (
+	// pythonLocation matches tracebacks: File "x.py", line 12.
+	pythonLocation = regexp.MustCompile(`File "([^"]+)", line (\d+)`)
+	// pathLocation matches path, path:line and path:line:col, with an
+	// optional drive letter, and path(line,col) as MSBuild writes it.
+	pathLocation = regexp.MustCompile(`((?:[A-Za-z]:[\\/])?[^\s:"'()<>\[\]{},;|*?]+)(?::(\d+)(?::(\d+))?|\((\d+)(?:,(\d+))?\))?`)
+)
+
+// FindLocations returns the file locations text may name, those giving a
+// line first, each group in the order they appear. They are only
+// candidates: callers check which exist.
+func FindLocations(text string) []Location {
+	var withLine, without []Location
+	for _, m := range pythonLocation.FindAllStringSubmatch(text, -1) {
+		withLine = append(withLine, Location{Path: m[1], Line: atoi(m[2])})
+	}
+	for _, m := range pathLocation.FindAllStringSubmatch(text, -1) {
+		path := strings.TrimRight(m[1], ".")
+		if path == "" {
+			continue
+		}
+		loc := Location{Path: path, Line: atoi(m[2] + m[4]), Column: atoi(m[3] + m[5])}
+		if loc.Line > 0 {
+			withLine = append(withLine, loc)
+		} else {
+			without = append(without, loc)
+		}
+	}
+	return append(withLine, without...)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,53 @@
+package pathutil
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+func Canonical(path string) string {
+	if path == "" || IsVirtual(path) {
+		return path
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+
+	dir, base := filepath.Split(abs)
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		return filepath.Join(resolved, base)
+	}
+	return abs
+}
+
+func Same(a, b string) bool {
+	return Canonical(a) == Canonical(b)
+}
+
+// IsVirtual reports whether path names a document without a file behind
+// it, such as "output:make test". Its scheme is two or more lowercase
+// letters or dashes, so Windows drive letters do not count.
+func IsVirtual(path string) bool {
+	i := strings.IndexByte(path, ':')
+	if i < 2 {
+		return false
+	}
+	for _, r := range path[:i] {
+		if (r < 'a' || r > 'z') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Within reports whether path is dir or lies beneath it. Both are taken
+// as they are, so they should be canonical.
+func Within(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
@@ -0,0 +1,36 @@
+// Package trash moves files to the desktop's trash rather than deleting
+// them, so they can be restored from it, by the editor or the desktop.
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrUnsupported is returned where there is no trash to move files to.
+var ErrUnsupported = errors.New("no trash on this system")
+
+// Item is a file or directory moved to the trash.
+type Item struct {
+	// Path is where it was.
+	Path    string
+	trashed string
+	// info is the XDG .trashinfo file describing it, if any.
+	info string
+}
+
+// Restore moves the item back to where it was, unless something has taken
+// its place since.
+func (it Item) Restore() error {
+	if _, err := os.Lstat(it.Path); err == nil {
+		return fmt.Errorf("%s already exists", it.Path)
+	}
+	if err := os.Rename(it.trashed, it.Path); err != nil {
+		return err
+	}
+	if it.info != "" {
+		_ = os.Remove(it.info)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package trash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Move moves path to the user's ~/.Trash, numbering the name when the
+// trash already holds one like it.
+func Move(path string) (Item, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Item{}, err
+	}
+	if _, err := os.Lstat(abs); err != nil {
+		return Item{}, err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return Item{}, err
+	}
+	dir := filepath.Join(home, ".Trash")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return Item{}, err
+	}
+
+	base := filepath.Base(abs)
+	ext := filepath.Ext(base)
+	for n := 1; ; n++ {
+		name := base
+		if n > 1 {
+			name = strings.TrimSuffix(base, ext) + " " + strconv.Itoa(n) + ext
+		}
+		trashed := filepath.Join(dir, name)
+		if _, err := os.Lstat(trashed); err == nil {
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return Item{}, err
+		}
+		if err := os.Rename(abs, trashed); err != nil {
+			return Item{}, err
+		}
+		return Item{Path: abs, trashed: trashed}, nil
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package trash
+
+// Move reports ErrUnsupported; files are never deleted in its place.
+func Move(path string) (Item, error) {
+	return Item{}, ErrUnsupported
+}
@@ -0,0 +1,78 @@
+//go:build unix && !darwin
+
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Move moves path to the home trash of the freedesktop.org trash
+// specification, writing the .trashinfo file desktops restore it from.
+// Files on another file system than the trash are left in place.
+func Move(path string) (Item, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Item{}, err
+	}
+	if _, err := os.Lstat(abs); err != nil {
+		return Item{}, err
+	}
+	dir, err := homeTrash()
+	if err != nil {
+		return Item{}, err
+	}
+	files, infos := filepath.Join(dir, "files"), filepath.Join(dir, "info")
+	for _, d := range []string{files, infos} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			return Item{}, err
+		}
+	}
+
+	base := filepath.Base(abs)
+	for n := 1; ; n++ {
+		name := base
+		if n > 1 {
+			name = base + "." + strconv.Itoa(n)
+		}
+		// Creating the info file exclusively claims the name.
+		info := filepath.Join(infos, name+".trashinfo")
+		f, err := os.OpenFile(info, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return Item{}, err
+		}
+		_, err = fmt.Fprintf(f, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+			(&url.URL{Path: abs}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		trashed := filepath.Join(files, name)
+		if err == nil {
+			err = os.Rename(abs, trashed)
+		}
+		if err != nil {
+			_ = os.Remove(info)
+			return Item{}, err
+		}
+		return Item{Path: abs, trashed: trashed, info: info}, nil
+	}
+}
+
+func homeTrash() (string, error) {
+	if data := os.Getenv("XDG_DATA_HOME"); data != "" {
+		return filepath.Join(data, "Trash"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "Trash"), nil
+}